@@ -0,0 +1,79 @@
+package gwob
+
+import "fmt"
+
+// TextureCoordinates gets the texture coordinates for a stride index.
+// ok is false if the mesh has no texture coordinates.
+func (o *Obj) TextureCoordinates(stride int) (u, v float32, ok bool) {
+	if !o.TextCoordFound {
+		return 0, 0, false
+	}
+	t := o.vertexTexCoord(stride)
+	return t[0], t[1], true
+}
+
+// NormalCoordinates gets the normal for a stride index.
+// ok is false if the mesh has no normals.
+func (o *Obj) NormalCoordinates(stride int) (x, y, z float32, ok bool) {
+	if !o.NormCoordFound {
+		return 0, 0, 0, false
+	}
+	n := o.vertexNormal(stride)
+	return n[0], n[1], n[2], true
+}
+
+// SetVertexCoordinates sets the position for a stride index.
+func (o *Obj) SetVertexCoordinates(stride int, x, y, z float32) {
+	p := o.vertexPosition(stride)
+	p[0], p[1], p[2] = x, y, z
+}
+
+// SetTextureCoordinates sets the texture coordinates for a stride index.
+// It fails if the mesh has no texture coordinates.
+func (o *Obj) SetTextureCoordinates(stride int, u, v float32) error {
+	if !o.TextCoordFound {
+		return fmt.Errorf("SetTextureCoordinates: mesh without texture coordinates")
+	}
+	t := o.vertexTexCoord(stride)
+	t[0], t[1] = u, v
+	return nil
+}
+
+// SetNormalCoordinates sets the normal for a stride index.
+// It fails if the mesh has no normals.
+func (o *Obj) SetNormalCoordinates(stride int, x, y, z float32) error {
+	if !o.NormCoordFound {
+		return fmt.Errorf("SetNormalCoordinates: mesh without normals")
+	}
+	n := o.vertexNormal(stride)
+	n[0], n[1], n[2] = x, y, z
+	return nil
+}
+
+// vertexPosition gets the position for a stride index, as a slice
+// into the vertex data.
+func (o *Obj) vertexPosition(stride int) []float32 {
+	if o.Coord == nil && o.Positions != nil {
+		return o.Positions[3*stride : 3*stride+3]
+	}
+	p := stride*o.StrideSize/4 + o.StrideOffsetPosition/4
+	return o.Coord[p : p+3]
+}
+
+// vertexNormal gets the normal for a stride index.
+func (o *Obj) vertexNormal(stride int) []float32 {
+	if o.Coord == nil && o.Positions != nil {
+		return o.Normals[3*stride : 3*stride+3]
+	}
+	n := stride*o.StrideSize/4 + o.StrideOffsetNormal/4
+	return o.Coord[n : n+3]
+}
+
+// vertexTexCoord gets the texture coordinates for a stride index.
+func (o *Obj) vertexTexCoord(stride int) []float32 {
+	if o.Coord == nil && o.Positions != nil {
+		return o.TexCoords[2*stride : 2*stride+2]
+	}
+	t := stride*o.StrideSize/4 + o.StrideOffsetTexture/4
+	return o.Coord[t : t+2]
+}
@@ -0,0 +1,90 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestAccessors(t *testing.T) {
+	table := []struct {
+		name    string
+		options *ObjParserOptions
+	}{
+		{"interleaved", NewObjParserOptions()},
+		{"separate", NewObjParserOptions(WithNonInterleaved(true))},
+	}
+
+	for _, data := range table {
+		o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), data.options)
+		if err != nil {
+			t.Fatalf("TestAccessors: %s: NewObjFromBuf: %v", data.name, err)
+		}
+		for s := 0; s < o.NumberOfElements(); s++ {
+			f := s * cubeStrideSize / 4
+			u, v, ok := o.TextureCoordinates(s)
+			if want := cubeCoord[f+3 : f+5]; !ok || !sliceEqualFloat(want, []float32{u, v}) {
+				t.Errorf("TestAccessors: %s: stride %d uv: want=%v got=%v,%v ok=%v", data.name, s, want, u, v, ok)
+			}
+			x, y, z, ok := o.NormalCoordinates(s)
+			if want := cubeCoord[f+5 : f+8]; !ok || !sliceEqualFloat(want, []float32{x, y, z}) {
+				t.Errorf("TestAccessors: %s: stride %d normal: want=%v got=%v,%v,%v ok=%v", data.name, s, want, x, y, z, ok)
+			}
+		}
+	}
+
+	o, _ := NewObjFromBuf("box", []byte(boxObj), NewObjParserOptions())
+	if _, _, ok := o.TextureCoordinates(0); ok {
+		t.Errorf("TestAccessors: box: unexpected texture coordinates")
+	}
+	if _, _, _, ok := o.NormalCoordinates(0); ok {
+		t.Errorf("TestAccessors: box: unexpected normals")
+	}
+}
+
+func TestSetters(t *testing.T) {
+	table := []struct {
+		name    string
+		options *ObjParserOptions
+	}{
+		{"interleaved", NewObjParserOptions()},
+		{"separate", NewObjParserOptions(WithNonInterleaved(true))},
+	}
+
+	for _, data := range table {
+		o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), data.options)
+		if err != nil {
+			t.Fatalf("TestSetters: %s: NewObjFromBuf: %v", data.name, err)
+		}
+		o.SetVertexCoordinates(3, 7, 8, 9)
+		if err := o.SetTextureCoordinates(3, 0.25, 0.75); err != nil {
+			t.Errorf("TestSetters: %s: SetTextureCoordinates: %v", data.name, err)
+		}
+		if err := o.SetNormalCoordinates(3, 0, 0, -1); err != nil {
+			t.Errorf("TestSetters: %s: SetNormalCoordinates: %v", data.name, err)
+		}
+
+		x, y, z := o.VertexCoordinates(3)
+		u, v, _ := o.TextureCoordinates(3)
+		nx, ny, nz, _ := o.NormalCoordinates(3)
+		if want, got := []float32{7, 8, 9, 0.25, 0.75, 0, 0, -1}, []float32{x, y, z, u, v, nx, ny, nz}; !sliceEqualFloat(want, got) {
+			t.Errorf("TestSetters: %s: want=%v got=%v", data.name, want, got)
+		}
+
+		// neighbors untouched
+		x, _, _ = o.VertexCoordinates(4)
+		u, _, _ = o.TextureCoordinates(2)
+		if f := 4 * cubeStrideSize / 4; x != cubeCoord[f] {
+			t.Errorf("TestSetters: %s: next vertex changed: %v", data.name, x)
+		}
+		if f := 2 * cubeStrideSize / 4; u != cubeCoord[f+3] {
+			t.Errorf("TestSetters: %s: previous uv changed: %v", data.name, u)
+		}
+	}
+
+	o, _ := NewObjFromBuf("box", []byte(boxObj), NewObjParserOptions())
+	if err := o.SetTextureCoordinates(0, 0, 0); err == nil {
+		t.Errorf("TestSetters: box: expected error for texture coordinates")
+	}
+	if err := o.SetNormalCoordinates(0, 0, 0, 1); err == nil {
+		t.Errorf("TestSetters: box: expected error for normals")
+	}
+}
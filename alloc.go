@@ -0,0 +1,123 @@
+package gwob
+
+import (
+	"slices"
+	"sync"
+)
+
+// Allocator supplies the slices used for parser scratch buffers and for
+// the output Coord and Indices, so applications can keep them out of
+// the general heap, e.g. in a level arena or a sync.Pool.
+// Output slices are owned by the caller and never freed by the parser.
+type Allocator interface {
+	// Float32s returns a slice with zero length and capacity at least n.
+	Float32s(n int) []float32
+	// Ints returns a slice with zero length and capacity at least n.
+	Ints(n int) []int
+	// FreeFloat32s releases a slice no longer used by the parser.
+	FreeFloat32s(s []float32)
+	// FreeInts releases a slice no longer used by the parser.
+	FreeInts(s []int)
+}
+
+// WithAllocator sets the allocator for parser buffers.
+func WithAllocator(a Allocator) Option {
+	return func(o *ObjParserOptions) {
+		o.Allocator = a
+	}
+}
+
+// PoolAllocator is an Allocator recycling freed slices through sync.Pool.
+// Release output slices with FreeFloat32s and FreeInts to recycle them too.
+// The zero value is ready to use.
+type PoolAllocator struct {
+	floats sync.Pool
+	ints   sync.Pool
+}
+
+// Float32s implements Allocator.
+func (a *PoolAllocator) Float32s(n int) []float32 {
+	if s, ok := a.floats.Get().(*[]float32); ok && cap(*s) >= n {
+		return (*s)[:0]
+	}
+	return make([]float32, 0, n)
+}
+
+// Ints implements Allocator.
+func (a *PoolAllocator) Ints(n int) []int {
+	if s, ok := a.ints.Get().(*[]int); ok && cap(*s) >= n {
+		return (*s)[:0]
+	}
+	return make([]int, 0, n)
+}
+
+// FreeFloat32s implements Allocator.
+func (a *PoolAllocator) FreeFloat32s(s []float32) {
+	a.floats.Put(&s)
+}
+
+// FreeInts implements Allocator.
+func (a *PoolAllocator) FreeInts(s []int) {
+	a.ints.Put(&s)
+}
+
+// float32s returns an empty slice with capacity n.
+func (p *objParser) float32s(n int) []float32 {
+	if p.alloc == nil {
+		return make([]float32, 0, n)
+	}
+	return p.alloc.Float32s(n)
+}
+
+// ints returns an empty slice with capacity n.
+func (p *objParser) ints(n int) []int {
+	if p.alloc == nil {
+		return make([]int, 0, n)
+	}
+	return p.alloc.Ints(n)
+}
+
+// reserveFloat32s makes room for n more elements in s.
+func (p *objParser) reserveFloat32s(s []float32, n int) []float32 {
+	if len(s)+n <= cap(s) {
+		return s
+	}
+	if p.alloc == nil {
+		return slices.Grow(s, n)
+	}
+	t := append(p.alloc.Float32s(2*cap(s)+n), s...)
+	if cap(s) > 0 {
+		p.alloc.FreeFloat32s(s)
+	}
+	return t
+}
+
+// reserveInts makes room for n more elements in s.
+func (p *objParser) reserveInts(s []int, n int) []int {
+	if len(s)+n <= cap(s) {
+		return s
+	}
+	if p.alloc == nil {
+		return slices.Grow(s, n)
+	}
+	t := append(p.alloc.Ints(2*cap(s)+n), s...)
+	if cap(s) > 0 {
+		p.alloc.FreeInts(s)
+	}
+	return t
+}
+
+// freeScratch releases the scratch buffers to the allocator.
+func (p *objParser) freeScratch() {
+	if p.alloc == nil {
+		return
+	}
+	for _, s := range [][]float32{p.vertCoord, p.textCoord, p.normCoord} {
+		if cap(s) > 0 {
+			p.alloc.FreeFloat32s(s)
+		}
+	}
+	p.vertCoord = nil
+	p.textCoord = nil
+	p.normCoord = nil
+}
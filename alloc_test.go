@@ -0,0 +1,62 @@
+package gwob
+
+import (
+	"testing"
+)
+
+// countingAllocator tracks slices handed out and released.
+type countingAllocator struct {
+	PoolAllocator
+	gets  int
+	frees int
+}
+
+func (a *countingAllocator) Float32s(n int) []float32 {
+	a.gets++
+	return a.PoolAllocator.Float32s(n)
+}
+
+func (a *countingAllocator) Ints(n int) []int {
+	a.gets++
+	return a.PoolAllocator.Ints(n)
+}
+
+func (a *countingAllocator) FreeFloat32s(s []float32) {
+	a.frees++
+	a.PoolAllocator.FreeFloat32s(s)
+}
+
+func (a *countingAllocator) FreeInts(s []int) {
+	a.frees++
+	a.PoolAllocator.FreeInts(s)
+}
+
+func TestAllocator(t *testing.T) {
+
+	alloc := &countingAllocator{}
+
+	for _, h := range []SizeHint{{}, {Vertices: 1, TexCoords: 1, Normals: 1, Triangles: 1}} {
+		options := NewObjParserOptions(WithAllocator(alloc), WithSizeHint(h))
+
+		o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), options)
+		if err != nil {
+			t.Errorf("TestAllocator: %+v: NewObjFromBuf: %v", h, err)
+			continue
+		}
+		if !sliceEqualInt(cubeIndices, o.Indices) {
+			t.Errorf("TestAllocator: %+v: indices: want=%v got=%v", h, cubeIndices, o.Indices)
+		}
+		if !sliceEqualFloat(cubeCoord, o.Coord) {
+			t.Errorf("TestAllocator: %+v: coord: want=%v got=%v", h, cubeCoord, o.Coord)
+		}
+
+		// output slices are returned by the caller
+		alloc.FreeFloat32s(o.Coord)
+		alloc.FreeInts(o.Indices)
+	}
+
+	if alloc.gets == 0 {
+		t.Errorf("TestAllocator: allocator not used")
+	}
+	expectInt(t, "TestAllocator: every slice released", alloc.gets, alloc.frees)
+}
@@ -0,0 +1,104 @@
+package gwob
+
+import "fmt"
+
+// AxisConvention names a coordinate system: which axis points up, and
+// whether it is right- or left-handed.
+type AxisConvention int
+
+// Axis conventions, described with the viewer looking at the front of
+// the model.
+const (
+	YUpRightHanded AxisConvention = iota // x right, y up, z toward viewer: OpenGL, glTF
+	ZUpRightHanded                       // x right, y away from viewer, z up: Blender, 3ds Max
+	YUpLeftHanded                        // x right, y up, z away from viewer: Direct3D, Unity
+	ZUpLeftHanded                        // x right, y toward viewer, z up
+)
+
+var axisConventionNames = []string{"YUpRightHanded", "ZUpRightHanded", "YUpLeftHanded", "ZUpLeftHanded"}
+
+// String gets the name of the convention.
+func (c AxisConvention) String() string {
+	if c < 0 || int(c) >= len(axisConventionNames) {
+		return fmt.Sprintf("AxisConvention(%d)", int(c))
+	}
+	return axisConventionNames[c]
+}
+
+// axisMatrix is a signed permutation matrix mapping coordinates of a
+// convention into YUpRightHanded.
+type axisMatrix [3][3]float32
+
+var axisMatrices = []axisMatrix{
+	YUpRightHanded: {{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+	ZUpRightHanded: {{1, 0, 0}, {0, 0, 1}, {0, -1, 0}},
+	YUpLeftHanded:  {{1, 0, 0}, {0, 1, 0}, {0, 0, -1}},
+	ZUpLeftHanded:  {{1, 0, 0}, {0, 0, 1}, {0, 1, 0}},
+}
+
+func (m *axisMatrix) mul(n *axisMatrix) axisMatrix {
+	var r axisMatrix
+	for i := range r {
+		for j := range r[i] {
+			for k := range r {
+				r[i][j] += m[i][k] * n[k][j]
+			}
+		}
+	}
+	return r
+}
+
+func (m *axisMatrix) transpose() axisMatrix {
+	var r axisMatrix
+	for i := range r {
+		for j := range r[i] {
+			r[i][j] = m[j][i]
+		}
+	}
+	return r
+}
+
+func (m *axisMatrix) det() float32 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+func (m *axisMatrix) apply(v []float32) {
+	x, y, z := v[0], v[1], v[2]
+	for i := range m {
+		v[i] = m[i][0]*x + m[i][1]*y + m[i][2]*z
+	}
+}
+
+// ConvertAxes converts positions and normals from one axis convention
+// to another. Conversions between right- and left-handed conventions
+// mirror the mesh, so the triangle winding is flipped to keep front
+// faces facing out. The raw data kept by the KeepRawData option is not
+// changed.
+func (o *Obj) ConvertAxes(from, to AxisConvention) error {
+	for _, c := range []AxisConvention{from, to} {
+		if c < 0 || int(c) >= len(axisMatrices) {
+			return fmt.Errorf("ConvertAxes: bad convention: %v", c)
+		}
+	}
+	if from == to {
+		return nil
+	}
+
+	back := axisMatrices[to].transpose() // inverse of a rotation or mirror
+	m := back.mul(&axisMatrices[from])
+
+	for s := 0; s < o.NumberOfElements(); s++ {
+		m.apply(o.vertexPosition(s))
+		if o.NormCoordFound {
+			m.apply(o.vertexNormal(s))
+		}
+	}
+
+	if m.det() < 0 {
+		o.FlipWinding()
+	}
+
+	return nil
+}
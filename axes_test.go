@@ -0,0 +1,60 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestConvertAxes(t *testing.T) {
+	str := `
+v 0 0 0
+v 1 0 0
+v 0 1 0
+vn 0 0 1
+f 1//1 2//1 3//1
+`
+	conventions := []AxisConvention{YUpRightHanded, ZUpRightHanded, YUpLeftHanded, ZUpLeftHanded}
+
+	for _, from := range conventions {
+		for _, to := range conventions {
+			o, err := NewObjFromBuf("axes", []byte(str), NewObjParserOptions())
+			if err != nil {
+				t.Fatalf("TestConvertAxes: NewObjFromBuf: %v", err)
+			}
+			original := append([]float32(nil), o.Coord...)
+
+			if err := o.ConvertAxes(from, to); err != nil {
+				t.Errorf("TestConvertAxes: %v to %v: %v", from, to, err)
+				continue
+			}
+
+			// the geometric normal follows the vertex normal
+			n := o.triangleNormal(0)
+			if got := o.vertexNormal(o.Indices[0]); !sliceEqualFloat(n[:], got) {
+				t.Errorf("TestConvertAxes: %v to %v: triangle normal=%v vertex normal=%v", from, to, n, got)
+			}
+
+			if err := o.ConvertAxes(to, from); err != nil {
+				t.Errorf("TestConvertAxes: %v to %v back: %v", from, to, err)
+				continue
+			}
+			if !sliceEqualFloat(original, o.Coord) {
+				t.Errorf("TestConvertAxes: %v to %v back: want=%v got=%v", from, to, original, o.Coord)
+			}
+			if want := []int{0, 1, 2}; !sliceEqualInt(want, o.Indices) {
+				t.Errorf("TestConvertAxes: %v to %v back: indices=%v", from, to, o.Indices)
+			}
+		}
+	}
+
+	o, _ := NewObjFromBuf("axes", []byte(str), NewObjParserOptions())
+	if err := o.ConvertAxes(ZUpRightHanded, YUpRightHanded); err != nil {
+		t.Fatalf("TestConvertAxes: %v", err)
+	}
+	if want := []float32{0, 0, 0, 0, 1, 0, 1, 0, 0, 0, 1, 0, 0, 0, -1, 0, 1, 0}; !sliceEqualFloat(want, o.Coord) {
+		t.Errorf("TestConvertAxes: z-up to y-up: want=%v got=%v", want, o.Coord)
+	}
+
+	if err := o.ConvertAxes(AxisConvention(9), YUpRightHanded); err == nil {
+		t.Errorf("TestConvertAxes: expected error for bad convention")
+	}
+}
@@ -0,0 +1,45 @@
+package gwob
+
+import "math"
+
+// Bounds gets the corners of the axis-aligned box enclosing all
+// vertices. Both are zero for an Obj without vertices.
+func (o *Obj) Bounds() (min, max [3]float32) {
+	strides := o.NumberOfElements()
+	if strides == 0 {
+		return
+	}
+	min[0], min[1], min[2] = o.VertexCoordinates(0)
+	max = min
+	for s := 1; s < strides; s++ {
+		x, y, z := o.VertexCoordinates(s)
+		for i, c := range [3]float32{x, y, z} {
+			if c < min[i] {
+				min[i] = c
+			}
+			if c > max[i] {
+				max[i] = c
+			}
+		}
+	}
+	return
+}
+
+// BoundingSphere gets a sphere enclosing all vertices, centered on the
+// bounding box. It is not the smallest sphere, but it is never larger
+// than the sphere around the box.
+func (o *Obj) BoundingSphere() (center [3]float32, radius float32) {
+	min, max := o.Bounds()
+	for i := range center {
+		center[i] = (min[i] + max[i]) / 2
+	}
+	var r2 float64
+	for s := 0; s < o.NumberOfElements(); s++ {
+		x, y, z := o.VertexCoordinates(s)
+		dx := float64(x - center[0])
+		dy := float64(y - center[1])
+		dz := float64(z - center[2])
+		r2 = math.Max(r2, dx*dx+dy*dy+dz*dz)
+	}
+	return center, float32(math.Sqrt(r2))
+}
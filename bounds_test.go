@@ -0,0 +1,42 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestBounds(t *testing.T) {
+	str := `
+v 1 -2 3
+v -1 2 0
+v 0 0 -3
+f 1 2 3
+`
+	o, err := NewObjFromBuf("bounds", []byte(str), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestBounds: NewObjFromBuf: %v", err)
+	}
+	min, max := o.Bounds()
+	if want := []float32{-1, -2, -3}; !sliceEqualFloat(want, min[:]) {
+		t.Errorf("TestBounds: min: want=%v got=%v", want, min)
+	}
+	if want := []float32{1, 2, 3}; !sliceEqualFloat(want, max[:]) {
+		t.Errorf("TestBounds: max: want=%v got=%v", want, max)
+	}
+
+	center, radius := o.BoundingSphere()
+	if want := []float32{0, 0, 0}; !sliceEqualFloat(want, center[:]) {
+		t.Errorf("TestBounds: center: want=%v got=%v", want, center)
+	}
+	if want := float32(3.7416575); radius != want { // sqrt(1+4+9)
+		t.Errorf("TestBounds: radius: want=%v got=%v", want, radius)
+	}
+
+	empty, _ := NewObjFromVertex(nil, nil)
+	min, max = empty.Bounds()
+	if min != [3]float32{} || max != [3]float32{} {
+		t.Errorf("TestBounds: empty: min=%v max=%v", min, max)
+	}
+	if _, radius := empty.BoundingSphere(); radius != 0 {
+		t.Errorf("TestBounds: empty radius=%v", radius)
+	}
+}
@@ -0,0 +1,128 @@
+package gwob
+
+import "fmt"
+
+// ObjBuilder builds an Obj programmatically, with the same vertex
+// unification, grouping and parser options as parsing the equivalent
+// OBJ statements, so procedural geometry gets identical output.
+//
+// Positions, texture coordinates and normals are added first, like v,
+// vt and vn statements. AddVertex then combines them into a unified
+// vertex, and AddTriangle adds a triangle of unified vertices.
+type ObjBuilder struct {
+	p       *objParser
+	o       *Obj
+	options *ObjParserOptions
+	keys    []vertexKey // unified vertex -> v/vt/vn
+}
+
+// NewObjBuilder creates a builder honoring the parser options, which
+// may be nil. Options about reading text, such as Strict, are ignored.
+// Nil options are silent: unlike the parsers, the builder does not log
+// stats by default.
+func NewObjBuilder(options *ObjParserOptions) *ObjBuilder {
+	if options == nil {
+		options = &ObjParserOptions{}
+	}
+	b := &ObjBuilder{
+		p:       &objParser{material: -1, objName: "builder"},
+		o:       &Obj{},
+		options: options,
+	}
+	b.p.alloc = options.Allocator
+	b.p.presize(b.o, options.sizeHint(-1))
+	b.p.currGroup = b.o.newGroup("", "", 0, 0)
+	return b
+}
+
+// AddPosition adds a vertex position, as a v statement,
+// returning its zero-based index.
+func (b *ObjBuilder) AddPosition(x, y, z float32) int {
+	b.p.addPosition(float64(x), float64(y), float64(z), 1, b.options)
+	return b.p.vertLines - 1
+}
+
+// AddUV adds a texture coordinate, as a vt statement,
+// returning its zero-based index.
+func (b *ObjBuilder) AddUV(u, v float32) int {
+	b.p.addTexCoord(float64(u), float64(v), 0, b.options)
+	return b.p.textLines - 1
+}
+
+// AddNormal adds a normal, as a vn statement,
+// returning its zero-based index.
+func (b *ObjBuilder) AddNormal(x, y, z float32) int {
+	b.p.addNormal(float64(x), float64(y), float64(z), b.options)
+	return b.p.normLines - 1
+}
+
+// AddVertex gets the unified vertex combining the position v, the
+// texture coordinate vt and the normal vn, zero-based indices as
+// returned by AddPosition, AddUV and AddNormal, adding it if new.
+// Missing vt or vn are -1.
+func (b *ObjBuilder) AddVertex(v, vt, vn int) (int, error) {
+	if v < 0 || v >= b.p.vertLines {
+		return 0, fmt.Errorf("AddVertex: invalid position index=%d", v)
+	}
+	if vt < -1 || vt >= b.p.textLines {
+		return 0, fmt.Errorf("AddVertex: invalid texture index=%d", vt)
+	}
+	if vn < -1 || vn >= b.p.normLines {
+		return 0, fmt.Errorf("AddVertex: invalid normal index=%d", vn)
+	}
+	key := vertexKey{v: v, t: vt, n: vn}
+	i := unifyVertex(b.p, b.o, key, b.options)
+	if i == len(b.keys) {
+		b.keys = append(b.keys, key)
+	}
+	return i, nil
+}
+
+// AddTriangle adds a triangle of unified vertices, as returned by
+// AddVertex, to the current group.
+func (b *ObjBuilder) AddTriangle(v0, v1, v2 int) error {
+	vertices := [3]int{v0, v1, v2}
+	var keys [3]vertexKey
+	for i, v := range vertices {
+		if v < 0 || v >= len(b.keys) {
+			return fmt.Errorf("AddTriangle: invalid vertex index=%d", v)
+		}
+		keys[i] = b.keys[v]
+	}
+
+	o := b.o
+	indices := len(o.Indices)
+	count := b.p.currGroup.IndexCount
+	for i, v := range vertices {
+		pushCorner(o, keys[i], b.options)
+		pushIndex(b.p.currGroup, o, v)
+	}
+	b.p.faceLines++
+	b.p.triangles++
+	b.p.tagTriangles(o, indices, count, b.options)
+	if b.options.KeepFaces {
+		b.p.keepFace(o, keys[:], triangleOrder[:], indices, b.options)
+	}
+	return nil
+}
+
+// BeginGroup starts a group, as g, usemtl and s statements: triangles
+// added next belong to it. An empty name or material keeps the current
+// one, and smooth 0 turns smoothing off.
+func (b *ObjBuilder) BeginGroup(name, material string, smooth int) {
+	if name != "" {
+		b.p.setGroup(b.o, 'g', name)
+	}
+	if material != "" {
+		b.p.setMaterial(b.o, material, b.options)
+	}
+	b.p.setSmooth(b.o, smooth, b.options)
+}
+
+// Build completes and returns the Obj. The builder must not be used
+// afterwards.
+func (b *ObjBuilder) Build() *Obj {
+	finishObj(b.p, b.o, b.p.objName, b.options)
+	b.p.freeScratch()
+	return b.o
+}
@@ -0,0 +1,100 @@
+package gwob
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestObjBuilder(t *testing.T) {
+	str := `v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vt 0 0
+vt 1 0
+vt 1 1
+vn 0 0 1
+g quad
+usemtl red
+s 1
+f 1/1/1 2/2/1 3/3/1
+f 3/3/1 4/1/1 1/1/1
+g tri
+usemtl blue
+s 0
+f 1//1 2//1 4//1
+`
+	table := []struct {
+		name    string
+		options *ObjParserOptions
+	}{
+		{"default", NewObjParserOptions()},
+		{"faces", NewObjParserOptions(WithKeepFaces(true), WithKeepRawData(true))},
+		{"per triangle", NewObjParserOptions(WithSmoothPerTriangle(true), WithMaterialPerTriangle(true))},
+		{"separate", NewObjParserOptions(WithNonInterleaved(true))},
+		{"no unify", NewObjParserOptions(WithNoUnify(true))},
+		{"flip", NewObjParserOptions(WithFlipTextureV(true))},
+	}
+
+	for _, data := range table {
+		want, err := NewObjFromBuf("builder", []byte(str), data.options)
+		if err != nil {
+			t.Fatalf("TestObjBuilder: %s: NewObjFromBuf: %v", data.name, err)
+		}
+
+		b := NewObjBuilder(data.options)
+		b.AddPosition(0, 0, 0)
+		b.AddPosition(1, 0, 0)
+		b.AddPosition(1, 1, 0)
+		b.AddPosition(0, 1, 0)
+		b.AddUV(0, 0)
+		b.AddUV(1, 0)
+		b.AddUV(1, 1)
+		b.AddNormal(0, 0, 1)
+
+		triangle := func(corners ...[3]int) {
+			var v [3]int
+			for i, c := range corners {
+				var err error
+				v[i], err = b.AddVertex(c[0], c[1], c[2])
+				if err != nil {
+					t.Fatalf("TestObjBuilder: %s: AddVertex: %v", data.name, err)
+				}
+			}
+			if err := b.AddTriangle(v[0], v[1], v[2]); err != nil {
+				t.Fatalf("TestObjBuilder: %s: AddTriangle: %v", data.name, err)
+			}
+		}
+		b.BeginGroup("quad", "red", 1)
+		triangle([3]int{0, 0, 0}, [3]int{1, 1, 0}, [3]int{2, 2, 0})
+		triangle([3]int{2, 2, 0}, [3]int{3, 0, 0}, [3]int{0, 0, 0})
+		b.BeginGroup("tri", "blue", 0)
+		triangle([3]int{0, -1, 0}, [3]int{1, -1, 0}, [3]int{3, -1, 0})
+
+		got := b.Build()
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("TestObjBuilder: %s:\nwant=%+v\n got=%+v", data.name, want, got)
+		}
+	}
+}
+
+func TestObjBuilderErrors(t *testing.T) {
+	b := NewObjBuilder(nil)
+	b.AddPosition(0, 0, 0)
+	if _, err := b.AddVertex(1, -1, -1); err == nil {
+		t.Errorf("TestObjBuilderErrors: expected error for bad position")
+	}
+	if _, err := b.AddVertex(0, 0, -1); err == nil {
+		t.Errorf("TestObjBuilderErrors: expected error for bad texture coordinate")
+	}
+	if err := b.AddTriangle(0, 0, 0); err == nil {
+		t.Errorf("TestObjBuilderErrors: expected error for unknown vertex")
+	}
+}
+
+func TestObjBuilderSilent(t *testing.T) {
+	b := NewObjBuilder(nil)
+	if b.options.LogStats || b.options.Logger != nil {
+		t.Errorf("TestObjBuilderSilent: nil options must not log")
+	}
+}
@@ -0,0 +1,286 @@
+/*
+Package bvh builds bounding volume hierarchies over the triangles of gwob
+OBJ data, to answer ray casts and proximity queries in logarithmic time,
+instead of testing every triangle.
+
+The tree refers to the Obj it was built from. After moving vertices,
+call Refit to update the bounds; after changing the triangles, build a
+new tree.
+*/
+package bvh
+
+import (
+	"math"
+	"sort"
+
+	"github.com/udhos/gwob"
+)
+
+// LeafSize is the largest number of triangles in a leaf.
+const LeafSize = 4
+
+// AABB is an axis-aligned bounding box.
+type AABB struct {
+	Min, Max [3]float32
+}
+
+// empty is the box containing nothing, neutral for union.
+func empty() AABB {
+	inf := float32(math.Inf(1))
+	return AABB{Min: [3]float32{inf, inf, inf}, Max: [3]float32{-inf, -inf, -inf}}
+}
+
+func (b *AABB) add(p [3]float32) {
+	for i := range p {
+		b.Min[i] = min(b.Min[i], p[i])
+		b.Max[i] = max(b.Max[i], p[i])
+	}
+}
+
+func (b *AABB) union(c AABB) {
+	b.add(c.Min)
+	b.add(c.Max)
+}
+
+// Node is a tree node: a leaf holding Count triangles from First in
+// Tree.Triangles, or an inner node, with Count zero, whose children
+// are Left and Right.
+type Node struct {
+	Bounds      AABB
+	Left, Right int
+	First       int
+	Count       int
+}
+
+// Leaf reports whether the node holds triangles.
+func (n *Node) Leaf() bool {
+	return n.Count > 0
+}
+
+// Tree is a bounding volume hierarchy. The root is Nodes[0], and every
+// node comes before its children.
+type Tree struct {
+	Nodes     []Node
+	Triangles []int // triangle numbers, in leaf order
+	obj       *gwob.Obj
+}
+
+// New builds the tree for the triangles of o, splitting nodes at the
+// median centroid along their longest axis.
+func New(o *gwob.Obj) *Tree {
+	t := &Tree{obj: o}
+	count := len(o.Indices) / 3
+	t.Triangles = make([]int, count)
+	centroids := make([][3]float32, count)
+	for i := range t.Triangles {
+		t.Triangles[i] = i
+		p := t.positions(i)
+		for a := range centroids[i] {
+			centroids[i][a] = (p[0][a] + p[1][a] + p[2][a]) / 3
+		}
+	}
+	t.Nodes = append(t.Nodes, Node{})
+	t.build(0, 0, count, centroids)
+	return t
+}
+
+// build fills node n with triangles [first, first+count).
+func (t *Tree) build(n, first, count int, centroids [][3]float32) {
+	node := Node{Bounds: empty(), First: first, Count: count}
+	span := empty()
+	for _, tri := range t.Triangles[first : first+count] {
+		for _, p := range t.positions(tri) {
+			node.Bounds.add(p)
+		}
+		span.add(centroids[tri])
+	}
+	if count <= LeafSize {
+		t.Nodes[n] = node
+		return
+	}
+
+	axis := 0
+	for a := 1; a < 3; a++ {
+		if span.Max[a]-span.Min[a] > span.Max[axis]-span.Min[axis] {
+			axis = a
+		}
+	}
+	list := t.Triangles[first : first+count]
+	sort.Slice(list, func(i, j int) bool {
+		return centroids[list[i]][axis] < centroids[list[j]][axis]
+	})
+	half := count / 2
+
+	node.Left = len(t.Nodes)
+	node.Right = node.Left + 1
+	node.Count = 0
+	t.Nodes[n] = node
+	t.Nodes = append(t.Nodes, Node{}, Node{})
+	t.build(node.Left, first, half, centroids)
+	t.build(node.Right, first+half, count-half, centroids)
+}
+
+// positions gets the corners of triangle tri.
+func (t *Tree) positions(tri int) [3][3]float32 {
+	var p [3][3]float32
+	for c := range p {
+		p[c][0], p[c][1], p[c][2] = t.obj.VertexCoordinates(t.obj.Indices[3*tri+c])
+	}
+	return p
+}
+
+// Refit recomputes the node bounds from the current vertex positions,
+// keeping the tree shape.
+func (t *Tree) Refit() {
+	for n := len(t.Nodes) - 1; n >= 0; n-- { // children first
+		node := &t.Nodes[n]
+		node.Bounds = empty()
+		if node.Leaf() {
+			for _, tri := range t.Triangles[node.First : node.First+node.Count] {
+				for _, p := range t.positions(tri) {
+					node.Bounds.add(p)
+				}
+			}
+			continue
+		}
+		node.Bounds.union(t.Nodes[node.Left].Bounds)
+		node.Bounds.union(t.Nodes[node.Right].Bounds)
+	}
+}
+
+// Traverse visits the tree depth-first, descending into the nodes whose
+// bounds are accepted by enter, and calling visit for the triangles of
+// the accepted leaves, until visit returns false.
+func (t *Tree) Traverse(enter func(bounds AABB) bool, visit func(triangle int) bool) {
+	if len(t.Triangles) == 0 {
+		return
+	}
+	stack := []int{0}
+	for len(stack) > 0 {
+		node := &t.Nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if !enter(node.Bounds) {
+			continue
+		}
+		if !node.Leaf() {
+			stack = append(stack, node.Right, node.Left)
+			continue
+		}
+		for _, tri := range t.Triangles[node.First : node.First+node.Count] {
+			if !visit(tri) {
+				return
+			}
+		}
+	}
+}
+
+// RayIntersect finds the nearest triangle hit by the ray from origin
+// along dir, as gwob.Obj.RayIntersect.
+func (t *Tree) RayIntersect(origin, dir [3]float32) (gwob.RayHit, bool) {
+	var best gwob.RayHit
+	found := false
+
+	d := [3]float64{float64(dir[0]), float64(dir[1]), float64(dir[2])}
+	l := math.Sqrt(d[0]*d[0] + d[1]*d[1] + d[2]*d[2])
+	if l == 0 || len(t.Triangles) == 0 {
+		return best, false
+	}
+	var inv [3]float64
+	for i := range d {
+		inv[i] = l / d[i] // 1 over the unit direction, possibly infinite
+	}
+
+	// entry gets the ray distance to the box, or +Inf for a miss.
+	entry := func(b AABB) float64 {
+		near, far := 0.0, math.Inf(1)
+		for i := range inv {
+			t0 := (float64(b.Min[i]) - float64(origin[i])) * inv[i]
+			t1 := (float64(b.Max[i]) - float64(origin[i])) * inv[i]
+			if math.IsNaN(t0) || math.IsNaN(t1) {
+				continue // origin on a slab plane of a parallel ray
+			}
+			if t0 > t1 {
+				t0, t1 = t1, t0
+			}
+			near = max(near, t0)
+			far = min(far, t1)
+		}
+		if near > far {
+			return math.Inf(1)
+		}
+		return near
+	}
+
+	stack := []int{0}
+	for len(stack) > 0 {
+		node := &t.Nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		e := entry(node.Bounds)
+		if math.IsInf(e, 1) || (found && e > float64(best.Distance)) {
+			continue
+		}
+		if !node.Leaf() {
+			// visit the nearer child first
+			near, far := node.Left, node.Right
+			if entry(t.Nodes[far].Bounds) < entry(t.Nodes[near].Bounds) {
+				near, far = far, near
+			}
+			stack = append(stack, far, near)
+			continue
+		}
+		for _, tri := range t.Triangles[node.First : node.First+node.Count] {
+			hit, ok := t.obj.IntersectTriangle(tri, origin, dir)
+			if ok && (!found || hit.Distance < best.Distance) {
+				best, found = hit, true
+			}
+		}
+	}
+	return best, found
+}
+
+// Nearest finds the point of the triangles closest to p, returning its
+// triangle, position and distance. It returns triangle -1 when there
+// are no triangles.
+func (t *Tree) Nearest(p [3]float32) (triangle int, point [3]float32, distance float32) {
+	triangle = -1
+	if len(t.Triangles) == 0 {
+		return
+	}
+	q := [3]float64{float64(p[0]), float64(p[1]), float64(p[2])}
+	best := math.Inf(1) // squared distance
+
+	stack := []int{0}
+	for len(stack) > 0 {
+		node := &t.Nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if boxDistance2(node.Bounds, q) > best {
+			continue
+		}
+		if !node.Leaf() {
+			near, far := node.Left, node.Right
+			if boxDistance2(t.Nodes[far].Bounds, q) < boxDistance2(t.Nodes[near].Bounds, q) {
+				near, far = far, near
+			}
+			stack = append(stack, far, near)
+			continue
+		}
+		for _, tri := range t.Triangles[node.First : node.First+node.Count] {
+			c := ClosestPoint(t.positions(tri), p)
+			d := sub(vec64(c), q)
+			if d2 := dot(d, d); d2 < best {
+				best, triangle, point = d2, tri, c
+			}
+		}
+	}
+	return triangle, point, float32(math.Sqrt(best))
+}
+
+// boxDistance2 gets the squared distance from q to box b.
+func boxDistance2(b AABB, q [3]float64) float64 {
+	var sum float64
+	for i := range q {
+		d := max(float64(b.Min[i])-q[i], 0, q[i]-float64(b.Max[i]))
+		sum += d * d
+	}
+	return sum
+}
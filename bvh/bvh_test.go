@@ -0,0 +1,212 @@
+package bvh
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/udhos/gwob"
+)
+
+// sphereObj builds a UV sphere of radius 1 with vertex normals.
+func sphereObj(t *testing.T, rings, sectors int) *gwob.Obj {
+	var b strings.Builder
+	for r := 0; r <= rings; r++ {
+		phi := math.Pi * float64(r) / float64(rings)
+		for s := 0; s < sectors; s++ {
+			theta := 2 * math.Pi * float64(s) / float64(sectors)
+			x, y, z := math.Sin(phi)*math.Cos(theta), math.Cos(phi), math.Sin(phi)*math.Sin(theta)
+			fmt.Fprintf(&b, "v %v %v %v\nvn %v %v %v\n", x, y, z, x, y, z)
+		}
+	}
+	for r := 0; r < rings; r++ {
+		for s := 0; s < sectors; s++ {
+			a := r*sectors + s + 1
+			c := r*sectors + (s+1)%sectors + 1
+			fmt.Fprintf(&b, "f %d//%d %d//%d %d//%d\n", a, a, c, c, c+sectors, c+sectors)
+			fmt.Fprintf(&b, "f %d//%d %d//%d %d//%d\n", a, a, c+sectors, c+sectors, a+sectors, a+sectors)
+		}
+	}
+	o, err := gwob.NewObjFromBuf("sphere", []byte(b.String()), gwob.NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("sphereObj: NewObjFromBuf: %v", err)
+	}
+	return o
+}
+
+func randomPoint(r *rand.Rand, scale float32) [3]float32 {
+	return [3]float32{
+		scale * (2*r.Float32() - 1),
+		scale * (2*r.Float32() - 1),
+		scale * (2*r.Float32() - 1),
+	}
+}
+
+func TestNew(t *testing.T) {
+	o := sphereObj(t, 16, 24)
+	tree := New(o)
+
+	seen := make([]bool, len(o.Indices)/3)
+	for _, tri := range tree.Triangles {
+		seen[tri] = true
+	}
+	for tri, s := range seen {
+		if !s {
+			t.Errorf("TestNew: triangle %d missing", tri)
+		}
+	}
+
+	for n, node := range tree.Nodes {
+		if node.Leaf() {
+			if node.Count > LeafSize {
+				t.Errorf("TestNew: node %d: count=%d", n, node.Count)
+			}
+			continue
+		}
+		for _, child := range []int{node.Left, node.Right} {
+			if child <= n {
+				t.Errorf("TestNew: node %d: child %d before parent", n, child)
+			}
+			cb := tree.Nodes[child].Bounds
+			for i := 0; i < 3; i++ {
+				if cb.Min[i] < node.Bounds.Min[i] || cb.Max[i] > node.Bounds.Max[i] {
+					t.Errorf("TestNew: node %d: child %d outside bounds", n, child)
+				}
+			}
+		}
+	}
+
+	visited := 0
+	tree.Traverse(func(AABB) bool { return true }, func(int) bool {
+		visited++
+		return true
+	})
+	if visited != len(seen) {
+		t.Errorf("TestNew: traverse visited=%d triangles=%d", visited, len(seen))
+	}
+
+	// only the upper half
+	tree.Traverse(func(b AABB) bool { return b.Max[1] > 0.5 }, func(tri int) bool {
+		x, y, z := o.VertexCoordinates(o.Indices[3*tri])
+		if y < 0 {
+			t.Errorf("TestNew: traverse visited triangle %d at %v,%v,%v", tri, x, y, z)
+		}
+		return true
+	})
+}
+
+func TestRayIntersect(t *testing.T) {
+	o := sphereObj(t, 16, 24)
+	tree := New(o)
+	r := rand.New(rand.NewSource(1))
+
+	hits := 0
+	for i := 0; i < 200; i++ {
+		origin := randomPoint(r, 3)
+		target := randomPoint(r, 1)
+		dir := [3]float32{target[0] - origin[0], target[1] - origin[1], target[2] - origin[2]}
+
+		want, wantOK := o.RayIntersect(origin, dir)
+		got, gotOK := tree.RayIntersect(origin, dir)
+		if gotOK != wantOK || got.Distance != want.Distance {
+			t.Errorf("TestRayIntersect: ray %d: ok=%v distance=%v want ok=%v distance=%v", i, gotOK, got.Distance, wantOK, want.Distance)
+		}
+		if gotOK {
+			hits++
+		}
+	}
+	if hits == 0 {
+		t.Errorf("TestRayIntersect: no hits")
+	}
+
+	// axis-aligned ray, parallel to slab planes
+	hit, ok := tree.RayIntersect([3]float32{0, 0, 0}, [3]float32{0, 0, -1})
+	if !ok || math.Abs(float64(hit.Distance)-1) > 0.05 {
+		t.Errorf("TestRayIntersect: axis ray: ok=%v distance=%v", ok, hit.Distance)
+	}
+}
+
+func TestNearest(t *testing.T) {
+	o := sphereObj(t, 16, 24)
+	tree := New(o)
+	r := rand.New(rand.NewSource(2))
+
+	for i := 0; i < 200; i++ {
+		p := randomPoint(r, 3)
+
+		wantDist := float32(math.Inf(1))
+		for tri := 0; tri < len(o.Indices)/3; tri++ {
+			c := ClosestPoint(tree.positions(tri), p)
+			d := sub(vec64(c), vec64(p))
+			wantDist = min(wantDist, float32(math.Sqrt(dot(d, d))))
+		}
+
+		tri, point, dist := tree.Nearest(p)
+		if tri < 0 || dist != wantDist {
+			t.Errorf("TestNearest: point %d: triangle=%d distance=%v want=%v", i, tri, dist, wantDist)
+		}
+		d := sub(vec64(point), vec64(p))
+		if math.Abs(math.Sqrt(dot(d, d))-float64(dist)) > 1e-5 {
+			t.Errorf("TestNearest: point %d: point=%v at distance=%v", i, point, dist)
+		}
+	}
+}
+
+func TestClosestPoint(t *testing.T) {
+	tri := [3][3]float32{{0, 0, 0}, {2, 0, 0}, {0, 2, 0}}
+	table := []struct {
+		p, want [3]float32
+	}{
+		{[3]float32{0.5, 0.5, 3}, [3]float32{0.5, 0.5, 0}}, // face
+		{[3]float32{-1, -1, 0}, [3]float32{0, 0, 0}},       // vertex
+		{[3]float32{1, -1, 1}, [3]float32{1, 0, 0}},        // edge
+		{[3]float32{2, 2, 0}, [3]float32{1, 1, 0}},         // hypotenuse
+		{[3]float32{3, -1, 0}, [3]float32{2, 0, 0}},        // vertex
+	}
+	for _, data := range table {
+		if got := ClosestPoint(tri, data.p); got != data.want {
+			t.Errorf("TestClosestPoint: %v: got=%v want=%v", data.p, got, data.want)
+		}
+	}
+
+	// degenerate triangle, collapsed to a segment
+	segment := [3][3]float32{{0, 0, 0}, {0, 0, 0}, {2, 0, 0}}
+	if got := ClosestPoint(segment, [3]float32{1, 1, 0}); got != [3]float32{1, 0, 0} {
+		t.Errorf("TestClosestPoint: segment: got=%v", got)
+	}
+}
+
+func TestRefit(t *testing.T) {
+	o := sphereObj(t, 8, 12)
+	tree := New(o)
+	o.Translate(10, 0, 0)
+
+	if _, ok := tree.RayIntersect([3]float32{10, 5, 0}, [3]float32{0, -1, 0}); ok {
+		t.Errorf("TestRefit: hit before refit") // stale bounds
+	}
+	tree.Refit()
+	hit, ok := tree.RayIntersect([3]float32{10, 5, 0}, [3]float32{0, -1, 0})
+	if !ok || math.Abs(float64(hit.Distance)-4) > 1e-5 {
+		t.Errorf("TestRefit: ok=%v distance=%v", ok, hit.Distance)
+	}
+	if b := tree.Nodes[0].Bounds; b.Min[0] < 8.9 || b.Max[0] > 11.1 {
+		t.Errorf("TestRefit: root bounds=%v", b)
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	o, err := gwob.NewObjFromVertex(nil, nil)
+	if err != nil {
+		t.Fatalf("TestEmpty: NewObjFromVertex: %v", err)
+	}
+	tree := New(o)
+	if _, ok := tree.RayIntersect([3]float32{}, [3]float32{1, 0, 0}); ok {
+		t.Errorf("TestEmpty: hit")
+	}
+	if tri, _, _ := tree.Nearest([3]float32{}); tri != -1 {
+		t.Errorf("TestEmpty: nearest=%d", tri)
+	}
+	tree.Refit()
+}
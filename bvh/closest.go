@@ -0,0 +1,91 @@
+package bvh
+
+type vec [3]float64
+
+func vec64(p [3]float32) vec {
+	return vec{float64(p[0]), float64(p[1]), float64(p[2])}
+}
+
+func sub(a, b vec) vec { return vec{a[0] - b[0], a[1] - b[1], a[2] - b[2]} }
+
+func dot(a, b vec) float64 { return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] }
+
+// along gets a + s*u.
+func along(a vec, s float64, u vec) vec {
+	return vec{a[0] + s*u[0], a[1] + s*u[1], a[2] + s*u[2]}
+}
+
+func cross(a, b vec) vec {
+	return vec{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+// segmentPoint gets the point of segment ab closest to q.
+func segmentPoint(a, b, q vec) vec {
+	ab := sub(b, a)
+	s := max(0, min(1, ratio(dot(sub(q, a), ab), dot(ab, ab))))
+	return along(a, s, ab)
+}
+
+// ratio gets n/d, or zero for d zero, as found on degenerate triangles.
+func ratio(n, d float64) float64 {
+	if d == 0 {
+		return 0
+	}
+	return n / d
+}
+
+// ClosestPoint gets the point of the triangle closest to p, after
+// Ericson, "Real-Time Collision Detection" (2005), section 5.1.5.
+func ClosestPoint(tri [3][3]float32, p [3]float32) [3]float32 {
+	a, b, c := vec64(tri[0]), vec64(tri[1]), vec64(tri[2])
+	q := vec64(p)
+	ab, ac, ap := sub(b, a), sub(c, a), sub(q, a)
+
+	result := func(r vec) [3]float32 {
+		return [3]float32{float32(r[0]), float32(r[1]), float32(r[2])}
+	}
+
+	if n := cross(ab, ac); dot(n, n) == 0 {
+		// degenerate triangle: nearest point of its sides
+		best := segmentPoint(a, b, q)
+		for _, r := range []vec{segmentPoint(b, c, q), segmentPoint(c, a, q)} {
+			if d, e := sub(r, q), sub(best, q); dot(d, d) < dot(e, e) {
+				best = r
+			}
+		}
+		return result(best)
+	}
+
+	d1, d2 := dot(ab, ap), dot(ac, ap)
+	if d1 <= 0 && d2 <= 0 {
+		return result(a) // vertex region a
+	}
+	bp := sub(q, b)
+	d3, d4 := dot(ab, bp), dot(ac, bp)
+	if d3 >= 0 && d4 <= d3 {
+		return result(b) // vertex region b
+	}
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		return result(along(a, ratio(d1, d1-d3), ab)) // edge ab
+	}
+	cp := sub(q, c)
+	d5, d6 := dot(ab, cp), dot(ac, cp)
+	if d6 >= 0 && d5 <= d6 {
+		return result(c) // vertex region c
+	}
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		return result(along(a, ratio(d2, d2-d6), ac)) // edge ac
+	}
+	va := d3*d6 - d5*d4
+	if va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		return result(along(b, ratio(d4-d3, (d4-d3)+(d5-d6)), sub(c, b))) // edge bc
+	}
+	denom := va + vb + vc
+	if denom == 0 {
+		return result(a) // degenerate triangle
+	}
+	v, w := vb/denom, vc/denom
+	return result(along(along(a, v, ab), w, ac)) // face
+}
@@ -0,0 +1,79 @@
+package gwob
+
+// SizeHint gives the expected element counts of an OBJ input,
+// used to pre-allocate parser buffers and avoid repeated slice growth.
+// Zero fields are estimated from the input size, when known.
+type SizeHint struct {
+	Vertices  int // v statements
+	TexCoords int // vt statements
+	Normals   int // vn statements
+	Triangles int // triangles, after splitting quads
+}
+
+// hintBytesPerVertex is the rough input size per vertex position,
+// accounting for its v/vt/vn lines and about two triangles per vertex.
+const hintBytesPerVertex = 128
+
+// EstimateSizeHint guesses element counts from the input size in bytes.
+// Negative size means unknown and yields a zero hint.
+func EstimateSizeHint(size int64) SizeHint {
+	if size <= 0 {
+		return SizeHint{}
+	}
+	v := int(size / hintBytesPerVertex)
+	return SizeHint{
+		Vertices:  v,
+		TexCoords: v,
+		Normals:   v,
+		Triangles: 2 * v,
+	}
+}
+
+// WithSizeHint sets the expected element counts.
+func WithSizeHint(h SizeHint) Option {
+	return func(o *ObjParserOptions) {
+		o.SizeHint = h
+	}
+}
+
+// sizeHint fills zero fields of options.SizeHint with estimates,
+// capped by the configured limits.
+func (options *ObjParserOptions) sizeHint(size int64) SizeHint {
+	h := options.SizeHint
+	e := EstimateSizeHint(size)
+	if h.Vertices == 0 {
+		h.Vertices = e.Vertices
+	}
+	if h.TexCoords == 0 {
+		h.TexCoords = e.TexCoords
+	}
+	if h.Normals == 0 {
+		h.Normals = e.Normals
+	}
+	if h.Triangles == 0 {
+		h.Triangles = e.Triangles
+	}
+
+	if m := options.Limits.MaxVertices; m > 0 {
+		h.Vertices = min(h.Vertices, m)
+		h.TexCoords = min(h.TexCoords, m)
+		h.Normals = min(h.Normals, m)
+	}
+	if m := options.Limits.MaxFaces; m > 0 {
+		h.Triangles = min(h.Triangles, 2*m)
+	}
+
+	return h
+}
+
+// presize reserves buffers for the expected element counts.
+// Texture and normal buffers are reserved lazily on first use,
+// since many inputs lack them.
+func (p *objParser) presize(o *Obj, h SizeHint) {
+	p.hint = h
+	p.vertCoord = p.reserveFloat32s(p.vertCoord, 3*h.Vertices)
+	if p.indexTable == nil {
+		p.indexTable = make(map[vertexKey]int, h.Vertices)
+	}
+	o.Indices = p.ints(3 * h.Triangles)
+}
@@ -0,0 +1,50 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestEstimateSizeHint(t *testing.T) {
+	if h := EstimateSizeHint(-1); h != (SizeHint{}) {
+		t.Errorf("TestEstimateSizeHint: unknown size: want zero hint got %+v", h)
+	}
+
+	h := EstimateSizeHint(1280)
+	expectInt(t, "TestEstimateSizeHint: vertices", 10, h.Vertices)
+	expectInt(t, "TestEstimateSizeHint: triangles", 20, h.Triangles)
+
+	options := NewObjParserOptions(WithSizeHint(SizeHint{Vertices: 7}), WithLimits(Limits{MaxVertices: 5, MaxFaces: 3}))
+	h = options.sizeHint(1280)
+	expectInt(t, "TestEstimateSizeHint: capped vertices", 5, h.Vertices)
+	expectInt(t, "TestEstimateSizeHint: capped normals", 5, h.Normals)
+	expectInt(t, "TestEstimateSizeHint: capped triangles", 6, h.Triangles)
+}
+
+func TestSizeHint(t *testing.T) {
+	hints := []SizeHint{
+		{},
+		{Vertices: 8, TexCoords: 4, Normals: 6, Triangles: 12},
+		{Vertices: 1, Triangles: 1},
+	}
+
+	for _, h := range hints {
+		o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions(WithSizeHint(h)))
+		if err != nil {
+			t.Errorf("TestSizeHint: %+v: NewObjFromBuf: %v", h, err)
+			continue
+		}
+		if !sliceEqualInt(cubeIndices, o.Indices) {
+			t.Errorf("TestSizeHint: %+v: indices: want=%v got=%v", h, cubeIndices, o.Indices)
+		}
+		if !sliceEqualFloat(cubeCoord, o.Coord) {
+			t.Errorf("TestSizeHint: %+v: coord: want=%v got=%v", h, cubeCoord, o.Coord)
+		}
+	}
+
+	h := SizeHint{Vertices: 8, Triangles: 12}
+	o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions(WithSizeHint(h)))
+	if err != nil {
+		t.Fatalf("TestSizeHint: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestSizeHint: indices capacity", 3*h.Triangles, cap(o.Indices))
+}
@@ -0,0 +1,161 @@
+package gwob
+
+import "math"
+
+// CleanReport tells what Clean removed.
+type CleanReport struct {
+	DegenerateTriangles int // triangles with repeated vertices or zero area
+	UnusedVertices      int // strides not referenced by any element
+}
+
+// Clean drops degenerate triangles, either repeating a vertex or having
+// zero area, and then removes the strides no longer referenced by
+// triangles, points, lines or faces, compacting the vertex data.
+// Group ranges and per-triangle data are fixed up accordingly.
+func (o *Obj) Clean() CleanReport {
+	var report CleanReport
+
+	triangles := len(o.Indices) / 3
+	keep := make([]bool, triangles)
+	for t := range keep {
+		keep[t] = !o.degenerate(t)
+		if !keep[t] {
+			report.DegenerateTriangles++
+		}
+	}
+	if report.DegenerateTriangles > 0 {
+		selectTriangles(o, keep)
+	}
+
+	strides := o.NumberOfElements()
+	remap, src := o.usedStrides()
+	report.UnusedVertices = strides - len(src)
+	if report.UnusedVertices > 0 {
+		o.remapIndices(remap)
+		selectStrides(o, src)
+	}
+
+	return report
+}
+
+// usedStrides finds the strides referenced by triangles, points, lines
+// or faces, returning the compacting remap from old to new strides and
+// the old stride of each new one.
+func (o *Obj) usedStrides() (remap, src []int) {
+	strides := o.NumberOfElements()
+	used := make([]bool, strides)
+	for _, v := range o.Indices {
+		used[v] = true
+	}
+	for _, g := range o.Groups {
+		for _, v := range g.Points {
+			used[v] = true
+		}
+		for _, line := range g.Lines {
+			for _, v := range line {
+				used[v] = true
+			}
+		}
+	}
+	for _, f := range o.Faces {
+		for _, v := range f.Vertices {
+			used[v] = true
+		}
+	}
+
+	remap = make([]int, strides)
+	for s, u := range used {
+		if u {
+			remap[s] = len(src)
+			src = append(src, s)
+		}
+	}
+	return remap, src
+}
+
+// degenerate reports whether triangle t repeats a vertex or has zero area.
+func (o *Obj) degenerate(t int) bool {
+	a, b, c := o.Indices[3*t], o.Indices[3*t+1], o.Indices[3*t+2]
+	if a == b || b == c || c == a {
+		return true
+	}
+	ax, ay, az := o.VertexCoordinates(a)
+	bx, by, bz := o.VertexCoordinates(b)
+	cx, cy, cz := o.VertexCoordinates(c)
+	ux, uy, uz := float64(bx-ax), float64(by-ay), float64(bz-az)
+	vx, vy, vz := float64(cx-ax), float64(cy-ay), float64(cz-az)
+	nx := uy*vz - uz*vy
+	ny := uz*vx - ux*vz
+	nz := ux*vy - uy*vx
+	return math.Sqrt(nx*nx+ny*ny+nz*nz) == 0
+}
+
+// selectTriangles removes the triangles not marked in keep, fixing up
+// group and face ranges, comments and the per-triangle and per-index
+// arrays. Faces left without triangles are dropped.
+func selectTriangles(o *Obj, keep []bool) {
+	kept := make([]int, len(keep)+1) // kept triangles before t
+	for t, k := range keep {
+		kept[t+1] = kept[t]
+		if k {
+			kept[t+1]++
+		}
+	}
+	newIndex := func(i int) int {
+		return 3 * kept[min(i/3, len(keep))]
+	}
+
+	n := 0
+	for t, k := range keep {
+		if !k {
+			continue
+		}
+		copy(o.Indices[3*n:3*n+3], o.Indices[3*t:3*t+3])
+		if len(o.Corners) == 3*len(keep) {
+			copy(o.Corners[3*n:3*n+3], o.Corners[3*t:3*t+3])
+		}
+		if len(o.Smoothing) == len(keep) {
+			o.Smoothing[n] = o.Smoothing[t]
+		}
+		if len(o.MaterialIndex) == len(keep) {
+			o.MaterialIndex[n] = o.MaterialIndex[t]
+		}
+		n++
+	}
+	o.Indices = o.Indices[:3*n]
+	if len(o.Corners) == 3*len(keep) {
+		o.Corners = o.Corners[:3*n]
+	}
+	if len(o.Smoothing) == len(keep) {
+		o.Smoothing = o.Smoothing[:n]
+	}
+	if len(o.MaterialIndex) == len(keep) {
+		o.MaterialIndex = o.MaterialIndex[:n]
+	}
+
+	for _, g := range o.Groups {
+		end := newIndex(g.IndexBegin + g.IndexCount)
+		g.IndexBegin = newIndex(g.IndexBegin)
+		g.IndexCount = end - g.IndexBegin
+	}
+
+	faces := o.Faces[:0]
+	for _, f := range o.Faces {
+		if f.IndexCount > 0 {
+			end := newIndex(f.IndexBegin + f.IndexCount)
+			f.IndexBegin = newIndex(f.IndexBegin)
+			f.IndexCount = end - f.IndexBegin
+			if f.IndexCount == 0 {
+				continue
+			}
+		}
+		faces = append(faces, f)
+	}
+	if o.Faces != nil {
+		o.Faces = faces
+	}
+
+	for i, c := range o.Comments {
+		o.Comments[i].Index = newIndex(c.Index)
+	}
+}
@@ -0,0 +1,51 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestClean(t *testing.T) {
+	str := `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 2 2 0
+v 5 5 5
+v 0 1 0
+g a
+f 1 2 3
+f 1 3 4
+g b
+f 1 3 6
+f 2 2 3
+`
+	options := NewObjParserOptions(WithMaterialPerTriangle(true))
+	o, err := NewObjFromBuf("clean", []byte(str), options)
+	if err != nil {
+		t.Fatalf("TestClean: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestClean: elements", 5, o.NumberOfElements())
+
+	report := o.Clean()
+
+	expectInt(t, "TestClean: degenerate", 2, report.DegenerateTriangles)
+	expectInt(t, "TestClean: unused", 1, report.UnusedVertices)
+	expectInt(t, "TestClean: elements after", 4, o.NumberOfElements())
+	if want := []int{0, 1, 2, 0, 2, 3}; !sliceEqualInt(want, o.Indices) {
+		t.Errorf("TestClean: indices: want=%v got=%v", want, o.Indices)
+	}
+	expectInt(t, "TestClean: material index", 2, len(o.MaterialIndex))
+	expectInt(t, "TestClean: group a begin", 0, o.Groups[0].IndexBegin)
+	expectInt(t, "TestClean: group a count", 3, o.Groups[0].IndexCount)
+	expectInt(t, "TestClean: group b begin", 3, o.Groups[1].IndexBegin)
+	expectInt(t, "TestClean: group b count", 3, o.Groups[1].IndexCount)
+	x, y, z := o.VertexCoordinates(3)
+	if x != 0 || y != 1 || z != 0 {
+		t.Errorf("TestClean: last vertex: %v %v %v", x, y, z)
+	}
+
+	report = o.Clean()
+	if report != (CleanReport{}) {
+		t.Errorf("TestClean: second pass: %+v", report)
+	}
+}
@@ -0,0 +1,115 @@
+package gwob
+
+import "slices"
+
+// Clone returns a deep copy of o: no slice, group, face or free-form
+// element is shared, so either copy may be changed without affecting
+// the other. Only the immutable errors of Diagnostics are shared.
+func (o *Obj) Clone() *Obj {
+	c := *o
+
+	c.Indices = slices.Clone(o.Indices)
+	c.Coord = slices.Clone(o.Coord)
+	c.Mtllibs = slices.Clone(o.Mtllibs)
+	c.Maplibs = slices.Clone(o.Maplibs)
+	c.Positions = slices.Clone(o.Positions)
+	c.TexCoords = slices.Clone(o.TexCoords)
+	c.Normals = slices.Clone(o.Normals)
+	c.W = slices.Clone(o.W)
+	c.TexW = slices.Clone(o.TexW)
+	c.Colors = slices.Clone(o.Colors)
+	c.RawPositions = slices.Clone(o.RawPositions)
+	c.RawTexCoords = slices.Clone(o.RawTexCoords)
+	c.RawNormals = slices.Clone(o.RawNormals)
+	c.Corners = slices.Clone(o.Corners)
+	c.Smoothing = slices.Clone(o.Smoothing)
+	c.MaterialIndex = slices.Clone(o.MaterialIndex)
+	c.MaterialNames = slices.Clone(o.MaterialNames)
+	c.Comments = slices.Clone(o.Comments)
+	c.Diagnostics = slices.Clone(o.Diagnostics)
+
+	groups := map[*Group]*Group{}
+	group := func(g *Group) *Group {
+		if g == nil {
+			return nil
+		}
+		if cg, found := groups[g]; found {
+			return cg
+		}
+		cg := *g
+		cg.Names = slices.Clone(g.Names)
+		cg.Points = slices.Clone(g.Points)
+		if g.Lines != nil {
+			cg.Lines = make([][]int, len(g.Lines))
+			for i, line := range g.Lines {
+				cg.Lines[i] = slices.Clone(line)
+			}
+		}
+		groups[g] = &cg
+		return &cg
+	}
+
+	if o.Groups != nil {
+		c.Groups = make([]*Group, len(o.Groups))
+		for i, g := range o.Groups {
+			c.Groups[i] = group(g)
+		}
+	}
+
+	if o.Faces != nil {
+		c.Faces = make([]Face, len(o.Faces))
+		for i, f := range o.Faces {
+			f.Group = group(f.Group)
+			f.Corners = slices.Clone(f.Corners)
+			f.Vertices = slices.Clone(f.Vertices)
+			c.Faces[i] = f
+		}
+	}
+
+	if ff := o.FreeForm; ff != nil {
+		cf := &FreeForm{Params: slices.Clone(ff.Params)}
+		for _, cv := range ff.Curves {
+			n := *cv
+			n.Vertices = slices.Clone(cv.Vertices)
+			n.Knots = slices.Clone(cv.Knots)
+			n.Group = group(cv.Group)
+			cf.Curves = append(cf.Curves, &n)
+		}
+		for _, cv := range ff.Curves2D {
+			n := *cv
+			n.Params = slices.Clone(cv.Params)
+			n.Knots = slices.Clone(cv.Knots)
+			n.Group = group(cv.Group)
+			cf.Curves2D = append(cf.Curves2D, &n)
+		}
+		for _, s := range ff.Surfaces {
+			n := *s
+			n.Corners = slices.Clone(s.Corners)
+			n.KnotsU = slices.Clone(s.KnotsU)
+			n.KnotsV = slices.Clone(s.KnotsV)
+			n.Group = group(s.Group)
+			cf.Surfaces = append(cf.Surfaces, &n)
+		}
+		c.FreeForm = cf
+	}
+
+	return &c
+}
+
+// Clone returns a deep copy of the library: materials are copied, so
+// either library may be changed without affecting the other.
+func (lib MaterialLib) Clone() MaterialLib {
+	c := MaterialLib{Diagnostics: slices.Clone(lib.Diagnostics)}
+	if lib.Lib != nil {
+		c.Lib = make(map[string]*Material, len(lib.Lib))
+		for name, m := range lib.Lib {
+			if m == nil {
+				c.Lib[name] = nil
+				continue
+			}
+			mc := *m
+			c.Lib[name] = &mc
+		}
+	}
+	return c
+}
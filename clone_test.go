@@ -0,0 +1,107 @@
+package gwob
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	table := []struct {
+		name    string
+		str     string
+		options *ObjParserOptions
+	}{
+		{"cube", cubeObj, NewObjParserOptions(WithKeepFaces(true), WithKeepRawData(true), WithKeepComments(true))},
+		{"materials", materialsObj, NewObjParserOptions(WithMaterialPerTriangle(true), WithSmoothPerTriangle(true))},
+		{"colors", colorsObj, NewObjParserOptions(WithNonInterleaved(true))},
+		{"lines", linesObj, NewObjParserOptions()},
+		{"freeform", freeFormObj, NewObjParserOptions()},
+	}
+
+	for _, data := range table {
+		o, err := NewObjFromBuf(data.name, []byte(data.str), data.options)
+		if err != nil {
+			t.Fatalf("TestClone: %s: NewObjFromBuf: %v", data.name, err)
+		}
+		c := o.Clone()
+		if !reflect.DeepEqual(o, c) {
+			t.Errorf("TestClone: %s: clone differs", data.name)
+		}
+
+		for i, g := range c.Groups {
+			if g == o.Groups[i] {
+				t.Errorf("TestClone: %s: group %d shared", data.name, i)
+			}
+		}
+		for i, f := range c.Faces {
+			if f.Group != nil && f.Group != c.Groups[indexOfGroup(o.Groups, o.Faces[i].Group)] {
+				t.Errorf("TestClone: %s: face %d group not remapped", data.name, i)
+			}
+		}
+		if ff := c.FreeForm; ff != nil {
+			for _, cv := range ff.Curves {
+				for _, g := range o.Groups {
+					if cv.Group == g {
+						t.Errorf("TestClone: %s: curve group shared", data.name)
+					}
+				}
+			}
+		}
+
+		// changing the clone leaves the original alone
+		c.Translate(1, 1, 1)
+		if len(c.Indices) > 0 {
+			c.Indices[0]++
+		}
+		if len(c.Groups) > 0 {
+			c.Groups[0].Name += "x"
+		}
+		if c.NumberOfElements() > 0 {
+			x, _, _ := o.VertexCoordinates(0)
+			cx, _, _ := c.VertexCoordinates(0)
+			if x == cx {
+				t.Errorf("TestClone: %s: vertex data shared", data.name)
+			}
+		}
+		if len(c.Indices) > 0 && o.Indices[0] == c.Indices[0] {
+			t.Errorf("TestClone: %s: indices shared", data.name)
+		}
+		if len(c.Groups) > 0 && o.Groups[0].Name == c.Groups[0].Name {
+			t.Errorf("TestClone: %s: group name shared", data.name)
+		}
+	}
+}
+
+func indexOfGroup(groups []*Group, g *Group) int {
+	for i, gr := range groups {
+		if gr == g {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestMaterialLibClone(t *testing.T) {
+	lib, err := ReadMaterialLibFromBuf([]byte(sceneMtl), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestMaterialLibClone: ReadMaterialLibFromBuf: %v", err)
+	}
+	c := lib.Clone()
+	if !reflect.DeepEqual(lib, c) {
+		t.Errorf("TestMaterialLibClone: clone differs")
+	}
+	for name, m := range c.Lib {
+		if m == lib.Lib[name] {
+			t.Errorf("TestMaterialLibClone: material %s shared", name)
+		}
+		m.Kd[0] = 42
+		if lib.Lib[name].Kd[0] == 42 {
+			t.Errorf("TestMaterialLibClone: material %s changed", name)
+		}
+	}
+	delete(c.Lib, "nothing")
+	c.Lib["new"] = &Material{}
+	if _, found := lib.Lib["new"]; found {
+		t.Errorf("TestMaterialLibClone: map shared")
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Package main converts an OBJ file into Go source declaring the mesh as a
+*gwob.Obj variable, so that small meshes can be compiled into binaries.
+
+Usage:
+
+	gwobgen [-package name] [-name var] [-output file.go] input.obj
+
+Example, for go generate:
+
+	//go:generate go run github.com/udhos/gwob/cmd/gwobgen -package shapes -name Gizmo -output gizmo.go gizmo.obj
+
+See also: https://github.com/udhos/gwob
+*/
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/udhos/gwob"
+)
+
+func main() {
+
+	pkg := flag.String("package", "main", "package name for the generated file")
+	name := flag.String("name", "Mesh", "variable name for the mesh")
+	output := flag.String("output", "", "output file, stdout if empty")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] input.obj\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	input := flag.Arg(0)
+
+	options := &gwob.ObjParserOptions{
+		Logger: func(msg string) { fmt.Fprintln(os.Stderr, msg) },
+	}
+
+	o, errObj := gwob.NewObjFromFile(input, options)
+	if errObj != nil {
+		log.Fatalf("gwobgen: parse error input=%s: %v", input, errObj)
+	}
+
+	var buf bytes.Buffer
+	errGen := o.ToGoSource(&buf, &gwob.GoSourceOptions{
+		Package: *pkg,
+		Name:    *name,
+		Source:  filepath.Base(input),
+	})
+	if errGen != nil {
+		log.Fatalf("gwobgen: input=%s: %v", input, errGen)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(buf.Bytes())
+		return
+	}
+
+	if errWrite := os.WriteFile(*output, buf.Bytes(), 0o644); errWrite != nil {
+		log.Fatalf("gwobgen: %v", errWrite)
+	}
+}
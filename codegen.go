@@ -0,0 +1,138 @@
+package gwob
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+)
+
+// GoSourceOptions controls the Go source generated by ToGoSource.
+type GoSourceOptions struct {
+	Package string // package clause, "main" if empty
+	Name    string // variable name, "Mesh" if empty
+	Source  string // input name mentioned in the comments, optional
+}
+
+// ToGoSource writes a Go source file declaring the Obj as a *gwob.Obj
+// variable, with Coord, Indices and Groups literals, so that small
+// meshes can be compiled into binaries and used without parsing.
+// Only the vertex data, the groups, the material libs and the layout
+// are emitted. Options may be nil.
+func (o *Obj) ToGoSource(w io.Writer, options *GoSourceOptions) error {
+	if o.Coord == nil && o.Positions != nil {
+		c := *o
+		c.Interleave()
+		return c.ToGoSource(w, options)
+	}
+	if options == nil {
+		options = &GoSourceOptions{}
+	}
+	pkg := options.Package
+	if pkg == "" {
+		pkg = "main"
+	}
+	name := options.Name
+	if name == "" {
+		name = "Mesh"
+	}
+
+	var buf bytes.Buffer
+
+	from := ""
+	if options.Source != "" {
+		from = " from " + options.Source
+	}
+	fmt.Fprintf(&buf, "// Code generated by gwob%s; DO NOT EDIT.\n\n", from)
+	fmt.Fprintf(&buf, "package %s\n\nimport \"github.com/udhos/gwob\"\n\n", pkg)
+	fmt.Fprintf(&buf, "// %s is an embedded mesh%s.\n", name, from)
+	fmt.Fprintf(&buf, "var %s = &gwob.Obj{\n", name)
+
+	if o.Mtllib != "" {
+		fmt.Fprintf(&buf, "Mtllib: %q,\n", o.Mtllib)
+	}
+	if len(o.Mtllibs) > 0 {
+		fmt.Fprintf(&buf, "Mtllibs: %#v,\n", o.Mtllibs)
+	}
+
+	floatsPerStride := o.StrideSize / 4
+	buf.WriteString("Coord: []float32{\n")
+	for i := 0; i < len(o.Coord); i += floatsPerStride {
+		writeGoFloats(&buf, o.Coord[i:i+floatsPerStride])
+	}
+	buf.WriteString("},\n")
+
+	if len(o.Colors) > 0 {
+		buf.WriteString("Colors: []float32{\n")
+		for i := 0; i < len(o.Colors); i += 3 {
+			writeGoFloats(&buf, o.Colors[i:min(i+3, len(o.Colors))])
+		}
+		buf.WriteString("},\n")
+	}
+
+	buf.WriteString("Indices: []int{\n")
+	for i := 0; i < len(o.Indices); i += 3 {
+		for _, index := range o.Indices[i:min(i+3, len(o.Indices))] {
+			fmt.Fprintf(&buf, "%d, ", index)
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("},\n")
+
+	buf.WriteString("Groups: []*gwob.Group{\n")
+	for _, g := range o.Groups {
+		fmt.Fprintf(&buf, "{Name: %q, ", g.Name)
+		if g.Usemtl != "" {
+			fmt.Fprintf(&buf, "Usemtl: %q, ", g.Usemtl)
+		}
+		if g.Usemap != "" {
+			fmt.Fprintf(&buf, "Usemap: %q, ", g.Usemap)
+		}
+		if g.Smooth != 0 {
+			fmt.Fprintf(&buf, "Smooth: %d, ", g.Smooth)
+		}
+		if g.Lod != 0 {
+			fmt.Fprintf(&buf, "Lod: %d, ", g.Lod)
+		}
+		fmt.Fprintf(&buf, "IndexBegin: %d, IndexCount: %d},\n", g.IndexBegin, g.IndexCount)
+	}
+	buf.WriteString("},\n")
+
+	for _, flag := range []struct {
+		name  string
+		value bool
+	}{
+		{"BigIndexFound", o.BigIndexFound},
+		{"TextCoordFound", o.TextCoordFound},
+		{"NormCoordFound", o.NormCoordFound},
+		{"ColorFound", o.ColorFound},
+	} {
+		if flag.value {
+			fmt.Fprintf(&buf, "%s: true,\n", flag.name)
+		}
+	}
+	fmt.Fprintf(&buf, "StrideSize: %d,\n", o.StrideSize)
+	fmt.Fprintf(&buf, "StrideOffsetPosition: %d,\n", o.StrideOffsetPosition)
+	fmt.Fprintf(&buf, "StrideOffsetTexture: %d,\n", o.StrideOffsetTexture)
+	fmt.Fprintf(&buf, "StrideOffsetNormal: %d,\n", o.StrideOffsetNormal)
+	if o.StrideOffsetColor != 0 {
+		fmt.Fprintf(&buf, "StrideOffsetColor: %d,\n", o.StrideOffsetColor)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("ToGoSource: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+func writeGoFloats(buf *bytes.Buffer, values []float32) {
+	for _, f := range values {
+		buf.Write(strconv.AppendFloat(nil, float64(f), 'g', -1, 32))
+		buf.WriteString(", ")
+	}
+	buf.WriteString("\n")
+}
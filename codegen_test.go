@@ -0,0 +1,44 @@
+package gwob
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestToGoSource(t *testing.T) {
+	o, err := NewObjFromBuf("sceneObj", []byte(sceneObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestToGoSource: NewObjFromBuf: %v", err)
+	}
+
+	var buf bytes.Buffer
+	options := &GoSourceOptions{Package: "shapes", Name: "Triangle", Source: "scene.obj"}
+	if errGen := o.ToGoSource(&buf, options); errGen != nil {
+		t.Fatalf("TestToGoSource: ToGoSource: %v", errGen)
+	}
+	src := buf.String()
+
+	f, errParse := parser.ParseFile(token.NewFileSet(), "triangle.go", src, 0)
+	if errParse != nil {
+		t.Fatalf("TestToGoSource: generated source: %v\n%s", errParse, src)
+	}
+	if f.Name.Name != "shapes" {
+		t.Errorf("TestToGoSource: package: want=shapes got=%s", f.Name.Name)
+	}
+
+	for _, want := range []string{
+		"// Code generated by gwob from scene.obj; DO NOT EDIT.\n",
+		"var Triangle = &gwob.Obj{",
+		"\t\t0, 1, 2,\n",
+		`{Name: "b", Usemtl: "unknown", IndexBegin: 3, IndexCount: 3}`,
+		`Mtllibs: []string{"scene.mtl", "missing.mtl"}`,
+		"StrideSize:           12,",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("TestToGoSource: missing %q in:\n%s", want, src)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package gwob
+
+// pushColor stores the color of a v statement.
+// Vertices read before the first color get white.
+func (p *objParser) pushColor(r, g, b float64) {
+	for len(p.colorCoord) < 3*p.vertLines {
+		p.colorCoord = append(p.colorCoord, 1, 1, 1)
+	}
+	p.colorCoord = append(p.colorCoord, float32(r), float32(g), float32(b))
+}
+
+// appendColor appends the color for a new unified vertex i.
+func (p *objParser) appendColor(o *Obj, i int, v int) {
+	for len(o.Colors) < 3*i {
+		o.Colors = append(o.Colors, 1, 1, 1)
+	}
+	o.Colors = append(o.Colors, p.colorCoord[3*v:3*v+3]...)
+	o.ColorFound = true
+}
+
+// interleaveColors moves Colors into the Coord stride, after the other attributes.
+func (o *Obj) interleaveColors() {
+	if !o.ColorFound || o.Coord == nil || o.StrideOffsetColor != 0 {
+		return
+	}
+
+	elements := o.NumberOfElements()
+	floatsPerStride := o.StrideSize / 4
+	coord := make([]float32, 0, elements*(floatsPerStride+3))
+
+	for i := 0; i < elements; i++ {
+		coord = append(coord, o.Coord[i*floatsPerStride:(i+1)*floatsPerStride]...)
+		if 3*i+2 < len(o.Colors) {
+			coord = append(coord, o.Colors[3*i:3*i+3]...)
+		} else {
+			coord = append(coord, 1, 1, 1)
+		}
+	}
+
+	o.Coord = coord
+	o.Colors = nil
+	o.StrideOffsetColor = o.StrideSize
+	o.StrideSize += 3 * 4 // add (r,g,b) = 3 x 4-byte floats
+}
+
+// VertexColor gets the color for a stride index,
+// either from Colors or interleaved in Coord.
+// Vertices without color are white.
+func (o *Obj) VertexColor(stride int) (float32, float32, float32) {
+	if o.StrideOffsetColor != 0 {
+		f := o.StrideOffsetColor/4 + stride*o.StrideSize/4
+		return o.Coord[f], o.Coord[f+1], o.Coord[f+2]
+	}
+	if 3*stride+2 < len(o.Colors) {
+		return o.Colors[3*stride], o.Colors[3*stride+1], o.Colors[3*stride+2]
+	}
+	return 1, 1, 1
+}
+
+// vertexColor gets the color for a stride index, as a slice into the
+// vertex data, or nil if the stride has no color.
+func (o *Obj) vertexColor(stride int) []float32 {
+	if o.StrideOffsetColor != 0 {
+		f := o.StrideOffsetColor/4 + stride*o.StrideSize/4
+		return o.Coord[f : f+3]
+	}
+	if 3*stride+2 < len(o.Colors) {
+		return o.Colors[3*stride : 3*stride+3]
+	}
+	return nil
+}
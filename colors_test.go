@@ -0,0 +1,59 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+var colorsObj = `
+v 0 0 0
+v 1 0 0 1 0 0
+v 1 1 0 0 1 0
+v 0 1 0 0 0 1
+f 1 2 3
+f 3 4 1
+`
+
+func TestVertexColors(t *testing.T) {
+
+	o, err := NewObjFromBuf("colorsObj", []byte(colorsObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestVertexColors: NewObjFromBuf: %v", err)
+	}
+	if !o.ColorFound {
+		t.Errorf("TestVertexColors: colors not found")
+	}
+	want := []float32{1, 1, 1, 1, 0, 0, 0, 1, 0, 0, 0, 1}
+	if !sliceEqualFloat(want, o.Colors) {
+		t.Errorf("TestVertexColors: colors: want=%v got=%v", want, o.Colors)
+	}
+	expectInt(t, "TestVertexColors: stride", 12, o.StrideSize)
+
+	// round trip
+	var buf bytes.Buffer
+	if errWrite := o.ToWriter(&buf); errWrite != nil {
+		t.Fatalf("TestVertexColors: ToWriter: %v", errWrite)
+	}
+
+	o, err = NewObjFromBuf("colorsObj2", buf.Bytes(), NewObjParserOptions(WithInterleaveColors(true)))
+	if err != nil {
+		t.Fatalf("TestVertexColors: reparse: %v", err)
+	}
+	if o.Colors != nil {
+		t.Errorf("TestVertexColors: interleaved: unexpected Colors")
+	}
+	expectInt(t, "TestVertexColors: interleaved stride", 24, o.StrideSize)
+	expectInt(t, "TestVertexColors: interleaved color offset", 12, o.StrideOffsetColor)
+	expectInt(t, "TestVertexColors: interleaved elements", 4, o.NumberOfElements())
+	for i := 0; i < 4; i++ {
+		r, g, b := o.VertexColor(i)
+		if got := []float32{r, g, b}; !sliceEqualFloat(want[3*i:3*i+3], got) {
+			t.Errorf("TestVertexColors: interleaved color %d: want=%v got=%v", i, want[3*i:3*i+3], got)
+		}
+	}
+
+	o.Deinterleave()
+	if !sliceEqualFloat(want, o.Colors) {
+		t.Errorf("TestVertexColors: deinterleaved colors: want=%v got=%v", want, o.Colors)
+	}
+}
@@ -0,0 +1,27 @@
+package gwob
+
+import (
+	"strings"
+)
+
+// Comment is a "#" comment line retained by the KeepComments option.
+type Comment struct {
+	Text  string // text following "#"
+	Line  int    // input line number
+	Index int    // position in Obj.Indices of the next face
+}
+
+// exportHeader is the comment written by ToWriter, which is not
+// retained so that repeated round trips do not accumulate it.
+const exportHeader = " OBJ exported by gwob - https://github.com/udhos/gwob"
+
+func keepComment(p *objParser, o *Obj, line string) {
+	if line[1:] == exportHeader {
+		return
+	}
+	o.Comments = append(o.Comments, Comment{
+		Text:  strings.Clone(line[1:]), // do not retain the input line
+		Line:  p.lineCount,
+		Index: len(o.Indices),
+	})
+}
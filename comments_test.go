@@ -0,0 +1,69 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+var commentsObj = `# Copyright (c) example
+# License: CC-BY
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+f 1 2 3
+#second face
+f 1 3 4
+# end
+`
+
+func TestKeepComments(t *testing.T) {
+
+	o, err := NewObjFromBuf("commentsObj", []byte(commentsObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestKeepComments: NewObjFromBuf: %v", err)
+	}
+	if o.Comments != nil {
+		t.Errorf("TestKeepComments: unexpected comments: %v", o.Comments)
+	}
+
+	options := NewObjParserOptions(WithKeepComments(true))
+	o, err = NewObjFromBuf("commentsObj", []byte(commentsObj), options)
+	if err != nil {
+		t.Fatalf("TestKeepComments: NewObjFromBuf: %v", err)
+	}
+	want := []Comment{
+		{Text: " Copyright (c) example", Line: 1, Index: 0},
+		{Text: " License: CC-BY", Line: 2, Index: 0},
+		{Text: "second face", Line: 8, Index: 3},
+		{Text: " end", Line: 10, Index: 6},
+	}
+	if len(o.Comments) != len(want) {
+		t.Fatalf("TestKeepComments: want=%v got=%v", want, o.Comments)
+	}
+	for i, c := range want {
+		if o.Comments[i] != c {
+			t.Errorf("TestKeepComments: comment %d: want=%+v got=%+v", i, c, o.Comments[i])
+		}
+	}
+
+	// round trip, twice
+	for r := 0; r < 2; r++ {
+		var buf bytes.Buffer
+		if errWrite := o.ToWriter(&buf); errWrite != nil {
+			t.Fatalf("TestKeepComments: ToWriter: %v", errWrite)
+		}
+		o, err = NewObjFromBuf("commentsObj2", buf.Bytes(), options)
+		if err != nil {
+			t.Fatalf("TestKeepComments: reparse: %v", err)
+		}
+		if len(o.Comments) != len(want) {
+			t.Fatalf("TestKeepComments: round trip %d: want=%v got=%v", r, want, o.Comments)
+		}
+		for i, c := range want {
+			if o.Comments[i].Text != c.Text || o.Comments[i].Index != c.Index {
+				t.Errorf("TestKeepComments: round trip %d: comment %d: want=%+v got=%+v", r, i, c, o.Comments[i])
+			}
+		}
+	}
+}
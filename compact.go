@@ -0,0 +1,74 @@
+package gwob
+
+// compact writes the distinct positions, texture coordinates and normals,
+// recording the index of each unified vertex into them.
+func (ow *objWriter) compact() {
+	o := ow.o
+	stride := o.StrideSize / 4
+
+	var posFirst, texFirst, normFirst []int
+
+	ow.posMap, posFirst = dedup(ow.strides, func(s int) [7]float32 {
+		var k [7]float32
+		v := s*stride + o.StrideOffsetPosition/4
+		copy(k[:3], o.Coord[v:v+3])
+		if s < len(o.W) {
+			k[3] = o.W[s]
+		} else if o.ColorFound {
+			k[4], k[5], k[6] = o.VertexColor(s)
+		}
+		return k
+	})
+	ow.positions = len(posFirst)
+	for _, s := range posFirst {
+		ow.position(s)
+	}
+
+	if o.TextCoordFound {
+		ow.texMap, texFirst = dedup(ow.strides, func(s int) [3]float32 {
+			var k [3]float32
+			t := s*stride + o.StrideOffsetTexture/4
+			copy(k[:2], o.Coord[t:t+2])
+			if s < len(o.TexW) {
+				k[2] = o.TexW[s]
+			}
+			return k
+		})
+		ow.texs = len(texFirst)
+		for _, s := range texFirst {
+			ow.texCoord(s)
+		}
+	}
+
+	if o.NormCoordFound {
+		ow.normMap, normFirst = dedup(ow.strides, func(s int) [3]float32 {
+			var k [3]float32
+			n := s*stride + o.StrideOffsetNormal/4
+			copy(k[:], o.Coord[n:n+3])
+			return k
+		})
+		ow.normals = len(normFirst)
+		for _, s := range normFirst {
+			ow.normal(s)
+		}
+	}
+}
+
+// dedup maps each of n elements to the index of its distinct key,
+// also returning the first element holding each distinct key.
+func dedup[K comparable](n int, key func(i int) K) ([]int, []int) {
+	remap := make([]int, n)
+	var first []int
+	seen := map[K]int{}
+	for i := 0; i < n; i++ {
+		k := key(i)
+		j, found := seen[k]
+		if !found {
+			j = len(first)
+			seen[k] = j
+			first = append(first, i)
+		}
+		remap[i] = j
+	}
+	return remap, first
+}
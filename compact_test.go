@@ -0,0 +1,41 @@
+package gwob
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteCompact(t *testing.T) {
+	o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestWriteCompact: NewObjFromBuf: %v", err)
+	}
+
+	for _, relative := range []bool{false, true} {
+		var buf bytes.Buffer
+		if errWrite := o.ToWriterWithOptions(&buf, &WriteOptions{Compact: true, RelativeIndices: relative}); errWrite != nil {
+			t.Fatalf("TestWriteCompact: ToWriterWithOptions: %v", errWrite)
+		}
+		out := buf.String()
+		expectInt(t, "TestWriteCompact: v statements", 8, strings.Count(out, "\nv "))
+		expectInt(t, "TestWriteCompact: vt statements", 3, strings.Count(out, "\nvt "))
+		expectInt(t, "TestWriteCompact: vn statements", 6, strings.Count(out, "\nvn "))
+
+		o2, err := NewObjFromBuf("cubeObj2", buf.Bytes(), NewObjParserOptions())
+		if err != nil {
+			t.Fatalf("TestWriteCompact: reparse: %v", err)
+		}
+		if len(o2.Indices) != len(o.Indices) {
+			t.Fatalf("TestWriteCompact: indices: want=%d got=%d", len(o.Indices), len(o2.Indices))
+		}
+		size := o.StrideSize / 4
+		for i, v := range o.Indices {
+			v2 := o2.Indices[i]
+			if !sliceEqualFloat(o.Coord[v*size:(v+1)*size], o2.Coord[v2*size:(v2+1)*size]) {
+				t.Errorf("TestWriteCompact: relative=%v corner %d: want=%v got=%v", relative, i,
+					o.Coord[v*size:(v+1)*size], o2.Coord[v2*size:(v2+1)*size])
+			}
+		}
+	}
+}
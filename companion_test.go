@@ -0,0 +1,33 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestShadowTraceObj(t *testing.T) {
+
+	str := cubeObj + "\nshadow_obj cube_shadow.obj\ntrace_obj cube_trace.obj\n"
+
+	o, err := NewObjFromBuf("cubeObj", []byte(str), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestShadowTraceObj: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestShadowTraceObj: errors", 0, o.Errors())
+	if o.ShadowObj != "cube_shadow.obj" || o.TraceObj != "cube_trace.obj" {
+		t.Errorf("TestShadowTraceObj: shadow=%q trace=%q", o.ShadowObj, o.TraceObj)
+	}
+
+	// round trip
+	var buf bytes.Buffer
+	if errWrite := o.ToWriter(&buf); errWrite != nil {
+		t.Fatalf("TestShadowTraceObj: ToWriter: %v", errWrite)
+	}
+	o2, err := NewObjFromBuf("cubeObj2", buf.Bytes(), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestShadowTraceObj: reparse: %v", err)
+	}
+	if o2.ShadowObj != o.ShadowObj || o2.TraceObj != o.TraceObj {
+		t.Errorf("TestShadowTraceObj: reparsed shadow=%q trace=%q", o2.ShadowObj, o2.TraceObj)
+	}
+}
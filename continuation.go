@@ -0,0 +1,37 @@
+package gwob
+
+import (
+	"strings"
+)
+
+// readStatement reads a line, joining the following lines while it ends
+// with a backslash. It also returns the number of physical lines read.
+func readStatement(reader StringReader, max int) (string, int, error) {
+	line, err := readLine(reader, max)
+	lines := 1
+	for {
+		head, more := cutContinuation(line)
+		if !more {
+			return line, lines, err
+		}
+		if err != nil {
+			return head, lines, err // dangling backslash at end of input
+		}
+		next, errNext := readLine(reader, max)
+		lines++
+		line = head + " " + next
+		err = errNext
+		if max > 0 && len(line) > max {
+			return "", lines, &LimitError{Limit: "MaxLineLength", Max: int64(max), Value: int64(len(line))}
+		}
+	}
+}
+
+// cutContinuation removes the trailing backslash from a continued line.
+func cutContinuation(line string) (string, bool) {
+	t := strings.TrimRight(line, " \t\r\n")
+	if !strings.HasSuffix(t, `\`) {
+		return line, false
+	}
+	return t[:len(t)-1], true
+}
@@ -0,0 +1,37 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestLineContinuation(t *testing.T) {
+
+	str := "v 0 0 0\nv 1 0 0\nv 1 1 \\\n 0\nv 0 1 0\nf 1 2 \\\n  3 \\\n  4\nf 1 2 x\n"
+
+	o, err := NewObjFromBuf("continued", []byte(str), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestLineContinuation: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestLineContinuation: elements", 4, o.NumberOfElements())
+	expectInt(t, "TestLineContinuation: indices", 6, len(o.Indices))
+	if len(o.Diagnostics) != 1 {
+		t.Fatalf("TestLineContinuation: diagnostics: %v", o.Diagnostics)
+	}
+	expectInt(t, "TestLineContinuation: error line", 9, o.Diagnostics[0].Err.Line)
+
+	// dangling backslash at end of input
+	o, err = NewObjFromBuf("dangling", []byte("v 0 0 0\nv 1 0 0\nv 1 1 0\nf 1 2 3 \\"), NewObjParserOptions(WithStrict(true)))
+	if err != nil {
+		t.Fatalf("TestLineContinuation: dangling: %v", err)
+	}
+	expectInt(t, "TestLineContinuation: dangling indices", 3, len(o.Indices))
+
+	lib, errLib := ReadMaterialLibFromBuf([]byte("newmtl m\nKd 0.1 \\\n0.2 0.3\nNs 10\n"), NewObjParserOptions())
+	if errLib != nil {
+		t.Fatalf("TestLineContinuation: ReadMaterialLibFromBuf: %v", errLib)
+	}
+	m := lib.Lib["m"]
+	if m == nil || m.Kd[2] != 0.3 || m.Ns != 10 {
+		t.Errorf("TestLineContinuation: material: %+v", m)
+	}
+}
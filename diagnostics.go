@@ -0,0 +1,67 @@
+package gwob
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Severity classifies a diagnostic.
+type Severity int
+
+// Diagnostic severities.
+const (
+	SeverityWarning Severity = iota // input accepted with a caveat
+	SeverityError                   // statement skipped
+)
+
+// String implements fmt.Stringer.
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// Diagnostic is a non-fatal problem found while parsing.
+type Diagnostic struct {
+	Severity Severity
+	Err      *ParseError
+}
+
+// String implements fmt.Stringer.
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %v", d.Severity, d.Err)
+}
+
+// appendDiagnostic records err if it is a parse error.
+// Other errors, like IO failures, are not diagnostics.
+func appendDiagnostic(list []Diagnostic, severity Severity, err error) []Diagnostic {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		list = append(list, Diagnostic{Severity: severity, Err: pe})
+	}
+	return list
+}
+
+// Errors counts diagnostics with SeverityError.
+func (o *Obj) Errors() int {
+	return countDiagnostics(o.Diagnostics, SeverityError)
+}
+
+// Warnings counts diagnostics with SeverityWarning.
+func (o *Obj) Warnings() int {
+	return countDiagnostics(o.Diagnostics, SeverityWarning)
+}
+
+func countDiagnostics(list []Diagnostic, severity Severity) int {
+	var count int
+	for _, d := range list {
+		if d.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,62 @@
+package gwob
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDiagnostics(t *testing.T) {
+
+	options := ObjParserOptions{}
+
+	o, err := NewObjFromBuf("diagObj", []byte(diagObj), &options)
+	if err != nil {
+		t.Errorf("TestDiagnostics: NewObjFromBuf: %v", err)
+		return
+	}
+
+	expectInt(t, "TestDiagnostics: skipped faces", 2, o.SkippedFaces)
+	expectInt(t, "TestDiagnostics: errors", 4, o.Errors())
+	expectInt(t, "TestDiagnostics: warnings", 1, o.Warnings())
+	expectInt(t, "TestDiagnostics: indices", 3, len(o.Indices))
+	expectInt(t, "TestDiagnostics: group index count", 3, o.Groups[0].IndexCount)
+
+	want := []struct {
+		line int
+		kind error
+	}{
+		{4, ErrBadCoord},
+		{5, ErrBadCoord},
+		{6, ErrUnexpected},
+		{8, ErrBadFace},
+		{9, ErrBadFace},
+	}
+	if len(o.Diagnostics) != len(want) {
+		t.Errorf("TestDiagnostics: diagnostics: want=%d got=%d: %v", len(want), len(o.Diagnostics), o.Diagnostics)
+		return
+	}
+	for i, w := range want {
+		d := o.Diagnostics[i]
+		if d.Err.Line != w.line || !errors.Is(d.Err, w.kind) {
+			t.Errorf("TestDiagnostics: diagnostic %d: want line=%d kind=%v got %v", i, w.line, w.kind, d)
+		}
+	}
+
+	lib, errLib := ReadMaterialLibFromBuf([]byte("Kd 1 1 1\nnewmtl a\nKd 1 x 1\n"), &options)
+	if errLib != nil {
+		t.Errorf("TestDiagnostics: ReadMaterialLibFromBuf: %v", errLib)
+		return
+	}
+	expectInt(t, "TestDiagnostics: lib diagnostics", 2, len(lib.Diagnostics))
+}
+
+var diagObj = `v 0 0 0
+v 1 0 0
+v 1 1 0
+vt 0 0 1
+vn 0 x 1
+bogus line
+f 1 2 3
+f 1 2 9
+f 1 2
+`
@@ -0,0 +1,184 @@
+package gwob
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// maxDiffMessages caps the messages kept by Diff.
+const maxDiffMessages = 20
+
+// DiffReport summarizes the differences found by Diff.
+type DiffReport struct {
+	VerticesA, VerticesB int // vertex (stride) counts
+	IndicesA, IndicesB   int // index counts
+
+	IndexMismatches    int // indices differing, among the common ones
+	GroupMismatches    int // groups differing in name, material, smoothing or range, or missing
+	PositionMismatches int // common vertices with positions beyond tolerance
+	TexCoordMismatches int // common vertices with texture coordinates beyond tolerance, or missing
+	NormalMismatches   int // common vertices with normals beyond tolerance, or missing
+	ColorMismatches    int // common vertices with colors beyond tolerance
+
+	MaxDeviation float64 // largest attribute difference found
+
+	// Messages describes the first differences, up to a limit.
+	Messages []string
+}
+
+// Equal reports whether no difference was found.
+func (r *DiffReport) Equal() bool {
+	return r.VerticesA == r.VerticesB && r.IndicesA == r.IndicesB &&
+		r.IndexMismatches == 0 && r.GroupMismatches == 0 &&
+		r.PositionMismatches == 0 && r.TexCoordMismatches == 0 &&
+		r.NormalMismatches == 0 && r.ColorMismatches == 0
+}
+
+// String summarizes the report in a line.
+func (r *DiffReport) String() string {
+	if r.Equal() {
+		return "equal"
+	}
+	var parts []string
+	add := func(format string, args ...any) {
+		parts = append(parts, fmt.Sprintf(format, args...))
+	}
+	if r.VerticesA != r.VerticesB {
+		add("vertices %d/%d", r.VerticesA, r.VerticesB)
+	}
+	if r.IndicesA != r.IndicesB {
+		add("indices %d/%d", r.IndicesA, r.IndicesB)
+	}
+	for _, c := range []struct {
+		name  string
+		count int
+	}{
+		{"index", r.IndexMismatches},
+		{"group", r.GroupMismatches},
+		{"position", r.PositionMismatches},
+		{"texcoord", r.TexCoordMismatches},
+		{"normal", r.NormalMismatches},
+		{"color", r.ColorMismatches},
+	} {
+		if c.count > 0 {
+			add("%s mismatches=%d", c.name, c.count)
+		}
+	}
+	add("max deviation=%g", r.MaxDeviation)
+	return strings.Join(parts, " ")
+}
+
+func (r *DiffReport) message(format string, args ...any) {
+	if len(r.Messages) < maxDiffMessages {
+		r.Messages = append(r.Messages, fmt.Sprintf(format, args...))
+	}
+}
+
+// Diff compares two meshes: vertex and index counts, index buffers,
+// group structure and vertex attributes, which may differ by up to
+// epsilon. Meshes are compared element by element, so reordered but
+// otherwise identical meshes differ. Diff is meant for regression
+// tests of pipelines producing meshes.
+func Diff(a, b *Obj, epsilon float64) DiffReport {
+	r := DiffReport{
+		VerticesA: a.NumberOfElements(),
+		VerticesB: b.NumberOfElements(),
+		IndicesA:  len(a.Indices),
+		IndicesB:  len(b.Indices),
+	}
+	if r.VerticesA != r.VerticesB {
+		r.message("vertex count: %d != %d", r.VerticesA, r.VerticesB)
+	}
+	if r.IndicesA != r.IndicesB {
+		r.message("index count: %d != %d", r.IndicesA, r.IndicesB)
+	}
+
+	for i := 0; i < min(r.IndicesA, r.IndicesB); i++ {
+		if a.Indices[i] != b.Indices[i] {
+			r.IndexMismatches++
+			r.message("index %d: %d != %d", i, a.Indices[i], b.Indices[i])
+		}
+	}
+
+	diffGroups(&r, a, b)
+
+	// compare reports whether the attribute values are within epsilon,
+	// tracking the largest deviation
+	compare := func(x, y []float32) bool {
+		equal := true
+		for i := range x {
+			d := math.Abs(float64(x[i]) - float64(y[i]))
+			r.MaxDeviation = math.Max(r.MaxDeviation, d)
+			if d > epsilon {
+				equal = false
+			}
+		}
+		return equal
+	}
+
+	for s := 0; s < min(r.VerticesA, r.VerticesB); s++ {
+		ax, ay, az := a.VertexCoordinates(s)
+		bx, by, bz := b.VertexCoordinates(s)
+		if !compare([]float32{ax, ay, az}, []float32{bx, by, bz}) {
+			r.PositionMismatches++
+			r.message("vertex %d position: %v,%v,%v != %v,%v,%v", s, ax, ay, az, bx, by, bz)
+		}
+
+		switch {
+		case a.TextCoordFound && b.TextCoordFound:
+			if ta, tb := a.vertexTexCoord(s), b.vertexTexCoord(s); !compare(ta, tb) {
+				r.TexCoordMismatches++
+				r.message("vertex %d texcoord: %v != %v", s, ta, tb)
+			}
+		case a.TextCoordFound != b.TextCoordFound:
+			r.TexCoordMismatches++
+		}
+
+		switch {
+		case a.NormCoordFound && b.NormCoordFound:
+			if na, nb := a.vertexNormal(s), b.vertexNormal(s); !compare(na, nb) {
+				r.NormalMismatches++
+				r.message("vertex %d normal: %v != %v", s, na, nb)
+			}
+		case a.NormCoordFound != b.NormCoordFound:
+			r.NormalMismatches++
+		}
+
+		if a.ColorFound || b.ColorFound {
+			ar, ag, ab := a.VertexColor(s)
+			br, bg, bb := b.VertexColor(s)
+			if !compare([]float32{ar, ag, ab}, []float32{br, bg, bb}) {
+				r.ColorMismatches++
+				r.message("vertex %d color: %v,%v,%v != %v,%v,%v", s, ar, ag, ab, br, bg, bb)
+			}
+		}
+	}
+	if a.TextCoordFound != b.TextCoordFound {
+		r.message("texture coordinates: %v != %v", a.TextCoordFound, b.TextCoordFound)
+	}
+	if a.NormCoordFound != b.NormCoordFound {
+		r.message("normals: %v != %v", a.NormCoordFound, b.NormCoordFound)
+	}
+
+	return r
+}
+
+// diffGroups compares the groups of a and b, in order.
+func diffGroups(r *DiffReport, a, b *Obj) {
+	for i := 0; i < max(len(a.Groups), len(b.Groups)); i++ {
+		if i >= len(a.Groups) || i >= len(b.Groups) {
+			r.GroupMismatches++
+			r.message("group %d: missing in one mesh", i)
+			continue
+		}
+		ga, gb := a.Groups[i], b.Groups[i]
+		if ga.Name != gb.Name || ga.Usemtl != gb.Usemtl || ga.Smooth != gb.Smooth ||
+			ga.IndexBegin != gb.IndexBegin || ga.IndexCount != gb.IndexCount {
+			r.GroupMismatches++
+			r.message("group %d: name=%q usemtl=%q s=%d range=%d+%d != name=%q usemtl=%q s=%d range=%d+%d", i,
+				ga.Name, ga.Usemtl, ga.Smooth, ga.IndexBegin, ga.IndexCount,
+				gb.Name, gb.Usemtl, gb.Smooth, gb.IndexBegin, gb.IndexCount)
+		}
+	}
+}
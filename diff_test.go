@@ -0,0 +1,53 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a, err := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestDiff: NewObjFromBuf: %v", err)
+	}
+
+	same := Diff(a, a.Clone(), 0)
+	if !same.Equal() || same.String() != "equal" || len(same.Messages) != 0 {
+		t.Errorf("TestDiff: clone differs: %v %v", same.String(), same.Messages)
+	}
+
+	// separate layout holds the same mesh
+	b, _ := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions(WithNonInterleaved(true)))
+	if r := Diff(a, b, 0); !r.Equal() {
+		t.Errorf("TestDiff: separate layout differs: %v %v", r.String(), r.Messages)
+	}
+
+	c := a.Clone()
+	c.Translate(0.001, 0, 0)
+	if r := Diff(a, c, 0.01); !r.Equal() {
+		t.Errorf("TestDiff: within tolerance differs: %v", r.String())
+	}
+	r := Diff(a, c, 0.0001)
+	expectInt(t, "TestDiff: position mismatches", a.NumberOfElements(), r.PositionMismatches)
+	if r.Equal() || r.MaxDeviation < 0.0009 || r.MaxDeviation > 0.0011 {
+		t.Errorf("TestDiff: moved: equal=%v max deviation=%v", r.Equal(), r.MaxDeviation)
+	}
+	if len(r.Messages) != maxDiffMessages {
+		t.Errorf("TestDiff: messages=%d want=%d", len(r.Messages), maxDiffMessages)
+	}
+
+	d := a.Clone()
+	d.Indices[0], d.Indices[1] = d.Indices[1], d.Indices[0]
+	d.Groups[0].Usemtl = "other"
+	d.InvertNormals()
+	r = Diff(a, d, 0)
+	expectInt(t, "TestDiff: index mismatches", 2, r.IndexMismatches)
+	expectInt(t, "TestDiff: group mismatches", 1, r.GroupMismatches)
+	expectInt(t, "TestDiff: normal mismatches", a.NumberOfElements(), r.NormalMismatches)
+	expectInt(t, "TestDiff: texcoord mismatches", 0, r.TexCoordMismatches)
+
+	e, _ := NewObjFromBuf("box", []byte(boxObj), NewObjParserOptions())
+	r = Diff(a, e, 0)
+	if r.Equal() || r.VerticesA == r.VerticesB || r.TexCoordMismatches == 0 {
+		t.Errorf("TestDiff: different meshes: %v", r.String())
+	}
+}
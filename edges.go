@@ -0,0 +1,118 @@
+package gwob
+
+// Edge is an undirected mesh edge, between strides A < B.
+type Edge struct {
+	A, B int
+}
+
+func newEdge(a, b int) Edge {
+	if a > b {
+		a, b = b, a
+	}
+	return Edge{A: a, B: b}
+}
+
+// Adjacency holds the connectivity of the triangles of a mesh.
+//
+// Vertices are identified by position, so that texture or normal seams,
+// which duplicate strides, do not split the surface: edges are given by
+// the first stride found at each end position. Triangles repeating a
+// position have no proper edges and are ignored.
+type Adjacency struct {
+	Edges       []Edge       // unique edges, in order of first use
+	EdgeIndex   map[Edge]int // edge -> position in Edges
+	EdgeFaces   [][]int      // triangles using each edge, parallel to Edges
+	VertexFaces [][]int      // triangles using the position of each stride
+	Position    []int        // first stride with the position of each stride
+}
+
+// positionIDs maps each stride to the first stride with the same position.
+func (o *Obj) positionIDs() []int {
+	strides := o.NumberOfElements()
+	ids := make([]int, strides)
+	first := make(map[[3]float32]int, strides)
+	for s := 0; s < strides; s++ {
+		x, y, z := o.VertexCoordinates(s)
+		p := [3]float32{x + 0, y + 0, z + 0} // +0 turns -0 into 0
+		id, found := first[p]
+		if !found {
+			id = s
+			first[p] = s
+		}
+		ids[s] = id
+	}
+	return ids
+}
+
+// degenerateIDs reports whether a triangle repeats a position.
+func degenerateIDs(ids []int, tri []int) bool {
+	a, b, c := ids[tri[0]], ids[tri[1]], ids[tri[2]]
+	return a == b || b == c || c == a
+}
+
+// Edges lists the unique edges of the triangles, in order of first use.
+// See Adjacency for how vertices are identified.
+func (o *Obj) Edges() []Edge {
+	ids := o.positionIDs()
+	seen := map[Edge]bool{}
+	var edges []Edge
+	for i := 0; i+2 < len(o.Indices); i += 3 {
+		if degenerateIDs(ids, o.Indices[i:i+3]) {
+			continue
+		}
+		for c := 0; c < 3; c++ {
+			e := newEdge(ids[o.Indices[i+c]], ids[o.Indices[i+(c+1)%3]])
+			if seen[e] {
+				continue
+			}
+			seen[e] = true
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// Adjacency computes the edge to triangle and vertex to triangle
+// connectivity of the triangles.
+func (o *Obj) Adjacency() *Adjacency {
+	ids := o.positionIDs()
+	adj := &Adjacency{
+		EdgeIndex:   map[Edge]int{},
+		VertexFaces: make([][]int, len(ids)),
+		Position:    ids,
+	}
+	byPosition := map[int][]int{} // position id -> triangles
+	for t := 0; t < len(o.Indices)/3; t++ {
+		if degenerateIDs(ids, o.Indices[3*t:3*t+3]) {
+			continue
+		}
+		for c := 0; c < 3; c++ {
+			a := ids[o.Indices[3*t+c]]
+			b := ids[o.Indices[3*t+(c+1)%3]]
+			byPosition[a] = append(byPosition[a], t)
+			e := newEdge(a, b)
+			k, found := adj.EdgeIndex[e]
+			if !found {
+				k = len(adj.Edges)
+				adj.EdgeIndex[e] = k
+				adj.Edges = append(adj.Edges, e)
+				adj.EdgeFaces = append(adj.EdgeFaces, nil)
+			}
+			adj.EdgeFaces[k] = append(adj.EdgeFaces[k], t)
+		}
+	}
+	for s, id := range ids {
+		adj.VertexFaces[s] = byPosition[id]
+	}
+	return adj
+}
+
+// Faces gets the triangles using the edge between strides a and b,
+// identified by position.
+func (adj *Adjacency) Faces(a, b int) []int {
+	k, found := adj.EdgeIndex[newEdge(adj.Position[a], adj.Position[b])]
+	if !found {
+		return nil
+	}
+	return adj.EdgeFaces[k]
+}
@@ -0,0 +1,84 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestEdges(t *testing.T) {
+	table := []struct {
+		name string
+		buf  string
+	}{
+		{"box", boxObj},
+		{"cube", cubeObj}, // texture and normal seams
+	}
+
+	for _, data := range table {
+		o, err := NewObjFromBuf(data.name, []byte(data.buf), NewObjParserOptions())
+		if err != nil {
+			t.Fatalf("TestEdges: %s: NewObjFromBuf: %v", data.name, err)
+		}
+		triangles := len(o.Indices) / 3
+		expectInt(t, "TestEdges: "+data.name+": triangles", 12, triangles)
+
+		edges := o.Edges()
+		expectInt(t, "TestEdges: "+data.name+": edges", 18, len(edges)) // 12 sides, 6 diagonals
+
+		adj := o.Adjacency()
+		expectInt(t, "TestEdges: "+data.name+": adjacency edges", len(edges), len(adj.Edges))
+		for i, e := range adj.Edges {
+			if e != edges[i] {
+				t.Errorf("TestEdges: %s: edge %d: %v want %v", data.name, i, e, edges[i])
+			}
+			if e.A >= e.B {
+				t.Errorf("TestEdges: %s: edge %d: unordered %v", data.name, i, e)
+			}
+			expectInt(t, "TestEdges: "+data.name+": edge faces", 2, len(adj.EdgeFaces[i])) // closed
+		}
+
+		expectInt(t, "TestEdges: "+data.name+": vertex faces", o.NumberOfElements(), len(adj.VertexFaces))
+		corners := 0
+		for s, list := range adj.VertexFaces {
+			if adj.Position[s] != s {
+				continue // seam duplicate
+			}
+			corners += len(list)
+		}
+		expectInt(t, "TestEdges: "+data.name+": corners", 3*triangles, corners)
+
+		// each triangle is found from its own edges
+		for tri := 0; tri < triangles; tri++ {
+			for c := 0; c < 3; c++ {
+				a, b := o.Indices[3*tri+c], o.Indices[3*tri+(c+1)%3]
+				found := false
+				for _, f := range adj.Faces(a, b) {
+					found = found || f == tri
+				}
+				if !found {
+					t.Errorf("TestEdges: %s: triangle %d not on its edge %d-%d", data.name, tri, a, b)
+				}
+			}
+		}
+	}
+}
+
+func TestEdgesOpen(t *testing.T) {
+	o, err := NewObjFromBuf("quad", []byte("v 0 0 0\nv 1 0 0\nv 1 1 0\nv 0 1 0\nf 1 2 3 4\n"), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestEdgesOpen: NewObjFromBuf: %v", err)
+	}
+	adj := o.Adjacency()
+	expectInt(t, "TestEdgesOpen: edges", 5, len(adj.Edges))
+	borders := 0
+	for _, faces := range adj.EdgeFaces {
+		if len(faces) == 1 {
+			borders++
+		}
+	}
+	expectInt(t, "TestEdgesOpen: borders", 4, borders)
+	expectInt(t, "TestEdgesOpen: vertex 0", 2, len(adj.VertexFaces[0]))
+	expectInt(t, "TestEdgesOpen: vertex 1", 1, len(adj.VertexFaces[1]))
+	if adj.Faces(1, 3) != nil {
+		t.Errorf("TestEdgesOpen: unexpected edge 1-3")
+	}
+}
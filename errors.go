@@ -0,0 +1,80 @@
+package gwob
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Parse error kinds, to be matched with errors.Is.
+var (
+	ErrUnexpected        = errors.New("unexpected directive")
+	ErrBadFace           = errors.New("bad face")
+	ErrBadIndex          = errors.New("bad index")
+	ErrIndexOutOfRange   = errors.New("index out of range")
+	ErrBadCoord          = errors.New("bad coordinate")
+	ErrBadValue          = errors.New("bad value")
+	ErrUndefinedMaterial = errors.New("undefined material")
+)
+
+// errForwardRef signals a face referencing vertex data not yet defined.
+var errForwardRef = errors.New("forward reference")
+
+// ParseError reports a problem found at a specific line of OBJ or MTL input.
+// Use errors.As to retrieve it and errors.Is to check its Kind.
+type ParseError struct {
+	File      string // input name
+	Line      int    // line number, starting at 1
+	Directive string // statement keyword, like "f" or "Kd"
+	Text      string // offending line
+	Msg       string // description
+	Kind      error  // one of the Err* parse error kinds
+	Err       error  // underlying cause, if any
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("file=%s line=%d: %s", e.File, e.Line, e.Msg)
+}
+
+// Unwrap exposes both the error kind and the underlying cause.
+func (e *ParseError) Unwrap() []error {
+	var list []error
+	if e.Kind != nil {
+		list = append(list, e.Kind)
+	}
+	if e.Err != nil {
+		list = append(list, e.Err)
+	}
+	return list
+}
+
+// newParseError builds a ParseError.
+// The first error found in args, if any, is recorded as the underlying cause.
+func newParseError(file string, line int, text string, kind error, format string, args ...interface{}) *ParseError {
+	text = strings.Clone(text) // do not retain the input buffer
+	e := &ParseError{
+		File:      file,
+		Line:      line,
+		Directive: directive(text),
+		Text:      text,
+		Msg:       fmt.Sprintf(format, args...),
+		Kind:      kind,
+	}
+	for _, a := range args {
+		if cause, ok := a.(error); ok {
+			e.Err = cause
+			break
+		}
+	}
+	return e
+}
+
+// directive extracts the statement keyword from a line.
+func directive(line string) string {
+	f := strings.Fields(line)
+	if len(f) == 0 {
+		return ""
+	}
+	return f[0]
+}
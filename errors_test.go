@@ -0,0 +1,55 @@
+package gwob
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+type failReader struct{}
+
+func (failReader) Read([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestParseError(t *testing.T) {
+
+	options := ObjParserOptions{LogStats: LogStats, Logger: func(msg string) { fmt.Printf("TestParseError: log: %s\n", msg) }}
+
+	p := &objParser{objName: "bad.obj", indexTable: make(map[vertexKey]int)}
+	o := &Obj{}
+	p.currGroup = o.newGroup("", "", 0, 0)
+	p.lineCount = 7
+	p.replay = true // all vertex data known
+
+	_, err := parseLine(p, o, "f 1 2 3", &options)
+	if err == nil {
+		t.Errorf("TestParseError: unexpected success for face referencing missing vertices")
+		return
+	}
+
+	if !errors.Is(err, ErrBadFace) {
+		t.Errorf("TestParseError: want ErrBadFace: %v", err)
+	}
+	if !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("TestParseError: want ErrIndexOutOfRange: %v", err)
+	}
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Errorf("TestParseError: want *ParseError: %v", err)
+		return
+	}
+	if pe.File != "bad.obj" || pe.Line != 7 || pe.Directive != "f" || pe.Text != "f 1 2 3" {
+		t.Errorf("TestParseError: unexpected position: %+v", pe)
+	}
+
+	_, errIO := NewObjFromReader("fail", failReader{}, &options)
+	if !errors.Is(errIO, io.ErrClosedPipe) {
+		t.Errorf("TestParseError: want io.ErrClosedPipe: %v", errIO)
+	}
+	if errors.As(errIO, &pe) {
+		t.Errorf("TestParseError: IO failure should not be a ParseError: %v", errIO)
+	}
+}
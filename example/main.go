@@ -27,33 +27,25 @@ func main() {
 		Logger:   func(msg string) { fmt.Fprintln(os.Stderr, msg) },
 	}
 
-	// Load OBJ
-	o, errObj := gwob.NewObjFromFile(fileObj, options)
-	if errObj != nil {
-		log.Printf("obj: parse error input=%s: %v", fileObj, errObj)
+	// Load OBJ and material libs
+	scene, errScene := gwob.LoadScene(fileObj, options)
+	if errScene != nil {
+		log.Printf("obj: parse error input=%s: %v", fileObj, errScene)
 		return
 	}
 
-	fileMtl := o.Mtllib
+	o := scene.Obj
 
-	// Load material lib
-	lib, errMtl := gwob.ReadMaterialLibFromFile(fileMtl, options)
-	if errMtl != nil {
-		log.Printf("mtl: parse error input=%s: %v", fileMtl, errMtl)
-	} else {
+	// Scan OBJ groups
+	for i, g := range o.Groups {
 
-		// Scan OBJ groups
-		for _, g := range o.Groups {
-
-			mtl, found := lib.Lib[g.Usemtl]
-			if found {
-				log.Printf("obj=%s lib=%s group=%s material=%s MapKd=%s Kd=%v", fileObj, fileMtl, g.Name, g.Usemtl, mtl.MapKd, mtl.Kd)
-				continue
-			}
-
-			log.Printf("obj=%s lib=%s group=%s material=%s NOT FOUND", fileObj, fileMtl, g.Name, g.Usemtl)
+		mtl := scene.Materials[i]
+		if mtl != nil {
+			log.Printf("obj=%s libs=%v group=%s material=%s MapKd=%s Kd=%v", fileObj, o.Mtllibs, g.Name, g.Usemtl, mtl.MapKd, mtl.Kd)
+			continue
 		}
 
+		log.Printf("obj=%s libs=%v group=%s material=%s NOT FOUND", fileObj, o.Mtllibs, g.Name, g.Usemtl)
 	}
 
 	if len(os.Args) < 2 {
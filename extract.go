@@ -0,0 +1,67 @@
+package gwob
+
+import "fmt"
+
+// ExtractGroup copies the named group into a new Obj, keeping only the
+// vertices it uses. All groups belonging to the name, as reported by
+// GroupsNamed, are extracted, such as the parts of a group split by
+// material changes. The new Obj keeps the vertex layout, materials and
+// per-triangle data of o, whose raw data kept by KeepRawData it shares.
+// Comments are not kept.
+func (o *Obj) ExtractGroup(name string) (*Obj, error) {
+	groups := map[*Group]*Group{} // original -> copy
+	c := *o
+	c.Groups = nil
+	for _, g := range o.GroupsNamed(name) {
+		gr := *g
+		gr.Names = append([]string(nil), g.Names...)
+		gr.Points = append([]int(nil), g.Points...)
+		gr.Lines = nil
+		for _, line := range g.Lines {
+			gr.Lines = append(gr.Lines, append([]int(nil), line...))
+		}
+		groups[g] = &gr
+		c.Groups = append(c.Groups, &gr)
+	}
+	if len(c.Groups) == 0 {
+		return nil, fmt.Errorf("ExtractGroup: group not found: %s", name)
+	}
+
+	triangles := len(o.Indices) / 3
+	keep := make([]bool, triangles)
+	for g := range groups {
+		for t := g.IndexBegin / 3; t < (g.IndexBegin+g.IndexCount)/3 && t < triangles; t++ {
+			keep[t] = true
+		}
+	}
+
+	c.Indices = append([]int(nil), o.Indices...)
+	c.Corners = append([]FaceIndex(nil), o.Corners...)
+	c.Smoothing = append([]int(nil), o.Smoothing...)
+	c.MaterialIndex = append([]int(nil), o.MaterialIndex...)
+	c.MaterialNames = append([]string(nil), o.MaterialNames...)
+	c.Comments = nil
+	c.Faces = nil
+	for _, f := range o.Faces {
+		if g, found := groups[f.Group]; found {
+			f.Group = g
+			f.Corners = append([]FaceIndex(nil), f.Corners...)
+			f.Vertices = append([]int(nil), f.Vertices...)
+			c.Faces = append(c.Faces, f)
+		}
+	}
+	if o.Faces != nil && c.Faces == nil {
+		c.Faces = []Face{}
+	}
+	c.FreeForm = nil
+	c.Diagnostics = nil
+
+	selectTriangles(&c, keep)
+
+	// always rebuild the vertex data, so that c does not share it
+	remap, src := c.usedStrides()
+	c.remapIndices(remap)
+	selectStrides(&c, src)
+
+	return &c, nil
+}
@@ -0,0 +1,84 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestExtractGroup(t *testing.T) {
+	str := `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+v 5 5 5
+v 6 5 5
+v 6 6 5
+g body
+usemtl skin
+f 1 2 3
+usemtl cloth
+f 1 3 4
+g collider
+f 5 6 7
+p 5
+`
+	table := []struct {
+		name    string
+		options *ObjParserOptions
+	}{
+		{"default", NewObjParserOptions(WithKeepFaces(true))},
+		{"per triangle", NewObjParserOptions(WithKeepFaces(true), WithMaterialPerTriangle(true), WithNonInterleaved(true))},
+	}
+
+	for _, data := range table {
+		o, err := NewObjFromBuf("extract", []byte(str), data.options)
+		if err != nil {
+			t.Fatalf("TestExtractGroup: %s: NewObjFromBuf: %v", data.name, err)
+		}
+
+		collider, err := o.ExtractGroup("collider")
+		if err != nil {
+			t.Fatalf("TestExtractGroup: %s: %v", data.name, err)
+		}
+		expectInt(t, "TestExtractGroup: "+data.name+": collider elements", 3, collider.NumberOfElements())
+		if want := []int{0, 1, 2}; !sliceEqualInt(want, collider.Indices) {
+			t.Errorf("TestExtractGroup: %s: collider indices: want=%v got=%v", data.name, want, collider.Indices)
+		}
+		if x, _, _ := collider.VertexCoordinates(0); x != 5 {
+			t.Errorf("TestExtractGroup: %s: collider first vertex x=%v", data.name, x)
+		}
+		g := collider.Groups[0]
+		if len(collider.Groups) != 1 || g.Name != "collider" || g.IndexBegin != 0 || g.IndexCount != 3 {
+			t.Errorf("TestExtractGroup: %s: collider group: %+v", data.name, g)
+		}
+		if want := []int{0}; !sliceEqualInt(want, g.Points) {
+			t.Errorf("TestExtractGroup: %s: collider points: want=%v got=%v", data.name, want, g.Points)
+		}
+		if len(collider.Faces) != 1 || collider.Faces[0].Group != g || collider.Faces[0].IndexBegin != 0 {
+			t.Errorf("TestExtractGroup: %s: collider faces: %+v", data.name, collider.Faces)
+		}
+
+		body, err := o.ExtractGroup("body")
+		if err != nil {
+			t.Fatalf("TestExtractGroup: %s: %v", data.name, err)
+		}
+		expectInt(t, "TestExtractGroup: "+data.name+": body elements", 4, body.NumberOfElements())
+		expectInt(t, "TestExtractGroup: "+data.name+": body indices", 6, len(body.Indices))
+		if len(body.MaterialIndex) > 0 {
+			if want := []int{0, 1}; !sliceEqualInt(want, body.MaterialIndex) {
+				t.Errorf("TestExtractGroup: %s: body materials: want=%v got=%v", data.name, want, body.MaterialIndex)
+			}
+		}
+
+		// the source is not changed
+		expectInt(t, "TestExtractGroup: "+data.name+": source elements", 7, o.NumberOfElements())
+		body.Translate(1, 0, 0)
+		if x, _, _ := o.VertexCoordinates(0); x != 0 {
+			t.Errorf("TestExtractGroup: %s: source vertex moved: x=%v", data.name, x)
+		}
+
+		if _, err := o.ExtractGroup("missing"); err == nil {
+			t.Errorf("TestExtractGroup: %s: expected error for missing group", data.name)
+		}
+	}
+}
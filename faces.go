@@ -0,0 +1,48 @@
+package gwob
+
+// Face is a polygon face as found in the input, before triangulation.
+// Material and smoothing group are those of Group.
+type Face struct {
+	Group      *Group
+	Corners    []FaceIndex // original v/vt/vn indices, any arity
+	Vertices   []int       // unified index of each corner, parallel to Corners
+	IndexBegin int         // first triangulated index in Obj.Indices
+	IndexCount int         // triangulated indices, 3 per triangle
+}
+
+// keepFace records a face emitted into Indices starting at begin,
+// whose corners were emitted in the given order.
+func (p *objParser) keepFace(o *Obj, keys []vertexKey, order []int, begin int, options *ObjParserOptions) {
+	corners := make([]FaceIndex, len(keys))
+	vertices := make([]int, len(keys))
+	for i, k := range keys {
+		corners[i] = FaceIndex{V: k.v, T: k.t, N: k.n}
+		if options.IgnoreNormals {
+			corners[i].N = -1
+		}
+		vertices[i] = -1
+	}
+	for j, c := range order {
+		if vertices[c] < 0 {
+			vertices[c] = o.Indices[begin+j]
+		}
+	}
+	for i, v := range vertices {
+		if v < 0 {
+			// corner left out by the triangulation
+			vertices[i] = unifyVertex(p, o, keys[i], options)
+		}
+	}
+	o.Faces = append(o.Faces, Face{
+		Group:      p.currGroup,
+		Corners:    corners,
+		Vertices:   vertices,
+		IndexBegin: begin,
+		IndexCount: len(o.Indices) - begin,
+	})
+}
+
+// Indices gets the triangulated indices for the face.
+func (f *Face) Indices(o *Obj) []int {
+	return o.Indices[f.IndexBegin : f.IndexBegin+f.IndexCount]
+}
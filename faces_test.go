@@ -0,0 +1,102 @@
+package gwob
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestKeepFaces(t *testing.T) {
+
+	options := NewObjParserOptions(WithKeepFaces(true))
+
+	o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), options)
+	if err != nil {
+		t.Fatalf("TestKeepFaces: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestKeepFaces: faces", 12, len(o.Faces))
+
+	// first face: f -6/-2/-6 -7/-2/-6 -8/-2/-6
+	want := []FaceIndex{{V: 2, T: 1, N: 0}, {V: 1, T: 1, N: 0}, {V: 0, T: 1, N: 0}}
+	f := o.Faces[0]
+	if len(f.Corners) != len(want) {
+		t.Fatalf("TestKeepFaces: corners: want=%v got=%v", want, f.Corners)
+	}
+	for i := range want {
+		if want[i] != f.Corners[i] {
+			t.Errorf("TestKeepFaces: corner %d: want=%v got=%v", i, want[i], f.Corners[i])
+		}
+	}
+	if f.Group != o.Groups[0] || f.Group.Usemtl != "3-pixel-rgb" {
+		t.Errorf("TestKeepFaces: group: got=%+v", f.Group)
+	}
+	if !sliceEqualInt(cubeIndices[:3], f.Indices(o)) {
+		t.Errorf("TestKeepFaces: indices: want=%v got=%v", cubeIndices[:3], f.Indices(o))
+	}
+
+	quads := `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+g quad
+f 1 2 3 4
+f 1 2 99
+f 4 3 2
+`
+	o, err = NewObjFromBuf("quads", []byte(quads), options)
+	if err != nil {
+		t.Fatalf("TestKeepFaces: quads: %v", err)
+	}
+	expectInt(t, "TestKeepFaces: quad faces", 2, len(o.Faces))
+	expectInt(t, "TestKeepFaces: quad corners", 4, len(o.Faces[0].Corners))
+	expectInt(t, "TestKeepFaces: quad indices", 6, o.Faces[0].IndexCount)
+	expectInt(t, "TestKeepFaces: triangle begin", 6, o.Faces[1].IndexBegin)
+	expectInt(t, "TestKeepFaces: triangle indices", 3, o.Faces[1].IndexCount)
+}
+
+func TestWritePolygons(t *testing.T) {
+	str := `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+v 2 0 0
+v 2 1 0
+v 3 0.5 0
+g mixed
+f 1 2 3 4
+f 2 5 7 6 3
+s 1
+f 1 2 3
+`
+	options := NewObjParserOptions(WithKeepFaces(true))
+
+	o, err := NewObjFromBuf("polygons", []byte(str), options)
+	if err != nil {
+		t.Fatalf("TestWritePolygons: NewObjFromBuf: %v", err)
+	}
+	if !sliceEqualInt([]int{0, 1, 2, 3}, o.Faces[0].Vertices) {
+		t.Errorf("TestWritePolygons: quad vertices: got=%v", o.Faces[0].Vertices)
+	}
+
+	var buf bytes.Buffer
+	if errWrite := o.ToWriterWithOptions(&buf, &WriteOptions{Polygons: true}); errWrite != nil {
+		t.Fatalf("TestWritePolygons: ToWriterWithOptions: %v", errWrite)
+	}
+	expectInt(t, "TestWritePolygons: f statements", 3, strings.Count(buf.String(), "\nf "))
+
+	o2, err := NewObjFromBuf("polygons2", buf.Bytes(), options)
+	if err != nil {
+		t.Fatalf("TestWritePolygons: reparse: %v", err)
+	}
+	expectInt(t, "TestWritePolygons: faces", len(o.Faces), len(o2.Faces))
+	for i := range o.Faces {
+		if i < len(o2.Faces) && len(o.Faces[i].Corners) != len(o2.Faces[i].Corners) {
+			t.Errorf("TestWritePolygons: face %d: want %d corners got %d", i, len(o.Faces[i].Corners), len(o2.Faces[i].Corners))
+		}
+	}
+	if !sliceEqualInt(o.Indices, o2.Indices) {
+		t.Errorf("TestWritePolygons: indices: want=%v got=%v", o.Indices, o2.Indices)
+	}
+}
@@ -0,0 +1,87 @@
+package gwob
+
+import "math"
+
+// Flatten expands the mesh into a non-indexed triangle soup: each
+// entry in Indices gets its own stride, so that Indices becomes
+// 0,1,2,...  Vertices of points and lines are appended after the
+// triangle corners, and face vertices are remapped to the corners.
+// See IndexTriangles for the inverse.
+func (o *Obj) Flatten() {
+	src := make([]int, 0, len(o.Indices)) // new stride -> old stride
+	src = append(src, o.Indices...)
+
+	// points and lines are not part of the soup, but must stay valid
+	extra := map[int]int{} // old stride -> new stride
+	appendExtra := func(list []int) {
+		for i, v := range list {
+			n, found := extra[v]
+			if !found {
+				n = len(src)
+				src = append(src, v)
+				extra[v] = n
+			}
+			list[i] = n
+		}
+	}
+	for _, g := range o.Groups {
+		appendExtra(g.Points)
+		for _, line := range g.Lines {
+			appendExtra(line)
+		}
+	}
+
+	for i := range o.Faces {
+		f := &o.Faces[i]
+		for c, v := range f.Vertices {
+			for j := f.IndexBegin; j < f.IndexBegin+f.IndexCount; j++ {
+				if o.Indices[j] == v {
+					f.Vertices[c] = j
+					break
+				}
+			}
+		}
+	}
+
+	selectStrides(o, src)
+
+	for i := range o.Indices {
+		o.Indices[i] = i
+	}
+}
+
+// selectStrides rebuilds the vertex data of o so that each new stride
+// k is a copy of the old stride src[k]. Indices are not changed.
+func selectStrides(o *Obj, src []int) {
+	// pick copies the values of the src strides. Arrays shorter than
+	// the vertex count, like Colors, W and TexW, get fill for the
+	// missing strides, unless they are empty.
+	pick := func(values []float32, fill ...float32) []float32 {
+		if len(values) == 0 {
+			return values
+		}
+		size := len(fill)
+		result := make([]float32, 0, size*len(src))
+		for _, s := range src {
+			if size*s+size > len(values) {
+				result = append(result, fill...)
+				continue
+			}
+			result = append(result, values[size*s:size*s+size]...)
+		}
+		return result
+	}
+
+	if o.Coord != nil {
+		o.Coord = pick(o.Coord, make([]float32, o.StrideSize/4)...)
+	} else {
+		o.Positions = pick(o.Positions, 0, 0, 0)
+		o.TexCoords = pick(o.TexCoords, 0, 0)
+		o.Normals = pick(o.Normals, 0, 0, 0)
+	}
+	o.Colors = pick(o.Colors, 1, 1, 1)
+	o.W = pick(o.W, 1)
+	o.TexW = pick(o.TexW, 0)
+
+	o.BigIndexFound = len(src) > math.MaxUint16+1
+}
@@ -0,0 +1,69 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestFlatten: NewObjFromBuf: %v", err)
+	}
+	size := o.StrideSize / 4
+	var want []float32
+	for _, i := range o.Indices {
+		want = append(want, o.Coord[i*size:(i+1)*size]...)
+	}
+	groups := len(o.Groups)
+
+	o.Flatten()
+
+	expectInt(t, "TestFlatten: elements", len(o.Indices), o.NumberOfElements())
+	for i, v := range o.Indices {
+		if i != v {
+			t.Fatalf("TestFlatten: index %d: got=%d", i, v)
+		}
+	}
+	if !sliceEqualFloat(want, o.Coord) {
+		t.Errorf("TestFlatten: coord: want=%v got=%v", want, o.Coord)
+	}
+	expectInt(t, "TestFlatten: groups", groups, len(o.Groups))
+}
+
+func TestFlattenSeparate(t *testing.T) {
+	str := `
+v 0 0 0 1 0 0
+v 1 0 0 0 1 0
+v 1 1 0 0 0 1
+v 0 1 0
+f 1 2 3
+f 3 4 1
+p 4
+l 1 2
+`
+	options := NewObjParserOptions(WithNonInterleaved(true), WithKeepFaces(true))
+	o, err := NewObjFromBuf("flatten", []byte(str), options)
+	if err != nil {
+		t.Fatalf("TestFlattenSeparate: NewObjFromBuf: %v", err)
+	}
+
+	o.Flatten()
+
+	expectInt(t, "TestFlattenSeparate: positions", 3*(6+3), len(o.Positions))
+	wantColors := []float32{1, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 1, 1, 1, 1, 1, 0, 0}
+	if !sliceEqualFloat(wantColors, o.Colors[:len(wantColors)]) {
+		t.Errorf("TestFlattenSeparate: colors: want=%v got=%v", wantColors, o.Colors[:len(wantColors)])
+	}
+	g := o.Groups[0]
+	x, y, _ := o.VertexCoordinates(g.Points[0])
+	if x != 0 || y != 1 {
+		t.Errorf("TestFlattenSeparate: point moved: %v,%v", x, y)
+	}
+	for i, v := range o.Faces[1].Vertices {
+		x1, y1, _ := o.VertexCoordinates(v)
+		x2, y2, _ := o.VertexCoordinates(o.Faces[1].Indices(o)[i])
+		if x1 != x2 || y1 != y2 {
+			t.Errorf("TestFlattenSeparate: face vertex %d: %v,%v vs %v,%v", i, x1, y1, x2, y2)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package gwob
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFlipTextureV(t *testing.T) {
+	str := `
+v 0 0 0
+v 1 0 0
+v 0 1 0
+vt 0 0.25
+vt 1 0.25
+vt 0 1
+f 1/1 2/2 3/3
+`
+	o, err := NewObjFromBuf("flip", []byte(str), NewObjParserOptions(WithFlipTextureV(true)))
+	if err != nil {
+		t.Fatalf("TestFlipTextureV: NewObjFromBuf: %v", err)
+	}
+	var got []float32
+	for s := 0; s < o.NumberOfElements(); s++ {
+		got = append(got, o.vertexTexCoord(s)...)
+	}
+	if want := []float32{0, 0.75, 1, 0.75, 0, 0}; !sliceEqualFloat(want, got) {
+		t.Errorf("TestFlipTextureV: parse: want=%v got=%v", want, got)
+	}
+
+	var buf bytes.Buffer
+	if err := o.ToWriterWithOptions(&buf, &WriteOptions{FlipTextureV: true}); err != nil {
+		t.Fatalf("TestFlipTextureV: write: %v", err)
+	}
+	for _, line := range []string{"vt 0.000000 0.250000", "vt 1.000000 0.250000", "vt 0.000000 1.000000"} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("TestFlipTextureV: write: missing %q in:\n%s", line, buf.String())
+		}
+	}
+
+	buf.Reset()
+	w := NewObjWriter(&buf, &WriteOptions{FlipTextureV: true, NoHeader: true})
+	w.WriteTexCoord(0.5, 0.25)
+	if err := w.Close(); err != nil {
+		t.Fatalf("TestFlipTextureV: stream write: %v", err)
+	}
+	if want := "vt 0.500000 0.750000\n"; buf.String() != want {
+		t.Errorf("TestFlipTextureV: stream write: want=%q got=%q", want, buf.String())
+	}
+}
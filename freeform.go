@@ -0,0 +1,249 @@
+package gwob
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// FreeForm holds free-form geometry: curves and surfaces given by
+// control points and knot vectors. Tessellation is left to the caller.
+type FreeForm struct {
+	Params   []float32 // vp statements (u,v,w), 3 floats per parameter vertex
+	Curves   []*Curve
+	Curves2D []*Curve2D
+	Surfaces []*Surface
+}
+
+// Basis describes a free-form element type, from the cstype statement.
+type Basis struct {
+	Type     string // bmatrix, bezier, bspline, cardinal or taylor
+	Rational bool
+}
+
+// Curve is a curv statement.
+type Curve struct {
+	Basis
+	Degree   int
+	U0, U1   float64
+	Vertices []int     // zero-based v indices of control points
+	Knots    []float64 // parm u
+	Group    *Group
+}
+
+// Curve2D is a curv2 statement, a curve in the parameter space of a surface.
+type Curve2D struct {
+	Basis
+	Degree int
+	Params []int     // zero-based vp indices of control points
+	Knots  []float64 // parm u
+	Group  *Group
+}
+
+// Surface is a surf statement.
+type Surface struct {
+	Basis
+	DegreeU, DegreeV int
+	S0, S1, T0, T1   float64
+	Corners          []FaceIndex // zero-based v/vt/vn indices of control points
+	KnotsU, KnotsV   []float64   // parm u, parm v
+	Group            *Group
+}
+
+// freeFormState holds the free-form statements in effect.
+type freeFormState struct {
+	basis   Basis
+	degU    int
+	degV    int
+	curve   *Curve   // element open until end
+	curve2D *Curve2D // element open until end
+	surface *Surface // element open until end
+}
+
+// isFreeFormLine reports whether the line is a free-form statement.
+func isFreeFormLine(line string) bool {
+	switch directive(line) {
+	case "vp", "cstype", "deg", "curv", "curv2", "surf", "parm", "end":
+		return true
+	}
+	return false
+}
+
+func (o *Obj) freeForm() *FreeForm {
+	if o.FreeForm == nil {
+		o.FreeForm = &FreeForm{}
+	}
+	return o.FreeForm
+}
+
+// parseFreeForm parses a free-form statement.
+func parseFreeForm(p *objParser, o *Obj, line string, options *ObjParserOptions) error {
+	fields := strings.Fields(line)
+	args := fields[1:]
+	ff := &p.freeForm
+
+	switch fields[0] {
+	case "vp":
+		if len(args) < 1 || len(args) > 3 {
+			return p.errorf(ErrBadCoord, "bad parameter vertex=[%s] size=%d", line, len(args))
+		}
+		vp := [3]float64{0, 0, 1}
+		for i, a := range args {
+			f, err := strconv.ParseFloat(a, 64)
+			if err != nil {
+				return p.errorf(ErrBadCoord, "bad parameter vertex=[%s]: %v", line, err)
+			}
+			vp[i] = f
+		}
+		form := o.freeForm()
+		form.Params = append(form.Params, float32(vp[0]), float32(vp[1]), float32(vp[2]))
+		p.paramLines++
+
+	case "cstype":
+		basis := Basis{}
+		if len(args) == 2 && args[0] == "rat" {
+			basis.Rational = true
+			args = args[1:]
+		}
+		if len(args) != 1 {
+			return p.errorf(ErrBadValue, "bad cstype=[%s]", line)
+		}
+		switch args[0] {
+		case "bmatrix", "bezier", "bspline", "cardinal", "taylor":
+		default:
+			return p.errorf(ErrBadValue, "unknown cstype=[%s]", args[0])
+		}
+		basis.Type = strings.Clone(args[0]) // do not retain the input buffer
+		ff.basis = basis
+
+	case "deg":
+		if len(args) < 1 || len(args) > 2 {
+			return p.errorf(ErrBadValue, "bad deg=[%s]", line)
+		}
+		degs, err := parseInts(args)
+		if err != nil {
+			return p.errorf(ErrBadValue, "bad deg=[%s]: %v", line, err)
+		}
+		ff.degU = degs[0]
+		ff.degV = 0
+		if len(degs) > 1 {
+			ff.degV = degs[1]
+		}
+
+	case "curv":
+		if len(args) < 4 {
+			return p.errorf(ErrBadValue, "bad curv=[%s] size=%d", line, len(args))
+		}
+		u, err := parseFloatSlice(args[:2])
+		if err != nil {
+			return p.errorf(ErrBadValue, "bad curv=[%s]: %v", line, err)
+		}
+		vertices := make([]int, len(args)-2)
+		for i, a := range args[2:] {
+			k, errCorner := resolveCorner(p, a, options)
+			if errCorner != nil {
+				return freeFormIndexError(p, line, a, errCorner)
+			}
+			vertices[i] = k.v
+		}
+		ff.curve = &Curve{Basis: ff.basis, Degree: ff.degU, U0: u[0], U1: u[1], Vertices: vertices, Group: p.currGroup}
+		form := o.freeForm()
+		form.Curves = append(form.Curves, ff.curve)
+
+	case "curv2":
+		if len(args) < 2 {
+			return p.errorf(ErrBadValue, "bad curv2=[%s] size=%d", line, len(args))
+		}
+		form := o.freeForm()
+		params := make([]int, len(args))
+		for i, a := range args {
+			n, err := strconv.Atoi(a)
+			if err != nil {
+				return p.errorf(ErrBadIndex, "bad curv2=[%s] index=[%s]: %v", line, a, err)
+			}
+			vp := solveRelativeIndex(n, p.paramLines)
+			if !p.replay && n > 0 && vp >= len(form.Params)/3 {
+				return errForwardRef
+			}
+			if vp < 0 || vp >= len(form.Params)/3 {
+				return p.errorf(ErrIndexOutOfRange, "bad curv2=[%s] invalid parameter vertex index=[%s]", line, a)
+			}
+			params[i] = vp
+		}
+		ff.curve2D = &Curve2D{Basis: ff.basis, Degree: ff.degU, Params: params, Group: p.currGroup}
+		form.Curves2D = append(form.Curves2D, ff.curve2D)
+
+	case "surf":
+		if len(args) < 5 {
+			return p.errorf(ErrBadValue, "bad surf=[%s] size=%d", line, len(args))
+		}
+		st, err := parseFloatSlice(args[:4])
+		if err != nil {
+			return p.errorf(ErrBadValue, "bad surf=[%s]: %v", line, err)
+		}
+		corners := make([]FaceIndex, len(args)-4)
+		for i, a := range args[4:] {
+			k, errCorner := resolveCorner(p, a, options)
+			if errCorner != nil {
+				return freeFormIndexError(p, line, a, errCorner)
+			}
+			corners[i] = FaceIndex{V: k.v, T: k.t, N: k.n}
+		}
+		ff.surface = &Surface{Basis: ff.basis, DegreeU: ff.degU, DegreeV: ff.degV,
+			S0: st[0], S1: st[1], T0: st[2], T1: st[3], Corners: corners, Group: p.currGroup}
+		form := o.freeForm()
+		form.Surfaces = append(form.Surfaces, ff.surface)
+
+	case "parm":
+		if len(args) < 2 || (args[0] != "u" && args[0] != "v") {
+			return p.errorf(ErrBadValue, "bad parm=[%s]", line)
+		}
+		knots, err := parseFloatSlice(args[1:])
+		if err != nil {
+			return p.errorf(ErrBadValue, "bad parm=[%s]: %v", line, err)
+		}
+		switch {
+		case ff.surface != nil && args[0] == "u":
+			ff.surface.KnotsU = knots
+		case ff.surface != nil:
+			ff.surface.KnotsV = knots
+		case ff.curve != nil && args[0] == "u":
+			ff.curve.Knots = knots
+		case ff.curve2D != nil && args[0] == "u":
+			ff.curve2D.Knots = knots
+		default:
+			return p.errorf(ErrBadValue, "parm outside of free-form element: [%s]", line)
+		}
+
+	case "end":
+		if ff.curve == nil && ff.curve2D == nil && ff.surface == nil {
+			return p.errorf(ErrBadValue, "end outside of free-form element")
+		}
+		ff.curve = nil
+		ff.curve2D = nil
+		ff.surface = nil
+	}
+
+	return nil
+}
+
+// freeFormIndexError reports a bad control point index,
+// passing forward references through.
+func freeFormIndexError(p *objParser, line, index string, err error) error {
+	if errors.Is(err, errForwardRef) {
+		return errForwardRef
+	}
+	return p.errorf(ErrBadIndex, "bad control point=[%s] in [%s]: %v", index, line, err)
+}
+
+func parseInts(list []string) ([]int, error) {
+	result := make([]int, len(list))
+	for i, s := range list {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = n
+	}
+	return result, nil
+}
@@ -0,0 +1,109 @@
+package gwob
+
+import (
+	"testing"
+)
+
+var freeFormObj = `
+v -2.3 1.95 0
+v -2.2 0.79 0
+v -2.34 -1.51 0
+v -1.81 -2.25 0
+g curves
+cstype bezier
+deg 3
+curv 0.0 1.0 1 2 3 4
+parm u 0.0 1.0
+end
+vp 0 0
+vp 1 0
+vp 1 1
+cstype rat bspline
+deg 1
+curv2 -3 -2 -1
+parm u 0 0 1 1
+end
+cstype bezier
+deg 1 1
+# forward reference
+surf 0 1 0 1 1 2 3 5
+parm u 0 1
+parm v 0 1
+end
+v 0 0 0
+parm u 1
+`
+
+func TestFreeForm(t *testing.T) {
+
+	o, err := NewObjFromBuf("freeFormObj", []byte(freeFormObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestFreeForm: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestFreeForm: errors", 1, o.Errors()) // parm outside of element
+
+	form := o.FreeForm
+	if form == nil {
+		t.Fatalf("TestFreeForm: missing free-form data")
+	}
+	expectInt(t, "TestFreeForm: curves", 1, len(form.Curves))
+	expectInt(t, "TestFreeForm: 2D curves", 1, len(form.Curves2D))
+	expectInt(t, "TestFreeForm: surfaces", 1, len(form.Surfaces))
+	expectInt(t, "TestFreeForm: params", 9, len(form.Params))
+
+	c := form.Curves[0]
+	if c.Type != "bezier" || c.Rational || c.Degree != 3 || c.U1 != 1 || c.Group.Name != "curves" {
+		t.Errorf("TestFreeForm: curve: %+v", *c)
+	}
+	if !sliceEqualInt([]int{0, 1, 2, 3}, c.Vertices) {
+		t.Errorf("TestFreeForm: curve vertices: %v", c.Vertices)
+	}
+	expectInt(t, "TestFreeForm: curve knots", 2, len(c.Knots))
+
+	c2 := form.Curves2D[0]
+	if c2.Type != "bspline" || !c2.Rational || c2.Degree != 1 || !sliceEqualInt([]int{0, 1, 2}, c2.Params) {
+		t.Errorf("TestFreeForm: 2D curve: %+v", *c2)
+	}
+	expectInt(t, "TestFreeForm: 2D curve knots", 4, len(c2.Knots))
+
+	s := form.Surfaces[0]
+	if s.Type != "bezier" || s.DegreeU != 1 || s.DegreeV != 1 || len(s.Corners) != 4 || s.Corners[3].V != 4 {
+		t.Errorf("TestFreeForm: surface: %+v", *s)
+	}
+	expectInt(t, "TestFreeForm: surface u knots", 2, len(s.KnotsU))
+	expectInt(t, "TestFreeForm: surface v knots", 2, len(s.KnotsV))
+}
+
+func TestFreeFormForwardParams(t *testing.T) {
+	str := `
+cstype bspline
+deg 1
+curv2 1 2 3
+end
+vp 0 0
+vp 1 0
+vp 1 1
+curv2 -3 -1
+end
+vp 2 2
+`
+	o, err := NewObjFromBuf("forwardParams", []byte(str), NewObjParserOptions(WithStrict(true)))
+	if err != nil {
+		t.Fatalf("TestFreeFormForwardParams: NewObjFromBuf: %v", err)
+	}
+	form := o.FreeForm
+	if form == nil || len(form.Curves2D) != 2 {
+		t.Fatalf("TestFreeFormForwardParams: free-form: %+v", form)
+	}
+	if !sliceEqualInt([]int{0, 1, 2}, form.Curves2D[0].Params) {
+		t.Errorf("TestFreeFormForwardParams: forward curve: %v", form.Curves2D[0].Params)
+	}
+	// relative to the 3 parameter vertices before the statement, not 4
+	if !sliceEqualInt([]int{0, 2}, form.Curves2D[1].Params) {
+		t.Errorf("TestFreeFormForwardParams: relative curve: %v", form.Curves2D[1].Params)
+	}
+
+	if _, err := NewObjFromBuf("badParams", []byte("vp 0 0\ncurv2 1 5\n"), NewObjParserOptions(WithStrict(true))); err == nil {
+		t.Errorf("TestFreeFormForwardParams: out of range: unexpected success")
+	}
+}
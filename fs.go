@@ -0,0 +1,47 @@
+package gwob
+
+import (
+	"io"
+	"io/fs"
+	"path"
+)
+
+// NewObjFromFS parses Obj from a file in a virtual filesystem (embed.FS, zip.Reader, os.DirFS, etc).
+func NewObjFromFS(fsys fs.FS, filename string, options *ObjParserOptions) (*Obj, error) {
+
+	input, errOpen := fsys.Open(filename)
+	if errOpen != nil {
+		return nil, errOpen
+	}
+
+	defer input.Close()
+
+	return NewObjFromReader(filename, input, options)
+}
+
+// ReadMaterialLibFromFS parses material lib from a file in a virtual filesystem.
+func ReadMaterialLibFromFS(fsys fs.FS, filename string, options *ObjParserOptions) (MaterialLib, error) {
+
+	input, errOpen := fsys.Open(filename)
+	if errOpen != nil {
+		return NewMaterialLib(), errOpen
+	}
+
+	defer input.Close()
+
+	return ReadMaterialLibFromReader(input, options)
+}
+
+// LoadSceneFS is like LoadScene, but loads the OBJ from a virtual filesystem.
+// Material libs and textures are resolved through the same filesystem,
+// relative to the OBJ path.
+func LoadSceneFS(fsys fs.FS, filename string, options *ObjParserOptions) (*Scene, error) {
+	return loadScene(path.Base(filename), fsOpener(fsys, path.Dir(filename)), options)
+}
+
+// fsOpener resolves names relative to dir within fsys.
+func fsOpener(fsys fs.FS, dir string) opener {
+	return func(name string) (io.ReadCloser, error) {
+		return fsys.Open(path.Clean(path.Join(dir, name)))
+	}
+}
@@ -0,0 +1,48 @@
+package gwob
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadSceneFS(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"models/scene.obj":      {Data: []byte(sceneObj)},
+		"models/scene.mtl":      {Data: []byte(sceneMtl)},
+		"models/textures/a.png": {Data: []byte("png")},
+	}
+
+	options := ObjParserOptions{LogStats: LogStats, Logger: func(msg string) { fmt.Printf("TestLoadSceneFS LoadSceneFS: log: %s\n", msg) }}
+
+	o, err := NewObjFromFS(fsys, "models/scene.obj", &options)
+	if err != nil {
+		t.Errorf("TestLoadSceneFS: NewObjFromFS: %v", err)
+		return
+	}
+	expectInt(t, "TestLoadSceneFS: groups", 2, len(o.Groups))
+
+	lib, errLib := ReadMaterialLibFromFS(fsys, "models/scene.mtl", &options)
+	if errLib != nil {
+		t.Errorf("TestLoadSceneFS: ReadMaterialLibFromFS: %v", errLib)
+		return
+	}
+	expectInt(t, "TestLoadSceneFS: lib size", 1, len(lib.Lib))
+
+	s, errScene := LoadSceneFS(fsys, "models/scene.obj", &options)
+	if errScene != nil {
+		t.Errorf("TestLoadSceneFS: LoadSceneFS: %v", errScene)
+		return
+	}
+	if s.Materials[0] == nil || s.Materials[0].Name != "red" {
+		t.Errorf("TestLoadSceneFS: group 0: want material red got %v", s.Materials[0])
+	}
+
+	tex, errTex := s.OpenTexture("textures/a.png")
+	if errTex != nil {
+		t.Errorf("TestLoadSceneFS: OpenTexture: %v", errTex)
+		return
+	}
+	tex.Close()
+}
@@ -0,0 +1,206 @@
+package gltf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/url"
+	"path/filepath"
+
+	"github.com/udhos/gwob"
+)
+
+// FromObj converts an Obj into a glTF model with a single mesh,
+// holding one triangle primitive per non-empty group. Materials are
+// taken from lib by the group usemtl names; diffuse texture maps are
+// referenced by their MTL names. Texture V coordinates are flipped,
+// since glTF places the texture origin at the top left corner.
+func FromObj(o *gwob.Obj, lib gwob.MaterialLib) (*Model, error) {
+	if o.Coord == nil && o.Positions != nil {
+		c := *o
+		c.Interleave()
+		o = &c
+	}
+
+	m := &Model{
+		Document: Document{
+			Asset: Asset{Version: "2.0", Generator: "gwob"},
+		},
+	}
+	doc := &m.Document
+
+	strides := o.NumberOfElements()
+	attributes := map[string]int{}
+
+	// vertex attributes
+	positions := make([]float32, 0, 3*strides)
+	for s := 0; s < strides; s++ {
+		x, y, z := o.VertexCoordinates(s)
+		positions = append(positions, x, y, z)
+	}
+	attributes["POSITION"] = m.addFloats(positions, "VEC3", 3, true)
+
+	if o.NormCoordFound {
+		normals := make([]float32, 0, 3*strides)
+		for s := 0; s < strides; s++ {
+			n := s*o.StrideSize/4 + o.StrideOffsetNormal/4
+			normals = append(normals, o.Coord[n:n+3]...)
+		}
+		attributes["NORMAL"] = m.addFloats(normals, "VEC3", 3, false)
+	}
+
+	if o.TextCoordFound {
+		texCoords := make([]float32, 0, 2*strides)
+		for s := 0; s < strides; s++ {
+			t := s*o.StrideSize/4 + o.StrideOffsetTexture/4
+			texCoords = append(texCoords, o.Coord[t], 1-o.Coord[t+1])
+		}
+		attributes["TEXCOORD_0"] = m.addFloats(texCoords, "VEC2", 2, false)
+	}
+
+	if o.ColorFound {
+		colors := make([]float32, 0, 3*strides)
+		for s := 0; s < strides; s++ {
+			r, g, b := o.VertexColor(s)
+			colors = append(colors, r, g, b)
+		}
+		attributes["COLOR_0"] = m.addFloats(colors, "VEC3", 3, false)
+	}
+
+	// indices
+	component, size := ComponentUnsignedInt, 4
+	if strides <= math.MaxUint16 {
+		component, size = ComponentUnsignedShort, 2
+	}
+	indexView := m.addView(len(o.Indices)*size, TargetElementArrayBuffer)
+	for _, i := range o.Indices {
+		if size == 2 {
+			m.Buffer = binary.LittleEndian.AppendUint16(m.Buffer, uint16(i))
+		} else {
+			m.Buffer = binary.LittleEndian.AppendUint32(m.Buffer, uint32(i))
+		}
+	}
+	m.align()
+
+	mesh := Mesh{}
+	materials := map[string]int{}
+	images := map[string]int{}
+	for _, g := range o.Groups {
+		if g.IndexCount < 1 {
+			continue
+		}
+		if g.IndexCount%3 != 0 {
+			return nil, fmt.Errorf("gltf.FromObj: group=%s count=%d must be a multiple of 3", g.Name, g.IndexCount)
+		}
+		view := indexView
+		indices := len(doc.Accessors)
+		doc.Accessors = append(doc.Accessors, Accessor{
+			BufferView:    &view,
+			ByteOffset:    g.IndexBegin * size,
+			ComponentType: component,
+			Count:         g.IndexCount,
+			Type:          "SCALAR",
+		})
+		prim := Primitive{Attributes: attributes, Indices: &indices}
+		if mat, found := lib.Lib[g.Usemtl]; found {
+			i, known := materials[mat.Name]
+			if !known {
+				i = len(doc.Materials)
+				doc.Materials = append(doc.Materials, convertMaterial(doc, mat, images))
+				materials[mat.Name] = i
+			}
+			prim.Material = &i
+		}
+		mesh.Primitives = append(mesh.Primitives, prim)
+	}
+
+	if len(mesh.Primitives) > 0 {
+		meshIndex := 0
+		doc.Meshes = []Mesh{mesh}
+		doc.Nodes = []Node{{Mesh: &meshIndex}}
+		doc.Scenes = []Scene{{Nodes: []int{0}}}
+	}
+
+	return m, nil
+}
+
+// addView appends a buffer view for length bytes at the end of the buffer.
+func (m *Model) addView(length, target int) int {
+	doc := &m.Document
+	doc.BufferViews = append(doc.BufferViews, BufferView{
+		ByteOffset: len(m.Buffer),
+		ByteLength: length,
+		Target:     target,
+	})
+	return len(doc.BufferViews) - 1
+}
+
+// addFloats stores a vertex attribute, returning its accessor.
+func (m *Model) addFloats(data []float32, typ string, components int, bounds bool) int {
+	view := m.addView(4*len(data), TargetArrayBuffer)
+	for _, f := range data {
+		m.Buffer = binary.LittleEndian.AppendUint32(m.Buffer, math.Float32bits(f))
+	}
+
+	acc := Accessor{
+		BufferView:    &view,
+		ComponentType: ComponentFloat,
+		Count:         len(data) / components,
+		Type:          typ,
+	}
+	if bounds && len(data) > 0 {
+		acc.Min = append([]float32(nil), data[:components]...)
+		acc.Max = append([]float32(nil), data[:components]...)
+		for i, f := range data {
+			c := i % components
+			acc.Min[c] = min(acc.Min[c], f)
+			acc.Max[c] = max(acc.Max[c], f)
+		}
+	}
+
+	doc := &m.Document
+	doc.Accessors = append(doc.Accessors, acc)
+	return len(doc.Accessors) - 1
+}
+
+// align pads the buffer to 4 bytes, for the next buffer view.
+func (m *Model) align() {
+	for len(m.Buffer)%4 != 0 {
+		m.Buffer = append(m.Buffer, 0)
+	}
+}
+
+// convertMaterial maps MTL parameters to PBR ones:
+// Kd is the base color, d its alpha, and Ns sets the roughness.
+func convertMaterial(doc *Document, mat *gwob.Material, images map[string]int) Material {
+	alpha := mat.D
+	if alpha == 0 {
+		alpha = 1 // d unset
+	}
+	metallic := float32(0)
+	roughness := float32(math.Sqrt(2 / (float64(mat.Ns) + 2)))
+
+	pbr := &PBRMetallicRoughness{
+		BaseColorFactor: &[4]float32{mat.Kd[0], mat.Kd[1], mat.Kd[2], alpha},
+		MetallicFactor:  &metallic,
+		RoughnessFactor: &roughness,
+	}
+
+	if mat.MapKd != "" {
+		img, found := images[mat.MapKd]
+		if !found {
+			img = len(doc.Images)
+			uri := (&url.URL{Path: filepath.ToSlash(mat.MapKd)}).EscapedPath()
+			doc.Images = append(doc.Images, Image{URI: uri})
+			images[mat.MapKd] = img
+		}
+		doc.Textures = append(doc.Textures, Texture{Source: &img})
+		pbr.BaseColorTexture = &TextureInfo{Index: len(doc.Textures) - 1}
+	}
+
+	m := Material{Name: mat.Name, PBRMetallicRoughness: pbr}
+	if alpha < 1 {
+		m.AlphaMode = "BLEND"
+	}
+	return m
+}
@@ -0,0 +1,151 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/udhos/gwob"
+)
+
+var squareObj = `
+mtllib square.mtl
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vt 0 0
+vt 1 0
+vt 1 1
+vt 0 1
+vn 0 0 1
+g red
+usemtl red
+f 1/1/1 2/2/1 3/3/1
+g glass
+usemtl glass
+f 3/3/1 4/4/1 1/1/1
+`
+
+var squareMtl = `
+newmtl red
+Kd 1 0 0
+map_Kd red tile.png
+newmtl glass
+Kd 0 0 1
+d 0.25
+`
+
+func loadSquare(t *testing.T) (*gwob.Obj, gwob.MaterialLib) {
+	options := gwob.NewObjParserOptions()
+	o, err := gwob.NewObjFromBuf("square", []byte(squareObj), options)
+	if err != nil {
+		t.Fatalf("loadSquare: NewObjFromBuf: %v", err)
+	}
+	lib, err := gwob.ReadMaterialLibFromBuf([]byte(squareMtl), options)
+	if err != nil {
+		t.Fatalf("loadSquare: ReadMaterialLibFromBuf: %v", err)
+	}
+	return o, lib
+}
+
+func TestFromObj(t *testing.T) {
+	o, lib := loadSquare(t)
+
+	m, err := FromObj(o, lib)
+	if err != nil {
+		t.Fatalf("TestFromObj: FromObj: %v", err)
+	}
+	doc := m.Document
+
+	if len(doc.Meshes) != 1 || len(doc.Meshes[0].Primitives) != 2 {
+		t.Fatalf("TestFromObj: meshes: %+v", doc.Meshes)
+	}
+	prim := doc.Meshes[0].Primitives[0]
+	for _, attr := range []string{"POSITION", "NORMAL", "TEXCOORD_0"} {
+		if _, found := prim.Attributes[attr]; !found {
+			t.Errorf("TestFromObj: missing attribute %s", attr)
+		}
+	}
+	pos := doc.Accessors[prim.Attributes["POSITION"]]
+	if pos.Count != 4 || pos.Min[0] != 0 || pos.Max[1] != 1 {
+		t.Errorf("TestFromObj: position accessor: %+v", pos)
+	}
+	indices := doc.Accessors[*doc.Meshes[0].Primitives[1].Indices]
+	if indices.Count != 3 || indices.ByteOffset != 6 || indices.ComponentType != ComponentUnsignedShort {
+		t.Errorf("TestFromObj: indices accessor: %+v", indices)
+	}
+
+	if len(doc.Materials) != 2 || doc.Materials[1].AlphaMode != "BLEND" {
+		t.Fatalf("TestFromObj: materials: %+v", doc.Materials)
+	}
+	if c := doc.Materials[0].PBRMetallicRoughness.BaseColorFactor; *c != [4]float32{1, 0, 0, 1} {
+		t.Errorf("TestFromObj: base color: %v", *c)
+	}
+	if len(doc.Images) != 1 || doc.Images[0].URI != "red%20tile.png" {
+		t.Errorf("TestFromObj: images: %+v", doc.Images)
+	}
+}
+
+func TestWriteGLB(t *testing.T) {
+	o, lib := loadSquare(t)
+
+	m, err := FromObj(o, lib)
+	if err != nil {
+		t.Fatalf("TestWriteGLB: FromObj: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if errWrite := m.WriteGLB(&buf); errWrite != nil {
+		t.Fatalf("TestWriteGLB: WriteGLB: %v", errWrite)
+	}
+	glb := buf.Bytes()
+
+	if binary.LittleEndian.Uint32(glb) != glbMagic || int(binary.LittleEndian.Uint32(glb[8:])) != len(glb) {
+		t.Fatalf("TestWriteGLB: bad header: % x", glb[:12])
+	}
+	jsonLen := int(binary.LittleEndian.Uint32(glb[12:]))
+	var doc Document
+	if errJSON := json.Unmarshal(glb[20:20+jsonLen], &doc); errJSON != nil {
+		t.Fatalf("TestWriteGLB: json: %v", errJSON)
+	}
+	if len(doc.Buffers) != 1 || doc.Buffers[0].URI != "" || doc.Buffers[0].ByteLength != len(m.Buffer) {
+		t.Errorf("TestWriteGLB: buffers: %+v", doc.Buffers)
+	}
+	binLen := int(binary.LittleEndian.Uint32(glb[20+jsonLen:]))
+	if binLen%4 != 0 || binLen < len(m.Buffer) {
+		t.Errorf("TestWriteGLB: bin chunk length=%d buffer=%d", binLen, len(m.Buffer))
+	}
+}
+
+func TestSaveGLTF(t *testing.T) {
+	o, lib := loadSquare(t)
+
+	m, err := FromObj(o, lib)
+	if err != nil {
+		t.Fatalf("TestSaveGLTF: FromObj: %v", err)
+	}
+
+	dir := t.TempDir()
+	if errSave := m.SaveGLTF(filepath.Join(dir, "square.gltf")); errSave != nil {
+		t.Fatalf("TestSaveGLTF: SaveGLTF: %v", errSave)
+	}
+	bin, errBin := os.ReadFile(filepath.Join(dir, "square.bin"))
+	if errBin != nil || !bytes.Equal(bin, m.Buffer) {
+		t.Errorf("TestSaveGLTF: bin: %v", errBin)
+	}
+	js, errJSON := os.ReadFile(filepath.Join(dir, "square.gltf"))
+	if errJSON != nil {
+		t.Fatalf("TestSaveGLTF: gltf: %v", errJSON)
+	}
+	var doc Document
+	if errDecode := json.Unmarshal(js, &doc); errDecode != nil {
+		t.Fatalf("TestSaveGLTF: decode: %v", errDecode)
+	}
+	if doc.Buffers[0].URI != "square.bin" {
+		t.Errorf("TestSaveGLTF: buffer uri: %q", doc.Buffers[0].URI)
+	}
+}
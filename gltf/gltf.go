@@ -0,0 +1,244 @@
+/*
+Package gltf converts between gwob OBJ data and glTF 2.0 assets.
+
+Only the subset of glTF needed to carry OBJ geometry and MTL materials
+is modeled: triangle meshes, PBR metallic-roughness materials and
+texture references.
+
+See also: https://registry.khronos.org/glTF/specs/2.0/glTF-2.0.html
+*/
+package gltf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Accessor component types.
+const (
+	ComponentUnsignedByte  = 5121
+	ComponentUnsignedShort = 5123
+	ComponentUnsignedInt   = 5125
+	ComponentFloat         = 5126
+)
+
+// Buffer view targets.
+const (
+	TargetArrayBuffer        = 34962
+	TargetElementArrayBuffer = 34963
+)
+
+// ModeTriangles is the primitive mode for triangle lists, the default.
+const ModeTriangles = 4
+
+// Document is the JSON part of a glTF asset.
+type Document struct {
+	Asset       Asset        `json:"asset"`
+	Scene       int          `json:"scene"`
+	Scenes      []Scene      `json:"scenes,omitempty"`
+	Nodes       []Node       `json:"nodes,omitempty"`
+	Meshes      []Mesh       `json:"meshes,omitempty"`
+	Materials   []Material   `json:"materials,omitempty"`
+	Textures    []Texture    `json:"textures,omitempty"`
+	Images      []Image      `json:"images,omitempty"`
+	Accessors   []Accessor   `json:"accessors,omitempty"`
+	BufferViews []BufferView `json:"bufferViews,omitempty"`
+	Buffers     []Buffer     `json:"buffers,omitempty"`
+}
+
+// Asset holds metadata about the asset.
+type Asset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator,omitempty"`
+}
+
+// Scene lists root nodes.
+type Scene struct {
+	Nodes []int `json:"nodes"`
+}
+
+// Node places a mesh in the scene.
+type Node struct {
+	Name        string      `json:"name,omitempty"`
+	Mesh        *int        `json:"mesh,omitempty"`
+	Children    []int       `json:"children,omitempty"`
+	Matrix      []float32   `json:"matrix,omitempty"`
+	Translation *[3]float32 `json:"translation,omitempty"`
+	Rotation    *[4]float32 `json:"rotation,omitempty"`
+	Scale       *[3]float32 `json:"scale,omitempty"`
+}
+
+// Mesh is a set of primitives.
+type Mesh struct {
+	Name       string      `json:"name,omitempty"`
+	Primitives []Primitive `json:"primitives"`
+}
+
+// Primitive is geometry drawn with a single material.
+type Primitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices,omitempty"`
+	Material   *int           `json:"material,omitempty"`
+	Mode       *int           `json:"mode,omitempty"`
+}
+
+// Material is a PBR metallic-roughness material.
+type Material struct {
+	Name                 string                `json:"name,omitempty"`
+	PBRMetallicRoughness *PBRMetallicRoughness `json:"pbrMetallicRoughness,omitempty"`
+	NormalTexture        *TextureInfo          `json:"normalTexture,omitempty"`
+	EmissiveFactor       *[3]float32           `json:"emissiveFactor,omitempty"`
+	AlphaMode            string                `json:"alphaMode,omitempty"`
+	DoubleSided          bool                  `json:"doubleSided,omitempty"`
+}
+
+// PBRMetallicRoughness holds the base material parameters.
+// Nil factors take the glTF defaults.
+type PBRMetallicRoughness struct {
+	BaseColorFactor  *[4]float32  `json:"baseColorFactor,omitempty"`
+	BaseColorTexture *TextureInfo `json:"baseColorTexture,omitempty"`
+	MetallicFactor   *float32     `json:"metallicFactor,omitempty"`
+	RoughnessFactor  *float32     `json:"roughnessFactor,omitempty"`
+}
+
+// TextureInfo references a texture.
+type TextureInfo struct {
+	Index int `json:"index"`
+}
+
+// Texture references an image.
+type Texture struct {
+	Source *int `json:"source,omitempty"`
+}
+
+// Image is either an external file or data within a buffer view.
+type Image struct {
+	URI        string `json:"uri,omitempty"`
+	MimeType   string `json:"mimeType,omitempty"`
+	BufferView *int   `json:"bufferView,omitempty"`
+}
+
+// Accessor describes typed data within a buffer view.
+type Accessor struct {
+	BufferView    *int      `json:"bufferView,omitempty"`
+	ByteOffset    int       `json:"byteOffset,omitempty"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float32 `json:"min,omitempty"`
+	Max           []float32 `json:"max,omitempty"`
+}
+
+// BufferView is a slice of a buffer.
+type BufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset,omitempty"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride,omitempty"`
+	Target     int `json:"target,omitempty"`
+}
+
+// Buffer is binary data, external or embedded in a GLB file.
+type Buffer struct {
+	ByteLength int    `json:"byteLength"`
+	URI        string `json:"uri,omitempty"`
+}
+
+// Model is a glTF document with its binary buffer.
+// Assets read with more than one buffer keep the others unexported.
+type Model struct {
+	Document Document
+	Buffer   []byte
+
+	buffers [][]byte // buffers after the 1st one
+}
+
+// GLB container constants.
+const (
+	glbMagic     = 0x46546C67 // "glTF"
+	glbVersion   = 2
+	glbChunkJSON = 0x4E4F534A // "JSON"
+	glbChunkBIN  = 0x004E4942 // "BIN\x00"
+)
+
+// WriteGLTF writes the JSON document, referencing the binary
+// buffer by binURI. See WriteBin.
+func (m *Model) WriteGLTF(w io.Writer, binURI string) error {
+	doc := m.Document
+	doc.Buffers = []Buffer{{ByteLength: len(m.Buffer), URI: binURI}}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// WriteBin writes the binary buffer referenced by the JSON document.
+func (m *Model) WriteBin(w io.Writer) error {
+	_, err := w.Write(m.Buffer)
+	return err
+}
+
+// WriteGLB writes the document and the buffer as a single binary file.
+func (m *Model) WriteGLB(w io.Writer) error {
+	doc := m.Document
+	doc.Buffers = []Buffer{{ByteLength: len(m.Buffer)}}
+	js, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	js = pad(js, ' ')
+	bin := pad(m.Buffer, 0)
+
+	var buf bytes.Buffer
+	header := []uint32{glbMagic, glbVersion, uint32(12 + 8 + len(js) + 8 + len(bin))}
+	binary.Write(&buf, binary.LittleEndian, header)
+	binary.Write(&buf, binary.LittleEndian, []uint32{uint32(len(js)), glbChunkJSON})
+	buf.Write(js)
+	binary.Write(&buf, binary.LittleEndian, []uint32{uint32(len(bin)), glbChunkBIN})
+	buf.Write(bin)
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// pad extends data to a multiple of 4 bytes, as required for GLB chunks.
+func pad(data []byte, fill byte) []byte {
+	for len(data)%4 != 0 {
+		data = append(data, fill)
+	}
+	return data
+}
+
+// SaveGLTF writes filename (.gltf) and its buffer beside it,
+// named after filename with the .bin extension.
+func (m *Model) SaveGLTF(filename string) error {
+	binName := strings.TrimSuffix(filename, filepath.Ext(filename)) + ".bin"
+	if err := writeFile(binName, m.WriteBin); err != nil {
+		return err
+	}
+	return writeFile(filename, func(w io.Writer) error {
+		return m.WriteGLTF(w, filepath.Base(binName))
+	})
+}
+
+// SaveGLB writes filename as a single binary file.
+func (m *Model) SaveGLB(filename string) error {
+	return writeFile(filename, m.WriteGLB)
+}
+
+func writeFile(filename string, write func(w io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if errWrite := write(f); errWrite != nil {
+		f.Close()
+		return fmt.Errorf("gltf: %s: %w", filename, errWrite)
+	}
+	return f.Close()
+}
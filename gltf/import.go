@@ -0,0 +1,658 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/udhos/gwob"
+)
+
+// Primitive modes converted into triangles.
+const (
+	modeTriangleStrip = 5
+	modeTriangleFan   = 6
+)
+
+// ReadGLB reads a single file binary glTF asset.
+// External buffers are not supported.
+func ReadGLB(r io.Reader) (*Model, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 20 || binary.LittleEndian.Uint32(data) != glbMagic {
+		return nil, errors.New("gltf.ReadGLB: not a GLB file")
+	}
+	if v := binary.LittleEndian.Uint32(data[4:]); v != glbVersion {
+		return nil, fmt.Errorf("gltf.ReadGLB: unsupported version=%d", v)
+	}
+
+	m := &Model{}
+	var jsonFound bool
+	for chunks := data[12:]; len(chunks) >= 8; {
+		size := int(binary.LittleEndian.Uint32(chunks))
+		kind := binary.LittleEndian.Uint32(chunks[4:])
+		if size > len(chunks)-8 {
+			return nil, fmt.Errorf("gltf.ReadGLB: truncated chunk size=%d", size)
+		}
+		chunk := chunks[8 : 8+size]
+		switch kind {
+		case glbChunkJSON:
+			if errJSON := json.Unmarshal(chunk, &m.Document); errJSON != nil {
+				return nil, fmt.Errorf("gltf.ReadGLB: %w", errJSON)
+			}
+			jsonFound = true
+		case glbChunkBIN:
+			m.Buffer = chunk
+		}
+		chunks = chunks[8+size:]
+	}
+	if !jsonFound {
+		return nil, errors.New("gltf.ReadGLB: missing JSON chunk")
+	}
+
+	for i, b := range m.Document.Buffers {
+		if i > 0 || b.URI != "" {
+			return nil, fmt.Errorf("gltf.ReadGLB: external buffer %d not supported", i)
+		}
+	}
+	return m, nil
+}
+
+// ReadGLTF reads a JSON glTF asset. Buffers are either embedded
+// as data URIs or loaded with open.
+func ReadGLTF(r io.Reader, open func(uri string) (io.ReadCloser, error)) (*Model, error) {
+	m := &Model{}
+	if err := json.NewDecoder(r).Decode(&m.Document); err != nil {
+		return nil, fmt.Errorf("gltf.ReadGLTF: %w", err)
+	}
+
+	for i, b := range m.Document.Buffers {
+		data, err := loadURI(b.URI, open)
+		if err != nil {
+			return nil, fmt.Errorf("gltf.ReadGLTF: buffer %d: %w", i, err)
+		}
+		if len(data) < b.ByteLength {
+			return nil, fmt.Errorf("gltf.ReadGLTF: buffer %d: short length=%d byteLength=%d", i, len(data), b.ByteLength)
+		}
+		if i == 0 {
+			m.Buffer = data
+		} else {
+			m.buffers = append(m.buffers, data)
+		}
+	}
+	return m, nil
+}
+
+func loadURI(uri string, open func(uri string) (io.ReadCloser, error)) ([]byte, error) {
+	if strings.HasPrefix(uri, "data:") {
+		_, payload, found := strings.Cut(uri, ";base64,")
+		if !found {
+			return nil, errors.New("unsupported data uri")
+		}
+		return base64.StdEncoding.DecodeString(payload)
+	}
+	if open == nil {
+		return nil, fmt.Errorf("no opener for uri=%s", uri)
+	}
+	name, err := url.PathUnescape(uri)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// Load reads a .gltf or .glb file, detected by content, resolving
+// external buffers relative to the file location.
+func Load(filename string) (*Model, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if isGLB(data) {
+		return ReadGLB(bytes.NewReader(data))
+	}
+
+	dir := filepath.Dir(filename)
+	return ReadGLTF(bytes.NewReader(data), func(name string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+	})
+}
+
+// bufferData gets buffer i.
+func (m *Model) bufferData(i int) ([]byte, error) {
+	switch {
+	case i == 0:
+		return m.Buffer, nil
+	case i > 0 && i <= len(m.buffers):
+		return m.buffers[i-1], nil
+	}
+	return nil, fmt.Errorf("bad buffer=%d", i)
+}
+
+// componentSize gets the byte size of an accessor component type.
+func componentSize(componentType int) int {
+	switch componentType {
+	case ComponentUnsignedByte:
+		return 1
+	case ComponentUnsignedShort:
+		return 2
+	case ComponentUnsignedInt, ComponentFloat:
+		return 4
+	}
+	return 0
+}
+
+// typeComponents gets the number of components of an accessor type.
+var typeComponents = map[string]int{
+	"SCALAR": 1,
+	"VEC2":   2,
+	"VEC3":   3,
+	"VEC4":   4,
+}
+
+// accessorData gets the bytes of each element of an accessor,
+// honoring the buffer view stride.
+func (m *Model) accessorData(i int) (elements [][]byte, acc Accessor, err error) {
+	doc := &m.Document
+	if i < 0 || i >= len(doc.Accessors) {
+		return nil, acc, fmt.Errorf("bad accessor=%d", i)
+	}
+	acc = doc.Accessors[i]
+	if acc.BufferView == nil {
+		return nil, acc, fmt.Errorf("accessor=%d: missing buffer view", i)
+	}
+	v := *acc.BufferView
+	if v < 0 || v >= len(doc.BufferViews) {
+		return nil, acc, fmt.Errorf("accessor=%d: bad buffer view=%d", i, v)
+	}
+	view := doc.BufferViews[v]
+	data, err := m.bufferData(view.Buffer)
+	if err != nil {
+		return nil, acc, fmt.Errorf("accessor=%d: %w", i, err)
+	}
+	if view.ByteOffset < 0 || view.ByteLength < 0 || view.ByteOffset > len(data) || view.ByteLength > len(data)-view.ByteOffset {
+		return nil, acc, fmt.Errorf("accessor=%d: buffer view=%d out of range", i, v)
+	}
+	data = data[view.ByteOffset : view.ByteOffset+view.ByteLength]
+
+	size := componentSize(acc.ComponentType) * typeComponents[acc.Type]
+	if size == 0 {
+		return nil, acc, fmt.Errorf("accessor=%d: unsupported type=%s component=%d", i, acc.Type, acc.ComponentType)
+	}
+	stride := view.ByteStride
+	if stride == 0 {
+		stride = size
+	}
+	if stride < 0 {
+		return nil, acc, fmt.Errorf("accessor=%d: bad stride=%d", i, stride)
+	}
+	if acc.Count < 0 || acc.ByteOffset < 0 {
+		return nil, acc, fmt.Errorf("accessor=%d: bad count=%d offset=%d", i, acc.Count, acc.ByteOffset)
+	}
+
+	// check the last element up front, dividing to avoid overflow
+	if acc.Count > 0 {
+		room := len(data) - acc.ByteOffset - size
+		if room < 0 || acc.Count-1 > room/stride {
+			return nil, acc, fmt.Errorf("accessor=%d: count=%d out of range", i, acc.Count)
+		}
+	}
+
+	elements = make([][]byte, acc.Count)
+	for e := range elements {
+		begin := acc.ByteOffset + e*stride
+		elements[e] = data[begin : begin+size]
+	}
+	return elements, acc, nil
+}
+
+// readFloats reads an attribute accessor as float32 values, scaling
+// normalized integer components into [0,1], keeping up to want components.
+func (m *Model) readFloats(i, want int) ([]float32, error) {
+	elements, acc, err := m.accessorData(i)
+	if err != nil {
+		return nil, err
+	}
+	n := min(want, typeComponents[acc.Type])
+	size := componentSize(acc.ComponentType)
+	result := make([]float32, 0, len(elements)*want)
+	for _, e := range elements {
+		for c := 0; c < want; c++ {
+			if c >= n {
+				result = append(result, 1) // missing alpha
+				continue
+			}
+			b := e[c*size:]
+			var f float32
+			switch acc.ComponentType {
+			case ComponentFloat:
+				f = math.Float32frombits(binary.LittleEndian.Uint32(b))
+			case ComponentUnsignedByte:
+				f = float32(b[0]) / math.MaxUint8
+			case ComponentUnsignedShort:
+				f = float32(binary.LittleEndian.Uint16(b)) / math.MaxUint16
+			default:
+				return nil, fmt.Errorf("accessor=%d: unsupported component=%d", i, acc.ComponentType)
+			}
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+// readIndices reads an index accessor.
+func (m *Model) readIndices(i int) ([]int, error) {
+	elements, acc, err := m.accessorData(i)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int, len(elements))
+	for e, b := range elements {
+		switch acc.ComponentType {
+		case ComponentUnsignedByte:
+			result[e] = int(b[0])
+		case ComponentUnsignedShort:
+			result[e] = int(binary.LittleEndian.Uint16(b))
+		case ComponentUnsignedInt:
+			result[e] = int(binary.LittleEndian.Uint32(b))
+		default:
+			return nil, fmt.Errorf("accessor=%d: bad index component=%d", i, acc.ComponentType)
+		}
+	}
+	return result, nil
+}
+
+// ToObj converts the triangle meshes of the default scene into an Obj,
+// with one group per primitive, and its materials into a MaterialLib.
+// Node transforms are applied to positions and normals. Primitives
+// other than triangles, strips and fans are skipped. Texture V
+// coordinates are flipped back to the OBJ convention.
+func (m *Model) ToObj() (*gwob.Obj, gwob.MaterialLib, error) {
+	doc := &m.Document
+	lib := gwob.NewMaterialLib()
+	materialNames := make([]string, len(doc.Materials))
+	for i, mat := range doc.Materials {
+		mtl := m.convertMaterial(i, mat)
+		materialNames[i] = mtl.Name
+		lib.Lib[mtl.Name] = mtl
+	}
+
+	var instances []meshInstance
+	roots := m.rootNodes()
+	for _, n := range roots {
+		if err := m.collect(&instances, n, identity(), 0); err != nil {
+			return nil, lib, err
+		}
+	}
+
+	// all strides share the same layout
+	o := &gwob.Obj{}
+	for _, inst := range instances {
+		for _, prim := range doc.Meshes[inst.mesh].Primitives {
+			if !triangles(prim) {
+				continue
+			}
+			_, tex := prim.Attributes["TEXCOORD_0"]
+			_, norm := prim.Attributes["NORMAL"]
+			_, color := prim.Attributes["COLOR_0"]
+			o.TextCoordFound = o.TextCoordFound || tex
+			o.NormCoordFound = o.NormCoordFound || norm
+			o.ColorFound = o.ColorFound || color
+		}
+	}
+
+	vertices := map[vertexSource]int{}
+	for i, inst := range instances {
+		mesh := doc.Meshes[inst.mesh]
+		for p, prim := range mesh.Primitives {
+			if !triangles(prim) {
+				continue
+			}
+			if err := m.appendPrimitive(o, inst, i, prim, materialNames, vertices); err != nil {
+				return nil, lib, fmt.Errorf("gltf.ToObj: mesh=%d primitive=%d: %w", inst.mesh, p, err)
+			}
+		}
+	}
+
+	if o.Positions == nil {
+		o.Positions = []float32{} // empty, but with stride layout
+	}
+	o.Interleave()
+
+	return o, lib, nil
+}
+
+func triangles(prim Primitive) bool {
+	if prim.Mode == nil {
+		return true
+	}
+	switch *prim.Mode {
+	case ModeTriangles, modeTriangleStrip, modeTriangleFan:
+		return true
+	}
+	return false
+}
+
+// meshInstance is a mesh placed in the scene by a node.
+type meshInstance struct {
+	mesh   int
+	name   string
+	matrix [16]float32 // column-major, as in glTF
+}
+
+// maxNodeDepth guards against cyclic node hierarchies.
+const maxNodeDepth = 64
+
+func (m *Model) rootNodes() []int {
+	doc := &m.Document
+	if doc.Scene >= 0 && doc.Scene < len(doc.Scenes) {
+		return doc.Scenes[doc.Scene].Nodes
+	}
+	// no scene: every node not listed as a child is a root
+	child := make([]bool, len(doc.Nodes))
+	for _, n := range doc.Nodes {
+		for _, c := range n.Children {
+			if c >= 0 && c < len(child) {
+				child[c] = true
+			}
+		}
+	}
+	var roots []int
+	for i := range doc.Nodes {
+		if !child[i] {
+			roots = append(roots, i)
+		}
+	}
+	return roots
+}
+
+func (m *Model) collect(instances *[]meshInstance, n int, parent [16]float32, depth int) error {
+	doc := &m.Document
+	if n < 0 || n >= len(doc.Nodes) {
+		return fmt.Errorf("gltf.ToObj: bad node=%d", n)
+	}
+	if depth > maxNodeDepth {
+		return fmt.Errorf("gltf.ToObj: node=%d: hierarchy too deep", n)
+	}
+	node := doc.Nodes[n]
+	matrix := multiply(parent, nodeMatrix(node))
+	if node.Mesh != nil {
+		mesh := *node.Mesh
+		if mesh < 0 || mesh >= len(doc.Meshes) {
+			return fmt.Errorf("gltf.ToObj: node=%d: bad mesh=%d", n, mesh)
+		}
+		name := doc.Meshes[mesh].Name
+		if name == "" {
+			name = node.Name
+		}
+		*instances = append(*instances, meshInstance{mesh: mesh, name: name, matrix: matrix})
+	}
+	for _, c := range node.Children {
+		if err := m.collect(instances, c, matrix, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vertexSource identifies the vertices of a mesh instance by their
+// attribute accessors, -1 when absent, so that primitives sharing
+// accessors also share the converted vertices.
+type vertexSource struct {
+	instance                     int
+	position, normal, tex, color int
+}
+
+func accessorID(prim Primitive, name string) int {
+	if a, found := prim.Attributes[name]; found {
+		return a
+	}
+	return -1
+}
+
+// appendPrimitive appends the triangles of prim as a new group.
+func (m *Model) appendPrimitive(o *gwob.Obj, inst meshInstance, instance int, prim Primitive, materialNames []string, vertices map[vertexSource]int) error {
+	posAccessor, found := prim.Attributes["POSITION"]
+	if !found {
+		return errors.New("missing POSITION")
+	}
+	if posAccessor < 0 || posAccessor >= len(m.Document.Accessors) {
+		return fmt.Errorf("bad POSITION accessor=%d", posAccessor)
+	}
+	count := m.Document.Accessors[posAccessor].Count
+
+	source := vertexSource{
+		instance: instance,
+		position: posAccessor,
+		normal:   accessorID(prim, "NORMAL"),
+		tex:      accessorID(prim, "TEXCOORD_0"),
+		color:    accessorID(prim, "COLOR_0"),
+	}
+	base, found := vertices[source]
+	if !found {
+		base = len(o.Positions) / 3
+		if err := m.appendVertices(o, inst, prim, posAccessor); err != nil {
+			return err
+		}
+		vertices[source] = base
+	}
+
+	var indices []int
+	var err error
+	if prim.Indices != nil {
+		if indices, err = m.readIndices(*prim.Indices); err != nil {
+			return err
+		}
+	} else {
+		indices = make([]int, count)
+		for i := range indices {
+			indices[i] = i
+		}
+	}
+	mode := ModeTriangles
+	if prim.Mode != nil {
+		mode = *prim.Mode
+	}
+
+	g := &gwob.Group{Name: inst.name, IndexBegin: len(o.Indices)}
+	if prim.Material != nil && *prim.Material >= 0 && *prim.Material < len(materialNames) {
+		g.Usemtl = materialNames[*prim.Material]
+	}
+
+	mirror := determinant(inst.matrix) < 0
+
+	push := func(a, b, c int) error {
+		if mirror {
+			b, c = c, b
+		}
+		for _, i := range []int{a, b, c} {
+			if i < 0 || i >= count {
+				return fmt.Errorf("index=%d out of range count=%d", i, count)
+			}
+			if base+i > math.MaxUint16 {
+				o.BigIndexFound = true
+			}
+			o.Indices = append(o.Indices, base+i)
+		}
+		g.IndexCount += 3
+		return nil
+	}
+
+	switch mode {
+	case ModeTriangles:
+		for i := 0; i+2 < len(indices); i += 3 {
+			if err := push(indices[i], indices[i+1], indices[i+2]); err != nil {
+				return err
+			}
+		}
+	case modeTriangleStrip:
+		for i := 0; i+2 < len(indices); i++ {
+			a, b := indices[i], indices[i+1]
+			if i%2 == 1 {
+				a, b = b, a // keep winding
+			}
+			if err := push(a, b, indices[i+2]); err != nil {
+				return err
+			}
+		}
+	case modeTriangleFan:
+		for i := 1; i+1 < len(indices); i++ {
+			if err := push(indices[0], indices[i], indices[i+1]); err != nil {
+				return err
+			}
+		}
+	}
+
+	o.Groups = append(o.Groups, g)
+	return nil
+}
+
+// appendVertices converts the vertex attributes of prim into o, with
+// the instance transform applied. Attributes found in any primitive are
+// filled in for every vertex, so that the arrays of o stay aligned.
+func (m *Model) appendVertices(o *gwob.Obj, inst meshInstance, prim Primitive, posAccessor int) error {
+	positions, err := m.readFloats(posAccessor, 3)
+	if err != nil {
+		return err
+	}
+	count := len(positions) / 3
+
+	for v := 0; v < count; v++ {
+		x, y, z := transformPoint(inst.matrix, positions[3*v:3*v+3])
+		o.Positions = append(o.Positions, x, y, z)
+	}
+
+	if o.NormCoordFound {
+		normals, errNorm := m.attribute(prim, "NORMAL", 3, count, []float32{0, 0, 1})
+		if errNorm != nil {
+			return errNorm
+		}
+		for v := 0; v < count; v++ {
+			x, y, z := transformNormal(inst.matrix, normals[3*v:3*v+3])
+			o.Normals = append(o.Normals, x, y, z)
+		}
+	}
+
+	if o.TextCoordFound {
+		texCoords, errTex := m.attribute(prim, "TEXCOORD_0", 2, count, []float32{0, 1})
+		if errTex != nil {
+			return errTex
+		}
+		for v := 0; v < count; v++ {
+			o.TexCoords = append(o.TexCoords, texCoords[2*v], 1-texCoords[2*v+1])
+		}
+	}
+
+	if o.ColorFound {
+		colors, errColor := m.attribute(prim, "COLOR_0", 3, count, []float32{1, 1, 1})
+		if errColor != nil {
+			return errColor
+		}
+		o.Colors = append(o.Colors, colors...)
+	}
+
+	return nil
+}
+
+// attribute reads an optional attribute, filling missing ones with a default.
+func (m *Model) attribute(prim Primitive, name string, components, count int, fill []float32) ([]float32, error) {
+	i, found := prim.Attributes[name]
+	if !found {
+		data := make([]float32, 0, components*count)
+		for v := 0; v < count; v++ {
+			data = append(data, fill...)
+		}
+		return data, nil
+	}
+	data, err := m.readFloats(i, components)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != components*count {
+		return nil, fmt.Errorf("attribute %s: count=%d want=%d", name, len(data)/components, count)
+	}
+	return data, nil
+}
+
+// convertMaterial maps PBR parameters back to MTL ones,
+// inverting the conversion done by FromObj.
+func (m *Model) convertMaterial(i int, mat Material) *gwob.Material {
+	name := mat.Name
+	if name == "" {
+		name = fmt.Sprintf("material%d", i)
+	}
+	mtl := &gwob.Material{Name: name, Kd: [3]float32{1, 1, 1}, D: 1, Illum: 2}
+
+	pbr := mat.PBRMetallicRoughness
+	if pbr == nil {
+		return mtl
+	}
+	if c := pbr.BaseColorFactor; c != nil {
+		mtl.Kd = [3]float32{c[0], c[1], c[2]}
+		mtl.D = c[3]
+	}
+	roughness := float32(1)
+	if pbr.RoughnessFactor != nil {
+		roughness = *pbr.RoughnessFactor
+	}
+	if roughness > 0 {
+		mtl.Ns = 2/(roughness*roughness) - 2
+	} else {
+		mtl.Ns = 1000
+	}
+	if t := pbr.BaseColorTexture; t != nil {
+		mtl.MapKd = m.imageName(t.Index)
+	}
+	return mtl
+}
+
+// imageName gets the file name of a texture, empty for embedded images.
+func (m *Model) imageName(texture int) string {
+	doc := &m.Document
+	if texture < 0 || texture >= len(doc.Textures) || doc.Textures[texture].Source == nil {
+		return ""
+	}
+	img := *doc.Textures[texture].Source
+	if img < 0 || img >= len(doc.Images) {
+		return ""
+	}
+	uri := doc.Images[img].URI
+	if uri == "" || strings.HasPrefix(uri, "data:") {
+		return ""
+	}
+	if name, err := url.PathUnescape(uri); err == nil {
+		return name
+	}
+	return uri
+}
+
+// ReadFile is a convenience for Load followed by ToObj.
+func ReadFile(filename string) (*gwob.Obj, gwob.MaterialLib, error) {
+	m, err := Load(filename)
+	if err != nil {
+		return nil, gwob.NewMaterialLib(), err
+	}
+	return m.ToObj()
+}
+
+// isGLB checks the binary container magic.
+func isGLB(data []byte) bool {
+	return len(data) >= 4 && bytes.Equal(data[:4], []byte("glTF"))
+}
@@ -0,0 +1,137 @@
+package gltf
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/udhos/gwob"
+)
+
+func TestRoundTrip(t *testing.T) {
+	o, lib := loadSquare(t)
+
+	m, err := FromObj(o, lib)
+	if err != nil {
+		t.Fatalf("TestRoundTrip: FromObj: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if errWrite := m.WriteGLB(&buf); errWrite != nil {
+		t.Fatalf("TestRoundTrip: WriteGLB: %v", errWrite)
+	}
+	glb, err := ReadGLB(&buf)
+	if err != nil {
+		t.Fatalf("TestRoundTrip: ReadGLB: %v", err)
+	}
+
+	dir := t.TempDir()
+	if errSave := m.SaveGLTF(filepath.Join(dir, "square.gltf")); errSave != nil {
+		t.Fatalf("TestRoundTrip: SaveGLTF: %v", errSave)
+	}
+	js, err := Load(filepath.Join(dir, "square.gltf"))
+	if err != nil {
+		t.Fatalf("TestRoundTrip: Load: %v", err)
+	}
+
+	for name, model := range map[string]*Model{"glb": glb, "gltf": js} {
+		o2, lib2, err := model.ToObj()
+		if err != nil {
+			t.Fatalf("TestRoundTrip: %s: ToObj: %v", name, err)
+		}
+		if len(o2.Indices) != len(o.Indices) {
+			t.Fatalf("TestRoundTrip: %s: indices: want=%v got=%v", name, o.Indices, o2.Indices)
+		}
+		if len(o2.Coord) != len(o.Coord) {
+			t.Fatalf("TestRoundTrip: %s: coord: want=%v got=%v", name, o.Coord, o2.Coord)
+		}
+		for i := range o.Coord {
+			if d := o.Coord[i] - o2.Coord[i]; d < -1e-6 || d > 1e-6 {
+				t.Errorf("TestRoundTrip: %s: coord %d: want=%v got=%v", name, i, o.Coord[i], o2.Coord[i])
+			}
+		}
+		if len(o2.Groups) != 2 || o2.Groups[1].Usemtl != "glass" {
+			t.Errorf("TestRoundTrip: %s: groups: %v", name, o2.Groups)
+		}
+		glass := lib2.Lib["glass"]
+		if glass == nil || glass.D != 0.25 || glass.Kd != [3]float32{0, 0, 1} {
+			t.Errorf("TestRoundTrip: %s: glass: %+v", name, glass)
+		}
+		if red := lib2.Lib["red"]; red == nil || red.MapKd != "red tile.png" {
+			t.Errorf("TestRoundTrip: %s: red: %+v", name, red)
+		}
+	}
+}
+
+func TestToObjNodes(t *testing.T) {
+	// a single triangle strip of 4 vertices, instanced twice:
+	// translated by +10 x, and mirrored on x
+	o, _ := loadSquare(t)
+	m, err := FromObj(o, gwob.NewMaterialLib())
+	if err != nil {
+		t.Fatalf("TestToObjNodes: FromObj: %v", err)
+	}
+	doc := &m.Document
+
+	strip := modeTriangleStrip
+	doc.Meshes[0].Primitives = []Primitive{{Attributes: map[string]int{"POSITION": 0}, Mode: &strip}}
+	mesh := 0
+	doc.Nodes = []Node{
+		{Children: []int{1, 2}},
+		{Name: "moved", Mesh: &mesh, Translation: &[3]float32{10, 0, 0}},
+		{Name: "mirrored", Mesh: &mesh, Scale: &[3]float32{-1, 1, 1}},
+	}
+
+	o2, _, err := m.ToObj()
+	if err != nil {
+		t.Fatalf("TestToObjNodes: ToObj: %v", err)
+	}
+	if len(o2.Groups) != 2 || o2.Groups[0].Name != "moved" || o2.Groups[1].Name != "mirrored" {
+		t.Fatalf("TestToObjNodes: groups: %v", o2.Groups)
+	}
+	// strip 0 1 2 3 => 0 1 2, 2 1 3; mirrored reverses winding
+	want := []int{0, 1, 2, 2, 1, 3, 4, 6, 5, 6, 7, 5}
+	if len(o2.Indices) != len(want) {
+		t.Fatalf("TestToObjNodes: indices: want=%v got=%v", want, o2.Indices)
+	}
+	for i := range want {
+		if want[i] != o2.Indices[i] {
+			t.Fatalf("TestToObjNodes: indices: want=%v got=%v", want, o2.Indices)
+		}
+	}
+	if x, _, _ := o2.VertexCoordinates(1); x != 11 {
+		t.Errorf("TestToObjNodes: moved x: want=11 got=%v", x)
+	}
+	if x, _, _ := o2.VertexCoordinates(5); x != -1 {
+		t.Errorf("TestToObjNodes: mirrored x: want=-1 got=%v", x)
+	}
+}
+
+func TestAccessorDataBad(t *testing.T) {
+	view := 0
+	table := []struct {
+		name     string
+		view     BufferView
+		accessor Accessor
+	}{
+		{"negative count", BufferView{ByteLength: 12}, Accessor{Count: -1}},
+		{"huge count", BufferView{ByteLength: 12}, Accessor{Count: 1 << 62}},
+		{"negative view offset", BufferView{ByteOffset: -4, ByteLength: 12}, Accessor{Count: 1}},
+		{"negative view length", BufferView{ByteLength: -4}, Accessor{Count: 1}},
+		{"view overflow", BufferView{ByteOffset: 4, ByteLength: int(^uint(0) >> 1)}, Accessor{Count: 1}},
+		{"negative accessor offset", BufferView{ByteLength: 12}, Accessor{ByteOffset: -4, Count: 1}},
+		{"negative stride", BufferView{ByteLength: 12, ByteStride: -12}, Accessor{Count: 1}},
+	}
+	for _, data := range table {
+		acc := data.accessor
+		acc.BufferView = &view
+		acc.ComponentType = ComponentFloat
+		acc.Type = "VEC3"
+		m := &Model{Buffer: make([]byte, 12)}
+		m.Document.BufferViews = []BufferView{data.view}
+		m.Document.Accessors = []Accessor{acc}
+		if _, _, err := m.accessorData(0); err == nil {
+			t.Errorf("TestAccessorDataBad: %s: unexpected success", data.name)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package gltf
+
+import (
+	"math"
+)
+
+// Matrices are 4x4, column-major, as in glTF.
+
+func identity() [16]float32 {
+	return [16]float32{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
+}
+
+func multiply(a, b [16]float32) [16]float32 {
+	var m [16]float32
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			var sum float32
+			for k := 0; k < 4; k++ {
+				sum += a[k*4+row] * b[col*4+k]
+			}
+			m[col*4+row] = sum
+		}
+	}
+	return m
+}
+
+// nodeMatrix gets the local transform of a node,
+// either its matrix or its translation, rotation and scale.
+func nodeMatrix(n Node) [16]float32 {
+	if len(n.Matrix) == 16 {
+		var m [16]float32
+		copy(m[:], n.Matrix)
+		return m
+	}
+
+	m := identity()
+	if r := n.Rotation; r != nil {
+		x, y, z, w := r[0], r[1], r[2], r[3] // unit quaternion
+		m = [16]float32{
+			1 - 2*(y*y+z*z), 2 * (x*y + z*w), 2 * (x*z - y*w), 0,
+			2 * (x*y - z*w), 1 - 2*(x*x+z*z), 2 * (y*z + x*w), 0,
+			2 * (x*z + y*w), 2 * (y*z - x*w), 1 - 2*(x*x+y*y), 0,
+			0, 0, 0, 1,
+		}
+	}
+	if s := n.Scale; s != nil {
+		for col := 0; col < 3; col++ {
+			for row := 0; row < 3; row++ {
+				m[col*4+row] *= s[col]
+			}
+		}
+	}
+	if t := n.Translation; t != nil {
+		m[12], m[13], m[14] = t[0], t[1], t[2]
+	}
+	return m
+}
+
+func transformPoint(m [16]float32, p []float32) (float32, float32, float32) {
+	x, y, z := p[0], p[1], p[2]
+	return m[0]*x + m[4]*y + m[8]*z + m[12],
+		m[1]*x + m[5]*y + m[9]*z + m[13],
+		m[2]*x + m[6]*y + m[10]*z + m[14]
+}
+
+// transformNormal applies the inverse transpose of the linear part,
+// computed as the cofactor matrix, and renormalizes.
+func transformNormal(m [16]float32, n []float32) (float32, float32, float32) {
+	a, b, c := m[0], m[4], m[8]
+	d, e, f := m[1], m[5], m[9]
+	g, h, i := m[2], m[6], m[10]
+
+	// cofactors
+	c00, c01, c02 := e*i-f*h, f*g-d*i, d*h-e*g
+	c10, c11, c12 := c*h-b*i, a*i-c*g, b*g-a*h
+	c20, c21, c22 := b*f-c*e, c*d-a*f, a*e-b*d
+
+	x := c00*n[0] + c01*n[1] + c02*n[2]
+	y := c10*n[0] + c11*n[1] + c12*n[2]
+	z := c20*n[0] + c21*n[1] + c22*n[2]
+
+	if determinant(m) < 0 {
+		x, y, z = -x, -y, -z
+	}
+	length := float32(math.Sqrt(float64(x*x + y*y + z*z)))
+	if length == 0 {
+		return n[0], n[1], n[2]
+	}
+	return x / length, y / length, z / length
+}
+
+// determinant of the linear part. Negative means mirroring,
+// which reverses the triangle winding.
+func determinant(m [16]float32) float32 {
+	return m[0]*(m[5]*m[10]-m[9]*m[6]) -
+		m[4]*(m[1]*m[10]-m[9]*m[2]) +
+		m[8]*(m[1]*m[6]-m[5]*m[2])
+}
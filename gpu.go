@@ -0,0 +1,142 @@
+package gwob
+
+import (
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// Vertex attribute names used by VertexLayout.
+const (
+	AttributePosition = "position" // (x,y,z)
+	AttributeTexCoord = "texcoord" // (tu,tv)
+	AttributeNormal   = "normal"   // (nx,ny,nz)
+	AttributeColor    = "color"    // (r,g,b)
+)
+
+// VertexAttribute describes one attribute within the interleaved stride.
+// Components are 4-byte floats in native byte order.
+type VertexAttribute struct {
+	Name       string // one of the Attribute* names
+	Offset     int    // byte offset within the stride
+	Components int    // number of float32 components
+}
+
+// VertexLayout describes the interleaved Coord buffer, as needed
+// by glVertexAttribPointer or a wgpu vertex buffer layout.
+type VertexLayout struct {
+	Stride     int // bytes per vertex, same as StrideSize
+	Attributes []VertexAttribute
+}
+
+// Attribute finds an attribute by name.
+func (l VertexLayout) Attribute(name string) (VertexAttribute, bool) {
+	for _, a := range l.Attributes {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return VertexAttribute{}, false
+}
+
+// VertexLayout gets the layout of the interleaved Coord buffer.
+// Colors are listed only when interleaved (see InterleaveColors).
+func (o *Obj) VertexLayout() VertexLayout {
+	l := VertexLayout{
+		Stride: o.StrideSize,
+		Attributes: []VertexAttribute{
+			{Name: AttributePosition, Offset: o.StrideOffsetPosition, Components: 3},
+		},
+	}
+	if o.TextCoordFound {
+		l.Attributes = append(l.Attributes, VertexAttribute{Name: AttributeTexCoord, Offset: o.StrideOffsetTexture, Components: 2})
+	}
+	if o.NormCoordFound {
+		l.Attributes = append(l.Attributes, VertexAttribute{Name: AttributeNormal, Offset: o.StrideOffsetNormal, Components: 3})
+	}
+	if o.StrideOffsetColor != 0 {
+		l.Attributes = append(l.Attributes, VertexAttribute{Name: AttributeColor, Offset: o.StrideOffsetColor, Components: 3})
+	}
+	return l
+}
+
+// VertexBufferBytes gets the interleaved Coord buffer as bytes, in
+// native byte order, ready for glBufferData or a wgpu buffer write.
+// The result shares memory with Coord: it is not a copy, and it is
+// only valid while Coord is not reassigned. It is nil when Coord is
+// empty, including for the NonInterleaved layout (see Interleave).
+func (o *Obj) VertexBufferBytes() []byte {
+	if len(o.Coord) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&o.Coord[0])), 4*len(o.Coord))
+}
+
+// OpenGL element types for glDrawElements, as returned by IndexType.
+const (
+	GLUnsignedShort = 0x1403 // GL_UNSIGNED_SHORT
+	GLUnsignedInt   = 0x1405 // GL_UNSIGNED_INT
+)
+
+// bigIndices reports whether some index does not fit 16 bits.
+// Indices are checked too, since they may have been changed after
+// BigIndexFound was set by the parser.
+func (o *Obj) bigIndices() bool {
+	if o.BigIndexFound {
+		return true
+	}
+	for _, i := range o.Indices {
+		if i > math.MaxUint16 {
+			return true
+		}
+	}
+	return false
+}
+
+// Indices16 converts Indices to 16-bit indices,
+// failing if some index is larger than 65535.
+func (o *Obj) Indices16() ([]uint16, error) {
+	indices := make([]uint16, len(o.Indices))
+	for n, i := range o.Indices {
+		if i < 0 || i > math.MaxUint16 {
+			return nil, fmt.Errorf("Indices16: index=%d does not fit 16 bits", i)
+		}
+		indices[n] = uint16(i)
+	}
+	return indices, nil
+}
+
+// Indices32 converts Indices to 32-bit indices.
+func (o *Obj) Indices32() []uint32 {
+	indices := make([]uint32, len(o.Indices))
+	for n, i := range o.Indices {
+		indices[n] = uint32(i)
+	}
+	return indices
+}
+
+// IndexType gets the OpenGL element type suited to Indices:
+// GLUnsignedShort when all of them fit 16 bits, else GLUnsignedInt.
+func (o *Obj) IndexType() uint32 {
+	if o.bigIndices() {
+		return GLUnsignedInt
+	}
+	return GLUnsignedShort
+}
+
+// IndexBufferBytes converts Indices to the narrowest width, in native
+// byte order, returning the bytes and their element type (see IndexType).
+func (o *Obj) IndexBufferBytes() ([]byte, uint32) {
+	if o.bigIndices() {
+		indices := o.Indices32()
+		if len(indices) == 0 {
+			return nil, GLUnsignedInt
+		}
+		return unsafe.Slice((*byte)(unsafe.Pointer(&indices[0])), 4*len(indices)), GLUnsignedInt
+	}
+	indices, _ := o.Indices16() // cannot fail
+	if len(indices) == 0 {
+		return nil, GLUnsignedShort
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&indices[0])), 2*len(indices)), GLUnsignedShort
+}
@@ -0,0 +1,79 @@
+package gwob
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestVertexBufferBytes(t *testing.T) {
+	o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestVertexBufferBytes: NewObjFromBuf: %v", err)
+	}
+
+	b := o.VertexBufferBytes()
+	expectInt(t, "TestVertexBufferBytes: size", 4*len(o.Coord), len(b))
+	for i, f := range o.Coord {
+		got := math.Float32frombits(binary.NativeEndian.Uint32(b[4*i:]))
+		if got != f {
+			t.Fatalf("TestVertexBufferBytes: float %d: want=%v got=%v", i, f, got)
+		}
+	}
+
+	// shared memory
+	o.Coord[0] = 42
+	if math.Float32frombits(binary.NativeEndian.Uint32(b)) != 42 {
+		t.Errorf("TestVertexBufferBytes: buffer is a copy")
+	}
+
+	l := o.VertexLayout()
+	expectInt(t, "TestVertexBufferBytes: stride", o.StrideSize, l.Stride)
+	for _, want := range []VertexAttribute{
+		{Name: AttributePosition, Offset: 0, Components: 3},
+		{Name: AttributeTexCoord, Offset: 12, Components: 2},
+		{Name: AttributeNormal, Offset: 20, Components: 3},
+	} {
+		if a, found := l.Attribute(want.Name); !found || a != want {
+			t.Errorf("TestVertexBufferBytes: attribute: want=%+v got=%+v", want, a)
+		}
+	}
+	if _, found := l.Attribute(AttributeColor); found {
+		t.Errorf("TestVertexBufferBytes: unexpected color attribute")
+	}
+
+	if (&Obj{}).VertexBufferBytes() != nil {
+		t.Errorf("TestVertexBufferBytes: empty Obj should have nil buffer")
+	}
+}
+
+func TestIndexBuffer(t *testing.T) {
+	o := &Obj{Indices: []int{0, 1, 2, 2, 3, 0}}
+
+	indices16, err := o.Indices16()
+	if err != nil {
+		t.Fatalf("TestIndexBuffer: Indices16: %v", err)
+	}
+	if len(indices16) != 6 || indices16[3] != 2 {
+		t.Errorf("TestIndexBuffer: Indices16: %v", indices16)
+	}
+	expectInt(t, "TestIndexBuffer: type", GLUnsignedShort, int(o.IndexType()))
+	b, typ := o.IndexBufferBytes()
+	expectInt(t, "TestIndexBuffer: bytes", 12, len(b))
+	expectInt(t, "TestIndexBuffer: bytes type", GLUnsignedShort, int(typ))
+	expectInt(t, "TestIndexBuffer: 4th index", 2, int(binary.NativeEndian.Uint16(b[6:])))
+
+	o.Indices[4] = 70000 // BigIndexFound not updated
+	if _, err := o.Indices16(); err == nil {
+		t.Errorf("TestIndexBuffer: Indices16: expected error for big index")
+	}
+	expectInt(t, "TestIndexBuffer: big type", GLUnsignedInt, int(o.IndexType()))
+	indices32 := o.Indices32()
+	if len(indices32) != 6 || indices32[4] != 70000 {
+		t.Errorf("TestIndexBuffer: Indices32: %v", indices32)
+	}
+	b, typ = o.IndexBufferBytes()
+	expectInt(t, "TestIndexBuffer: big bytes", 24, len(b))
+	expectInt(t, "TestIndexBuffer: big bytes type", GLUnsignedInt, int(typ))
+	expectInt(t, "TestIndexBuffer: 5th index", 70000, int(binary.NativeEndian.Uint32(b[16:])))
+}
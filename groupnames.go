@@ -0,0 +1,36 @@
+package gwob
+
+import (
+	"strings"
+)
+
+// groupNames splits the names listed by a g statement.
+// An o statement names a single object, which may contain spaces.
+func groupNames(statement byte, name string) []string {
+	if statement == 'o' {
+		return []string{name}
+	}
+	return strings.Fields(name)
+}
+
+// InGroup reports whether the group belongs to the named group,
+// which may be any of the names listed by its g statement.
+func (g *Group) InGroup(name string) bool {
+	for _, n := range g.Names {
+		if n == name {
+			return true
+		}
+	}
+	return g.Name == name
+}
+
+// GroupsNamed gets the groups belonging to the named group.
+func (o *Obj) GroupsNamed(name string) []*Group {
+	var groups []*Group
+	for _, g := range o.Groups {
+		if g.InGroup(name) {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
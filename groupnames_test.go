@@ -0,0 +1,46 @@
+package gwob
+
+import (
+	"testing"
+)
+
+var groupNamesObj = `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+g body wheels lod0
+f 1 2 3
+s 1
+f 1 2 3
+g wheels
+f 1 2 3
+o my car
+f 1 2 3
+`
+
+func TestGroupNames(t *testing.T) {
+
+	o, err := NewObjFromBuf("groupNamesObj", []byte(groupNamesObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestGroupNames: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestGroupNames: groups", 4, len(o.Groups))
+
+	want := []string{"body", "wheels", "lod0"}
+	for _, g := range o.Groups[:2] {
+		if len(g.Names) != len(want) {
+			t.Errorf("TestGroupNames: names: want=%v got=%v", want, g.Names)
+			continue
+		}
+		for i := range want {
+			if g.Names[i] != want[i] {
+				t.Errorf("TestGroupNames: names: want=%v got=%v", want, g.Names)
+			}
+		}
+	}
+
+	expectInt(t, "TestGroupNames: body", 2, len(o.GroupsNamed("body")))
+	expectInt(t, "TestGroupNames: wheels", 3, len(o.GroupsNamed("wheels")))
+	expectInt(t, "TestGroupNames: object", 1, len(o.GroupsNamed("my car")))
+	expectInt(t, "TestGroupNames: car", 0, len(o.GroupsNamed("car")))
+}
@@ -0,0 +1,37 @@
+package gwob
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// isGzip checks for gzip magic bytes.
+func isGzip(buf []byte) bool {
+	return bytes.HasPrefix(buf, gzipMagic)
+}
+
+// newBufReader wraps the input into a buffered reader,
+// transparently decompressing gzip streams.
+func newBufReader(rd io.Reader) (*bufio.Reader, bool, error) {
+	return gunzipBufReader(bufio.NewReader(rd))
+}
+
+// gunzipBufReader inserts gzip decompression when the buffered input is compressed.
+func gunzipBufReader(br *bufio.Reader) (*bufio.Reader, bool, error) {
+	magic, _ := br.Peek(len(gzipMagic))
+	if !isGzip(magic) {
+		return br, false, nil
+	}
+
+	gz, errGzip := gzip.NewReader(br)
+	if errGzip != nil {
+		return nil, true, fmt.Errorf("newBufReader: gzip: %v", errGzip)
+	}
+
+	return bufio.NewReader(gz), true, nil
+}
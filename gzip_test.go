@@ -0,0 +1,55 @@
+package gwob
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func gzipBuf(data string) []byte {
+	buf := bytes.Buffer{}
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(data))
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestGzip(t *testing.T) {
+
+	options := ObjParserOptions{LogStats: LogStats, Logger: func(msg string) { fmt.Printf("TestGzip: log: %s\n", msg) }}
+
+	compressed := gzipBuf(cubeObj)
+
+	o, err := NewObjFromBuf("cube.obj.gz", compressed, &options)
+	if err != nil {
+		t.Errorf("TestGzip: NewObjFromBuf: %v", err)
+		return
+	}
+	if !sliceEqualInt(cubeIndices, o.Indices) {
+		t.Errorf("TestGzip: indices: want=%v got=%v", cubeIndices, o.Indices)
+	}
+
+	filename := filepath.Join(t.TempDir(), "cube.obj.gz")
+	if errWrite := os.WriteFile(filename, compressed, 0o644); errWrite != nil {
+		t.Errorf("TestGzip: write: %v", errWrite)
+		return
+	}
+	o, err = NewObjFromFile(filename, &options)
+	if err != nil {
+		t.Errorf("TestGzip: NewObjFromFile: %v", err)
+		return
+	}
+	if !sliceEqualFloat(cubeCoord, o.Coord) {
+		t.Errorf("TestGzip: coord: want=%v got=%v", cubeCoord, o.Coord)
+	}
+
+	lib, errLib := ReadMaterialLibFromReader(bytes.NewReader(gzipBuf(sceneMtl)), &options)
+	if errLib != nil {
+		t.Errorf("TestGzip: ReadMaterialLibFromReader: %v", errLib)
+		return
+	}
+	expectInt(t, "TestGzip: lib size", 1, len(lib.Lib))
+}
@@ -0,0 +1,152 @@
+package gwob
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"math"
+	"slices"
+)
+
+// hashQuantum is the precision of attribute values in Hash: values
+// closer than it usually hash alike.
+const hashQuantum = 1e-5
+
+// hasher feeds quantized values into a hash.
+type hasher struct {
+	h   hash.Hash
+	buf []byte
+}
+
+func newHasher() *hasher {
+	return &hasher{h: sha256.New()}
+}
+
+func (hs *hasher) int(i int64) {
+	hs.buf = binary.LittleEndian.AppendUint64(hs.buf[:0], uint64(i))
+	hs.h.Write(hs.buf)
+}
+
+func (hs *hasher) float(f float32) {
+	hs.int(int64(math.Round(float64(f) / hashQuantum)))
+}
+
+func (hs *hasher) string(s string) {
+	hs.int(int64(len(s)))
+	hs.h.Write([]byte(s))
+}
+
+func (hs *hasher) sum() []byte {
+	return hs.h.Sum(nil)
+}
+
+// Hash computes a content fingerprint of the mesh: meshes with the same
+// groups, holding the same triangles, points and lines, with the same
+// vertex attributes and materials, hash alike, regardless of group and
+// triangle order, vertex numbering and layout. Attribute values are
+// quantized to 1e-5, so tiny numerical noise usually does not change
+// the hash. Comments, faces, raw data and diagnostics are ignored.
+func (o *Obj) Hash() [sha256.Size]byte {
+	triangles := len(o.Indices) / 3
+	smoothing := o.triangleSmoothing()
+
+	// vertex hashes the attributes of stride s
+	vertex := func(hs *hasher, s int) {
+		x, y, z := o.VertexCoordinates(s)
+		hs.float(x)
+		hs.float(y)
+		hs.float(z)
+		if s < len(o.W) {
+			hs.float(o.W[s])
+		}
+		if o.TextCoordFound {
+			for _, f := range o.vertexTexCoord(s) {
+				hs.float(f)
+			}
+		}
+		if o.NormCoordFound {
+			for _, f := range o.vertexNormal(s) {
+				hs.float(f)
+			}
+		}
+		if o.ColorFound {
+			r, g, b := o.VertexColor(s)
+			hs.float(r)
+			hs.float(g)
+			hs.float(b)
+		}
+	}
+
+	// corner gets the hash of a triangle corner
+	corner := func(s int) []byte {
+		hs := newHasher()
+		vertex(hs, s)
+		return hs.sum()
+	}
+
+	var groups [][]byte
+	for _, g := range o.Groups {
+		var tris [][]byte
+		for t := g.IndexBegin / 3; t < (g.IndexBegin+g.IndexCount)/3 && t < triangles; t++ {
+			c := [3][]byte{corner(o.Indices[3*t]), corner(o.Indices[3*t+1]), corner(o.Indices[3*t+2])}
+
+			// start at the smallest corner, keeping the winding
+			first := 0
+			for i := 1; i < 3; i++ {
+				if bytes.Compare(c[i], c[first]) < 0 {
+					first = i
+				}
+			}
+			hs := newHasher()
+			for i := 0; i < 3; i++ {
+				hs.h.Write(c[(first+i)%3])
+			}
+			hs.int(int64(smoothing[t]))
+			material := g.Usemtl
+			if t < len(o.MaterialIndex) && o.MaterialIndex[t] >= 0 {
+				material = o.MaterialNames[o.MaterialIndex[t]]
+			}
+			hs.string(material)
+			tris = append(tris, hs.sum())
+		}
+		slices.SortFunc(tris, bytes.Compare)
+
+		hs := newHasher()
+		hs.string(g.Name)
+		hs.string(g.Usemtl)
+		hs.string(g.Usemap)
+		hs.int(int64(g.Lod))
+		hs.int(int64(len(tris)))
+		for _, t := range tris {
+			hs.h.Write(t)
+		}
+		points := make([][]byte, 0, len(g.Points))
+		for _, p := range g.Points {
+			points = append(points, corner(p))
+		}
+		slices.SortFunc(points, bytes.Compare)
+		hs.int(int64(len(points)))
+		for _, p := range points {
+			hs.h.Write(p)
+		}
+		hs.int(int64(len(g.Lines)))
+		for _, line := range g.Lines {
+			hs.int(int64(len(line)))
+			for _, v := range line {
+				hs.h.Write(corner(v))
+			}
+		}
+		groups = append(groups, hs.sum())
+	}
+	slices.SortFunc(groups, bytes.Compare)
+
+	hs := newHasher()
+	hs.int(int64(len(groups)))
+	for _, g := range groups {
+		hs.h.Write(g)
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], hs.sum())
+	return sum
+}
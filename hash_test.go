@@ -0,0 +1,54 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	o, err := NewObjFromBuf("materials", []byte(materialsObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestHash: NewObjFromBuf: %v", err)
+	}
+	h := o.Hash()
+
+	same := []struct {
+		name   string
+		change func(o *Obj)
+	}{
+		{"clone", func(o *Obj) {}},
+		{"optimize", func(o *Obj) { o.Optimize() }},
+		{"flatten", func(o *Obj) { o.Flatten() }},
+		{"separate", func(o *Obj) { o.Deinterleave() }},
+		{"sort groups", func(o *Obj) { o.SortGroupsByMaterial() }},
+		{"reverse groups", func(o *Obj) {
+			for i, j := 0, len(o.Groups)-1; i < j; i, j = i+1, j-1 {
+				o.Groups[i], o.Groups[j] = o.Groups[j], o.Groups[i]
+			}
+		}},
+		{"noise", func(o *Obj) { o.Translate(1e-9, 0, 0) }},
+	}
+	for _, data := range same {
+		c := o.Clone()
+		data.change(c)
+		if c.Hash() != h {
+			t.Errorf("TestHash: %s: hash changed", data.name)
+		}
+	}
+
+	different := []struct {
+		name   string
+		change func(o *Obj)
+	}{
+		{"translate", func(o *Obj) { o.Translate(0.1, 0, 0) }},
+		{"material", func(o *Obj) { o.Groups[0].Usemtl = "other" }},
+		{"winding", func(o *Obj) { o.FlipWinding() }},
+		{"name", func(o *Obj) { o.Groups[0].Name = "other" }},
+	}
+	for _, data := range different {
+		c := o.Clone()
+		data.change(c)
+		if c.Hash() == h {
+			t.Errorf("TestHash: %s: hash not changed", data.name)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package gwob
+
+// pushW stores the weight of a v statement, if retained.
+func (p *objParser) pushW(w float64, options *ObjParserOptions) {
+	if options.KeepHomogeneous {
+		p.vertW = append(p.vertW, float32(w))
+	}
+}
+
+// appendW appends the homogeneous coordinates for a new unified vertex.
+func (p *objParser) appendW(o *Obj, key vertexKey) {
+	o.W = append(o.W, p.vertW[key.v])
+	var tw float32
+	if key.t != -1 {
+		tw = p.textW[key.t]
+	}
+	o.TexW = append(o.TexW, tw)
+}
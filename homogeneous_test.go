@@ -0,0 +1,58 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+var homogeneousObj = `
+v 2 0 0 2
+v 0 4 0 4
+v 0 0 1
+vt 0 0 0.5
+vt 1 0
+f 1/1 2/2 3/1
+`
+
+func TestKeepHomogeneous(t *testing.T) {
+
+	o, err := NewObjFromBuf("homogeneousObj", []byte(homogeneousObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestKeepHomogeneous: NewObjFromBuf: %v", err)
+	}
+	if x, _, _ := o.VertexCoordinates(0); x != 1 {
+		t.Errorf("TestKeepHomogeneous: default: want divided x=1 got=%v", x)
+	}
+	expectInt(t, "TestKeepHomogeneous: default warnings", 1, o.Warnings())
+	if o.W != nil || o.TexW != nil {
+		t.Errorf("TestKeepHomogeneous: default: unexpected W=%v TexW=%v", o.W, o.TexW)
+	}
+
+	o, err = NewObjFromBuf("homogeneousObj", []byte(homogeneousObj), NewObjParserOptions(WithKeepHomogeneous(true)))
+	if err != nil {
+		t.Fatalf("TestKeepHomogeneous: NewObjFromBuf: %v", err)
+	}
+	if x, _, _ := o.VertexCoordinates(0); x != 2 {
+		t.Errorf("TestKeepHomogeneous: want raw x=2 got=%v", x)
+	}
+	expectInt(t, "TestKeepHomogeneous: warnings", 0, o.Warnings())
+	if want := []float32{2, 4, 1}; !sliceEqualFloat(want, o.W) {
+		t.Errorf("TestKeepHomogeneous: W: want=%v got=%v", want, o.W)
+	}
+	if want := []float32{0.5, 0, 0.5}; !sliceEqualFloat(want, o.TexW) {
+		t.Errorf("TestKeepHomogeneous: TexW: want=%v got=%v", want, o.TexW)
+	}
+
+	// round trip
+	var buf bytes.Buffer
+	if errWrite := o.ToWriter(&buf); errWrite != nil {
+		t.Fatalf("TestKeepHomogeneous: ToWriter: %v", errWrite)
+	}
+	o2, err := NewObjFromBuf("homogeneousObj2", buf.Bytes(), NewObjParserOptions(WithKeepHomogeneous(true)))
+	if err != nil {
+		t.Fatalf("TestKeepHomogeneous: reparse: %v", err)
+	}
+	if !sliceEqualFloat(o.W, o2.W) || !sliceEqualFloat(o.TexW, o2.TexW) || !sliceEqualFloat(o.Coord, o2.Coord) {
+		t.Errorf("TestKeepHomogeneous: round trip: W=%v TexW=%v Coord=%v", o2.W, o2.TexW, o2.Coord)
+	}
+}
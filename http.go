@@ -0,0 +1,73 @@
+package gwob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// NewObjFromURL fetches and parses Obj from an HTTP(S) URL.
+func NewObjFromURL(ctx context.Context, rawURL string, options *ObjParserOptions) (*Obj, error) {
+
+	input, errGet := httpGet(ctx, rawURL)
+	if errGet != nil {
+		return nil, errGet
+	}
+
+	defer input.Close()
+
+	return NewObjFromReader(rawURL, input, options)
+}
+
+// LoadSceneFromURL is like LoadScene, but fetches the OBJ over HTTP(S).
+// Material libs and textures are resolved relative to the OBJ URL.
+func LoadSceneFromURL(ctx context.Context, rawURL string, options *ObjParserOptions) (*Scene, error) {
+
+	base, errParse := url.Parse(rawURL)
+	if errParse != nil {
+		return nil, fmt.Errorf("LoadSceneFromURL: bad url=%s: %v", rawURL, errParse)
+	}
+
+	open := func(name string) (io.ReadCloser, error) {
+		if name == rawURL {
+			return httpGet(ctx, rawURL)
+		}
+		return httpGet(ctx, resolveURL(base, name))
+	}
+
+	return loadScene(rawURL, open, options)
+}
+
+// resolveURL resolves an asset name found in OBJ/MTL relative to the base URL.
+func resolveURL(base *url.URL, name string) string {
+	if strings.Contains(name, "://") {
+		if ref, err := url.Parse(name); err == nil {
+			return ref.String() // already absolute
+		}
+	}
+	// build the reference from the raw path so that names with spaces are escaped
+	ref := &url.URL{Path: strings.ReplaceAll(name, "\\", "/")}
+	return base.ResolveReference(ref).String()
+}
+
+func httpGet(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if errReq != nil {
+		return nil, fmt.Errorf("httpGet: url=%s: %v", rawURL, errReq)
+	}
+
+	resp, errDo := http.DefaultClient.Do(req)
+	if errDo != nil {
+		return nil, fmt.Errorf("httpGet: url=%s: %v", rawURL, errDo)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("httpGet: url=%s: bad status: %s", rawURL, resp.Status)
+	}
+
+	return resp.Body, nil
+}
@@ -0,0 +1,52 @@
+package gwob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoadSceneFromURL(t *testing.T) {
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/models/scene.obj", func(w http.ResponseWriter, _ *http.Request) { io.WriteString(w, sceneObj) })
+	mux.HandleFunc("/models/scene.mtl", func(w http.ResponseWriter, _ *http.Request) { io.WriteString(w, sceneMtl) })
+	mux.HandleFunc("/models/red.png", func(w http.ResponseWriter, _ *http.Request) { io.WriteString(w, "png") })
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	options := ObjParserOptions{LogStats: LogStats, Logger: func(msg string) { fmt.Printf("TestLoadSceneFromURL: log: %s\n", msg) }}
+
+	ctx := context.Background()
+
+	o, err := NewObjFromURL(ctx, server.URL+"/models/scene.obj", &options)
+	if err != nil {
+		t.Errorf("TestLoadSceneFromURL: NewObjFromURL: %v", err)
+		return
+	}
+	expectInt(t, "TestLoadSceneFromURL: groups", 2, len(o.Groups))
+
+	if _, errMissing := NewObjFromURL(ctx, server.URL+"/missing.obj", &options); errMissing == nil {
+		t.Errorf("TestLoadSceneFromURL: unexpected success for missing url")
+	}
+
+	s, errScene := LoadSceneFromURL(ctx, server.URL+"/models/scene.obj", &options)
+	if errScene != nil {
+		t.Errorf("TestLoadSceneFromURL: LoadSceneFromURL: %v", errScene)
+		return
+	}
+	if s.Materials[0] == nil || s.Materials[0].Name != "red" {
+		t.Errorf("TestLoadSceneFromURL: group 0: want material red got %v", s.Materials[0])
+	}
+
+	tex, errTex := s.OpenTexture(s.TextureNames()[0])
+	if errTex != nil {
+		t.Errorf("TestLoadSceneFromURL: OpenTexture: %v", errTex)
+		return
+	}
+	tex.Close()
+}
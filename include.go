@@ -0,0 +1,97 @@
+package gwob
+
+import (
+	"io"
+	"path"
+	"slices"
+	"strings"
+)
+
+// MaxIncludeDepth is the maximum nesting of "call" statements.
+const MaxIncludeDepth = 16
+
+// WithInclude sets the function opening files pulled in by "call" statements.
+func WithInclude(include func(name string) (io.Reader, error)) Option {
+	return func(opt *ObjParserOptions) {
+		opt.Include = include
+	}
+}
+
+func isIncludeLine(line string) bool {
+	return strings.HasPrefix(line, "call ") || strings.HasPrefix(line, "csh ")
+}
+
+// parseInclude handles "call filename [args]" by parsing the included
+// file in place, sharing the parser state with the including file.
+// Arguments are ignored. "csh" shell commands are never executed.
+func parseInclude(p *objParser, o *Obj, line string, options *ObjParserOptions) (bool, error) {
+	if strings.HasPrefix(line, "csh ") {
+		return ErrNonFatal, p.errorf(ErrUnexpected, "csh command not executed: [%s]", line)
+	}
+
+	fields := strings.Fields(line[5:])
+	if len(fields) < 1 {
+		return ErrNonFatal, p.errorf(ErrBadValue, "call: missing filename")
+	}
+	name := strings.Clone(fields[0]) // do not retain the input buffer
+
+	if options.Include == nil {
+		return ErrNonFatal, p.errorf(ErrUnexpected, "call: no include function for file=%s", name)
+	}
+	if isIncluded(p, name) {
+		return ErrNonFatal, p.errorf(ErrBadValue, "call: include cycle for file=%s", name)
+	}
+	if len(p.includes) >= MaxIncludeDepth {
+		return ErrNonFatal, p.errorf(ErrBadValue, "call: include depth=%d exceeded for file=%s", MaxIncludeDepth, name)
+	}
+
+	rd, errOpen := options.Include(name)
+	if errOpen != nil {
+		return ErrNonFatal, p.errorf(ErrBadValue, "call: file=%s: %v", name, errOpen)
+	}
+	if c, isCloser := rd.(io.Closer); isCloser {
+		defer c.Close()
+	}
+
+	reader, _, errBuf := newBufReader(rd)
+	if errBuf != nil {
+		return ErrNonFatal, p.errorf(ErrBadValue, "call: file=%s: %v", name, errBuf)
+	}
+
+	objName, lineCount := p.objName, p.lineCount
+	p.includes = append(p.includes, objName)
+	p.objName = name
+
+	fatal, err := readStream(p, o, reader, options)
+
+	p.includes = p.includes[:len(p.includes)-1]
+	p.objName, p.lineCount = objName, lineCount
+
+	if err != nil {
+		return fatal, &includeError{err: err}
+	}
+
+	return ErrNonFatal, nil
+}
+
+// isIncluded reports whether name is a file being read, comparing
+// cleaned paths so that "./a.obj" or "sub/../a.obj" match "a.obj".
+func isIncluded(p *objParser, name string) bool {
+	name = path.Clean(name)
+	if path.Clean(p.objName) == name {
+		return true
+	}
+	return slices.ContainsFunc(p.includes, func(n string) bool {
+		return path.Clean(n) == name
+	})
+}
+
+// includeError wraps the error that stopped an included file.
+// The nested readStream has already reported it as a diagnostic.
+type includeError struct {
+	err error
+}
+
+func (e *includeError) Error() string { return "call: " + e.err.Error() }
+
+func (e *includeError) Unwrap() error { return e.err }
@@ -0,0 +1,137 @@
+package gwob
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"testing"
+)
+
+func includeFiles(files map[string]string) Option {
+	return WithInclude(func(name string) (io.Reader, error) {
+		s, found := files[name]
+		if !found {
+			return nil, errors.New("file not found")
+		}
+		return strings.NewReader(s), nil
+	})
+}
+
+func TestInclude(t *testing.T) {
+	files := map[string]string{
+		"verts.obj": "v 0 0 0\nv 1 0 0\ncall more.obj\n",
+		"more.obj":  "v 1 1 0\n",
+	}
+	str := `
+call verts.obj arg1
+f 1 2 3
+`
+	o, err := NewObjFromBuf("main.obj", []byte(str), NewObjParserOptions(includeFiles(files)))
+	if err != nil {
+		t.Fatalf("TestInclude: NewObjFromBuf: %v", err)
+	}
+	if !sliceEqualInt([]int{0, 1, 2}, o.Indices) {
+		t.Errorf("TestInclude: indices: got=%v", o.Indices)
+	}
+	if !sliceEqualFloat([]float32{0, 0, 0, 1, 0, 0, 1, 1, 0}, o.Coord) {
+		t.Errorf("TestInclude: coord: got=%v", o.Coord)
+	}
+}
+
+func TestIncludeErrors(t *testing.T) {
+	files := map[string]string{
+		"a.obj": "v 0 0 0\ncall b.obj\n",
+		"b.obj": "call a.obj\n",
+		"self":  "call self\n",
+	}
+
+	inputs := []struct {
+		name  string
+		input string
+		opts  []Option
+	}{
+		{"cycle", "call a.obj\n", []Option{includeFiles(files)}},
+		{"self", "call self\n", []Option{includeFiles(files)}},
+		{"missing file", "call missing.obj\n", []Option{includeFiles(files)}},
+		{"no include function", "call a.obj\n", nil},
+		{"csh", "csh rm -rf /\n", []Option{includeFiles(files)}},
+	}
+
+	for _, in := range inputs {
+		opts := append(in.opts, WithStrict(true))
+		_, err := NewObjFromBuf(in.name, []byte(in.input), NewObjParserOptions(opts...))
+		if err == nil {
+			t.Errorf("TestIncludeErrors: %s: unexpected success", in.name)
+		}
+	}
+
+	// depth limit: each file includes the next one
+	deep := map[string]string{}
+	for i := 0; i <= MaxIncludeDepth; i++ {
+		deep[string(rune('a'+i))] = "call " + string(rune('a'+i+1)) + "\n"
+	}
+	deep[string(rune('a'+MaxIncludeDepth+1))] = "v 0 0 0\n"
+	_, err := NewObjFromBuf("deep", []byte("call a\n"), NewObjParserOptions(includeFiles(deep), WithStrict(true)))
+	if err == nil {
+		t.Errorf("TestIncludeErrors: depth limit: unexpected success")
+	}
+}
+
+func TestIncludeDeferredFile(t *testing.T) {
+	files := map[string]string{
+		"faces.obj": "f 1 2 3\nf 1 2 x\n", // forward reference, then a bad face
+	}
+	str := "call faces.obj\nv 0 0 0\nv 1 0 0\nv 1 1 0\n"
+	o, err := NewObjFromBuf("main.obj", []byte(str), NewObjParserOptions(includeFiles(files)))
+	if err != nil {
+		t.Fatalf("TestIncludeDeferredFile: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestIncludeDeferredFile: errors", 1, o.Errors())
+	if e := o.Diagnostics[0].Err; e.File != "faces.obj" || e.Line != 2 {
+		t.Errorf("TestIncludeDeferredFile: file=%s line=%d", e.File, e.Line)
+	}
+	expectInt(t, "TestIncludeDeferredFile: indices", 3, len(o.Indices))
+}
+
+func TestIncludeStrictDiagnostics(t *testing.T) {
+	files := map[string]string{
+		"bad.obj": "v 0 0 0\nvn x\n",
+	}
+	options := NewObjParserOptions(includeFiles(files), WithStrict(true))
+	p := &objParser{material: -1}
+	_, err := parseObj(p, "main.obj", bufio.NewReader(strings.NewReader("call bad.obj\n")), 0, options)
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.File != "bad.obj" {
+		t.Errorf("TestIncludeStrictDiagnostics: error: %v", err)
+	}
+	expectInt(t, "TestIncludeStrictDiagnostics: diagnostics", 1, len(p.diagnostics))
+}
+
+func TestIncludeCycleCleanPath(t *testing.T) {
+	files := map[string]string{
+		"a.obj": "call ./a.obj\n",
+		"b.obj": "call sub/../b.obj\n",
+	}
+	include := WithInclude(func(name string) (io.Reader, error) {
+		s, found := files[path.Clean(name)]
+		if !found {
+			return nil, errors.New("file not found")
+		}
+		return strings.NewReader(s), nil
+	})
+
+	for _, name := range []string{"a.obj", "b.obj"} {
+		_, err := NewObjFromBuf(name, []byte("call "+name+"\n"), NewObjParserOptions(include, WithStrict(true)))
+		if err == nil || !strings.Contains(err.Error(), "include cycle") {
+			t.Errorf("TestIncludeCycleCleanPath: %s: want include cycle got %v", name, err)
+		}
+		// the cycle is found in the file itself, not one level deeper
+		_, err = NewObjFromBuf("main.obj", []byte("call "+name+"\n"), NewObjParserOptions(include, WithStrict(true)))
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.File != name || !strings.Contains(pe.Msg, "include cycle") {
+			t.Errorf("TestIncludeCycleCleanPath: main %s: want include cycle in %s got %v", name, name, err)
+		}
+	}
+}
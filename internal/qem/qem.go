@@ -0,0 +1,436 @@
+/*
+Package qem implements quadric error metric mesh decimation by
+half-edge collapse, after Garland and Heckbert, "Surface Simplification
+Using Quadric Error Metrics" (1997).
+
+Collapses move a vertex onto a neighbor, so surviving vertices keep
+their original attributes. The package works on plain arrays, so that
+both the gwob package and its subpackages can use it.
+*/
+package qem
+
+import (
+	"container/heap"
+	"math"
+)
+
+// Input is a triangle mesh to simplify.
+type Input struct {
+	Positions []float64 // (x,y,z) per vertex
+	Indices   []int     // 3 vertices per triangle
+
+	// Classes optionally assigns each triangle a class, like a group
+	// or a material. Vertices shared by triangles of different classes
+	// are not moved, so class boundaries are preserved.
+	Classes []int
+}
+
+// Options sets the stop criteria. Simplification stops when either
+// is reached, or when no collapse is possible.
+type Options struct {
+	// TargetTriangles is the triangle count to reach. Zero means none.
+	TargetTriangles int
+
+	// MaxError is the largest allowed deviation, relative to the
+	// bounding box diagonal. Zero means no limit.
+	MaxError float64
+}
+
+// Result is the simplified mesh.
+type Result struct {
+	Indices   []int   // 3 vertices per triangle, into the input vertices
+	Triangles []int   // input triangle of each result triangle, ascending
+	Error     float64 // largest collapse error, relative like MaxError
+}
+
+// borderWeight scales the quadrics keeping open borders in place.
+const borderWeight = 10
+
+type quadric [10]float64 // symmetric 4x4: a² ab ac ad b² bc bd c² cd d²
+
+func planeQuadric(a, b, c, d, w float64) quadric {
+	return quadric{
+		w * a * a, w * a * b, w * a * c, w * a * d,
+		w * b * b, w * b * c, w * b * d,
+		w * c * c, w * c * d,
+		w * d * d,
+	}
+}
+
+func (q *quadric) add(r *quadric) {
+	for i := range q {
+		q[i] += r[i]
+	}
+}
+
+func (q *quadric) eval(p [3]float64) float64 {
+	x, y, z := p[0], p[1], p[2]
+	e := q[0]*x*x + 2*q[1]*x*y + 2*q[2]*x*z + 2*q[3]*x +
+		q[4]*y*y + 2*q[5]*y*z + 2*q[6]*y +
+		q[7]*z*z + 2*q[8]*z +
+		q[9]
+	return max(e, 0)
+}
+
+type vec [3]float64
+
+func sub(a, b vec) vec { return vec{a[0] - b[0], a[1] - b[1], a[2] - b[2]} }
+
+func cross(a, b vec) vec {
+	return vec{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+func dot(a, b vec) float64 { return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] }
+
+func normalize(a vec) (vec, float64) {
+	l := math.Sqrt(dot(a, a))
+	if l == 0 {
+		return a, 0
+	}
+	return vec{a[0] / l, a[1] / l, a[2] / l}, l
+}
+
+// collapse is a candidate move of vertex u onto vertex v.
+type collapse struct {
+	cost           float64
+	u, v           int
+	stampU, stampV int
+}
+
+type collapseHeap []collapse
+
+func (h collapseHeap) Len() int           { return len(h) }
+func (h collapseHeap) Less(i, j int) bool { return h[i].cost < h[j].cost }
+func (h collapseHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *collapseHeap) Push(x any)        { *h = append(*h, x.(collapse)) }
+func (h *collapseHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+type mesher struct {
+	pos     []vec
+	tris    [][3]int
+	alive   []bool
+	vtris   [][]int // vertex -> incident triangles, possibly stale
+	locked  []bool
+	border  []bool
+	quadric []quadric
+	stamp   []int
+	removed []bool
+	heap    collapseHeap
+	edges   map[[2]int]int // input edge -> triangle count
+}
+
+// Simplify decimates the mesh until the options are met.
+func Simplify(in Input, options Options) Result {
+	n := len(in.Positions) / 3
+	m := len(in.Indices) / 3
+
+	s := &mesher{
+		pos:     make([]vec, n),
+		tris:    make([][3]int, m),
+		alive:   make([]bool, m),
+		vtris:   make([][]int, n),
+		locked:  make([]bool, n),
+		border:  make([]bool, n),
+		quadric: make([]quadric, n),
+		stamp:   make([]int, n),
+		removed: make([]bool, n),
+	}
+
+	for i := range s.pos {
+		s.pos[i] = vec{in.Positions[3*i], in.Positions[3*i+1], in.Positions[3*i+2]}
+	}
+	for t := range s.tris {
+		copy(s.tris[t][:], in.Indices[3*t:3*t+3])
+		s.alive[t] = true
+		for _, v := range s.tris[t] {
+			s.vtris[v] = append(s.vtris[v], t)
+		}
+	}
+
+	s.edges = map[[2]int]int{}
+	for _, tri := range s.tris {
+		for i := range tri {
+			s.edges[edgeKey(tri[i], tri[(i+1)%3])]++
+		}
+	}
+
+	s.lock(in.Classes)
+	s.initQuadrics()
+
+	diag := s.diagonal()
+	maxCost := math.Inf(1)
+	if options.MaxError > 0 {
+		maxCost = options.MaxError * diag
+		maxCost *= maxCost
+	}
+
+	for v := range s.pos {
+		s.pushCandidates(v)
+	}
+
+	triangles := m
+	var worst float64
+	for s.heap.Len() > 0 && triangles > options.TargetTriangles {
+		c := heap.Pop(&s.heap).(collapse)
+		if s.removed[c.u] || s.removed[c.v] || c.stampU != s.stamp[c.u] || c.stampV != s.stamp[c.v] {
+			continue // stale
+		}
+		if c.cost > maxCost {
+			break
+		}
+		if !s.valid(c.u, c.v) {
+			continue
+		}
+		triangles -= s.collapse(c.u, c.v)
+		worst = max(worst, c.cost)
+	}
+
+	var result Result
+	for t, tri := range s.tris {
+		if s.alive[t] {
+			result.Indices = append(result.Indices, tri[:]...)
+			result.Triangles = append(result.Triangles, t)
+		}
+	}
+	if diag > 0 {
+		result.Error = math.Sqrt(worst) / diag
+	}
+	return result
+}
+
+func (s *mesher) diagonal() float64 {
+	if len(s.pos) == 0 {
+		return 0
+	}
+	lo, hi := s.pos[0], s.pos[0]
+	for _, p := range s.pos {
+		for i := range p {
+			lo[i] = min(lo[i], p[i])
+			hi[i] = max(hi[i], p[i])
+		}
+	}
+	d := sub(hi, lo)
+	return math.Sqrt(dot(d, d))
+}
+
+// lock marks the vertices that must not move: those sharing a position
+// with another vertex (attribute seams), on class boundaries, or on
+// non-manifold edges. Vertices on open borders are marked as border.
+func (s *mesher) lock(classes []int) {
+	positions := map[vec]int{}
+	for v, p := range s.pos {
+		if len(s.vtris[v]) == 0 {
+			continue
+		}
+		if other, found := positions[p]; found {
+			s.locked[v] = true
+			s.locked[other] = true
+			continue
+		}
+		positions[p] = v
+	}
+
+	if len(classes) == len(s.tris) {
+		for v, list := range s.vtris {
+			for _, t := range list {
+				if classes[t] != classes[list[0]] {
+					s.locked[v] = true
+					break
+				}
+			}
+		}
+	}
+
+	for e, count := range s.edges {
+		switch {
+		case count == 1:
+			s.border[e[0]] = true
+			s.border[e[1]] = true
+		case count > 2:
+			s.locked[e[0]] = true
+			s.locked[e[1]] = true
+		}
+	}
+}
+
+func edgeKey(a, b int) [2]int {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int{a, b}
+}
+
+func (s *mesher) initQuadrics() {
+	for _, tri := range s.tris {
+		p0, p1, p2 := s.pos[tri[0]], s.pos[tri[1]], s.pos[tri[2]]
+		n, area := normalize(cross(sub(p1, p0), sub(p2, p0)))
+		if area == 0 {
+			continue
+		}
+		q := planeQuadric(n[0], n[1], n[2], -dot(n, p0), 1)
+		for _, v := range tri {
+			s.quadric[v].add(&q)
+		}
+
+		// planes perpendicular to border edges keep borders in place
+		for i := range tri {
+			a, b := tri[i], tri[(i+1)%3]
+			if s.edges[edgeKey(a, b)] != 1 {
+				continue
+			}
+			bn, length := normalize(cross(sub(s.pos[b], s.pos[a]), n))
+			if length == 0 {
+				continue
+			}
+			bq := planeQuadric(bn[0], bn[1], bn[2], -dot(bn, s.pos[a]), borderWeight)
+			s.quadric[a].add(&bq)
+			s.quadric[b].add(&bq)
+		}
+	}
+}
+
+// neighbors lists the vertices adjacent to v through live triangles.
+func (s *mesher) neighbors(v int) []int {
+	var list []int
+	for _, t := range s.vtris[v] {
+		if !s.alive[t] {
+			continue
+		}
+		for _, w := range s.tris[t] {
+			if w != v && !contains(list, w) {
+				list = append(list, w)
+			}
+		}
+	}
+	return list
+}
+
+func contains(list []int, v int) bool {
+	for _, w := range list {
+		if w == v {
+			return true
+		}
+	}
+	return false
+}
+
+// pushCandidates queues the collapses of v onto its neighbors.
+func (s *mesher) pushCandidates(v int) {
+	for _, w := range s.neighbors(v) {
+		s.pushCandidate(v, w)
+	}
+}
+
+// pushCandidate queues the collapse of u onto v.
+func (s *mesher) pushCandidate(u, v int) {
+	if s.locked[u] || s.removed[u] {
+		return
+	}
+	q := s.quadric[u]
+	q.add(&s.quadric[v])
+	heap.Push(&s.heap, collapse{
+		cost:   q.eval(s.pos[v]),
+		u:      u,
+		v:      v,
+		stampU: s.stamp[u],
+		stampV: s.stamp[v],
+	})
+}
+
+// shared counts the live triangles using both u and v.
+func (s *mesher) shared(u, v int) int {
+	count := 0
+	for _, t := range s.vtris[u] {
+		if s.alive[t] && contains(s.tris[t][:], v) {
+			count++
+		}
+	}
+	return count
+}
+
+// valid checks the collapse of u onto v keeps the mesh sound.
+func (s *mesher) valid(u, v int) bool {
+	shared := s.shared(u, v)
+	if shared == 0 {
+		return false
+	}
+
+	// border vertices only slide along the border
+	if s.border[u] && shared != 1 {
+		return false
+	}
+
+	// link condition: the common neighbors are the opposite corners
+	// of the shared triangles, else the collapse pinches the surface
+	nu := s.neighbors(u)
+	common := 0
+	for _, w := range s.neighbors(v) {
+		if contains(nu, w) {
+			common++
+		}
+	}
+	if common != shared {
+		return false
+	}
+
+	// no triangle may flip or collapse
+	for _, t := range s.vtris[u] {
+		tri := s.tris[t]
+		if !s.alive[t] || contains(tri[:], v) {
+			continue
+		}
+		p := [3]vec{s.pos[tri[0]], s.pos[tri[1]], s.pos[tri[2]]}
+		before := cross(sub(p[1], p[0]), sub(p[2], p[0]))
+		for i, w := range tri {
+			if w == u {
+				p[i] = s.pos[v]
+			}
+		}
+		after := cross(sub(p[1], p[0]), sub(p[2], p[0]))
+		if dot(before, after) <= 1e-3*dot(before, before) {
+			return false
+		}
+	}
+	return true
+}
+
+// collapse moves u onto v, returning the number of triangles removed.
+func (s *mesher) collapse(u, v int) int {
+	removed := 0
+	for _, t := range s.vtris[u] {
+		if !s.alive[t] {
+			continue
+		}
+		tri := &s.tris[t]
+		if contains(tri[:], v) {
+			s.alive[t] = false
+			removed++
+			continue
+		}
+		for i, w := range tri {
+			if w == u {
+				tri[i] = v
+			}
+		}
+		s.vtris[v] = append(s.vtris[v], t)
+	}
+	s.vtris[u] = nil
+	s.removed[u] = true
+	s.quadric[v].add(&s.quadric[u])
+	if s.border[u] {
+		s.border[v] = true
+	}
+
+	// the quadric of v changed: requeue the collapses involving it
+	s.stamp[v]++
+	for _, w := range s.neighbors(v) {
+		s.pushCandidate(v, w)
+		s.pushCandidate(w, v)
+	}
+	return removed
+}
@@ -0,0 +1,96 @@
+package qem
+
+import (
+	"math"
+	"testing"
+)
+
+// grid builds a size x size grid of quads on the z=height(x,y) surface.
+func grid(size int, height func(x, y int) float64) Input {
+	var in Input
+	for y := 0; y <= size; y++ {
+		for x := 0; x <= size; x++ {
+			in.Positions = append(in.Positions, float64(x), float64(y), height(x, y))
+		}
+	}
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			a := y*(size+1) + x
+			b, c, d := a+1, a+size+2, a+size+1
+			in.Indices = append(in.Indices, a, b, c, c, d, a)
+		}
+	}
+	return in
+}
+
+func flat(x, y int) float64 { return 0 }
+
+func TestSimplifyPlane(t *testing.T) {
+	in := grid(10, flat)
+	r := Simplify(in, Options{TargetTriangles: 20})
+	if n := len(r.Indices) / 3; n > 20 {
+		t.Errorf("TestSimplifyPlane: triangles=%d > target=20", n)
+	}
+	if len(r.Triangles) != len(r.Indices)/3 {
+		t.Errorf("TestSimplifyPlane: triangles=%d indices=%d", len(r.Triangles), len(r.Indices))
+	}
+	if r.Error > 1e-9 {
+		t.Errorf("TestSimplifyPlane: flat plane error=%v", r.Error)
+	}
+
+	// the corners cannot move
+	corners := []int{0, 10, 110, 120}
+	for _, c := range corners {
+		found := false
+		for _, v := range r.Indices {
+			if v == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("TestSimplifyPlane: corner %d removed", c)
+		}
+	}
+}
+
+func TestSimplifyMaxError(t *testing.T) {
+	bumpy := func(x, y int) float64 { return math.Sin(float64(x)) * math.Cos(float64(y)) }
+	in := grid(10, bumpy)
+
+	r := Simplify(in, Options{MaxError: 0.01})
+	if r.Error > 0.01 {
+		t.Errorf("TestSimplifyMaxError: error=%v > 0.01", r.Error)
+	}
+	all := Simplify(in, Options{})
+	if len(r.Indices) <= len(all.Indices) {
+		t.Errorf("TestSimplifyMaxError: bounded=%d unbounded=%d indices", len(r.Indices), len(all.Indices))
+	}
+}
+
+func TestSimplifyClasses(t *testing.T) {
+	in := grid(10, flat)
+	for i := 0; i < len(in.Indices); i += 3 {
+		class := 0
+		if in.Positions[3*in.Indices[i]] < 5 || in.Positions[3*in.Indices[i+1]] < 5 || in.Positions[3*in.Indices[i+2]] < 5 {
+			class = 1
+		}
+		in.Classes = append(in.Classes, class)
+	}
+	r := Simplify(in, Options{})
+
+	// the vertices on x=5 separate the classes
+	for y := 0; y <= 10; y++ {
+		v := y*11 + 5
+		found := false
+		for _, w := range r.Indices {
+			if w == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("TestSimplifyClasses: boundary vertex %d removed", v)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package gwob
+
+// appendSeparate appends the unified vertex data for a face corner
+// to the separate Positions, TexCoords and Normals arrays.
+func (p *objParser) appendSeparate(o *Obj, vOffset, tOffset, nOffset int, hasTexture, hasNormal bool) {
+	if o.Positions == nil {
+		o.Positions = p.float32s(3 * p.hint.Vertices)
+	}
+	o.Positions = p.reserveFloat32s(o.Positions, 3)
+	o.Positions = append(o.Positions, p.vertCoord[vOffset:vOffset+3]...)
+
+	if hasTexture {
+		if o.TexCoords == nil {
+			o.TexCoords = p.float32s(2 * p.hint.Vertices)
+		}
+		o.TexCoords = p.reserveFloat32s(o.TexCoords, 2)
+		o.TexCoords = append(o.TexCoords, p.textCoord[tOffset:tOffset+2]...)
+	}
+
+	if hasNormal {
+		if o.Normals == nil {
+			o.Normals = p.float32s(3 * p.hint.Vertices)
+		}
+		o.Normals = p.reserveFloat32s(o.Normals, 3)
+		o.Normals = append(o.Normals, p.normCoord[nOffset:nOffset+3]...)
+	}
+}
+
+// Interleave builds the interleaved Coord buffer from
+// Positions, TexCoords and Normals, which are then cleared.
+func (o *Obj) Interleave() {
+	if o.Positions == nil {
+		return
+	}
+
+	setupStride(o)
+	floatsPerStride := o.StrideSize / 4
+	elements := len(o.Positions) / 3
+	coord := make([]float32, 0, elements*floatsPerStride)
+
+	for i := 0; i < elements; i++ {
+		coord = append(coord, o.Positions[3*i:3*i+3]...)
+		if o.TextCoordFound {
+			coord = append(coord, o.TexCoords[2*i:2*i+2]...)
+		}
+		if o.NormCoordFound {
+			coord = append(coord, o.Normals[3*i:3*i+3]...)
+		}
+	}
+
+	o.Coord = coord
+	o.Positions = nil
+	o.TexCoords = nil
+	o.Normals = nil
+}
+
+// Deinterleave splits the interleaved Coord buffer into
+// Positions, TexCoords and Normals. Coord is then cleared.
+func (o *Obj) Deinterleave() {
+	if o.Coord == nil {
+		return
+	}
+
+	elements := o.NumberOfElements()
+	floatsPerStride := o.StrideSize / 4
+
+	o.Positions = make([]float32, 0, 3*elements)
+	o.TexCoords = nil
+	o.Normals = nil
+	if o.TextCoordFound {
+		o.TexCoords = make([]float32, 0, 2*elements)
+	}
+	if o.NormCoordFound {
+		o.Normals = make([]float32, 0, 3*elements)
+	}
+
+	var colors []float32
+
+	for i := 0; i < elements; i++ {
+		stride := i * floatsPerStride
+		v := stride + o.StrideOffsetPosition/4
+		o.Positions = append(o.Positions, o.Coord[v:v+3]...)
+		if o.TextCoordFound {
+			t := stride + o.StrideOffsetTexture/4
+			o.TexCoords = append(o.TexCoords, o.Coord[t:t+2]...)
+		}
+		if o.NormCoordFound {
+			n := stride + o.StrideOffsetNormal/4
+			o.Normals = append(o.Normals, o.Coord[n:n+3]...)
+		}
+		if o.StrideOffsetColor != 0 {
+			c := stride + o.StrideOffsetColor/4
+			colors = append(colors, o.Coord[c:c+3]...)
+		}
+	}
+
+	if o.StrideOffsetColor != 0 {
+		o.Colors = colors
+		setupStride(o) // colors no longer interleaved
+	}
+
+	o.Coord = nil
+}
@@ -0,0 +1,54 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNonInterleaved(t *testing.T) {
+
+	o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions(WithNonInterleaved(true)))
+	if err != nil {
+		t.Fatalf("TestNonInterleaved: NewObjFromBuf: %v", err)
+	}
+	if o.Coord != nil {
+		t.Errorf("TestNonInterleaved: unexpected Coord")
+	}
+	if !sliceEqualInt(cubeIndices, o.Indices) {
+		t.Errorf("TestNonInterleaved: indices: want=%v got=%v", cubeIndices, o.Indices)
+	}
+
+	elements := o.NumberOfElements()
+	expectInt(t, "TestNonInterleaved: positions", 3*elements, len(o.Positions))
+	if o.TextCoordFound {
+		expectInt(t, "TestNonInterleaved: texcoords", 2*elements, len(o.TexCoords))
+	}
+	if o.NormCoordFound {
+		expectInt(t, "TestNonInterleaved: normals", 3*elements, len(o.Normals))
+	}
+
+	// writing works on either layout
+	var got, want bytes.Buffer
+	if errWrite := o.ToWriter(&got); errWrite != nil {
+		t.Errorf("TestNonInterleaved: ToWriter: %v", errWrite)
+	}
+	ref, _ := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions())
+	ref.ToWriter(&want)
+	if got.String() != want.String() {
+		t.Errorf("TestNonInterleaved: ToWriter: want=%q got=%q", want.String(), got.String())
+	}
+
+	o.Interleave()
+	if !sliceEqualFloat(cubeCoord, o.Coord) {
+		t.Errorf("TestNonInterleaved: Interleave: want=%v got=%v", cubeCoord, o.Coord)
+	}
+	if o.Positions != nil {
+		t.Errorf("TestNonInterleaved: Interleave: Positions not cleared")
+	}
+
+	o.Deinterleave()
+	o.Interleave()
+	if !sliceEqualFloat(cubeCoord, o.Coord) {
+		t.Errorf("TestNonInterleaved: Deinterleave: want=%v got=%v", cubeCoord, o.Coord)
+	}
+}
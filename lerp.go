@@ -0,0 +1,65 @@
+package gwob
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Lerp blends two meshes of the same topology, such as frames of a
+// morph target animation, into a new Obj: t=0 gives a and t=1 gives b.
+// Both must have the same vertex count, the same Indices and the same
+// attributes. Positions, texture coordinates, colors and homogeneous
+// weights are interpolated linearly; normals are interpolated and
+// renormalized. Everything else, like groups and materials, comes
+// from a.
+func Lerp(a, b *Obj, t float32) (*Obj, error) {
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("Lerp: nil input")
+	}
+	strides := a.NumberOfElements()
+	if n := b.NumberOfElements(); n != strides {
+		return nil, fmt.Errorf("Lerp: vertex count mismatch: %d and %d", strides, n)
+	}
+	if !slices.Equal(a.Indices, b.Indices) {
+		return nil, fmt.Errorf("Lerp: indices mismatch")
+	}
+	if a.TextCoordFound != b.TextCoordFound || a.NormCoordFound != b.NormCoordFound || a.ColorFound != b.ColorFound {
+		return nil, fmt.Errorf("Lerp: attribute mismatch")
+	}
+	if len(a.W) != len(b.W) || len(a.TexW) != len(b.TexW) {
+		return nil, fmt.Errorf("Lerp: homogeneous weight mismatch")
+	}
+
+	mix := func(dst, from, to []float32) {
+		for i := range dst {
+			dst[i] = from[i] + t*(to[i]-from[i])
+		}
+	}
+
+	r := a.Clone()
+	for s := 0; s < strides; s++ {
+		mix(r.vertexPosition(s), a.vertexPosition(s), b.vertexPosition(s))
+		if a.TextCoordFound {
+			mix(r.vertexTexCoord(s), a.vertexTexCoord(s), b.vertexTexCoord(s))
+		}
+		if a.NormCoordFound {
+			n := r.vertexNormal(s)
+			mix(n, a.vertexNormal(s), b.vertexNormal(s))
+			if l := math.Sqrt(float64(n[0]*n[0] + n[1]*n[1] + n[2]*n[2])); l > 0 {
+				for i := range n {
+					n[i] = float32(float64(n[i]) / l)
+				}
+			}
+		}
+		if c := r.vertexColor(s); c != nil {
+			if from, to := a.vertexColor(s), b.vertexColor(s); to != nil {
+				mix(c, from, to)
+			}
+		}
+	}
+	mix(r.W, a.W, b.W)
+	mix(r.TexW, a.TexW, b.TexW)
+
+	return r, nil
+}
@@ -0,0 +1,107 @@
+package gwob
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLerp(t *testing.T) {
+	frame0 := `
+v 0 0 0 1 0 0
+v 2 0 0 1 0 0
+v 0 2 0 1 0 0
+vt 0 0
+vt 1 0
+vt 0 1
+vn 0 0 1
+vn 1 0 0
+f 1/1/1 2/2/2 3/3/1
+`
+	frame1 := `
+v 0 0 2 0 0 1
+v 4 0 2 0 0 1
+v 0 2 2 0 0 1
+vt 1 1
+vt 1 0
+vt 0 1
+vn 0 0 1
+vn 0 1 0
+f 1/1/1 2/2/2 3/3/1
+`
+	table := []struct {
+		name    string
+		options *ObjParserOptions
+	}{
+		{"interleaved", NewObjParserOptions()},
+		{"colors", NewObjParserOptions(WithInterleaveColors(true))},
+		{"separate", NewObjParserOptions(WithNonInterleaved(true))},
+	}
+	for _, data := range table {
+		a, err := NewObjFromBuf("frame0", []byte(frame0), data.options)
+		if err != nil {
+			t.Fatalf("TestLerp: %s: NewObjFromBuf: %v", data.name, err)
+		}
+		b, err := NewObjFromBuf("frame1", []byte(frame1), data.options)
+		if err != nil {
+			t.Fatalf("TestLerp: %s: NewObjFromBuf: %v", data.name, err)
+		}
+
+		m, err := Lerp(a, b, 0.5)
+		if err != nil {
+			t.Fatalf("TestLerp: %s: %v", data.name, err)
+		}
+		v := m.Vertex(1)
+		if v.Position != [3]float32{3, 0, 1} {
+			t.Errorf("TestLerp: %s: position=%v", data.name, v.Position)
+		}
+		if v0 := m.Vertex(0); v0.UV != [2]float32{0.5, 0.5} {
+			t.Errorf("TestLerp: %s: uv=%v", data.name, v0.UV)
+		}
+		h := float32(math.Sqrt(0.5))
+		if math.Abs(float64(v.Normal[0]-h)) > 1e-6 || math.Abs(float64(v.Normal[1]-h)) > 1e-6 || v.Normal[2] != 0 {
+			t.Errorf("TestLerp: %s: normal=%v", data.name, v.Normal)
+		}
+		if r, g, bl := m.VertexColor(1); r != 0.5 || g != 0 || bl != 0.5 {
+			t.Errorf("TestLerp: %s: color=%v,%v,%v", data.name, r, g, bl)
+		}
+
+		// inputs unchanged, ends reproduced
+		if a.Vertex(1).Position != [3]float32{2, 0, 0} {
+			t.Errorf("TestLerp: %s: input changed", data.name)
+		}
+		end, err := Lerp(a, b, 1)
+		if err != nil {
+			t.Fatalf("TestLerp: %s: %v", data.name, err)
+		}
+		for s := 0; s < end.NumberOfElements(); s++ {
+			if end.Vertex(s) != b.Vertex(s) {
+				t.Errorf("TestLerp: %s: t=1 stride %d: %+v want %+v", data.name, s, end.Vertex(s), b.Vertex(s))
+			}
+		}
+	}
+}
+
+func TestLerpMismatch(t *testing.T) {
+	parse := func(str string) *Obj {
+		o, err := NewObjFromBuf("lerp", []byte(str), NewObjParserOptions())
+		if err != nil {
+			t.Fatalf("TestLerpMismatch: NewObjFromBuf: %v", err)
+		}
+		return o
+	}
+	a := parse("v 0 0 0\nv 1 0 0\nv 0 1 0\nv 1 1 0\nf 1 2 3\nf 2 4 3\n")
+	table := []struct {
+		name string
+		b    *Obj
+	}{
+		{"vertices", parse("v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3\n")},
+		{"indices", parse("v 0 0 0\nv 1 0 0\nv 0 1 0\nv 1 1 0\nf 1 2 3\nf 2 3 4\n")},
+		{"attributes", parse("v 0 0 0\nv 1 0 0\nv 0 1 0\nv 1 1 0\nvn 0 0 1\nf 1//1 2//1 3//1\nf 2//1 4//1 3//1\n")},
+		{"nil", nil},
+	}
+	for _, data := range table {
+		if _, err := Lerp(a, data.b, 0.5); err == nil {
+			t.Errorf("TestLerpMismatch: %s: missing error", data.name)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package gwob
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+)
+
+// ErrLimitExceeded is matched by errors.Is for any LimitError.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// Limits bounds the resources the parser may consume,
+// protecting services that accept untrusted input.
+// Zero values mean unlimited.
+type Limits struct {
+	MaxLineLength int   // bytes in a single line
+	MaxVertices   int   // v statements, and also unified vertices
+	MaxFaces      int   // f statements
+	MaxGroups     int   // groups created
+	MaxMemory     int64 // estimated bytes held by the parser
+}
+
+// LimitError reports a resource limit exceeded while parsing.
+type LimitError struct {
+	File  string
+	Line  int
+	Limit string // name of the Limits field exceeded
+	Max   int64
+	Value int64
+}
+
+// Error implements the error interface.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("file=%s line=%d: %v: %s=%d value=%d", e.File, e.Line, ErrLimitExceeded, e.Limit, e.Max, e.Value)
+}
+
+// Is matches ErrLimitExceeded.
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+func (p *objParser) limitError(limit string, max, value int64) error {
+	return &LimitError{File: p.objName, Line: p.lineCount, Limit: limit, Max: max, Value: value}
+}
+
+// checkLimits verifies parser state against the limits.
+func (p *objParser) checkLimits(o *Obj, options *ObjParserOptions) error {
+	lim := &options.Limits
+
+	if max := lim.MaxVertices; max > 0 {
+		if v := (len(p.vertCoord) + len(p.vertCoord64)) / 3; v > max {
+			return p.limitError("MaxVertices", int64(max), int64(v))
+		}
+		if p.indexCount > max {
+			return p.limitError("MaxVertices", int64(max), int64(p.indexCount))
+		}
+	}
+
+	if max := lim.MaxFaces; max > 0 && p.faceLines > max {
+		return p.limitError("MaxFaces", int64(max), int64(p.faceLines))
+	}
+
+	if max := lim.MaxGroups; max > 0 && len(o.Groups) > max {
+		return p.limitError("MaxGroups", int64(max), int64(len(o.Groups)))
+	}
+
+	if max := lim.MaxMemory; max > 0 {
+		if m := p.memory(o); m > max {
+			return p.limitError("MaxMemory", max, m)
+		}
+	}
+
+	return nil
+}
+
+// memory estimates the bytes held by the parser.
+func (p *objParser) memory(o *Obj) int64 {
+	m := 4 * int64(len(p.vertCoord)+len(p.textCoord)+len(p.normCoord)+len(p.colorCoord))
+	m += 8 * int64(len(p.vertCoord64)+len(p.textCoord64)+len(p.normCoord64)+len(p.coord64))
+	m += p.lineBytes + 72*int64(len(p.lineBuf)) // deferred line headers
+	m += 48 * int64(len(p.indexTable))          // rough map entry cost
+	m += 4*int64(len(o.Coord)) + 8*int64(len(o.Indices)) + 64*int64(len(o.Groups))
+	m += 4 * int64(len(o.Positions)+len(o.TexCoords)+len(o.Normals)+len(o.Colors))
+	return m
+}
+
+// readLine reads a line, enforcing the maximum line length (if max > 0)
+// before the whole line is buffered in memory, when possible.
+func readLine(reader StringReader, max int) (string, error) {
+	if max < 1 {
+		return reader.ReadString('\n')
+	}
+
+	br, isBuf := reader.(*bufio.Reader)
+	if !isBuf {
+		line, err := reader.ReadString('\n')
+		if len(line) > max {
+			return "", &LimitError{Limit: "MaxLineLength", Max: int64(max), Value: int64(len(line))}
+		}
+		return line, err
+	}
+
+	var buf []byte
+	for {
+		chunk, err := br.ReadSlice('\n')
+		if len(buf)+len(chunk) > max {
+			return "", &LimitError{Limit: "MaxLineLength", Max: int64(max), Value: int64(len(buf) + len(chunk))}
+		}
+		buf = append(buf, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue // line longer than buffer
+		}
+		return string(buf), err
+	}
+}
@@ -0,0 +1,78 @@
+package gwob
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+
+	table := []struct {
+		name   string
+		limits Limits
+		limit  string
+	}{
+		{"none", Limits{}, ""},
+		{"line", Limits{MaxLineLength: 10}, "MaxLineLength"},
+		{"vertices", Limits{MaxVertices: 10}, "MaxVertices"},
+		{"faces", Limits{MaxFaces: 5}, "MaxFaces"},
+		{"groups", Limits{MaxGroups: 1}, "MaxGroups"},
+		{"memory", Limits{MaxMemory: 100}, "MaxMemory"},
+		{"generous", Limits{MaxLineLength: 100, MaxVertices: 100, MaxFaces: 100, MaxGroups: 10, MaxMemory: 1 << 20}, ""},
+	}
+
+	for _, data := range table {
+		options := NewObjParserOptions(WithLimits(data.limits))
+
+		_, err := NewObjFromBuf("cubeObj", []byte(cubeObj+"\ng other\nf 1 2 3\n"), options)
+
+		if data.limit == "" {
+			if err != nil {
+				t.Errorf("TestLimits: %s: unexpected error: %v", data.name, err)
+			}
+			continue
+		}
+
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("TestLimits: %s: want ErrLimitExceeded got %v", data.name, err)
+			continue
+		}
+
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) || limitErr.Limit != data.limit {
+			t.Errorf("TestLimits: %s: want limit %s got %v", data.name, data.limit, err)
+		}
+	}
+
+	// line longer than the bufio buffer is rejected without buffering it whole
+	long := "v 1 1 1" + strings.Repeat(" ", 10000) + "\n"
+	_, err := NewObjFromReader("long", strings.NewReader(long), NewObjParserOptions(WithLimits(Limits{MaxLineLength: 5000})))
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("TestLimits: long line: want ErrLimitExceeded got %v", err)
+	}
+
+	_, errLib := ReadMaterialLibFromReader(strings.NewReader(long), NewObjParserOptions(WithLimits(Limits{MaxLineLength: 5000})))
+	if !errors.Is(errLib, ErrLimitExceeded) {
+		t.Errorf("TestLimits: lib long line: want ErrLimitExceeded got %v", errLib)
+	}
+
+	// lib limit errors locate the line like obj ones
+	libFile := filepath.Join(t.TempDir(), "long.mtl")
+	if err := os.WriteFile(libFile, []byte("newmtl a\n"+long), 0o644); err != nil {
+		t.Fatalf("TestLimits: WriteFile: %v", err)
+	}
+	_, errLib = ReadMaterialLibFromFile(libFile, NewObjParserOptions(WithLimits(Limits{MaxLineLength: 5000})))
+	var libLimit *LimitError
+	if !errors.As(errLib, &libLimit) || libLimit.File != libFile || libLimit.Line != 2 {
+		t.Errorf("TestLimits: lib location: got %v", errLib)
+	}
+
+	// double precision vertices count too
+	_, err = NewObj64FromBuf("verts", []byte(strings.Repeat("v 0 0 0\n", 5)), NewObjParserOptions(WithLimits(Limits{MaxVertices: 2})))
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("TestLimits: Obj64 vertices: want ErrLimitExceeded got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package gwob
+
+import (
+	"strings"
+)
+
+// parseLineElement parses the corners of an l statement into
+// a polyline of unified indices on the current group.
+func parseLineElement(p *objParser, o *Obj, text string, options *ObjParserOptions) error {
+	corners := strings.Fields(text)
+	if len(corners) < 2 {
+		return p.errorf(ErrBadFace, "bad line=[%s] size=%d", text, len(corners))
+	}
+
+	// resolve all corners before emitting any vertex
+	keys := make([]vertexKey, len(corners))
+	for i, corner := range corners {
+		k, err := resolveCorner(p, corner, options)
+		if err != nil {
+			return p.errorf(ErrBadFace, "bad line=[%s] index_v%d=[%s]: %v", text, i, corner, err)
+		}
+		keys[i] = k
+	}
+
+	line := make([]int, len(keys))
+	for i, k := range keys {
+		line[i] = unifyVertex(p, o, k, options)
+	}
+	p.currGroup.Lines = append(p.currGroup.Lines, line)
+
+	return nil
+}
+
+// isEmpty reports whether the group holds no elements.
+func (g *Group) isEmpty() bool {
+	return g.IndexCount == 0 && len(g.Lines) == 0 && len(g.Points) == 0
+}
@@ -0,0 +1,60 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+var linesObj = `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+vt 0 0
+vt 1 0
+g path
+l 1 2 3 1
+l 1 2
+# forward reference
+l 3 4
+v 0 1 0
+l 1 2 x
+g mesh
+f 1 2 3
+`
+
+func TestLines(t *testing.T) {
+
+	o, err := NewObjFromBuf("linesObj", []byte(linesObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestLines: NewObjFromBuf: %v", err)
+	}
+
+	expectInt(t, "TestLines: groups", 2, len(o.Groups))
+	expectInt(t, "TestLines: errors", 1, o.Errors())
+	expectInt(t, "TestLines: warnings", 0, o.Warnings())
+
+	path := o.Groups[0]
+	if path.Name != "path" {
+		t.Fatalf("TestLines: group: want=path got=%s", path.Name)
+	}
+	expectInt(t, "TestLines: path indices", 0, path.IndexCount)
+	expectInt(t, "TestLines: lines", 3, len(path.Lines))
+
+	want := [][]int{{0, 1, 2, 0}, {0, 1}, {2, 3}}
+	for i, line := range path.Lines {
+		if !sliceEqualInt(want[i], line) {
+			t.Errorf("TestLines: line %d: want=%v got=%v", i, want[i], line)
+		}
+	}
+
+	// round trip
+	var buf bytes.Buffer
+	if errWrite := o.ToWriter(&buf); errWrite != nil {
+		t.Fatalf("TestLines: ToWriter: %v", errWrite)
+	}
+	o2, err := NewObjFromBuf("linesObj2", buf.Bytes(), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestLines: reparse: %v", err)
+	}
+	expectInt(t, "TestLines: reparsed lines", len(path.Lines), len(o2.Groups[0].Lines))
+}
@@ -0,0 +1,55 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+var lodObj = `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+g rock
+lod 1
+f 1 2 3
+lod 2
+f 1 2 3
+lod 0
+f 1 2 3
+lod 101
+`
+
+func TestLod(t *testing.T) {
+
+	o, err := NewObjFromBuf("lodObj", []byte(lodObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestLod: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestLod: errors", 1, o.Errors())
+
+	want := []int{1, 2, 0}
+	expectInt(t, "TestLod: groups", len(want), len(o.Groups))
+	for i, g := range o.Groups {
+		if i < len(want) {
+			expectInt(t, "TestLod: group lod", want[i], g.Lod)
+			if g.Name != "rock" {
+				t.Errorf("TestLod: group %d: name=%q", i, g.Name)
+			}
+		}
+	}
+
+	// round trip
+	var buf bytes.Buffer
+	if errWrite := o.ToWriter(&buf); errWrite != nil {
+		t.Fatalf("TestLod: ToWriter: %v", errWrite)
+	}
+	o2, err := NewObjFromBuf("lodObj2", buf.Bytes(), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestLod: reparse: %v", err)
+	}
+	for i, g := range o2.Groups {
+		if i < len(want) {
+			expectInt(t, "TestLod: reparsed group lod", want[i], g.Lod)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package gwob
+
+import (
+	"fmt"
+
+	"github.com/udhos/gwob/internal/qem"
+)
+
+// GenerateLODs builds progressively simplified copies of the mesh, one
+// for each ratio of the original triangle count, by quadric error metric
+// decimation as in the simplify package. Ratios must be in (0,1] and not
+// increasing; each level is decimated from the previous one, so the
+// chain costs about as much as its first level. Group, material and
+// smoothing boundaries and texture seams are preserved, every level keeps
+// all groups, and the original faces are discarded. The Obj is not
+// changed.
+func (o *Obj) GenerateLODs(ratios []float64) ([]*Obj, error) {
+	if len(o.Indices)%3 != 0 {
+		return nil, fmt.Errorf("GenerateLODs: index count=%d must be a multiple of 3", len(o.Indices))
+	}
+	triangles := len(o.Indices) / 3
+
+	lods := make([]*Obj, 0, len(ratios))
+	prev := o
+	last := 1.0
+	for i, r := range ratios {
+		if r <= 0 || r > last {
+			return nil, fmt.Errorf("GenerateLODs: ratio %d=%v must be in (0,%v]", i, r, last)
+		}
+		last = r
+		lod, err := prev.Decimate(int(r*float64(triangles)), 0)
+		if err != nil {
+			return nil, err
+		}
+		lods = append(lods, lod)
+		prev = lod
+	}
+	return lods, nil
+}
+
+// Decimate returns a copy simplified by quadric error metric edge
+// collapse down to targetTriangles, or as close as boundaries allow.
+// maxError, relative to the bounding box diagonal, stops it earlier;
+// zero means no limit. Group, material and smoothing boundaries and
+// texture seams are preserved, and the original faces are discarded.
+// It backs both GenerateLODs and the simplify package.
+func (o *Obj) Decimate(targetTriangles int, maxError float64) (*Obj, error) {
+	if len(o.Indices)%3 != 0 {
+		return nil, fmt.Errorf("Decimate: index count=%d must be a multiple of 3", len(o.Indices))
+	}
+
+	strides := o.NumberOfElements()
+	positions := make([]float64, 0, 3*strides)
+	for s := 0; s < strides; s++ {
+		x, y, z := o.VertexCoordinates(s)
+		positions = append(positions, float64(x), float64(y), float64(z))
+	}
+
+	result := qem.Simplify(qem.Input{
+		Positions: positions,
+		Indices:   o.Indices,
+		Classes:   o.triangleClasses(),
+	}, qem.Options{TargetTriangles: targetTriangles, MaxError: maxError})
+
+	c := o.Clone()
+	c.Faces = nil
+	c.Corners = nil
+	keep := make([]bool, len(o.Indices)/3)
+	for _, t := range result.Triangles {
+		keep[t] = true
+	}
+	selectTriangles(c, keep)
+	copy(c.Indices, result.Indices) // kept triangles, same order
+	c.Clean()                       // drop the vertices collapsed away
+	return c, nil
+}
+
+// triangleClass identifies the group, material and smoothing group of
+// a triangle, whose boundaries decimation must keep.
+type triangleClass struct {
+	group, material, smooth int
+}
+
+// triangleClasses numbers the distinct classes of the triangles.
+func (o *Obj) triangleClasses() []int {
+	ids := map[triangleClass]int{}
+	list := make([]int, len(o.Indices)/3)
+	for g, gr := range o.Groups {
+		begin, end := o.triangleRange(gr)
+		for t := begin; t < end; t++ {
+			c := triangleClass{group: g, material: -1, smooth: gr.Smooth}
+			if t < len(o.MaterialIndex) {
+				c.material = o.MaterialIndex[t]
+			}
+			if t < len(o.Smoothing) {
+				c.smooth = o.Smoothing[t]
+			}
+			id, found := ids[c]
+			if !found {
+				id = len(ids)
+				ids[c] = id
+			}
+			list[t] = id
+		}
+	}
+	return list
+}
@@ -0,0 +1,99 @@
+package gwob
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// lodGrid builds a 10x10 grid of quads with texture coordinates, split
+// in groups left (x<5) and right (x>=5) of different materials.
+func lodGrid(t *testing.T) *Obj {
+	var b strings.Builder
+	for y := 0; y <= 10; y++ {
+		for x := 0; x <= 10; x++ {
+			fmt.Fprintf(&b, "v %d %d 0\nvt %v %v\n", x, y, float64(x)/10, float64(y)/10)
+		}
+	}
+	for _, g := range []string{"left", "right"} {
+		fmt.Fprintf(&b, "g %s\nusemtl %s_mtl\n", g, g)
+		for y := 0; y < 10; y++ {
+			for x := 0; x < 10; x++ {
+				if (x < 5) != (g == "left") {
+					continue
+				}
+				a := y*11 + x + 1
+				fmt.Fprintf(&b, "f %d/%d %d/%d %d/%d %d/%d\n", a, a, a+1, a+1, a+12, a+12, a+11, a+11)
+			}
+		}
+	}
+	o, err := NewObjFromBuf("grid", []byte(b.String()), NewObjParserOptions(WithKeepFaces(true)))
+	if err != nil {
+		t.Fatalf("lodGrid: NewObjFromBuf: %v", err)
+	}
+	return o
+}
+
+func TestGenerateLODs(t *testing.T) {
+	o := lodGrid(t)
+	indices := len(o.Indices)
+
+	lods, err := o.GenerateLODs([]float64{1, 0.5, 0.2})
+	if err != nil {
+		t.Fatalf("TestGenerateLODs: %v", err)
+	}
+	expectInt(t, "TestGenerateLODs: levels", 3, len(lods))
+	expectInt(t, "TestGenerateLODs: input indices", indices, len(o.Indices))
+	if o.Faces == nil {
+		t.Errorf("TestGenerateLODs: input faces dropped")
+	}
+	expectInt(t, "TestGenerateLODs: full level", indices, len(lods[0].Indices))
+
+	prev := indices
+	for i, lod := range lods[1:] {
+		if len(lod.Indices) >= prev {
+			t.Errorf("TestGenerateLODs: level %d: indices=%d not below %d", i+1, len(lod.Indices), prev)
+		}
+		prev = len(lod.Indices)
+		if lod.Faces != nil {
+			t.Errorf("TestGenerateLODs: level %d: faces kept", i+1)
+		}
+
+		expectInt(t, "TestGenerateLODs: groups", len(o.Groups), len(lod.Groups))
+		for _, g := range lod.Groups {
+			if g.IndexCount == 0 {
+				t.Errorf("TestGenerateLODs: level %d: group %s emptied", i+1, g.Name)
+			}
+			for _, tri := range lod.Triangles(g) {
+				for _, p := range tri.Positions {
+					if (g.Name == "left" && p[0] > 5) || (g.Name == "right" && p[0] < 5) {
+						t.Errorf("TestGenerateLODs: level %d: group %s has vertex %v", i+1, g.Name, p)
+					}
+				}
+			}
+			if want := g.Name + "_mtl"; g.Usemtl != want {
+				t.Errorf("TestGenerateLODs: level %d: group %s material=%s", i+1, g.Name, g.Usemtl)
+			}
+		}
+
+		// surviving vertices keep their texture coordinates
+		for s := 0; s < lod.NumberOfElements(); s++ {
+			v := lod.Vertex(s)
+			if v.UV != [2]float32{v.Position[0] / 10, v.Position[1] / 10} {
+				t.Errorf("TestGenerateLODs: level %d: vertex %v has uv %v", i+1, v.Position, v.UV)
+			}
+		}
+	}
+	if area := lods[2].SurfaceArea(); area != 100 {
+		t.Errorf("TestGenerateLODs: last level area=%v", area) // flat grid keeps its outline
+	}
+}
+
+func TestGenerateLODsErrors(t *testing.T) {
+	o := lodGrid(t)
+	for _, ratios := range [][]float64{{0}, {1.5}, {0.5, 0.8}, {-1}} {
+		if _, err := o.GenerateLODs(ratios); err == nil {
+			t.Errorf("TestGenerateLODsErrors: %v: missing error", ratios)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package gwob
+
+import (
+	"context"
+	"log/slog"
+)
+
+func (opt *ObjParserOptions) logLevel(level slog.Level, msg string) {
+	if opt.Logger != nil {
+		opt.Logger(msg)
+	}
+	if opt.Slog != nil {
+		opt.Slog.Log(context.Background(), level, msg)
+	}
+}
+
+// debug logs informative messages, like parser stats.
+func (opt *ObjParserOptions) debug(msg string) {
+	opt.logLevel(slog.LevelDebug, msg)
+}
+
+// warn logs problems found in the input.
+func (opt *ObjParserOptions) warn(msg string) {
+	opt.logLevel(slog.LevelWarn, msg)
+}
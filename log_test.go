@@ -0,0 +1,29 @@
+package gwob
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlog(t *testing.T) {
+
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	_, err := NewObjFromBuf("slog", []byte("v 0 0 0\nbogus\n"), NewObjParserOptions(WithSlog(logger), WithLogStats(true)))
+	if err != nil {
+		t.Errorf("TestSlog: NewObjFromBuf: %v", err)
+		return
+	}
+
+	out := buf.String()
+
+	if !strings.Contains(out, "level=WARN") || !strings.Contains(out, "bogus") {
+		t.Errorf("TestSlog: missing warning: %s", out)
+	}
+	if !strings.Contains(out, "level=DEBUG") || !strings.Contains(out, "STATS") {
+		t.Errorf("TestSlog: missing debug stats: %s", out)
+	}
+}
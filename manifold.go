@@ -0,0 +1,75 @@
+package gwob
+
+// ManifoldReport tells how the triangles of a mesh depart from a closed
+// 2-manifold surface. Vertices are identified by position, as in
+// Adjacency.
+type ManifoldReport struct {
+	BoundaryEdges       []Edge // edges of a single triangle
+	NonManifoldEdges    []Edge // edges shared by more than two triangles
+	NonManifoldVertices []int  // strides where separate surface sheets touch
+}
+
+// Manifold reports whether every edge and vertex has a disk or half-disk
+// neighborhood; boundaries are allowed.
+func (r *ManifoldReport) Manifold() bool {
+	return len(r.NonManifoldEdges) == 0 && len(r.NonManifoldVertices) == 0
+}
+
+// Watertight reports whether the surface is a closed manifold, without
+// boundaries, as required by 3D printing and CSG.
+func (r *ManifoldReport) Watertight() bool {
+	return r.Manifold() && len(r.BoundaryEdges) == 0
+}
+
+// CheckManifold finds the boundary and non-manifold edges, and the
+// non-manifold vertices, of the triangles.
+func (o *Obj) CheckManifold() ManifoldReport {
+	adj := o.Adjacency()
+	var report ManifoldReport
+
+	vertexEdges := map[int][]int{} // position -> edges
+	for i, e := range adj.Edges {
+		switch faces := len(adj.EdgeFaces[i]); {
+		case faces == 1:
+			report.BoundaryEdges = append(report.BoundaryEdges, e)
+		case faces > 2:
+			report.NonManifoldEdges = append(report.NonManifoldEdges, e)
+		}
+		vertexEdges[e.A] = append(vertexEdges[e.A], i)
+		vertexEdges[e.B] = append(vertexEdges[e.B], i)
+	}
+
+	// the triangles around a manifold vertex form a single fan,
+	// connected through the edges at the vertex
+	for s, p := range adj.Position {
+		if p != s || len(adj.VertexFaces[s]) < 2 {
+			continue
+		}
+		parent := map[int]int{}
+		for _, t := range adj.VertexFaces[s] {
+			parent[t] = t
+		}
+		var find func(t int) int
+		find = func(t int) int {
+			if parent[t] != t {
+				parent[t] = find(parent[t])
+			}
+			return parent[t]
+		}
+		fans := len(parent)
+		for _, i := range vertexEdges[s] {
+			faces := adj.EdgeFaces[i]
+			for _, t := range faces[1:] {
+				if a, b := find(faces[0]), find(t); a != b {
+					parent[a] = b
+					fans--
+				}
+			}
+		}
+		if fans > 1 {
+			report.NonManifoldVertices = append(report.NonManifoldVertices, s)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,41 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestCheckManifold(t *testing.T) {
+	table := []struct {
+		name       string
+		buf        string
+		boundary   int
+		edges      int
+		vertices   int
+		manifold   bool
+		watertight bool
+	}{
+		{"box", boxObj, 0, 0, 0, true, true},
+		{"cube", cubeObj, 0, 0, 0, true, true}, // seams do not open the surface
+		{"quad", "v 0 0 0\nv 1 0 0\nv 1 1 0\nv 0 1 0\nf 1 2 3 4\n", 4, 0, 0, true, false},
+		{"fin", "v 0 0 0\nv 1 0 0\nv 0 1 0\nv 0 -1 0\nv 0 0 1\nf 1 2 3\nf 2 1 4\nf 1 2 5\n", 6, 1, 0, false, false},
+		{"bowtie", "v 0 0 0\nv 1 0 0\nv 1 1 0\nv -1 0 0\nv -1 -1 0\nf 1 2 3\nf 1 4 5\n", 6, 0, 1, false, false},
+		{"degenerate", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3\nf 1 2 2\n", 3, 0, 0, true, false},
+	}
+
+	for _, data := range table {
+		o, err := NewObjFromBuf(data.name, []byte(data.buf), NewObjParserOptions())
+		if err != nil {
+			t.Fatalf("TestCheckManifold: %s: NewObjFromBuf: %v", data.name, err)
+		}
+		report := o.CheckManifold()
+		expectInt(t, "TestCheckManifold: "+data.name+": boundary edges", data.boundary, len(report.BoundaryEdges))
+		expectInt(t, "TestCheckManifold: "+data.name+": non-manifold edges", data.edges, len(report.NonManifoldEdges))
+		expectInt(t, "TestCheckManifold: "+data.name+": non-manifold vertices", data.vertices, len(report.NonManifoldVertices))
+		if report.Manifold() != data.manifold {
+			t.Errorf("TestCheckManifold: %s: manifold=%v want=%v", data.name, report.Manifold(), data.manifold)
+		}
+		if report.Watertight() != data.watertight {
+			t.Errorf("TestCheckManifold: %s: watertight=%v want=%v", data.name, report.Watertight(), data.watertight)
+		}
+	}
+}
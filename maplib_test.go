@@ -0,0 +1,33 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMaplib(t *testing.T) {
+
+	str := "maplib grass.mpc stone.mpc\nmaplib grass.mpc\n" + cubeObj
+
+	o, err := NewObjFromBuf("cubeObj", []byte(str), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestMaplib: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestMaplib: errors", 0, o.Errors())
+	if len(o.Maplibs) != 2 || o.Maplibs[0] != "grass.mpc" || o.Maplibs[1] != "stone.mpc" {
+		t.Errorf("TestMaplib: maplibs: got=%q", o.Maplibs)
+	}
+
+	// round trip
+	var buf bytes.Buffer
+	if errWrite := o.ToWriter(&buf); errWrite != nil {
+		t.Fatalf("TestMaplib: ToWriter: %v", errWrite)
+	}
+	o2, err := NewObjFromBuf("cubeObj2", buf.Bytes(), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestMaplib: reparse: %v", err)
+	}
+	if len(o2.Maplibs) != 2 || o2.Maplibs[0] != o.Maplibs[0] || o2.Maplibs[1] != o.Maplibs[1] {
+		t.Errorf("TestMaplib: reparsed maplibs: got=%q", o2.Maplibs)
+	}
+}
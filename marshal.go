@@ -0,0 +1,414 @@
+package gwob
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// SchemaVersion is the version of the JSON and gob encodings of
+// Obj and MaterialLib. Decoding rejects other versions.
+const SchemaVersion = 1
+
+func init() {
+	// allow Obj and MaterialLib values inside interfaces, as in net/rpc
+	gob.Register(&Obj{})
+	gob.Register(MaterialLib{})
+}
+
+// objData is the stable encoding of Obj. The vertex data is always
+// interleaved. Parser details like raw arrays, original faces,
+// free-form geometry and diagnostics are not encoded.
+type objData struct {
+	Version        int           `json:"version"`
+	Mtllib         string        `json:"mtllib,omitempty"`
+	Mtllibs        []string      `json:"mtllibs,omitempty"`
+	Maplibs        []string      `json:"maplibs,omitempty"`
+	ShadowObj      string        `json:"shadowObj,omitempty"`
+	TraceObj       string        `json:"traceObj,omitempty"`
+	Groups         []groupData   `json:"groups"`
+	Coord          []float32     `json:"coord"`
+	Indices        []int         `json:"indices"`
+	StrideSize     int           `json:"strideSize"`
+	OffsetPosition int           `json:"strideOffsetPosition"`
+	OffsetTexture  int           `json:"strideOffsetTexture"`
+	OffsetNormal   int           `json:"strideOffsetNormal"`
+	OffsetColor    int           `json:"strideOffsetColor,omitempty"`
+	TexCoordFound  bool          `json:"texCoordFound,omitempty"`
+	NormalFound    bool          `json:"normalFound,omitempty"`
+	ColorFound     bool          `json:"colorFound,omitempty"`
+	BigIndexFound  bool          `json:"bigIndexFound,omitempty"`
+	Colors         []float32     `json:"colors,omitempty"`
+	W              []float32     `json:"w,omitempty"`
+	TexW           []float32     `json:"texW,omitempty"`
+	Smoothing      []int         `json:"smoothing,omitempty"`
+	MaterialIndex  []int         `json:"materialIndex,omitempty"`
+	MaterialNames  []string      `json:"materialNames,omitempty"`
+	Comments       []commentData `json:"comments,omitempty"`
+}
+
+// groupData is the stable encoding of Group.
+type groupData struct {
+	Name       string   `json:"name"`
+	Smooth     int      `json:"smooth,omitempty"`
+	Usemtl     string   `json:"usemtl,omitempty"`
+	Usemap     string   `json:"usemap,omitempty"`
+	Lod        int      `json:"lod,omitempty"`
+	IndexBegin int      `json:"indexBegin"`
+	IndexCount int      `json:"indexCount"`
+	Names      []string `json:"names,omitempty"`
+	Lines      [][]int  `json:"lines,omitempty"`
+	Points     []int    `json:"points,omitempty"`
+}
+
+// commentData is the stable encoding of Comment.
+type commentData struct {
+	Text  string `json:"text"`
+	Line  int    `json:"line"`
+	Index int    `json:"index"`
+}
+
+// materialData is the stable encoding of Material.
+type materialData struct {
+	Name  string     `json:"name"`
+	MapKd string     `json:"map_Kd,omitempty"`
+	MapKa string     `json:"map_Ka,omitempty"`
+	MapKs string     `json:"map_Ks,omitempty"`
+	MapD  string     `json:"map_d,omitempty"`
+	Bump  string     `json:"bump,omitempty"`
+	MapKe string     `json:"map_Ke,omitempty"`
+	Kd    [3]float32 `json:"Kd"`
+	Ka    [3]float32 `json:"Ka"`
+	Ks    [3]float32 `json:"Ks"`
+	Ns    float32    `json:"Ns"`
+	Ni    float32    `json:"Ni"`
+	Illum int        `json:"illum"`
+	D     float32    `json:"d"`
+	Tr    float32    `json:"Tr"`
+}
+
+// libData is the stable encoding of MaterialLib.
+type libData struct {
+	Version   int            `json:"version"`
+	Materials []materialData `json:"materials"` // sorted by name
+}
+
+func (o *Obj) data() objData {
+	if o.Coord == nil && o.Positions != nil {
+		c := *o
+		c.Interleave()
+		return c.data()
+	}
+	d := objData{
+		Version:        SchemaVersion,
+		Mtllib:         o.Mtllib,
+		Mtllibs:        o.Mtllibs,
+		Maplibs:        o.Maplibs,
+		ShadowObj:      o.ShadowObj,
+		TraceObj:       o.TraceObj,
+		Groups:         make([]groupData, len(o.Groups)),
+		Coord:          o.Coord,
+		Indices:        o.Indices,
+		StrideSize:     o.StrideSize,
+		OffsetPosition: o.StrideOffsetPosition,
+		OffsetTexture:  o.StrideOffsetTexture,
+		OffsetNormal:   o.StrideOffsetNormal,
+		OffsetColor:    o.StrideOffsetColor,
+		TexCoordFound:  o.TextCoordFound,
+		NormalFound:    o.NormCoordFound,
+		ColorFound:     o.ColorFound,
+		BigIndexFound:  o.BigIndexFound,
+		Colors:         o.Colors,
+		W:              o.W,
+		TexW:           o.TexW,
+		Smoothing:      o.Smoothing,
+		MaterialIndex:  o.MaterialIndex,
+		MaterialNames:  o.MaterialNames,
+	}
+	for i, g := range o.Groups {
+		d.Groups[i] = groupData(*g)
+	}
+	for _, c := range o.Comments {
+		d.Comments = append(d.Comments, commentData(c))
+	}
+	if d.Coord == nil {
+		d.Coord = []float32{}
+	}
+	if d.Indices == nil {
+		d.Indices = []int{}
+	}
+	return d
+}
+
+func (o *Obj) setData(d objData) error {
+	if d.Version != SchemaVersion {
+		return fmt.Errorf("unsupported schema version=%d", d.Version)
+	}
+	*o = Obj{
+		Indices:              d.Indices,
+		Coord:                d.Coord,
+		Mtllib:               d.Mtllib,
+		Mtllibs:              d.Mtllibs,
+		Maplibs:              d.Maplibs,
+		ShadowObj:            d.ShadowObj,
+		TraceObj:             d.TraceObj,
+		Groups:               make([]*Group, len(d.Groups)),
+		W:                    d.W,
+		TexW:                 d.TexW,
+		Colors:               d.Colors,
+		Smoothing:            d.Smoothing,
+		MaterialIndex:        d.MaterialIndex,
+		MaterialNames:        d.MaterialNames,
+		BigIndexFound:        d.BigIndexFound,
+		TextCoordFound:       d.TexCoordFound,
+		NormCoordFound:       d.NormalFound,
+		ColorFound:           d.ColorFound,
+		StrideSize:           d.StrideSize,
+		StrideOffsetPosition: d.OffsetPosition,
+		StrideOffsetTexture:  d.OffsetTexture,
+		StrideOffsetNormal:   d.OffsetNormal,
+		StrideOffsetColor:    d.OffsetColor,
+	}
+	for i, g := range d.Groups {
+		gr := Group(g)
+		o.Groups[i] = &gr
+	}
+	for _, c := range d.Comments {
+		o.Comments = append(o.Comments, Comment(c))
+	}
+	if o.StrideSize == 0 {
+		setupStride(o)
+	}
+	if err := o.checkLayout(); err != nil {
+		return err
+	}
+	if len(o.Coord)%(o.StrideSize/4) != 0 {
+		return fmt.Errorf("coord size=%d not a multiple of stride size=%d", len(o.Coord), o.StrideSize)
+	}
+	return o.checkElements()
+}
+
+// checkElements verifies that indices, groups and per-vertex and
+// per-triangle arrays are consistent with the vertex data.
+func (o *Obj) checkElements() error {
+	strides := o.NumberOfElements()
+	checkIndex := func(i int) error {
+		if i < 0 || i >= strides {
+			return fmt.Errorf("index=%d out of range strides=%d", i, strides)
+		}
+		return nil
+	}
+	if len(o.Indices)%3 != 0 {
+		return fmt.Errorf("indices size=%d not a multiple of 3", len(o.Indices))
+	}
+	for _, i := range o.Indices {
+		if err := checkIndex(i); err != nil {
+			return err
+		}
+	}
+
+	for _, g := range o.Groups {
+		if g.IndexBegin < 0 || g.IndexCount < 0 || g.IndexCount > len(o.Indices)-g.IndexBegin {
+			return fmt.Errorf("group=%s begin=%d count=%d out of range indices=%d", g.Name, g.IndexBegin, g.IndexCount, len(o.Indices))
+		}
+		for _, line := range g.Lines {
+			for _, i := range line {
+				if err := checkIndex(i); err != nil {
+					return fmt.Errorf("group=%s line: %w", g.Name, err)
+				}
+			}
+		}
+		for _, i := range g.Points {
+			if err := checkIndex(i); err != nil {
+				return fmt.Errorf("group=%s point: %w", g.Name, err)
+			}
+		}
+	}
+
+	perVertex := []struct {
+		name   string
+		size   int
+		floats int
+	}{
+		{"colors", len(o.Colors), 3},
+		{"w", len(o.W), 1},
+		{"texW", len(o.TexW), 1},
+	}
+	for _, a := range perVertex {
+		if a.size != 0 && a.size != a.floats*strides {
+			return fmt.Errorf("%s size=%d want=%d", a.name, a.size, a.floats*strides)
+		}
+	}
+
+	triangles := len(o.Indices) / 3
+	if n := len(o.Smoothing); n != 0 && n != triangles {
+		return fmt.Errorf("smoothing size=%d want=%d triangles", n, triangles)
+	}
+	if n := len(o.MaterialIndex); n != 0 && n != triangles {
+		return fmt.Errorf("material index size=%d want=%d triangles", n, triangles)
+	}
+	for _, m := range o.MaterialIndex {
+		if m < -1 || m >= len(o.MaterialNames) {
+			return fmt.Errorf("material index=%d out of range materials=%d", m, len(o.MaterialNames))
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler with a stable schema.
+func (o *Obj) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.data())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (o *Obj) UnmarshalJSON(b []byte) error {
+	var d objData
+	if err := json.Unmarshal(b, &d); err != nil {
+		return err
+	}
+	if err := o.setData(d); err != nil {
+		return fmt.Errorf("Obj.UnmarshalJSON: %w", err)
+	}
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder with the same schema as MarshalJSON.
+func (o *Obj) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(o.data())
+	return buf.Bytes(), err
+}
+
+// GobDecode implements gob.GobDecoder.
+func (o *Obj) GobDecode(b []byte) error {
+	var d objData
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&d); err != nil {
+		return err
+	}
+	if err := o.setData(d); err != nil {
+		return fmt.Errorf("Obj.GobDecode: %w", err)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler with a stable schema.
+func (g Group) MarshalJSON() ([]byte, error) {
+	return json.Marshal(groupData(g))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (g *Group) UnmarshalJSON(b []byte) error {
+	var d groupData
+	if err := json.Unmarshal(b, &d); err != nil {
+		return err
+	}
+	*g = Group(d)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler with a stable schema,
+// using the MTL statement names as keys.
+func (m Material) MarshalJSON() ([]byte, error) {
+	return json.Marshal(materialData(m))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *Material) UnmarshalJSON(b []byte) error {
+	var d materialData
+	if err := json.Unmarshal(b, &d); err != nil {
+		return err
+	}
+	*m = Material(d)
+	return nil
+}
+
+func (lib MaterialLib) data() libData {
+	d := libData{Version: SchemaVersion, Materials: make([]materialData, 0, len(lib.Lib))}
+	for _, m := range lib.Lib {
+		d.Materials = append(d.Materials, materialData(*m))
+	}
+	sort.Slice(d.Materials, func(i, j int) bool {
+		return d.Materials[i].Name < d.Materials[j].Name
+	})
+	return d
+}
+
+func (lib *MaterialLib) setData(d libData) error {
+	if d.Version != SchemaVersion {
+		return fmt.Errorf("unsupported schema version=%d", d.Version)
+	}
+	*lib = NewMaterialLib()
+	for _, m := range d.Materials {
+		mat := Material(m)
+		lib.Lib[mat.Name] = &mat
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler with a stable schema:
+// a list of materials sorted by name. Diagnostics are not encoded.
+func (lib MaterialLib) MarshalJSON() ([]byte, error) {
+	return json.Marshal(lib.data())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (lib *MaterialLib) UnmarshalJSON(b []byte) error {
+	var d libData
+	if err := json.Unmarshal(b, &d); err != nil {
+		return err
+	}
+	if err := lib.setData(d); err != nil {
+		return fmt.Errorf("MaterialLib.UnmarshalJSON: %w", err)
+	}
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder with the same schema as MarshalJSON.
+func (lib MaterialLib) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(lib.data())
+	return buf.Bytes(), err
+}
+
+// GobDecode implements gob.GobDecoder.
+func (lib *MaterialLib) GobDecode(b []byte) error {
+	var d libData
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&d); err != nil {
+		return err
+	}
+	if err := lib.setData(d); err != nil {
+		return fmt.Errorf("MaterialLib.GobDecode: %w", err)
+	}
+	return nil
+}
+
+// checkLayout verifies that the stride holds a position and that every
+// attribute offset fits inside it.
+func (o *Obj) checkLayout() error {
+	if o.StrideSize < 3*4 || o.StrideSize%4 != 0 {
+		return fmt.Errorf("bad stride size=%d", o.StrideSize)
+	}
+	attributes := []struct {
+		name    string
+		offset  int
+		floats  int
+		present bool
+	}{
+		{"position", o.StrideOffsetPosition, 3, true},
+		{"texture", o.StrideOffsetTexture, 2, o.TextCoordFound},
+		{"normal", o.StrideOffsetNormal, 3, o.NormCoordFound},
+		{"color", o.StrideOffsetColor, 3, o.StrideOffsetColor != 0},
+	}
+	for _, a := range attributes {
+		if !a.present {
+			continue
+		}
+		if a.offset < 0 || a.offset%4 != 0 || a.offset+4*a.floats > o.StrideSize {
+			return fmt.Errorf("bad %s offset=%d for stride size=%d", a.name, a.offset, o.StrideSize)
+		}
+	}
+	return nil
+}
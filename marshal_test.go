@@ -0,0 +1,151 @@
+package gwob
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshalJSONObj(t *testing.T) {
+	options := NewObjParserOptions(WithMaterialPerTriangle(true), WithKeepComments(true))
+	o, err := NewObjFromBuf("materialsObj", []byte("# square\n"+materialsObj), options)
+	if err != nil {
+		t.Fatalf("TestMarshalJSONObj: NewObjFromBuf: %v", err)
+	}
+
+	b, err := json.Marshal(o)
+	if err != nil {
+		t.Fatalf("TestMarshalJSONObj: Marshal: %v", err)
+	}
+	for _, key := range []string{`"version":1`, `"coord":`, `"indices":`, `"indexBegin":`, `"materialNames":`} {
+		if !strings.Contains(string(b), key) {
+			t.Errorf("TestMarshalJSONObj: missing %s in %s", key, b)
+		}
+	}
+
+	var o2 Obj
+	if errUnmarshal := json.Unmarshal(b, &o2); errUnmarshal != nil {
+		t.Fatalf("TestMarshalJSONObj: Unmarshal: %v", errUnmarshal)
+	}
+	compareMarshaledObj(t, "TestMarshalJSONObj", o, &o2)
+}
+
+func TestMarshalGobObj(t *testing.T) {
+	o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestMarshalGobObj: NewObjFromBuf: %v", err)
+	}
+
+	// encode as interface, as net/rpc might
+	var buf bytes.Buffer
+	var in interface{} = o
+	if errEnc := gob.NewEncoder(&buf).Encode(&in); errEnc != nil {
+		t.Fatalf("TestMarshalGobObj: Encode: %v", errEnc)
+	}
+	var out interface{}
+	if errDec := gob.NewDecoder(&buf).Decode(&out); errDec != nil {
+		t.Fatalf("TestMarshalGobObj: Decode: %v", errDec)
+	}
+	o2, ok := out.(*Obj)
+	if !ok {
+		t.Fatalf("TestMarshalGobObj: decoded type %T", out)
+	}
+	compareMarshaledObj(t, "TestMarshalGobObj", o, o2)
+}
+
+func compareMarshaledObj(t *testing.T, label string, want, got *Obj) {
+	t.Helper()
+	if !sliceEqualFloat(want.Coord, got.Coord) {
+		t.Errorf("%s: coord: want=%v got=%v", label, want.Coord, got.Coord)
+	}
+	if !sliceEqualInt(want.Indices, got.Indices) {
+		t.Errorf("%s: indices: want=%v got=%v", label, want.Indices, got.Indices)
+	}
+	if !reflect.DeepEqual(want.Groups, got.Groups) {
+		t.Errorf("%s: groups: want=%v got=%v", label, want.Groups, got.Groups)
+	}
+	if !reflect.DeepEqual(want.MaterialIndex, got.MaterialIndex) || !reflect.DeepEqual(want.MaterialNames, got.MaterialNames) {
+		t.Errorf("%s: materials: want=%v %v got=%v %v", label, want.MaterialIndex, want.MaterialNames, got.MaterialIndex, got.MaterialNames)
+	}
+	if !reflect.DeepEqual(want.Comments, got.Comments) {
+		t.Errorf("%s: comments: want=%v got=%v", label, want.Comments, got.Comments)
+	}
+	if want.StrideSize != got.StrideSize || want.TextCoordFound != got.TextCoordFound || want.NormCoordFound != got.NormCoordFound {
+		t.Errorf("%s: layout: want=%d,%v,%v got=%d,%v,%v", label,
+			want.StrideSize, want.TextCoordFound, want.NormCoordFound,
+			got.StrideSize, got.TextCoordFound, got.NormCoordFound)
+	}
+}
+
+func TestMarshalMaterialLib(t *testing.T) {
+	lib, err := ReadMaterialLibFromBuf([]byte(sceneMtl+"newmtl glass\nd 0.5\nNs 10\n"), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestMarshalMaterialLib: ReadMaterialLibFromBuf: %v", err)
+	}
+
+	b, err := json.Marshal(lib)
+	if err != nil {
+		t.Fatalf("TestMarshalMaterialLib: Marshal: %v", err)
+	}
+	if !strings.Contains(string(b), `"map_Kd":"red.png"`) {
+		t.Errorf("TestMarshalMaterialLib: missing map_Kd: %s", b)
+	}
+	if strings.Index(string(b), `"glass"`) > strings.Index(string(b), `"red"`) {
+		t.Errorf("TestMarshalMaterialLib: materials not sorted: %s", b)
+	}
+	var lib2 MaterialLib
+	if errUnmarshal := json.Unmarshal(b, &lib2); errUnmarshal != nil {
+		t.Fatalf("TestMarshalMaterialLib: Unmarshal: %v", errUnmarshal)
+	}
+	if !reflect.DeepEqual(lib.Lib, lib2.Lib) {
+		t.Errorf("TestMarshalMaterialLib: json: want=%v got=%v", lib.Lib, lib2.Lib)
+	}
+
+	var buf bytes.Buffer
+	if errEnc := gob.NewEncoder(&buf).Encode(lib); errEnc != nil {
+		t.Fatalf("TestMarshalMaterialLib: Encode: %v", errEnc)
+	}
+	var lib3 MaterialLib
+	if errDec := gob.NewDecoder(&buf).Decode(&lib3); errDec != nil {
+		t.Fatalf("TestMarshalMaterialLib: Decode: %v", errDec)
+	}
+	if !reflect.DeepEqual(lib.Lib, lib3.Lib) {
+		t.Errorf("TestMarshalMaterialLib: gob: want=%v got=%v", lib.Lib, lib3.Lib)
+	}
+}
+
+func TestUnmarshalBad(t *testing.T) {
+	bad := []string{
+		`{"version":2,"coord":[],"indices":[]}`,
+		`{"version":1,"coord":[0,0,0],"indices":[1]}`,
+		`{"version":1,"coord":[0,0],"indices":[]}`,
+		`{"version":1,"strideSize":2,"coord":[1,2]}`,
+		`{"version":1,"strideSize":-12,"coord":[1,2,3]}`,
+		`{"version":1,"strideSize":12,"strideOffsetPosition":4,"coord":[1,2,3]}`,
+		`{"version":1,"strideSize":20,"strideOffsetTexture":16,"texCoordFound":true,"coord":[1,2,3,4,5]}`,
+		`{"version":1,"strideSize":24,"strideOffsetNormal":14,"normalFound":true,"coord":[1,2,3,4,5,6]}`,
+		`{"version":1,"coord":[0,0,0,1,0,0,0,1,0],"indices":[0,1,2],"groups":[{"name":"g","indexBegin":0,"indexCount":6}]}`,
+		`{"version":1,"coord":[0,0,0,1,0,0,0,1,0],"indices":[0,1,2],"groups":[{"name":"g","indexBegin":-3,"indexCount":3}]}`,
+		`{"version":1,"coord":[0,0,0,1,0,0,0,1,0],"indices":[0,1,2],"groups":[{"name":"g","indexBegin":0,"indexCount":3,"lines":[[0,3]]}]}`,
+		`{"version":1,"coord":[0,0,0,1,0,0,0,1,0],"indices":[0,1,2],"groups":[{"name":"g","indexBegin":0,"indexCount":3,"points":[-1]}]}`,
+		`{"version":1,"coord":[0,0,0,1,0,0,0,1,0],"indices":[0,1,2],"materialIndex":[1],"materialNames":["a"]}`,
+		`{"version":1,"coord":[0,0,0,1,0,0,0,1,0],"indices":[0,1,2],"materialIndex":[0,0],"materialNames":["a"]}`,
+		`{"version":1,"coord":[0,0,0,1,0,0,0,1,0],"indices":[0,1,2],"smoothing":[1,1]}`,
+		`{"version":1,"coord":[0,0,0,1,0,0,0,1,0],"indices":[0,1,2],"colors":[1,1,1]}`,
+	}
+	for _, s := range bad {
+		var o Obj
+		if err := json.Unmarshal([]byte(s), &o); err == nil {
+			t.Errorf("TestUnmarshalBad: unexpected success: %s", s)
+		}
+	}
+
+	good := `{"version":1,"coord":[0,0,0,1,0,0,0,1,0],"indices":[0,1,2],"groups":[{"name":"g","indexBegin":0,"indexCount":3}],"materialIndex":[0],"materialNames":["a"],"colors":[1,1,1,1,1,1,1,1,1]}`
+	var o Obj
+	if err := json.Unmarshal([]byte(good), &o); err != nil {
+		t.Errorf("TestUnmarshalBad: good: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+var materialsObj = `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+g square
+f 1 2 3
+usemtl red
+f 1 2 3 4
+usemtl blue
+f 1 2 3
+usemtl red
+f 1 3 4
+g other
+f 1 2 4
+`
+
+func TestMaterialPerTriangle(t *testing.T) {
+
+	o, err := NewObjFromBuf("materialsObj", []byte(materialsObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestMaterialPerTriangle: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestMaterialPerTriangle: split groups", 4, len(o.Groups))
+
+	options := NewObjParserOptions(WithMaterialPerTriangle(true))
+	o, err = NewObjFromBuf("materialsObj", []byte(materialsObj), options)
+	if err != nil {
+		t.Fatalf("TestMaterialPerTriangle: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestMaterialPerTriangle: groups", 2, len(o.Groups))
+	if o.Groups[0].Usemtl != "" || o.Groups[1].Usemtl != "red" {
+		t.Errorf("TestMaterialPerTriangle: group materials: %q %q", o.Groups[0].Usemtl, o.Groups[1].Usemtl)
+	}
+	if len(o.MaterialNames) != 2 || o.MaterialNames[0] != "red" || o.MaterialNames[1] != "blue" {
+		t.Errorf("TestMaterialPerTriangle: material names: %q", o.MaterialNames)
+	}
+	want := []int{-1, 0, 0, 1, 0, 0}
+	if !sliceEqualInt(want, o.MaterialIndex) {
+		t.Errorf("TestMaterialPerTriangle: material index: want=%v got=%v", want, o.MaterialIndex)
+	}
+
+	// round trip
+	var buf bytes.Buffer
+	if errWrite := o.ToWriter(&buf); errWrite != nil {
+		t.Fatalf("TestMaterialPerTriangle: ToWriter: %v", errWrite)
+	}
+	o2, err := NewObjFromBuf("materialsObj2", buf.Bytes(), options)
+	if err != nil {
+		t.Fatalf("TestMaterialPerTriangle: reparse: %v", err)
+	}
+	if !sliceEqualInt(want, o2.MaterialIndex) {
+		t.Errorf("TestMaterialPerTriangle: reparsed material index: want=%v got=%v", want, o2.MaterialIndex)
+	}
+}
@@ -0,0 +1,45 @@
+package gwob
+
+import "math"
+
+// SurfaceArea gets the total area of the triangles.
+func (o *Obj) SurfaceArea() float64 {
+	return o.GroupSurfaceArea(nil)
+}
+
+// GroupSurfaceArea gets the area of the triangles of the group, or of
+// the whole mesh if group is nil.
+func (o *Obj) GroupSurfaceArea(group *Group) float64 {
+	begin, end := o.triangleRange(group)
+	var area float64
+	for t := begin; t < end; t++ {
+		n := o.triangleCross(t)
+		area += math.Sqrt(n[0]*n[0]+n[1]*n[1]+n[2]*n[2]) / 2
+	}
+	return area
+}
+
+// Volume gets the signed volume enclosed by the triangles, summing the
+// tetrahedra they form with the origin (divergence theorem). It is
+// positive for counter-clockwise triangles seen from outside. The result
+// is only meaningful for a watertight mesh with consistent winding: see
+// CheckManifold and FixWinding.
+func (o *Obj) Volume() float64 {
+	return o.GroupVolume(nil)
+}
+
+// GroupVolume gets the signed volume contribution of the triangles of
+// the group, or of the whole mesh if group is nil, as in Volume. The
+// contributions of all groups add up to Volume; for a group that is not
+// closed by itself the value depends on the position of the origin.
+func (o *Obj) GroupVolume(group *Group) float64 {
+	begin, end := o.triangleRange(group)
+	var volume float64
+	for t := begin; t < end; t++ {
+		ax, ay, az := o.VertexCoordinates(o.Indices[3*t])
+		n := o.triangleCross(t)
+		// a·(b×c) = a·((b-a)×(c-a))
+		volume += float64(ax)*n[0] + float64(ay)*n[1] + float64(az)*n[2]
+	}
+	return volume / 6
+}
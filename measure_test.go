@@ -0,0 +1,73 @@
+package gwob
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSurfaceAreaVolume(t *testing.T) {
+	o, err := NewObjFromBuf("box", []byte(boxObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestSurfaceAreaVolume: NewObjFromBuf: %v", err)
+	}
+	expectFloat := func(label string, want, got float64) {
+		if math.Abs(want-got) > 1e-9 {
+			t.Errorf("TestSurfaceAreaVolume: %s: want=%v got=%v", label, want, got)
+		}
+	}
+
+	expectFloat("area", 6, o.SurfaceArea())
+	expectFloat("volume", 1, o.Volume())
+
+	// volume does not depend on position for a closed mesh
+	o.Translate(10, -20, 30)
+	expectFloat("moved volume", 1, o.Volume())
+
+	o.FlipWinding()
+	expectFloat("inverted volume", -1, o.Volume())
+	expectFloat("inverted area", 6, o.SurfaceArea())
+}
+
+func TestGroupSurfaceAreaVolume(t *testing.T) {
+	str := `
+v 0 0 0
+v 2 0 0
+v 2 2 0
+v 0 2 0
+v 1 1 2
+g base
+f 1 4 3 2
+g sides
+f 1 2 5
+f 2 3 5
+f 3 4 5
+f 4 1 5
+`
+	o, err := NewObjFromBuf("pyramid", []byte(str), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestGroupSurfaceAreaVolume: NewObjFromBuf: %v", err)
+	}
+	base, sides := o.Groups[0], o.Groups[1]
+
+	if a := o.GroupSurfaceArea(base); a != 4 {
+		t.Errorf("TestGroupSurfaceAreaVolume: base area=%v", a)
+	}
+	side := 4 * math.Sqrt(5) // 4 triangles of base 2 and height sqrt(5)
+	if a := o.GroupSurfaceArea(sides); math.Abs(a-side) > 1e-6 {
+		t.Errorf("TestGroupSurfaceAreaVolume: sides area=%v want=%v", a, side)
+	}
+	if a := o.SurfaceArea(); math.Abs(a-4-side) > 1e-6 {
+		t.Errorf("TestGroupSurfaceAreaVolume: area=%v", a)
+	}
+
+	want := 4.0 * 2 / 3
+	if v := o.Volume(); math.Abs(v-want) > 1e-6 {
+		t.Errorf("TestGroupSurfaceAreaVolume: volume=%v want=%v", v, want)
+	}
+	if v := o.GroupVolume(base) + o.GroupVolume(sides); math.Abs(v-want) > 1e-6 {
+		t.Errorf("TestGroupSurfaceAreaVolume: group volumes=%v want=%v", v, want)
+	}
+	if v := o.GroupVolume(base); v != 0 {
+		t.Errorf("TestGroupSurfaceAreaVolume: base volume=%v", v) // base plane holds the origin
+	}
+}
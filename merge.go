@@ -0,0 +1,213 @@
+package gwob
+
+import (
+	"fmt"
+	"math"
+)
+
+// MergeOptions changes how Merge combines meshes.
+type MergeOptions struct {
+	// GroupPrefixes prefixes the group names of each input, in order.
+	// Inputs beyond the list get no prefix.
+	GroupPrefixes []string
+}
+
+// Merge concatenates the meshes into a new interleaved Obj. See
+// MergeWithOptions.
+func Merge(objs ...*Obj) (*Obj, error) {
+	return MergeWithOptions(nil, objs...)
+}
+
+// MergeWithOptions concatenates the meshes into a new interleaved Obj,
+// offsetting indices and keeping groups, per-triangle smoothing and
+// materials, comments, faces and material libraries. The vertex layout
+// is the union of the input layouts: inputs lacking texture coordinates
+// or normals get zeros, and inputs lacking colors get white. Colors are
+// kept in the separate Colors array. The raw data kept by KeepRawData
+// is not merged, so face corners are dropped. Inputs are not changed.
+func MergeWithOptions(options *MergeOptions, objs ...*Obj) (*Obj, error) {
+	if options == nil {
+		options = &MergeOptions{}
+	}
+
+	m := &Obj{}
+	var perTriangleSmooth, perTriangleMaterial, homogeneous bool
+	for i, o := range objs {
+		if o == nil {
+			return nil, fmt.Errorf("Merge: nil input %d", i)
+		}
+		if len(o.Indices)%3 != 0 {
+			return nil, fmt.Errorf("Merge: input %d: index count=%d must be a multiple of 3", i, len(o.Indices))
+		}
+		strides := o.NumberOfElements()
+		for _, v := range o.Indices {
+			if v < 0 || v >= strides {
+				return nil, fmt.Errorf("Merge: input %d: index=%d out of range for %d vertices", i, v, strides)
+			}
+		}
+		m.TextCoordFound = m.TextCoordFound || o.TextCoordFound
+		m.NormCoordFound = m.NormCoordFound || o.NormCoordFound
+		m.ColorFound = m.ColorFound || o.ColorFound
+		triangles := len(o.Indices) / 3
+		perTriangleSmooth = perTriangleSmooth || triangles > 0 && len(o.Smoothing) == triangles
+		perTriangleMaterial = perTriangleMaterial || triangles > 0 && len(o.MaterialIndex) == triangles
+		homogeneous = homogeneous || len(o.W) > 0
+	}
+
+	materials := map[string]int{}
+	material := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		i, found := materials[name]
+		if !found {
+			i = len(m.MaterialNames)
+			materials[name] = i
+			m.MaterialNames = append(m.MaterialNames, name)
+		}
+		return i
+	}
+
+	for i, o := range objs {
+		var prefix string
+		if i < len(options.GroupPrefixes) {
+			prefix = options.GroupPrefixes[i]
+		}
+		base := len(m.Positions) / 3
+		indexBase := len(m.Indices)
+
+		mergeVertices(m, o, homogeneous)
+
+		for _, v := range o.Indices {
+			m.Indices = append(m.Indices, base+v)
+		}
+
+		groups := map[*Group]*Group{}
+		for _, g := range o.Groups {
+			c := *g
+			c.Name = prefixName(prefix, g.Name)
+			c.Names = nil
+			for _, n := range g.Names {
+				c.Names = append(c.Names, prefixName(prefix, n))
+			}
+			c.IndexBegin += indexBase
+			c.Points = offsetIndices(g.Points, base)
+			c.Lines = nil
+			for _, line := range g.Lines {
+				c.Lines = append(c.Lines, offsetIndices(line, base))
+			}
+			groups[g] = &c
+			m.Groups = append(m.Groups, &c)
+
+			triangles := len(o.Indices) / 3
+			for t := g.IndexBegin / 3; t < (g.IndexBegin+g.IndexCount)/3 && t < triangles; t++ {
+				if perTriangleSmooth {
+					smooth := g.Smooth
+					if len(o.Smoothing) == triangles {
+						smooth = o.Smoothing[t]
+					}
+					m.Smoothing = append(m.Smoothing, smooth)
+				}
+				if perTriangleMaterial {
+					mat := material(g.Usemtl)
+					if len(o.MaterialIndex) == triangles {
+						mat = -1
+						if k := o.MaterialIndex[t]; k >= 0 {
+							mat = material(o.MaterialNames[k])
+						}
+					}
+					m.MaterialIndex = append(m.MaterialIndex, mat)
+				}
+			}
+		}
+
+		for _, f := range o.Faces {
+			c := f
+			c.Group = groups[f.Group]
+			c.Corners = nil
+			c.Vertices = offsetIndices(f.Vertices, base)
+			c.IndexBegin += indexBase
+			m.Faces = append(m.Faces, c)
+		}
+
+		for _, c := range o.Comments {
+			c.Index += indexBase
+			m.Comments = append(m.Comments, c)
+		}
+
+		for _, lib := range o.Mtllibs {
+			addMtllib(m, lib)
+		}
+		if len(o.Mtllibs) == 0 && o.Mtllib != "" {
+			addMtllib(m, o.Mtllib)
+		}
+		for _, lib := range o.Maplibs {
+			addMaplib(m, lib)
+		}
+	}
+
+	m.Interleave()
+	if m.Coord == nil {
+		setupStride(m)
+	}
+	m.BigIndexFound = m.NumberOfElements() > math.MaxUint16+1
+
+	return m, nil
+}
+
+// mergeVertices appends the vertex data of o to the separate arrays
+// of m, filling the attributes o lacks with defaults.
+func mergeVertices(m, o *Obj, homogeneous bool) {
+	for s := 0; s < o.NumberOfElements(); s++ {
+		x, y, z := o.VertexCoordinates(s)
+		m.Positions = append(m.Positions, x, y, z)
+		if m.TextCoordFound {
+			if o.TextCoordFound {
+				m.TexCoords = append(m.TexCoords, o.vertexTexCoord(s)...)
+			} else {
+				m.TexCoords = append(m.TexCoords, 0, 0)
+			}
+		}
+		if m.NormCoordFound {
+			if o.NormCoordFound {
+				m.Normals = append(m.Normals, o.vertexNormal(s)...)
+			} else {
+				m.Normals = append(m.Normals, 0, 0, 0)
+			}
+		}
+		if m.ColorFound {
+			r, g, b := o.VertexColor(s)
+			m.Colors = append(m.Colors, r, g, b)
+		}
+		if homogeneous {
+			w, tw := float32(1), float32(0)
+			if s < len(o.W) {
+				w = o.W[s]
+			}
+			if s < len(o.TexW) {
+				tw = o.TexW[s]
+			}
+			m.W = append(m.W, w)
+			m.TexW = append(m.TexW, tw)
+		}
+	}
+}
+
+func prefixName(prefix, name string) string {
+	if name == "" {
+		return name
+	}
+	return prefix + name
+}
+
+// offsetIndices copies list, adding base to each index.
+func offsetIndices(list []int, base int) []int {
+	if list == nil {
+		return nil
+	}
+	result := make([]int, len(list))
+	for i, v := range list {
+		result[i] = base + v
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	cube, err := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestMerge: NewObjFromBuf: %v", err)
+	}
+	box, err := NewObjFromBuf("box", []byte("g box\nusemtl red\n"+boxObj+"p 1\n"), NewObjParserOptions(WithNonInterleaved(true)))
+	if err != nil {
+		t.Fatalf("TestMerge: NewObjFromBuf: %v", err)
+	}
+	cubeIndices := append([]int(nil), cube.Indices...)
+
+	m, err := MergeWithOptions(&MergeOptions{GroupPrefixes: []string{"a_", "b_"}}, cube, box)
+	if err != nil {
+		t.Fatalf("TestMerge: %v", err)
+	}
+
+	if !sliceEqualInt(cubeIndices, cube.Indices) {
+		t.Errorf("TestMerge: input changed")
+	}
+	if !m.TextCoordFound || !m.NormCoordFound || !m.ColorFound {
+		t.Errorf("TestMerge: layout: tex=%v norm=%v color=%v", m.TextCoordFound, m.NormCoordFound, m.ColorFound)
+	}
+	expectInt(t, "TestMerge: stride size", 32, m.StrideSize)
+	cubeStrides := cube.NumberOfElements()
+	expectInt(t, "TestMerge: elements", cubeStrides+box.NumberOfElements(), m.NumberOfElements())
+	expectInt(t, "TestMerge: indices", len(cube.Indices)+len(box.Indices), len(m.Indices))
+	expectInt(t, "TestMerge: groups", len(cube.Groups)+len(box.Groups), len(m.Groups))
+
+	for i, v := range box.Indices {
+		got := m.Indices[len(cube.Indices)+i]
+		if got != cubeStrides+v {
+			t.Errorf("TestMerge: box index %d: want=%d got=%d", i, cubeStrides+v, got)
+			break
+		}
+		x, y, z := box.VertexCoordinates(v)
+		mx, my, mz := m.VertexCoordinates(got)
+		if x != mx || y != my || z != mz {
+			t.Errorf("TestMerge: box vertex %d moved", v)
+		}
+	}
+
+	// cube keeps its attributes, box gets defaults
+	if want, got := cube.vertexTexCoord(1), m.vertexTexCoord(1); !sliceEqualFloat(want, got) {
+		t.Errorf("TestMerge: cube uv: want=%v got=%v", want, got)
+	}
+	if got := m.vertexNormal(cubeStrides); !sliceEqualFloat([]float32{0, 0, 0}, got) {
+		t.Errorf("TestMerge: box normal: %v", got)
+	}
+	if r, g, b := m.VertexColor(0); r != 1 || g != 1 || b != 1 {
+		t.Errorf("TestMerge: cube color: %v %v %v", r, g, b)
+	}
+	if r, g, b := m.VertexColor(cubeStrides); r != 1 || g != 0 || b != 0 {
+		t.Errorf("TestMerge: box color: %v %v %v", r, g, b)
+	}
+
+	last := m.Groups[len(m.Groups)-1]
+	if last.Name != "b_box" || last.Usemtl != "red" {
+		t.Errorf("TestMerge: last group: name=%s usemtl=%s", last.Name, last.Usemtl)
+	}
+	expectInt(t, "TestMerge: last group begin", len(cube.Indices), last.IndexBegin)
+	if want := []int{cubeStrides}; !sliceEqualInt(want, last.Points) {
+		t.Errorf("TestMerge: points: want=%v got=%v", want, last.Points)
+	}
+	if m.Groups[0].Name != "a_"+cube.Groups[0].Name {
+		t.Errorf("TestMerge: first group name=%s", m.Groups[0].Name)
+	}
+	if want := []string{"texture_cube.mtl"}; len(m.Mtllibs) != 1 || m.Mtllibs[0] != want[0] {
+		t.Errorf("TestMerge: mtllibs=%v", m.Mtllibs)
+	}
+
+	// the merged mesh is writable and parsable
+	var buf bytes.Buffer
+	if err := m.ToWriter(&buf); err != nil {
+		t.Fatalf("TestMerge: ToWriter: %v", err)
+	}
+	back, err := NewObjFromBuf("merged", buf.Bytes(), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestMerge: parse merged: %v", err)
+	}
+	expectInt(t, "TestMerge: parsed indices", len(m.Indices), len(back.Indices))
+}
+
+func TestMergePerTriangle(t *testing.T) {
+	a, _ := NewObjFromBuf("a", []byte("usemtl red\n"+boxObj), NewObjParserOptions(WithMaterialPerTriangle(true)))
+	b, _ := NewObjFromBuf("b", []byte("usemtl blue\n"+boxObj), NewObjParserOptions())
+
+	m, err := Merge(a, b)
+	if err != nil {
+		t.Fatalf("TestMergePerTriangle: %v", err)
+	}
+	expectInt(t, "TestMergePerTriangle: materials", 24, len(m.MaterialIndex))
+	if m.MaterialNames[m.MaterialIndex[0]] != "red" || m.MaterialNames[m.MaterialIndex[23]] != "blue" {
+		t.Errorf("TestMergePerTriangle: materials=%v names=%v", m.MaterialIndex, m.MaterialNames)
+	}
+
+	if _, err := Merge(a, nil); err == nil {
+		t.Errorf("TestMergePerTriangle: expected error for nil input")
+	}
+}
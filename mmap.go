@@ -0,0 +1,36 @@
+package gwob
+
+import (
+	"io"
+	"strings"
+	"unsafe"
+)
+
+// mappedReader reads lines as substrings of a memory-mapped buffer,
+// without copying. Strings retained after parsing must be cloned,
+// since the buffer is unmapped once the parser returns.
+type mappedReader struct {
+	s string
+}
+
+// ReadString implements StringReader.
+func (r *mappedReader) ReadString(delim byte) (string, error) {
+	i := strings.IndexByte(r.s, delim)
+	if i < 0 {
+		line := r.s
+		r.s = ""
+		return line, io.EOF
+	}
+	line := r.s[:i+1]
+	r.s = r.s[i+1:]
+	return line, nil
+}
+
+// parseMapped parses Obj from mapped file data.
+func parseMapped(objName string, data []byte, options *ObjParserOptions) (*Obj, error) {
+	if len(data) == 0 || isGzip(data) {
+		return NewObjFromBuf(objName, data, options) // decompressed output is copied anyway
+	}
+	reader := &mappedReader{s: unsafe.String(&data[0], len(data))}
+	return readObj(objName, reader, int64(len(data)), options)
+}
@@ -0,0 +1,19 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package gwob
+
+import (
+	"fmt"
+	"os"
+)
+
+// NewObjFromFileMmap parses Obj from a file.
+// Memory mapping is not supported on this platform,
+// so the whole file is read into memory instead.
+func NewObjFromFileMmap(filename string, options *ObjParserOptions) (*Obj, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("NewObjFromFileMmap: %w", err)
+	}
+	return parseMapped(filename, data, options)
+}
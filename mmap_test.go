@@ -0,0 +1,85 @@
+package gwob
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMmap(t *testing.T) {
+
+	dir := t.TempDir()
+
+	table := []struct {
+		name string
+		data []byte
+	}{
+		{"cube.obj", []byte(cubeObj)},
+		{"cube.obj.gz", gzipBuf(cubeObj)},
+	}
+
+	for _, data := range table {
+		filename := filepath.Join(dir, data.name)
+		if err := os.WriteFile(filename, data.data, 0o644); err != nil {
+			t.Fatalf("TestMmap: write: %v", err)
+		}
+		o, err := NewObjFromFileMmap(filename, NewObjParserOptions())
+		if err != nil {
+			t.Errorf("TestMmap: %s: %v", data.name, err)
+			continue
+		}
+		if !sliceEqualInt(cubeIndices, o.Indices) {
+			t.Errorf("TestMmap: %s: indices: want=%v got=%v", data.name, cubeIndices, o.Indices)
+		}
+		if !sliceEqualFloat(cubeCoord, o.Coord) {
+			t.Errorf("TestMmap: %s: coord: want=%v got=%v", data.name, cubeCoord, o.Coord)
+		}
+	}
+
+	// strings must survive unmapping
+	filename := filepath.Join(dir, "scene.obj")
+	if err := os.WriteFile(filename, []byte(sceneObj+"\nf 1 2 x\n"), 0o644); err != nil {
+		t.Fatalf("TestMmap: write: %v", err)
+	}
+	o, err := NewObjFromFileMmap(filename, NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestMmap: scene: %v", err)
+	}
+	want, _ := NewObjFromBuf(filename, []byte(sceneObj+"\nf 1 2 x\n"), NewObjParserOptions())
+	expectInt(t, "TestMmap: groups", len(want.Groups), len(o.Groups))
+	for i, g := range o.Groups {
+		if i < len(want.Groups) && (g.Name != want.Groups[i].Name || g.Usemtl != want.Groups[i].Usemtl) {
+			t.Errorf("TestMmap: group %d: want=%+v got=%+v", i, *want.Groups[i], *g)
+		}
+	}
+	if o.Mtllib != want.Mtllib {
+		t.Errorf("TestMmap: mtllib: want=%q got=%q", want.Mtllib, o.Mtllib)
+	}
+	if len(o.Diagnostics) != 1 || o.Diagnostics[0].Err.Text != "f 1 2 x" {
+		t.Errorf("TestMmap: diagnostics: %v", o.Diagnostics)
+	}
+
+	// free-form strings must survive unmapping too
+	filename = filepath.Join(dir, "freeform.obj")
+	if err := os.WriteFile(filename, []byte(freeFormObj), 0o644); err != nil {
+		t.Fatalf("TestMmap: write: %v", err)
+	}
+	o, err = NewObjFromFileMmap(filename, NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestMmap: freeform: %v", err)
+	}
+	form := o.FreeForm
+	if form == nil || len(form.Curves) != 1 || len(form.Curves2D) != 1 || len(form.Surfaces) != 1 {
+		t.Fatalf("TestMmap: freeform: %+v", form)
+	}
+	if form.Curves[0].Type != "bezier" || form.Curves2D[0].Type != "bspline" || form.Surfaces[0].Type != "bezier" {
+		t.Errorf("TestMmap: freeform types: %q %q %q", form.Curves[0].Type, form.Curves2D[0].Type, form.Surfaces[0].Type)
+	}
+	if form.Curves[0].Group.Name != "curves" {
+		t.Errorf("TestMmap: freeform group: %q", form.Curves[0].Group.Name)
+	}
+
+	if _, err := NewObjFromFileMmap(filepath.Join(dir, "missing.obj"), NewObjParserOptions()); err == nil {
+		t.Errorf("TestMmap: missing file: unexpected success")
+	}
+}
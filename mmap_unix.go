@@ -0,0 +1,34 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package gwob
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// NewObjFromFileMmap parses Obj from a file mapped into memory.
+// Lines are parsed directly from the mapped pages, avoiding the
+// buffered reader copies, which helps with very large assets.
+// Files too large to map fall back to NewObjFromFile.
+func NewObjFromFileMmap(filename string, options *ObjParserOptions) (*Obj, error) {
+	input, size, errOpen := openFileSize(filename)
+	if errOpen != nil {
+		return nil, errOpen
+	}
+
+	defer input.Close()
+
+	if size < 1 || int64(int(size)) != size {
+		return newObjFromReaderSize(filename, input, size, options)
+	}
+
+	data, errMap := syscall.Mmap(int(input.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if errMap != nil {
+		return nil, fmt.Errorf("NewObjFromFileMmap: mmap: %s: %w", filename, errMap)
+	}
+
+	defer syscall.Munmap(data)
+
+	return parseMapped(filename, data, options)
+}
@@ -0,0 +1,75 @@
+package gwob
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// ToWriter writes the material lib in MTL format, sorted by material name.
+func (lib MaterialLib) ToWriter(w io.Writer) error {
+	names := make([]string, 0, len(lib.Lib))
+	for name := range lib.Lib {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "#%s\n", exportHeader)
+
+	for _, name := range names {
+		writeMaterial(&buf, lib.Lib[name])
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ToFile saves the material lib to file.
+func (lib MaterialLib) ToFile(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return lib.ToWriter(f)
+}
+
+// writeMaterial writes a material. Unset (zero) optional values are
+// omitted, so that other tools apply their own defaults.
+func writeMaterial(w io.Writer, mat *Material) {
+	fmt.Fprintf(w, "\nnewmtl %s\n", mat.Name)
+	fmt.Fprintf(w, "Ka %f %f %f\n", mat.Ka[0], mat.Ka[1], mat.Ka[2])
+	fmt.Fprintf(w, "Kd %f %f %f\n", mat.Kd[0], mat.Kd[1], mat.Kd[2])
+	fmt.Fprintf(w, "Ks %f %f %f\n", mat.Ks[0], mat.Ks[1], mat.Ks[2])
+	if mat.Ns != 0 {
+		fmt.Fprintf(w, "Ns %f\n", mat.Ns)
+	}
+	if mat.Ni != 0 {
+		fmt.Fprintf(w, "Ni %f\n", mat.Ni)
+	}
+	if mat.D != 0 {
+		fmt.Fprintf(w, "d %f\n", mat.D)
+	}
+	fmt.Fprintf(w, "illum %d\n", mat.Illum)
+
+	maps := []struct {
+		keyword string
+		name    string
+	}{
+		{"Ke", mat.MapKe},
+		{"map_Ka", mat.MapKa},
+		{"map_Kd", mat.MapKd},
+		{"map_Ks", mat.MapKs},
+		{"map_d", mat.MapD},
+		{"map_Bump", mat.Bump},
+	}
+	for _, m := range maps {
+		if m.name != "" {
+			fmt.Fprintf(w, "%s %s\n", m.keyword, m.name)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package gwob
+
+import "math"
+
+// triangleCross gets the cross product of the edges of triangle t:
+// the normal from its winding, with length twice the triangle area.
+func (o *Obj) triangleCross(t int) [3]float64 {
+	ax, ay, az := o.VertexCoordinates(o.Indices[3*t])
+	bx, by, bz := o.VertexCoordinates(o.Indices[3*t+1])
+	cx, cy, cz := o.VertexCoordinates(o.Indices[3*t+2])
+	ux, uy, uz := float64(bx-ax), float64(by-ay), float64(bz-az)
+	vx, vy, vz := float64(cx-ax), float64(cy-ay), float64(cz-az)
+	return [3]float64{uy*vz - uz*vy, uz*vx - ux*vz, ux*vy - uy*vx}
+}
+
+// unit scales n to unit length, as float32, or zero if n is zero.
+func unit(n [3]float64) [3]float32 {
+	l := math.Sqrt(n[0]*n[0] + n[1]*n[1] + n[2]*n[2])
+	if l == 0 {
+		return [3]float32{}
+	}
+	return [3]float32{float32(n[0] / l), float32(n[1] / l), float32(n[2] / l)}
+}
+
+// triangleNormal gets the unit normal of triangle t, from its winding,
+// or zero if the triangle has no area.
+func (o *Obj) triangleNormal(t int) [3]float32 {
+	return unit(o.triangleCross(t))
+}
+
+// GenerateFlatNormals sets the normal of each triangle corner to the
+// face normal, replacing any normals present. Vertices shared by
+// triangles with different normals are split, so the vertex count may
+// grow. Triangles with no area get a zero normal. The vertex layout,
+// interleaved or not, is kept, with NormCoordFound set.
+func (o *Obj) GenerateFlatNormals() {
+	corners := make([][3]float32, len(o.Indices))
+	for t := 0; t < len(o.Indices)/3; t++ {
+		n := o.triangleNormal(t)
+		corners[3*t], corners[3*t+1], corners[3*t+2] = n, n, n
+	}
+	o.setCornerNormals(corners)
+}
+
+// GenerateSmoothNormals sets the normal of each triangle corner to the
+// area-weighted average of the normals of the triangles around its
+// position that share the smoothing group of the triangle (from
+// Smoothing, or else from the group) and whose angle to it is at most
+// creaseAngleDeg degrees. Triangles with smoothing off (group 0) get
+// flat normals. Vertices across hard edges are split, and vertices on
+// texture seams are smoothed alike, since they share the position.
+// Any normals present are replaced; see GenerateFlatNormals for the
+// vertex layout.
+func (o *Obj) GenerateSmoothNormals(creaseAngleDeg float64) {
+	triangles := len(o.Indices) / 3
+	minCos := math.Cos(creaseAngleDeg * math.Pi / 180)
+
+	smoothing := o.triangleSmoothing()
+	cross := make([][3]float64, triangles)
+	normal := make([][3]float32, triangles)
+	around := map[[3]float32][]int{} // position -> triangles
+	for t := 0; t < triangles; t++ {
+		cross[t] = o.triangleCross(t)
+		normal[t] = unit(cross[t])
+		for c := 0; c < 3; c++ {
+			x, y, z := o.VertexCoordinates(o.Indices[3*t+c])
+			p := [3]float32{x, y, z}
+			list := around[p]
+			if len(list) == 0 || list[len(list)-1] != t {
+				around[p] = append(list, t)
+			}
+		}
+	}
+
+	corners := make([][3]float32, len(o.Indices))
+	for t := 0; t < triangles; t++ {
+		for c := 0; c < 3; c++ {
+			if smoothing[t] == 0 {
+				corners[3*t+c] = normal[t]
+				continue
+			}
+			x, y, z := o.VertexCoordinates(o.Indices[3*t+c])
+			var sum [3]float64
+			for _, u := range around[[3]float32{x, y, z}] {
+				if u != t {
+					if smoothing[u] != smoothing[t] {
+						continue
+					}
+					n, m := normal[t], normal[u]
+					if float64(n[0]*m[0]+n[1]*m[1]+n[2]*m[2]) < minCos {
+						continue
+					}
+				}
+				for i := range sum {
+					sum[i] += cross[u][i]
+				}
+			}
+			corners[3*t+c] = unit(sum)
+		}
+	}
+
+	o.setCornerNormals(corners)
+}
+
+// triangleSmoothing gets the smoothing group of each triangle.
+func (o *Obj) triangleSmoothing() []int {
+	triangles := len(o.Indices) / 3
+	if len(o.Smoothing) == triangles {
+		return o.Smoothing
+	}
+	smoothing := make([]int, triangles)
+	for _, g := range o.Groups {
+		for t := g.IndexBegin / 3; t < (g.IndexBegin+g.IndexCount)/3 && t < triangles; t++ {
+			smoothing[t] = g.Smooth
+		}
+	}
+	return smoothing
+}
+
+// setCornerNormals sets the normal of each entry in Indices, splitting
+// the strides used with more than one normal.
+func (o *Obj) setCornerNormals(corners [][3]float32) {
+	interleaved := o.Coord != nil
+	colors := o.StrideOffsetColor != 0
+	o.Deinterleave()
+
+	type key struct {
+		stride int
+		normal [3]float32
+	}
+	strides := map[key]int{} // (old stride, normal) -> new stride
+	first := map[int]int{}   // old stride -> its first new stride
+	var src []int            // new stride -> old stride
+	var normals []float32
+
+	old := append([]int(nil), o.Indices...)
+	for i, v := range old {
+		k := key{v, corners[i]}
+		s, found := strides[k]
+		if !found {
+			s = len(src)
+			strides[k] = s
+			src = append(src, v)
+			normals = append(normals, k.normal[:]...)
+			if _, found := first[v]; !found {
+				first[v] = s
+			}
+		}
+		o.Indices[i] = s
+	}
+
+	// points and lines keep a vertex of their position, if any
+	remap := func(list []int) {
+		for i, v := range list {
+			s, found := first[v]
+			if !found {
+				s = len(src)
+				first[v] = s
+				src = append(src, v)
+				normals = append(normals, 0, 0, 0)
+			}
+			list[i] = s
+		}
+	}
+	for _, g := range o.Groups {
+		remap(g.Points)
+		for _, line := range g.Lines {
+			remap(line)
+		}
+	}
+
+	// face vertices take the stride of their corner in the face
+	for i := range o.Faces {
+		f := &o.Faces[i]
+		for c, v := range f.Vertices {
+			for j := f.IndexBegin; j < f.IndexBegin+f.IndexCount; j++ {
+				if old[j] == v {
+					f.Vertices[c] = o.Indices[j]
+					break
+				}
+			}
+		}
+	}
+
+	selectStrides(o, src)
+	o.Normals = normals
+	o.NormCoordFound = true
+
+	if interleaved {
+		o.Interleave()
+		if colors {
+			o.interleaveColors()
+		}
+	}
+}
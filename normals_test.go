@@ -0,0 +1,152 @@
+package gwob
+
+import (
+	"testing"
+)
+
+// boxObj is a unit cube of quads, without normals, with vertex colors.
+var boxObj = `
+v 0 0 0 1 0 0
+v 1 0 0 1 0 0
+v 1 1 0 1 0 0
+v 0 1 0 1 0 0
+v 0 0 1 0 0 1
+v 1 0 1 0 0 1
+v 1 1 1 0 0 1
+v 0 1 1 0 0 1
+f 1 4 3 2
+f 5 6 7 8
+f 1 2 6 5
+f 2 3 7 6
+f 3 4 8 7
+f 4 1 5 8
+`
+
+func TestGenerateFlatNormals(t *testing.T) {
+	table := []struct {
+		name    string
+		options *ObjParserOptions
+	}{
+		{"interleaved", NewObjParserOptions(WithKeepFaces(true))},
+		{"colors", NewObjParserOptions(WithKeepFaces(true), WithInterleaveColors(true))},
+		{"separate", NewObjParserOptions(WithKeepFaces(true), WithNonInterleaved(true))},
+	}
+
+	for _, data := range table {
+		o, err := NewObjFromBuf("box", []byte(boxObj), data.options)
+		if err != nil {
+			t.Fatalf("TestGenerateFlatNormals: %s: NewObjFromBuf: %v", data.name, err)
+		}
+		expectInt(t, "TestGenerateFlatNormals: "+data.name+": elements", 8, o.NumberOfElements())
+		interleaved := o.Coord != nil
+		colorOffset := o.StrideOffsetColor
+
+		o.GenerateFlatNormals()
+
+		if !o.NormCoordFound {
+			t.Errorf("TestGenerateFlatNormals: %s: NormCoordFound not set", data.name)
+		}
+		if (o.Coord != nil) != interleaved {
+			t.Errorf("TestGenerateFlatNormals: %s: layout changed", data.name)
+		}
+		if (o.StrideOffsetColor != 0) != (colorOffset != 0) {
+			t.Errorf("TestGenerateFlatNormals: %s: color layout changed", data.name)
+		}
+		expectInt(t, "TestGenerateFlatNormals: "+data.name+": elements after", 24, o.NumberOfElements())
+		expectInt(t, "TestGenerateFlatNormals: "+data.name+": indices", 36, len(o.Indices))
+
+		for t0 := 0; t0 < 12; t0++ {
+			want := o.triangleNormal(t0)
+			for c := 0; c < 3; c++ {
+				s := o.Indices[3*t0+c]
+				if got := o.vertexNormal(s); !sliceEqualFloat(want[:], got) {
+					t.Errorf("TestGenerateFlatNormals: %s: triangle %d corner %d: want=%v got=%v", data.name, t0, c, want, got)
+				}
+			}
+		}
+
+		// bottom face points down, top face points up
+		if n := o.vertexNormal(o.Indices[0]); n[2] != -1 {
+			t.Errorf("TestGenerateFlatNormals: %s: bottom normal=%v", data.name, n)
+		}
+		if n := o.vertexNormal(o.Indices[6]); n[2] != 1 {
+			t.Errorf("TestGenerateFlatNormals: %s: top normal=%v", data.name, n)
+		}
+
+		if r, _, b := o.VertexColor(o.Indices[0]); r != 1 || b != 0 {
+			t.Errorf("TestGenerateFlatNormals: %s: bottom color lost: r=%v b=%v", data.name, r, b)
+		}
+
+		for i, f := range o.Faces {
+			for _, v := range f.Vertices {
+				found := false
+				for j := f.IndexBegin; j < f.IndexBegin+f.IndexCount; j++ {
+					found = found || o.Indices[j] == v
+				}
+				if !found {
+					t.Errorf("TestGenerateFlatNormals: %s: face %d vertex %d not in face", data.name, i, v)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateSmoothNormals(t *testing.T) {
+	table := []struct {
+		name     string
+		smooth   string
+		crease   float64
+		options  *ObjParserOptions
+		elements int
+	}{
+		{"smooth", "s 1\n", 180, NewObjParserOptions(), 8},
+		{"crease", "s 1\n", 60, NewObjParserOptions(), 24},
+		{"off", "s off\n", 180, NewObjParserOptions(), 24},
+		{"per triangle", "s 1\n", 180, NewObjParserOptions(WithSmoothPerTriangle(true)), 8},
+		{"separate", "s 1\n", 180, NewObjParserOptions(WithNonInterleaved(true)), 8},
+	}
+
+	for _, data := range table {
+		o, err := NewObjFromBuf("box", []byte(data.smooth+boxObj), data.options)
+		if err != nil {
+			t.Fatalf("TestGenerateSmoothNormals: %s: NewObjFromBuf: %v", data.name, err)
+		}
+
+		o.GenerateSmoothNormals(data.crease)
+
+		expectInt(t, "TestGenerateSmoothNormals: "+data.name+": elements", data.elements, o.NumberOfElements())
+		if data.elements != 8 {
+			continue
+		}
+
+		// corner normals point away from the center
+		for s := 0; s < o.NumberOfElements(); s++ {
+			x, y, z := o.VertexCoordinates(s)
+			n := o.vertexNormal(s)
+			for i, c := range []float32{x, y, z} {
+				if (c-0.5)*n[i] <= 0 {
+					t.Errorf("TestGenerateSmoothNormals: %s: vertex %v,%v,%v normal=%v", data.name, x, y, z, n)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateSmoothNormalsGroups(t *testing.T) {
+	// two smoothing groups: the bottom face alone, the others together
+	str := "s 2\n" + boxObj[:len(boxObj)-len("f 5 6 7 8\nf 1 2 6 5\nf 2 3 7 6\nf 3 4 8 7\nf 4 1 5 8\n")] +
+		"s 1\nf 5 6 7 8\nf 1 2 6 5\nf 2 3 7 6\nf 3 4 8 7\nf 4 1 5 8\n"
+	o, err := NewObjFromBuf("box", []byte(str), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestGenerateSmoothNormalsGroups: NewObjFromBuf: %v", err)
+	}
+
+	o.GenerateSmoothNormals(180)
+
+	// bottom 4 flat, top 4 and bottom side 4 smooth
+	expectInt(t, "TestGenerateSmoothNormalsGroups: elements", 12, o.NumberOfElements())
+	if n := o.vertexNormal(o.Indices[0]); n[0] != 0 || n[1] != 0 || n[2] != -1 {
+		t.Errorf("TestGenerateSmoothNormalsGroups: bottom normal=%v", n)
+	}
+}
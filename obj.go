@@ -21,12 +21,14 @@ See also: https://github.com/udhos/gwob
 package gwob
 
 import (
-	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"math"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -71,7 +73,8 @@ type Material struct {
 
 // MaterialLib stores materials.
 type MaterialLib struct {
-	Lib map[string]*Material
+	Lib         map[string]*Material
+	Diagnostics []Diagnostic // non-fatal problems found by the parser
 }
 
 // StringReader is input for the parser.
@@ -80,18 +83,27 @@ type StringReader interface {
 }
 
 // ReadMaterialLibFromBuf parses material lib from a buffer.
+// Gzip-compressed buffers are decompressed transparently.
 func ReadMaterialLibFromBuf(buf []byte, options *ObjParserOptions) (MaterialLib, error) {
-	return readLib(bytes.NewBuffer(buf), options)
+	if isGzip(buf) {
+		return ReadMaterialLibFromReader(bytes.NewReader(buf), options)
+	}
+	return readLib("", bytes.NewBuffer(buf), options)
 }
 
 // ReadMaterialLibFromReader parses material lib from a reader.
+// Gzip-compressed streams are decompressed transparently.
 func ReadMaterialLibFromReader(rd io.Reader, options *ObjParserOptions) (MaterialLib, error) {
-	return readLib(bufio.NewReader(rd), options)
+	reader, _, err := newBufReader(rd)
+	if err != nil {
+		return NewMaterialLib(), err
+	}
+	return readLib("", reader, options)
 }
 
 // ReadMaterialLibFromStringReader parses material lib from StringReader.
 func ReadMaterialLibFromStringReader(rd StringReader, options *ObjParserOptions) (MaterialLib, error) {
-	return readLib(rd, options)
+	return readLib("", rd, options)
 }
 
 // ReadMaterialLibFromFile parses material lib from a file.
@@ -104,7 +116,12 @@ func ReadMaterialLibFromFile(filename string, options *ObjParserOptions) (Materi
 
 	defer input.Close()
 
-	return ReadMaterialLibFromReader(input, options)
+	reader, _, err := newBufReader(input)
+	if err != nil {
+		return NewMaterialLib(), err
+	}
+
+	return readLib(filename, reader, options)
 }
 
 // NewMaterialLib creates a new material lib.
@@ -115,22 +132,42 @@ func NewMaterialLib() MaterialLib {
 // libParser holds auxiliary internal state for the parsing.
 type libParser struct {
 	currMaterial *Material
+	libName      string
+	lineCount    int
+	line         string
+}
+
+func (p *libParser) errorf(kind error, format string, args ...interface{}) error {
+	return newParseError(p.libName, p.lineCount, p.line, kind, format, args...)
 }
 
-func readLib(reader StringReader, options *ObjParserOptions) (MaterialLib, error) {
+func readLib(libName string, reader StringReader, options *ObjParserOptions) (MaterialLib, error) {
+
+	if options == nil {
+		options = defaultObjParserOptions()
+	}
 
 	lineCount := 0
 
-	parser := &libParser{}
+	parser := &libParser{libName: libName}
 	lib := NewMaterialLib()
 
 	for {
 		lineCount++
-		line, err := reader.ReadString('\n')
+		line, lines, err := readStatement(reader, options.Limits.MaxLineLength)
+
+		var limit *LimitError
+		if errors.As(err, &limit) {
+			limit.File = libName
+			limit.Line = lineCount
+			return lib, limit
+		}
+
 		if err == io.EOF {
 			// parse last line
 			if _, e := parseLibLine(parser, lib, line, lineCount); e != nil {
-				options.log(fmt.Sprintf("readLib: %v", e))
+				options.warn(fmt.Sprintf("readLib: %v", e))
+				lib.Diagnostics = appendDiagnostic(lib.Diagnostics, SeverityError, e)
 				return lib, e
 			}
 			break // EOF
@@ -138,22 +175,28 @@ func readLib(reader StringReader, options *ObjParserOptions) (MaterialLib, error
 
 		if err != nil {
 			// unexpected IO error
-			return lib, fmt.Errorf("readLib: error: %v", err)
+			return lib, fmt.Errorf("readLib: error: %w", err)
 		}
 
 		if fatal, e := parseLibLine(parser, lib, line, lineCount); e != nil {
-			options.log(fmt.Sprintf("readLib: %v", e))
-			if fatal {
+			options.warn(fmt.Sprintf("readLib: %v", e))
+			lib.Diagnostics = appendDiagnostic(lib.Diagnostics, SeverityError, e)
+			if fatal || options.Strict {
 				return lib, e
 			}
 		}
+
+		lineCount += lines - 1 // continued lines
 	}
 
 	return lib, nil
 }
 
 func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int) (bool, error) {
-	line := strings.TrimSpace(rawLine)
+	line := cleanLine(rawLine)
+
+	p.lineCount = lineCount
+	p.line = line
 
 	switch {
 	case line == "" || line[0] == '#':
@@ -173,12 +216,12 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		Kd := line[3:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for Kd=%s [%s]", lineCount, Kd, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for Kd=%s", Kd)
 		}
 
 		color, err := parseFloatVector3Space(Kd)
 		if err != nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d parsing error for Kd=%s [%s]: %v", lineCount, Kd, line, err)
+			return ErrNonFatal, p.errorf(ErrBadValue, "parsing error for Kd=%s: %v", Kd, err)
 		}
 
 		p.currMaterial.Kd[0] = float32(color[0])
@@ -189,7 +232,7 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		mapKd := line[7:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for map_Kd=%s [%s]", lineCount, mapKd, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for map_Kd=%s", mapKd)
 		}
 
 		p.currMaterial.MapKd = mapKd
@@ -198,7 +241,7 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		mapKa := line[7:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for map_Ka=%s [%s]", lineCount, mapKa, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for map_Ka=%s", mapKa)
 		}
 
 		p.currMaterial.MapKa = mapKa
@@ -207,7 +250,7 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		mapKs := line[7:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for map_Ks=%s [%s]", lineCount, mapKs, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for map_Ks=%s", mapKs)
 		}
 
 		p.currMaterial.MapKs = mapKs
@@ -216,7 +259,7 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		mapD := line[6:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for map_D=%s [%s]", lineCount, mapD, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for map_D=%s", mapD)
 		}
 
 		p.currMaterial.MapD = mapD
@@ -225,7 +268,7 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		bump := line[9:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for bump=%s [%s]", lineCount, bump, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for bump=%s", bump)
 		}
 
 		p.currMaterial.Bump = bump
@@ -234,7 +277,7 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		bump := line[5:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for bump=%s [%s]", lineCount, bump, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for bump=%s", bump)
 		}
 
 		p.currMaterial.Bump = bump
@@ -243,12 +286,12 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		Ns := line[3:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for Ns=%s [%s]", lineCount, Ns, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for Ns=%s", Ns)
 		}
 
 		value, err := parseFloatVectorSpace(Ns, 1)
 		if err != nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d parsing error for Ns=%s [%s]: %v", lineCount, Ns, line, err)
+			return ErrNonFatal, p.errorf(ErrBadValue, "parsing error for Ns=%s: %v", Ns, err)
 		}
 
 		p.currMaterial.Ns = float32(value[0])
@@ -257,12 +300,12 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		Ka := line[3:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for Ka=%s [%s]", lineCount, Ka, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for Ka=%s", Ka)
 		}
 
 		color, err := parseFloatVector3Space(Ka)
 		if err != nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d parsing error for Ka=%s [%s]: %v", lineCount, Ka, line, err)
+			return ErrNonFatal, p.errorf(ErrBadValue, "parsing error for Ka=%s: %v", Ka, err)
 		}
 
 		p.currMaterial.Ka[0] = float32(color[0])
@@ -273,7 +316,7 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		MapKe := line[3:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for MapKe=%s [%s]", lineCount, MapKe, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for MapKe=%s", MapKe)
 		}
 
 		p.currMaterial.MapKe = MapKe
@@ -282,12 +325,12 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		Ks := line[3:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for Ks=%s [%s]", lineCount, Ks, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for Ks=%s", Ks)
 		}
 
 		color, err := parseFloatVector3Space(Ks)
 		if err != nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d parsing error for Ks=%s [%s]: %v", lineCount, Ks, line, err)
+			return ErrNonFatal, p.errorf(ErrBadValue, "parsing error for Ks=%s: %v", Ks, err)
 		}
 
 		p.currMaterial.Ks[0] = float32(color[0])
@@ -298,12 +341,12 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		Ni := line[3:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for Ni=%s [%s]", lineCount, Ni, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for Ni=%s", Ni)
 		}
 
 		value, err := parseFloatVectorSpace(Ni, 1)
 		if err != nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d parsing error for Ni=%s [%s]: %v", lineCount, Ni, line, err)
+			return ErrNonFatal, p.errorf(ErrBadValue, "parsing error for Ni=%s: %v", Ni, err)
 		}
 
 		p.currMaterial.Ni = float32(value[0])
@@ -312,12 +355,12 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		D := line[2:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for D=%s [%s]", lineCount, D, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for D=%s", D)
 		}
 
 		value, err := parseFloatVectorSpace(D, 1)
 		if err != nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d parsing error for D=%s [%s]: %v", lineCount, D, line, err)
+			return ErrNonFatal, p.errorf(ErrBadValue, "parsing error for D=%s: %v", D, err)
 		}
 
 		p.currMaterial.D = float32(value[0])
@@ -326,12 +369,12 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 		Illum := line[6:]
 
 		if p.currMaterial == nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d undefined material for Illum=%s [%s]", lineCount, Illum, line)
+			return ErrNonFatal, p.errorf(ErrUndefinedMaterial, "undefined material for Illum=%s", Illum)
 		}
 
 		value, err := parseFloatVectorSpace(Illum, 1)
 		if err != nil {
-			return ErrNonFatal, fmt.Errorf("parseLibLine: %d parsing error for Illum=%s [%s]: %v", lineCount, Illum, line, err)
+			return ErrNonFatal, p.errorf(ErrBadValue, "parsing error for Illum=%s: %v", Illum, err)
 		}
 
 		p.currMaterial.Illum = int(value[0])
@@ -339,7 +382,7 @@ func parseLibLine(p *libParser, lib MaterialLib, rawLine string, lineCount int)
 	case strings.HasPrefix(line, "Tf "):
 	case strings.HasPrefix(line, "Tr "):
 	default:
-		return ErrNonFatal, fmt.Errorf("parseLibLine %v: [%v]: unexpected", lineCount, line)
+		return ErrNonFatal, p.errorf(ErrUnexpected, "unexpected: [%s]", line)
 	}
 
 	return ErrNonFatal, nil
@@ -350,42 +393,136 @@ type Group struct {
 	Name       string
 	Smooth     int
 	Usemtl     string
+	Usemap     string // texture map from usemap, empty if off
+	Lod        int    // level of detail from lod, 0 if off
 	IndexBegin int
 	IndexCount int
+	Names      []string // all names listed by the g statement, or the o name
+	Lines      [][]int  // polylines from l statements, as unified indices
+	Points     []int    // vertices from p statements, as unified indices
 }
 
 // Obj holds parser result for .obj file.
 type Obj struct {
 	Indices []int
 	Coord   []float32 // vertex data pos=(x,y,z) tex=(tx,ty) norm=(nx,ny,nz)
-	Mtllib  string
-	Groups  []*Group
+	Mtllib  string    // first material lib, kept for compatibility
+	Mtllibs []string  // all material libs referenced by mtllib statements
+	Maplibs []string  // texture map libs referenced by maplib statements
+
+	ShadowObj string // shadow geometry file from shadow_obj
+	TraceObj  string // ray tracing geometry file from trace_obj
+	Groups    []*Group
+
+	// Separate vertex arrays, filled instead of Coord by the
+	// NonInterleaved option. See Interleave and Deinterleave.
+	Positions []float32 // (x,y,z)
+	TexCoords []float32 // (tx,ty)
+	Normals   []float32 // (nx,ny,nz)
+
+	// Homogeneous coordinates, one per stride, retained by the
+	// KeepHomogeneous option: W is the v weight (Coord positions are
+	// then not divided by it) and TexW is the third vt coordinate.
+	W    []float32
+	TexW []float32
+
+	// Colors holds non-standard per-vertex colors (r,g,b) from
+	// "v x y z r g b" statements, one per stride. See InterleaveColors.
+	Colors []float32
+
+	// Original OBJ data, retained by the KeepRawData option.
+	// Corners holds the v/vt/vn indices into the raw arrays
+	// for each entry in Indices.
+	RawPositions []float32 // v (x,y,z)
+	RawTexCoords []float32 // vt (tx,ty)
+	RawNormals   []float32 // vn (nx,ny,nz)
+	Corners      []FaceIndex
+
+	// Smoothing holds the smoothing group of each triangle,
+	// filled by the SmoothPerTriangle option instead of splitting
+	// groups on "s" statements.
+	Smoothing []int
+
+	// MaterialIndex holds the material of each triangle, as an index
+	// into MaterialNames (-1 if none), filled by the MaterialPerTriangle
+	// option instead of splitting groups on "usemtl" statements.
+	MaterialIndex []int
+	MaterialNames []string
+
+	// Comments lists the "#" comment lines, retained by the KeepComments option.
+	Comments []Comment
+
+	// FreeForm holds curves and surfaces, nil if none.
+	FreeForm *FreeForm
+
+	// Faces lists the polygons as found in the input,
+	// retained by the KeepFaces option.
+	Faces []Face
 
 	BigIndexFound  bool // index larger than 65535
 	TextCoordFound bool // texture coord
 	NormCoordFound bool // normal coord
+	ColorFound     bool // vertex color
 
 	StrideSize           int // (px,py,pz),(tu,tv),(nx,ny,nz) = 8 x 4-byte floats = 32 bytes max
 	StrideOffsetPosition int // 0
 	StrideOffsetTexture  int // 3 x 4-byte floats
 	StrideOffsetNormal   int // 5 x 4-byte floats
+	StrideOffsetColor    int // after all other attributes, 0 if colors are not interleaved
+
+	Diagnostics  []Diagnostic // non-fatal problems found by the parser
+	SkippedFaces int          // faces dropped due to errors
 }
 
 // objParser holds auxiliary internal parser state.
 type objParser struct {
-	lineBuf    []string
+	objName    string
+	currLine   string
+	lineBuf    []deferredLine
+	replay     bool // replaying deferred lines
 	lineCount  int
 	vertCoord  []float32
 	textCoord  []float32
 	normCoord  []float32
+	colorCoord []float32 // non-standard v colors, nil if none
+	vertW      []float32 // v w, for KeepHomogeneous
+	textW      []float32 // vt w, for KeepHomogeneous
 	currGroup  *Group
-	indexTable map[string]int
-	indexCount int
-	vertLines  int
-	textLines  int
-	normLines  int
-	faceLines  int // stat-only
-	triangles  int // stat-only
+	indexTable map[vertexKey]int
+	hint       SizeHint
+	tris       []int // polygon triangulation scratch
+	freeForm   freeFormState
+	quads      int      // quads split, for QuadSplitAlternate
+	smooth     int      // current smoothing group, for SmoothPerTriangle
+	material   int      // current material index, for MaterialPerTriangle
+	includes   []string // names of the including files, for "call"
+	alloc      Allocator
+
+	// double precision buffers, used instead of the float32 ones
+	double      bool
+	vertCoord64 []float64
+	textCoord64 []float64
+	normCoord64 []float64
+	coord64     []float64
+	indexCount  int
+	vertLines   int
+	textLines   int
+	normLines   int
+	paramLines  int // vp
+	faceLines   int // stat-only
+	triangles   int // stat-only
+
+	diagnostics  []Diagnostic
+	skippedFaces int
+
+	lineBytes    int64 // bytes retained in lineBuf
+	totalBytes   int64 // input size, -1 if unknown
+	bytesDone    int64 // bytes processed in current phase
+	nextProgress int64
+}
+
+func (p *objParser) errorf(kind error, format string, args ...interface{}) error {
+	return newParseError(p.objName, p.lineCount, p.currLine, kind, format, args...)
 }
 
 // ObjParserOptions sets options for the parser.
@@ -393,13 +530,101 @@ type ObjParserOptions struct {
 	LogStats      bool
 	Logger        func(string)
 	IgnoreNormals bool
+
+	// MtllibKeepSpaces takes the whole mtllib argument as a single
+	// filename, instead of splitting it into multiple libraries.
+	MtllibKeepSpaces bool
+
+	// Strict aborts parsing on the first error, instead of skipping the bad line.
+	Strict bool
+
+	// NoUnify disables vertex unification: every face corner gets its
+	// own stride in Coord, with sequential indices (triangle soup).
+	NoUnify bool
+
+	// NonInterleaved fills Positions, TexCoords and Normals
+	// instead of the interleaved Coord buffer.
+	// It is ignored by the double precision parser.
+	NonInterleaved bool
+
+	// KeepRawData retains the original v/vt/vn arrays and
+	// the per-corner indices into them on the Obj.
+	// It is ignored by the double precision parser.
+	KeepRawData bool
+
+	// KeepFaces retains the original polygon faces on the Obj,
+	// alongside the triangulated buffers.
+	KeepFaces bool
+
+	// KeepHomogeneous retains the v weight w, instead of dividing
+	// positions by it, and the third vt coordinate, in Obj.W and Obj.TexW.
+	KeepHomogeneous bool
+
+	// FlipTextureV stores 1-v instead of v for texture coordinates,
+	// converting between the OBJ (OpenGL) bottom-left texture origin
+	// and the top-left origin used by Direct3D, Vulkan and Metal.
+	FlipTextureV bool
+
+	// Scale multiplies positions, for instance 0.01 to bring models
+	// authored in centimeters into meters. Zero means 1.
+	// ScaleXYZ additionally scales each axis, with zero meaning 1.
+	// Normals are adjusted for non-uniform and negative scaling, but
+	// the triangle winding is kept: see FlipWinding for mirroring.
+	Scale    float64
+	ScaleXYZ [3]float64
+
+	// InterleaveColors adds vertex colors (r,g,b) to the Coord stride,
+	// instead of the separate Colors array.
+	InterleaveColors bool
+
+	// SmoothPerTriangle records the smoothing group of each triangle
+	// in Obj.Smoothing, instead of creating a new group on every "s" change.
+	SmoothPerTriangle bool
+
+	// KeepComments retains "#" comment lines in Obj.Comments,
+	// reproduced by ToWriter.
+	KeepComments bool
+
+	// MaterialPerTriangle records the material of each triangle in
+	// Obj.MaterialIndex, instead of creating a new group on every "usemtl" change.
+	MaterialPerTriangle bool
+
+	// QuadSplit selects how quads are split into triangles.
+	QuadSplit QuadSplit
+
+	// Include, if set, opens files pulled in by "call" statements.
+	// Included readers implementing io.Closer are closed after use.
+	Include func(name string) (io.Reader, error)
+
+	// OnUnknown, if set, handles OBJ statements not recognized by the
+	// parser, instead of reporting them as ErrUnexpected errors.
+	// An error returned by the handler is reported for the line.
+	OnUnknown func(lineNum int, keyword, rest string) error
+
+	// Limits bounds the resources consumed by the parser.
+	// Exceeding a limit aborts parsing with a *LimitError.
+	Limits Limits
+
+	// SizeHint pre-sizes parser buffers.
+	// Zero fields are estimated from the input size, when known.
+	SizeHint SizeHint
+
+	// Allocator, if set, supplies parser buffers and output slices.
+	Allocator Allocator
+
+	// Progress, if set, is periodically invoked during parsing.
+	// totalBytes is -1 when the input size is unknown.
+	Progress func(bytesRead, totalBytes int64, phase string)
+
+	// Slog receives leveled, structured messages: stats are logged
+	// at debug level, while parse problems are logged as warnings.
+	// Logger, if also set, still receives every message.
+	Slog *slog.Logger
 }
 
-func (opt *ObjParserOptions) log(msg string) {
-	if opt.Logger == nil {
-		return
-	}
-	opt.Logger(msg)
+// defaultObjParserOptions is used when nil options are given.
+func defaultObjParserOptions() *ObjParserOptions {
+	return &ObjParserOptions{LogStats: true, Logger: func(msg string) { fmt.Print(msg) }}
 }
 
 func (o *Obj) newGroup(name, usemtl string, begin int, smooth int) *Group {
@@ -408,6 +633,16 @@ func (o *Obj) newGroup(name, usemtl string, begin int, smooth int) *Group {
 	return gr
 }
 
+// splitGroup creates a new group starting at the current index,
+// inheriting the attributes of prev.
+func (o *Obj) splitGroup(prev *Group) *Group {
+	gr := o.newGroup(prev.Name, prev.Usemtl, len(o.Indices), prev.Smooth)
+	gr.Names = prev.Names
+	gr.Usemap = prev.Usemap
+	gr.Lod = prev.Lod
+	return gr
+}
+
 // Coord64 gets vertex data as float64.
 func (o *Obj) Coord64(i int) float64 {
 	return float64(o.Coord[i])
@@ -415,11 +650,17 @@ func (o *Obj) Coord64(i int) float64 {
 
 // NumberOfElements gets the number of strides.
 func (o *Obj) NumberOfElements() int {
+	if o.Coord == nil && o.Positions != nil {
+		return len(o.Positions) / 3
+	}
 	return 4 * len(o.Coord) / o.StrideSize
 }
 
 // VertexCoordinates gets vertex coordinates for a stride index.
 func (o *Obj) VertexCoordinates(stride int) (float32, float32, float32) {
+	if o.Coord == nil && o.Positions != nil {
+		return o.Positions[3*stride], o.Positions[3*stride+1], o.Positions[3*stride+2]
+	}
 	offset := o.StrideOffsetPosition / 4
 	floatsPerStride := o.StrideSize / 4
 	f := offset + stride*floatsPerStride
@@ -438,69 +679,7 @@ func (o *Obj) ToFile(filename string) error {
 
 // ToWriter writes OBJ to writer stream.
 func (o *Obj) ToWriter(w io.Writer) error {
-
-	fmt.Fprintf(w, "# OBJ exported by gwob - https://github.com/udhos/gwob\n")
-	fmt.Fprintf(w, "\n")
-
-	if o.Mtllib != "" {
-		fmt.Fprintf(w, "mtllib %s\n", o.Mtllib)
-	}
-
-	// write vertex data
-	strides := o.NumberOfElements()
-	for s := 0; s < strides; s++ {
-		stride := s * o.StrideSize / 4
-		v := stride + o.StrideOffsetPosition/4
-		fmt.Fprintf(w, "v %f %f %f\n", o.Coord[v], o.Coord[v+1], o.Coord[v+2])
-
-		if o.TextCoordFound {
-			t := stride + o.StrideOffsetTexture/4
-			fmt.Fprintf(w, "vt %f %f\n", o.Coord[t], o.Coord[t+1])
-		}
-
-		if o.NormCoordFound {
-			n := stride + o.StrideOffsetNormal/4
-			fmt.Fprintf(w, "vn %f %f %f\n", o.Coord[n], o.Coord[n+1], o.Coord[n+2])
-		}
-	}
-
-	// write group faces
-	for _, g := range o.Groups {
-		if g.Name != "" {
-			fmt.Fprintf(w, "g %s\n", g.Name)
-		}
-		if g.Usemtl != "" {
-			fmt.Fprintf(w, "usemtl %s\n", g.Usemtl)
-		}
-		fmt.Fprintf(w, "s %d\n", g.Smooth)
-		if g.IndexCount%3 != 0 {
-			return fmt.Errorf("group=%s count=%d must be a multiple of 3", g.Name, g.IndexCount)
-		}
-		pastEnd := g.IndexBegin + g.IndexCount
-		for s := g.IndexBegin; s < pastEnd; s += 3 {
-			fmt.Fprintf(w, "f")
-			for f := s; f < s+3; f++ {
-				ff := o.Indices[f] + 1
-				str := strconv.Itoa(ff)
-				if o.TextCoordFound {
-					if o.NormCoordFound {
-						fmt.Fprintf(w, " %s/%s/%s", str, str, str)
-					} else {
-						fmt.Fprintf(w, " %s/%s", str, str)
-					}
-				} else {
-					if o.NormCoordFound {
-						fmt.Fprintf(w, " %s//%s", str, str)
-					} else {
-						fmt.Fprintf(w, " %s", str)
-					}
-				}
-			}
-			fmt.Fprintf(w, "\n")
-		}
-	}
-
-	return nil
+	return o.ToWriterWithOptions(w, nil)
 }
 
 // NewObjFromVertex creates Obj from vertex data.
@@ -520,31 +699,63 @@ func NewObjFromVertex(coord []float32, indices []int) (*Obj, error) {
 }
 
 // NewObjFromBuf parses Obj from a buffer.
+// Gzip-compressed buffers are decompressed transparently.
 func NewObjFromBuf(objName string, buf []byte, options *ObjParserOptions) (*Obj, error) {
-	return readObj(objName, bytes.NewBuffer(buf), options)
+	if isGzip(buf) {
+		return NewObjFromReader(objName, bytes.NewReader(buf), options)
+	}
+	return readObj(objName, bytes.NewBuffer(buf), int64(len(buf)), options)
 }
 
 // NewObjFromReader parses Obj from a reader.
+// Gzip-compressed streams (such as .obj.gz files) are decompressed transparently.
 func NewObjFromReader(objName string, rd io.Reader, options *ObjParserOptions) (*Obj, error) {
-	return readObj(objName, bufio.NewReader(rd), options)
+	return newObjFromReaderSize(objName, rd, -1, options)
+}
+
+// newObjFromReaderSize parses Obj from a reader whose size may be known (or -1).
+func newObjFromReaderSize(objName string, rd io.Reader, size int64, options *ObjParserOptions) (*Obj, error) {
+	reader, compressed, err := newBufReader(rd)
+	if err != nil {
+		return nil, err
+	}
+	if compressed {
+		size = -1 // decompressed size is unknown
+	}
+	return readObj(objName, reader, size, options)
 }
 
 // NewObjFromStringReader parses Obj from a StringReader.
 func NewObjFromStringReader(objName string, rd StringReader, options *ObjParserOptions) (*Obj, error) {
-	return readObj(objName, rd, options)
+	return readObj(objName, rd, -1, options)
 }
 
 // NewObjFromFile parses Obj from a file.
 func NewObjFromFile(filename string, options *ObjParserOptions) (*Obj, error) {
 
-	input, errOpen := os.Open(filename)
+	input, size, errOpen := openFileSize(filename)
 	if errOpen != nil {
 		return nil, errOpen
 	}
 
 	defer input.Close()
 
-	return NewObjFromReader(filename, input, options)
+	return newObjFromReaderSize(filename, input, size, options)
+}
+
+// openFileSize opens a file and gets its size, -1 if unknown.
+func openFileSize(filename string) (*os.File, int64, error) {
+	input, errOpen := os.Open(filename)
+	if errOpen != nil {
+		return nil, -1, errOpen
+	}
+
+	size := int64(-1)
+	if info, errStat := input.Stat(); errStat == nil {
+		size = info.Size()
+	}
+
+	return input, size, nil
 }
 
 func setupStride(o *Obj) {
@@ -552,6 +763,7 @@ func setupStride(o *Obj) {
 	o.StrideOffsetPosition = 0
 	o.StrideOffsetTexture = 0
 	o.StrideOffsetNormal = 0
+	o.StrideOffsetColor = 0
 
 	if o.TextCoordFound {
 		o.StrideOffsetTexture = o.StrideSize
@@ -564,28 +776,37 @@ func setupStride(o *Obj) {
 	}
 }
 
-func readObj(objName string, reader StringReader, options *ObjParserOptions) (*Obj, error) {
+func readObj(objName string, reader StringReader, size int64, options *ObjParserOptions) (*Obj, error) {
+	p := &objParser{material: -1}
+	return parseObj(p, objName, reader, size, options)
+}
+
+// parseObj parses Obj using parser state p, which must be empty.
+func parseObj(p *objParser, objName string, reader StringReader, size int64, options *ObjParserOptions) (*Obj, error) {
 
 	if options == nil {
-		options = &ObjParserOptions{LogStats: true, Logger: func(msg string) { fmt.Print(msg) }}
+		options = defaultObjParserOptions()
 	}
 
-	p := &objParser{indexTable: make(map[string]int)}
+	p.objName = objName
+	p.totalBytes = size
 	o := &Obj{}
 
-	// 1. vertex-only parsing
-	if fatal, err := readLines(p, reader, options); err != nil {
+	p.alloc = options.Allocator
+	defer p.freeScratch()
+
+	p.presize(o, options.sizeHint(size))
+
+	p.currGroup = o.newGroup("", "", 0, 0)
+
+	// 1. single-pass parsing
+	if fatal, err := readLines(p, o, reader, options); err != nil {
 		if fatal {
 			return o, err
 		}
 	}
 
-	p.faceLines = 0
-	p.vertLines = 0
-	p.textLines = 0
-	p.normLines = 0
-
-	// 2. full parsing
+	// 2. lines deferred due to forward references
 	if fatal, err := scanLines(p, o, options); err != nil {
 		if fatal {
 			return o, err
@@ -593,6 +814,13 @@ func readObj(objName string, reader StringReader, options *ObjParserOptions) (*O
 	}
 
 	// 3. output
+	finishObj(p, o, objName, options)
+
+	return o, nil
+}
+
+// finishObj completes the Obj once all statements are parsed.
+func finishObj(p *objParser, o *Obj, objName string, options *ObjParserOptions) {
 
 	// drop empty groups
 	tmp := []*Group{}
@@ -600,8 +828,11 @@ func readObj(objName string, reader StringReader, options *ObjParserOptions) (*O
 		switch {
 		case g.IndexCount < 0:
 			continue // discard empty bogus group created internally by parser
+		case g.IndexCount == 0 && !g.isEmpty():
+			// group holding only line or point elements
 		case g.IndexCount < 3:
-			options.log(fmt.Sprintf("readObj: obj=%s BAD GROUP SIZE group=%s size=%d < 3", objName, g.Name, g.IndexCount))
+			options.warn(fmt.Sprintf("readObj: obj=%s BAD GROUP SIZE group=%s size=%d < 3", objName, g.Name, g.IndexCount))
+			p.diagnostics = appendDiagnostic(p.diagnostics, SeverityWarning, newParseError(objName, 0, "", ErrBadFace, "bad group size group=%s size=%d < 3", g.Name, g.IndexCount))
 		}
 		tmp = append(tmp, g)
 	}
@@ -609,138 +840,395 @@ func readObj(objName string, reader StringReader, options *ObjParserOptions) (*O
 
 	setupStride(o) // setup stride size
 
+	if options.InterleaveColors {
+		o.interleaveColors()
+	}
+
+	o.Diagnostics = p.diagnostics
+	o.SkippedFaces = p.skippedFaces
+
+	if options.KeepRawData {
+		p.keepRawData(o)
+	}
+
 	if options.LogStats {
-		options.log(fmt.Sprintf("readObj: INPUT lines=%v vertLines=%v textLines=%v normLines=%v faceLines=%v triangles=%v",
-			p.lineCount, p.vertLines, p.textLines, p.normLines, p.faceLines, p.triangles))
+		options.debug(fmt.Sprintf("readObj: INPUT lines=%v vertLines=%v textLines=%v normLines=%v faceLines=%v triangles=%v deferredLines=%v",
+			p.lineCount, p.vertLines, p.textLines, p.normLines, p.faceLines, p.triangles, len(p.lineBuf)))
 
-		options.log(fmt.Sprintf("readObj: STATS numberOfElements=%v indicesArraySize=%v", p.indexCount, len(o.Indices)))
-		options.log(fmt.Sprintf("readObj: STATS bigIndexFound=%v groups=%v", o.BigIndexFound, len(o.Groups)))
-		options.log(fmt.Sprintf("readObj: STATS textureCoordFound=%v normalCoordFound=%v", o.TextCoordFound, o.NormCoordFound))
-		options.log(fmt.Sprintf("readObj: STATS stride=%v textureOffset=%v normalOffset=%v", o.StrideSize, o.StrideOffsetTexture, o.StrideOffsetNormal))
+		options.debug(fmt.Sprintf("readObj: STATS numberOfElements=%v indicesArraySize=%v", p.indexCount, len(o.Indices)))
+		options.debug(fmt.Sprintf("readObj: STATS bigIndexFound=%v groups=%v", o.BigIndexFound, len(o.Groups)))
+		options.debug(fmt.Sprintf("readObj: STATS textureCoordFound=%v normalCoordFound=%v", o.TextCoordFound, o.NormCoordFound))
+		options.debug(fmt.Sprintf("readObj: STATS stride=%v textureOffset=%v normalOffset=%v", o.StrideSize, o.StrideOffsetTexture, o.StrideOffsetNormal))
 		for _, g := range o.Groups {
-			options.log(fmt.Sprintf("readObj: GROUP name=%s first=%d count=%d", g.Name, g.IndexBegin, g.IndexCount))
+			options.debug(fmt.Sprintf("readObj: GROUP name=%s first=%d count=%d", g.Name, g.IndexBegin, g.IndexCount))
 		}
 	}
+}
 
-	return o, nil
+// readLines parses the input in a single pass.
+// Once a face referencing a vertex not yet defined is found,
+// every following non-vertex line is deferred to scanLines.
+func readLines(p *objParser, o *Obj, reader StringReader, options *ObjParserOptions) (bool, error) {
+	p.startProgress()
+	defer p.endProgress(ProgressRead, p.totalBytes, options)
+
+	return readStream(p, o, reader, options)
 }
 
-func readLines(p *objParser, reader StringReader, options *ObjParserOptions) (bool, error) {
+// readStream reads the lines of either the main input or an included file.
+func readStream(p *objParser, o *Obj, reader StringReader, options *ObjParserOptions) (bool, error) {
 	p.lineCount = 0
 
 	for {
 		p.lineCount++
-		line, err := reader.ReadString('\n')
-		if err == io.EOF {
-			// parse last line
-			if fatal, e := parseLineVertex(p, line, options); e != nil {
-				options.log(fmt.Sprintf("readLines: %v", e))
-				return fatal, e
-			}
-			break // EOF
+		line, lines, err := readStatement(reader, options.Limits.MaxLineLength)
+		if len(p.includes) == 0 {
+			p.advanceProgress(ProgressRead, p.totalBytes, len(line), options)
 		}
 
-		if err != nil {
+		var limit *LimitError
+		if errors.As(err, &limit) {
+			limit.File = p.objName
+			limit.Line = p.lineCount
+			return ErrFatal, limit
+		}
+
+		if err != nil && err != io.EOF {
 			// unexpected IO error
-			return ErrFatal, fmt.Errorf("readLines: error: %v", err)
+			return ErrFatal, fmt.Errorf("readLines: error: %w", err)
 		}
 
-		if fatal, e := parseLineVertex(p, line, options); e != nil {
-			options.log(fmt.Sprintf("readLines: %v", e))
-			if fatal {
-				return fatal, e
+		if fatal, e := readLineAny(p, o, line, options); e != nil {
+			var nested *includeError
+			if !errors.As(e, &nested) {
+				options.warn(fmt.Sprintf("readLines: %v", e))
+				p.diagnostics = appendDiagnostic(p.diagnostics, SeverityError, e)
+			}
+			if fatal || options.Strict {
+				return ErrFatal, e
 			}
 		}
+
+		if e := p.checkLimits(o, options); e != nil {
+			return ErrFatal, e
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		p.lineCount += lines - 1 // continued lines
 	}
 
 	return ErrNonFatal, nil
 }
 
-// parseLineVertex: parse only vertex lines
-func parseLineVertex(p *objParser, rawLine string, options *ObjParserOptions) (bool, error) {
-	line := strings.TrimSpace(rawLine)
+// readLineAny parses vertex lines immediately, and other lines either
+// immediately or deferred, depending on forward references found so far.
+func readLineAny(p *objParser, o *Obj, rawLine string, options *ObjParserOptions) (bool, error) {
+	line := cleanLine(rawLine)
+
+	p.currLine = line
+
+	if isVertexLine(line) {
+		return parseLineVertex(p, line, options)
+	}
+
+	if isIncludeLine(line) {
+		return parseInclude(p, o, line, options)
+	}
+
+	if strings.HasPrefix(line, "vp ") {
+		// parameter vertices, like vertices, are never deferred,
+		// so curv2 forward references resolve on replay
+		return ErrNonFatal, parseFreeForm(p, o, line, options)
+	}
+
+	if len(p.lineBuf) > 0 {
+		p.deferLine(line) // keep order after first forward reference
+		return ErrNonFatal, nil
+	}
+
+	fatal, err := parseLine(p, o, line, options)
+	if errors.Is(err, errForwardRef) {
+		p.deferLine(line)
+		return ErrNonFatal, nil
+	}
+
+	return fatal, err
+}
+
+// deferredLine is a line saved for the 2nd pass,
+// along with the vertex counts needed to solve relative indices.
+type deferredLine struct {
+	line       string
+	objName    string // file the line came from, for included files
+	lineCount  int
+	vertLines  int
+	textLines  int
+	normLines  int
+	paramLines int
+}
+
+func (p *objParser) deferLine(line string) {
+	p.lineBuf = append(p.lineBuf, deferredLine{
+		line:       line,
+		objName:    p.objName,
+		lineCount:  p.lineCount,
+		vertLines:  p.vertLines,
+		textLines:  p.textLines,
+		normLines:  p.normLines,
+		paramLines: p.paramLines,
+	})
+	p.lineBytes += int64(len(line))
+}
+
+func isVertexLine(line string) bool {
+	return strings.HasPrefix(line, "v ") || strings.HasPrefix(line, "vt ") || strings.HasPrefix(line, "vn ")
+}
 
-	p.lineBuf = append(p.lineBuf, line) // save line for 2nd pass
+// parseLineVertex: parse only vertex lines
+func parseLineVertex(p *objParser, line string, options *ObjParserOptions) (bool, error) {
 
 	switch {
-	case line == "" || line[0] == '#':
-	case strings.HasPrefix(line, "s "):
-	case strings.HasPrefix(line, "o "):
-	case strings.HasPrefix(line, "g "):
-	case strings.HasPrefix(line, "usemtl "):
-	case strings.HasPrefix(line, "mtllib "):
-	case strings.HasPrefix(line, "f "):
 	case strings.HasPrefix(line, "vt "):
 
 		tex := line[3:]
-		t, err := parseFloatSliceSpace(tex)
+		var t [3]float64
+		size, err := parseFloatsSpace(t[:], tex)
 		if err != nil {
-			return ErrNonFatal, fmt.Errorf("parseLine: line=%d bad vertex texture=[%s]: %v", p.lineCount, tex, err)
+			return ErrNonFatal, p.errorf(ErrBadCoord, "bad vertex texture=[%s]: %v", tex, err)
 		}
-		size := len(t)
 		if size < 2 || size > 3 {
-			return ErrNonFatal, fmt.Errorf("parseLine: line=%d bad vertex texture=[%s] size=%d", p.lineCount, tex, size)
+			return ErrNonFatal, p.errorf(ErrBadCoord, "bad vertex texture=[%s] size=%d", tex, size)
 		}
-		if size > 2 {
+		if !options.KeepHomogeneous && size > 2 {
 			if w := t[2]; !closeToZero(w) {
-				options.log(fmt.Sprintf("parseLine: line=%d non-zero third texture coordinate w=%f: [%v]", p.lineCount, w, line))
+				options.warn(fmt.Sprintf("parseLine: line=%d non-zero third texture coordinate w=%f: [%v]", p.lineCount, w, line))
+				p.diagnostics = appendDiagnostic(p.diagnostics, SeverityWarning, p.errorf(ErrBadCoord, "non-zero third texture coordinate w=%f", w))
 			}
 		}
-		p.textCoord = append(p.textCoord, float32(t[0]), float32(t[1]))
+		p.addTexCoord(t[0], t[1], t[2], options)
 
 	case strings.HasPrefix(line, "vn "):
 
 		norm := line[3:]
-		n, err := parseFloatVector3Space(norm)
+		var n [3]float64
+		size, err := parseFloatsSpace(n[:], norm)
 		if err != nil {
-			return ErrNonFatal, fmt.Errorf("parseLine: line=%d bad vertex normal=[%s]: %v", p.lineCount, norm, err)
+			return ErrNonFatal, p.errorf(ErrBadCoord, "bad vertex normal=[%s]: %v", norm, err)
+		}
+		if size != 3 {
+			return ErrNonFatal, p.errorf(ErrBadCoord, "bad vertex normal=[%s] size=%d", norm, size)
 		}
-		p.normCoord = append(p.normCoord, float32(n[0]), float32(n[1]), float32(n[2]))
+		p.addNormal(n[0], n[1], n[2], options)
 
 	case strings.HasPrefix(line, "v "):
 
-		result, err := parseFloatSliceSpace(line[2:])
+		var result [6]float64
+		coordLen, err := parseFloatsSpace(result[:], line[2:])
 		if err != nil {
-			return ErrNonFatal, fmt.Errorf("parseLine %v: [%v]: error: %v", p.lineCount, line, err)
+			return ErrNonFatal, p.errorf(ErrBadCoord, "bad vertex=[%s]: %v", line, err)
 		}
-		coordLen := len(result)
 		switch coordLen {
 		case 3:
-			p.vertCoord = append(p.vertCoord, float32(result[0]), float32(result[1]), float32(result[2]))
+			p.addPosition(result[0], result[1], result[2], 1, options)
 		case 4:
-			w := result[3]
-			p.vertCoord = append(p.vertCoord, float32(result[0]/w), float32(result[1]/w), float32(result[2]/w))
+			p.addPosition(result[0], result[1], result[2], result[3], options)
+		case 6:
+			// non-standard vertex color: x y z r g b
+			p.addColoredPosition(result[0], result[1], result[2], result[3], result[4], result[5], options)
 		default:
-			return ErrNonFatal, fmt.Errorf("parseLine %v: [%v]: bad number of coords: %v", p.lineCount, line, coordLen)
+			return ErrNonFatal, p.errorf(ErrBadCoord, "bad vertex=[%s] number of coords: %d", line, coordLen)
 		}
-
-	default:
-		return ErrNonFatal, fmt.Errorf("parseLine %v: [%v]: unexpected", p.lineCount, line)
 	}
 
 	return ErrNonFatal, nil
 }
 
-func scanLines(p *objParser, o *Obj, options *ObjParserOptions) (bool, error) {
+// setSmooth handles an "s" statement.
+func (p *objParser) setSmooth(o *Obj, s int, options *ObjParserOptions) {
+	if options.SmoothPerTriangle {
+		p.smooth = s
+		if p.currGroup.isEmpty() {
+			p.currGroup.Smooth = s
+		}
+		return
+	}
+	if p.currGroup.Smooth != s {
+		if p.currGroup.isEmpty() {
+			// mark previous empty group as bogus
+			p.currGroup.IndexCount = -1
+		}
+		// create new group
+		p.currGroup = o.splitGroup(p.currGroup)
+		p.currGroup.Smooth = s
+	}
+}
 
-	p.currGroup = o.newGroup("", "", 0, 0)
+// setGroup handles a "g" or "o" statement.
+func (p *objParser) setGroup(o *Obj, statement byte, name string) {
+	if p.currGroup.Name == name {
+		return
+	}
+	name = strings.Clone(name) // do not retain the input line
+	if p.currGroup.Name == "" {
+		// only set missing name for group
+		p.currGroup.Name = name
+	} else {
+		// create new group
+		p.currGroup = o.splitGroup(p.currGroup)
+		p.currGroup.Name = name
+	}
+	p.currGroup.Names = groupNames(statement, name)
+}
 
-	p.lineCount = 0
+// setMaterial handles an "usemtl" statement.
+func (p *objParser) setMaterial(o *Obj, usemtl string, options *ObjParserOptions) {
+	if options.MaterialPerTriangle {
+		p.material = materialIndex(o, usemtl)
+		return
+	}
+	if p.currGroup.Usemtl == usemtl {
+		return
+	}
+	usemtl = strings.Clone(usemtl) // do not retain the input line
+	if p.currGroup.Usemtl == "" {
+		// only set the missing material name for group
+		p.currGroup.Usemtl = usemtl
+	} else {
+		if p.currGroup.isEmpty() {
+			// mark previous empty group as bogus
+			p.currGroup.IndexCount = -1
+		}
+		// create new group for material
+		p.currGroup = o.splitGroup(p.currGroup)
+		p.currGroup.Usemtl = usemtl
+	}
+}
 
-	for _, line := range p.lineBuf {
-		p.lineCount++
+// tagTriangles records the per-triangle smoothing group and material
+// of the triangles added from Indices position indices on, to the
+// current group holding count indices before.
+func (p *objParser) tagTriangles(o *Obj, indices, count int, options *ObjParserOptions) {
+	if options.SmoothPerTriangle {
+		for i := indices; i < len(o.Indices); i += 3 {
+			o.Smoothing = append(o.Smoothing, p.smooth)
+		}
+	}
+	if options.MaterialPerTriangle {
+		if count == 0 && p.material >= 0 {
+			// group material is the one of its first triangle
+			p.currGroup.Usemtl = o.MaterialNames[p.material]
+		}
+		for i := indices; i < len(o.Indices); i += 3 {
+			o.MaterialIndex = append(o.MaterialIndex, p.material)
+		}
+	}
+}
+
+// addPosition stores a v statement with weight w, scaled as set by
+// the options.
+func (p *objParser) addPosition(x, y, z, w float64, options *ObjParserOptions) {
+	if factors, ok := options.scaleFactors(); ok {
+		x, y, z = x*factors[0], y*factors[1], z*factors[2]
+	}
+	if options.KeepHomogeneous {
+		p.pushVert(x, y, z)
+	} else {
+		p.pushVert(x/w, y/w, z/w)
+	}
+	if p.colorCoord != nil {
+		p.pushColor(1, 1, 1)
+	}
+	p.pushW(w, options)
+	p.vertLines++
+}
+
+// addColoredPosition stores a non-standard "v x y z r g b" statement.
+func (p *objParser) addColoredPosition(x, y, z, r, g, b float64, options *ObjParserOptions) {
+	if factors, ok := options.scaleFactors(); ok {
+		x, y, z = x*factors[0], y*factors[1], z*factors[2]
+	}
+	p.pushVert(x, y, z)
+	p.pushColor(r, g, b)
+	p.pushW(1, options)
+	p.vertLines++
+}
+
+// addTexCoord stores a vt statement, flipped as set by the options.
+func (p *objParser) addTexCoord(u, v, w float64, options *ObjParserOptions) {
+	if options.KeepHomogeneous {
+		p.textW = append(p.textW, float32(w)) // zero if missing
+	}
+	if options.FlipTextureV {
+		v = 1 - v
+	}
+	p.pushText(u, v)
+	p.textLines++
+}
 
-		if fatal, e := parseLine(p, o, line, options); e != nil {
-			options.log(fmt.Sprintf("scanLines: %v", e))
-			if fatal {
-				return fatal, e
+// addNormal stores a vn statement, adjusted for the scale set by the options.
+func (p *objParser) addNormal(x, y, z float64, options *ObjParserOptions) {
+	if factors, ok := options.scaleFactors(); ok && (factors[0] != factors[1] || factors[1] != factors[2] || factors[0] < 0) {
+		n := [3]float64{x, y, z}
+		scaleNormal(&n, factors)
+		x, y, z = n[0], n[1], n[2]
+	}
+	p.pushNorm(x, y, z)
+	p.normLines++
+}
+
+// scanLines parses lines deferred by readLines, now that all vertex data is known.
+func scanLines(p *objParser, o *Obj, options *ObjParserOptions) (bool, error) {
+
+	if len(p.lineBuf) == 0 {
+		return ErrNonFatal, nil // fast path: no forward reference found
+	}
+
+	p.replay = true
+
+	// restore counters after replay
+	objName, lineCount, vertLines, textLines, normLines, paramLines := p.objName, p.lineCount, p.vertLines, p.textLines, p.normLines, p.paramLines
+	defer func() {
+		p.objName, p.lineCount, p.vertLines, p.textLines, p.normLines, p.paramLines = objName, lineCount, vertLines, textLines, normLines, paramLines
+	}()
+
+	p.startProgress()
+	defer p.endProgress(ProgressScan, p.lineBytes, options)
+
+	for _, d := range p.lineBuf {
+		p.objName = d.objName
+		p.lineCount = d.lineCount
+		p.vertLines = d.vertLines
+		p.textLines = d.textLines
+		p.normLines = d.normLines
+		p.paramLines = d.paramLines
+		p.currLine = d.line
+		p.advanceProgress(ProgressScan, p.lineBytes, len(d.line), options)
+
+		if fatal, e := parseLine(p, o, d.line, options); e != nil {
+			options.warn(fmt.Sprintf("scanLines: %v", e))
+			p.diagnostics = appendDiagnostic(p.diagnostics, SeverityError, e)
+			if fatal || options.Strict {
+				return ErrFatal, e
 			}
 		}
+
+		if e := p.checkLimits(o, options); e != nil {
+			return ErrFatal, e
+		}
 	}
 
 	return ErrNonFatal, nil
 }
 
+// vertexKey identifies an unified vertex by its absolute v/vt/vn indices.
+// Missing texture or normal indices are -1.
+type vertexKey struct {
+	v, t, n int
+}
+
 func solveRelativeIndex(index, size int) int {
 	if index > 0 {
 		return index - 1
@@ -748,15 +1236,6 @@ func solveRelativeIndex(index, size int) int {
 	return size + index
 }
 
-func splitSlash(s string) []string {
-	isSlash := func(c rune) bool {
-		return c == '/'
-	}
-
-	return strings.FieldsFunc(s, isSlash)
-
-}
-
 func pushIndex(currGroup *Group, o *Obj, i int) {
 	if i > 65535 {
 		o.BigIndexFound = true
@@ -765,87 +1244,136 @@ func pushIndex(currGroup *Group, o *Obj, i int) {
 	currGroup.IndexCount++
 }
 
-func addVertex(p *objParser, o *Obj, index string, options *ObjParserOptions) error {
-	ind := splitSlash(strings.Replace(index, "//", "/0/", 1))
-	size := len(ind)
-	if size < 1 || size > 3 {
-		return fmt.Errorf("addVertex: line=%d bad index=[%s] size=%d", p.lineCount, index, size)
+// resolveCorner parses a face corner into absolute v/vt/vn indices,
+// validating them against the vertex data read so far.
+func resolveCorner(p *objParser, index string, options *ObjParserOptions) (vertexKey, error) {
+	var ind [3]string
+	size, ok := splitCorner(&ind, index)
+	if !ok {
+		return vertexKey{}, p.errorf(ErrBadIndex, "bad index=[%s] size=%d", index, size)
 	}
 
 	v, err := strconv.ParseInt(ind[0], 10, 32)
 	if err != nil {
-		return fmt.Errorf("addVertex: line=%d bad integer 1st index=[%s]: %v", p.lineCount, ind[0], err)
+		return vertexKey{}, p.errorf(ErrBadIndex, "bad integer 1st index=[%s]: %v", ind[0], err)
 	}
 	vi := solveRelativeIndex(int(v), p.vertLines)
 
-	var ti int
-	var tIndex string
-	hasTextureCoord := strings.Index(index, "//") == -1 && size > 1
-	if hasTextureCoord {
+	ti := -1
+	if ind[1] != "" {
 		t, e := strconv.ParseInt(ind[1], 10, 32)
 		if e != nil {
-			return fmt.Errorf("addVertex: line=%d bad integer 2nd index=[%s]: %v", p.lineCount, ind[1], e)
+			return vertexKey{}, p.errorf(ErrBadIndex, "bad integer 2nd index=[%s]: %v", ind[1], e)
 		}
 		ti = solveRelativeIndex(int(t), p.textLines)
-		tIndex = strconv.Itoa(ti)
 	}
 
-	var ni int
-	var nIndex string
-	if size > 2 {
+	ni := -1
+	if ind[2] != "" {
 		n, e := strconv.ParseInt(ind[2], 10, 32)
 		if e != nil {
-			return fmt.Errorf("addVertex: line=%d bad integer 3rd index=[%s]: %v", p.lineCount, ind[2], e)
+			return vertexKey{}, p.errorf(ErrBadIndex, "bad integer 3rd index=[%s]: %v", ind[2], e)
 		}
 		ni = solveRelativeIndex(int(n), p.normLines)
-		nIndex = strconv.Itoa(ni)
 	}
 
-	absIndex := fmt.Sprintf("%d/%s/%s", vi, tIndex, nIndex)
-
-	// known unified index?
-	if i, ok := p.indexTable[absIndex]; ok {
-		pushIndex(p.currGroup, o, i)
-		return nil
-	}
+	vertLen, textLen, normLen := p.scratchLen()
 
 	vOffset := vi * 3
-	if vOffset+2 >= len(p.vertCoord) {
-		return fmt.Errorf("err: line=%d invalid vertex index=[%s]", p.lineCount, ind[0])
+	if !p.replay && v > 0 && vOffset+2 >= vertLen {
+		return vertexKey{}, errForwardRef
+	}
+	if vOffset < 0 || vOffset+2 >= vertLen {
+		return vertexKey{}, p.errorf(ErrIndexOutOfRange, "invalid vertex index=[%s]", ind[0])
 	}
 
-	o.Coord = append(o.Coord, p.vertCoord[vOffset+0]) // x
-	o.Coord = append(o.Coord, p.vertCoord[vOffset+1]) // y
-	o.Coord = append(o.Coord, p.vertCoord[vOffset+2]) // z
-
-	if tIndex != "" && hasTextureCoord {
+	if ti != -1 {
 		tOffset := ti * 2
+		if !p.replay && ti >= p.textLines && tOffset+1 >= textLen {
+			return vertexKey{}, errForwardRef
+		}
+		if tOffset < 0 || tOffset+1 >= textLen {
+			return vertexKey{}, p.errorf(ErrIndexOutOfRange, "invalid texture index=[%s]", ind[1])
+		}
+	}
 
-		if tOffset+1 >= len(p.textCoord) {
-			return fmt.Errorf("err: line=%d invalid texture index=[%s]", p.lineCount, ind[1])
+	if ni != -1 && !options.IgnoreNormals {
+		nOffset := ni * 3
+		if !p.replay && ni >= p.normLines && nOffset+2 >= normLen {
+			return vertexKey{}, errForwardRef
 		}
+		if nOffset < 0 || nOffset+2 >= normLen {
+			return vertexKey{}, p.errorf(ErrIndexOutOfRange, "invalid normal index=[%s]", ind[2])
+		}
+	}
 
-		o.Coord = append(o.Coord, p.textCoord[tOffset+0]) // u
-		o.Coord = append(o.Coord, p.textCoord[tOffset+1]) // v
-		o.TextCoordFound = true
+	return vertexKey{v: vi, t: ti, n: ni}, nil
+}
+
+// addVertex pushes the unified index for a resolved face corner.
+func addVertex(p *objParser, o *Obj, key vertexKey, options *ObjParserOptions) {
+	i := unifyVertex(p, o, key, options)
+	pushCorner(o, key, options)
+	pushIndex(p.currGroup, o, i)
+}
+
+// unifyVertex gets the unified index for a resolved corner,
+// appending its vertex data to the output when first seen.
+func unifyVertex(p *objParser, o *Obj, key vertexKey, options *ObjParserOptions) int {
+
+	// known unified index?
+	if !options.NoUnify {
+		if i, ok := p.indexTable[key]; ok {
+			return i
+		}
 	}
 
-	if !options.IgnoreNormals && nIndex != "" {
-		nOffset := ni * 3
+	hasTextureCoord := key.t != -1
+	hasNormal := key.n != -1 && !options.IgnoreNormals
+	vOffset := key.v * 3
+	tOffset := key.t * 2
+	nOffset := key.n * 3
 
-		o.Coord = append(o.Coord, p.normCoord[nOffset+0]) // x
-		o.Coord = append(o.Coord, p.normCoord[nOffset+1]) // y
-		o.Coord = append(o.Coord, p.normCoord[nOffset+2]) // z
+	stride := 3
+	if hasTextureCoord {
+		stride += 2
+	}
+	if hasNormal {
+		stride += 3
+	}
+	if p.double {
+		p.coord64 = appendCorner(p.coord64, p.vertCoord64, p.textCoord64, p.normCoord64, vOffset, tOffset, nOffset, hasTextureCoord, hasNormal)
+	} else if options.NonInterleaved {
+		p.appendSeparate(o, vOffset, tOffset, nOffset, hasTextureCoord, hasNormal)
+	} else {
+		if o.Coord == nil {
+			o.Coord = p.float32s(stride * p.hint.Vertices)
+		}
+		o.Coord = p.reserveFloat32s(o.Coord, stride)
+		o.Coord = appendCorner(o.Coord, p.vertCoord, p.textCoord, p.normCoord, vOffset, tOffset, nOffset, hasTextureCoord, hasNormal)
+	}
 
+	if hasTextureCoord {
+		o.TextCoordFound = true
+	}
+	if hasNormal {
 		o.NormCoordFound = true
 	}
 
 	// add unified index
-	pushIndex(p.currGroup, o, p.indexCount)
-	p.indexTable[absIndex] = p.indexCount
+	i := p.indexCount
+	if 3*key.v < len(p.colorCoord) {
+		p.appendColor(o, i, key.v)
+	}
+	if options.KeepHomogeneous {
+		p.appendW(o, key)
+	}
+	if !options.NoUnify {
+		p.indexTable[key] = i
+	}
 	p.indexCount++
 
-	return nil
+	return i
 }
 
 func smoothGroup(s string) (int, error) {
@@ -860,102 +1388,315 @@ func smoothGroup(s string) (int, error) {
 	return int(i), err
 }
 
+// Corners emitted for triangles and quads.
+var (
+	triangleOrder = [3]int{0, 1, 2}
+	quadOrder     = [6]int{0, 1, 2, 2, 3, 0}
+	quadOddOrder  = [6]int{0, 1, 3, 1, 2, 3}
+)
+
+func parseFace(p *objParser, o *Obj, face string, options *ObjParserOptions) error {
+	var fields [4]string // usual faces kept on stack
+	f := fields[:]
+	size := splitFields(f, face)
+	if size > len(f) {
+		f = make([]string, size) // polygon
+		splitFields(f, face)
+	}
+	if size < 3 {
+		return p.errorf(ErrBadFace, "bad face=[%s] size=%d", face, size)
+	}
+	f = f[:size]
+
+	// resolve all corners before emitting any triangle
+	var keyBuf [4]vertexKey
+	keys := keyBuf[:0]
+	for i, corner := range f {
+		k, err := resolveCorner(p, corner, options)
+		if err != nil {
+			return p.errorf(ErrBadFace, "bad face=[%s] index_v%d=[%s]: %v", face, i, corner, err)
+		}
+		keys = append(keys, k)
+	}
+
+	begin := len(o.Indices)
+	o.Indices = p.reserveInts(o.Indices, 3*(size-2))
+	p.triangles += size - 2
+
+	var order []int
+	switch size {
+	case 3:
+		// triangle face: v0 v1 v2
+		order = triangleOrder[:]
+	case 4:
+		if p.splitQuadOdd(keys, options) {
+			// quad face along the other diagonal:
+			// v0 v1 v2 v3 =>
+			// v0 v1 v3
+			// v1 v2 v3
+			order = quadOddOrder[:]
+			break
+		}
+		// quad face:
+		// v0 v1 v2 v3 =>
+		// v0 v1 v2
+		// v2 v3 v0
+		order = quadOrder[:]
+	default:
+		// polygon face
+		p.tris = p.triangulate(p.tris[:0], keys)
+		order = p.tris
+	}
+	for _, c := range order {
+		addVertex(p, o, keys[c], options)
+	}
+
+	if options.KeepFaces {
+		p.keepFace(o, keys, order, begin, options)
+	}
+
+	return nil
+}
+
+// objStatements lists the keywords accepted by the parser: vertex data,
+// elements, grouping, display attributes, free-form and include
+// statements. ScanObj shares it to pass over statements it has no
+// callback for.
+var objStatements = map[string]bool{
+	"v": true, "vt": true, "vn": true, "vp": true,
+	"f": true, "l": true, "p": true,
+	"g": true, "o": true, "s": true, "lod": true,
+	"usemtl": true, "mtllib": true, "usemap": true, "maplib": true,
+	"shadow_obj": true, "trace_obj": true,
+	"cstype": true, "deg": true, "curv": true, "curv2": true, "surf": true, "parm": true, "end": true,
+	"call": true, "csh": true,
+}
+
 func parseLine(p *objParser, o *Obj, line string, options *ObjParserOptions) (bool, error) {
 
+	p.currLine = line
+
 	switch {
-	case line == "" || line[0] == '#':
+	case line == "":
+	case line[0] == '#':
+		if options.KeepComments {
+			keepComment(p, o, line)
+		}
 	case strings.HasPrefix(line, "s "):
 		smooth := line[2:]
-		if s, err := smoothGroup(smooth); err == nil {
-			if p.currGroup.Smooth != s {
-				if p.currGroup.IndexCount == 0 {
-					// mark previous empty group as bogus
-					p.currGroup.IndexCount = -1
-				}
-				// create new group
-				p.currGroup = o.newGroup(p.currGroup.Name, p.currGroup.Usemtl, len(o.Indices), s)
-			}
-		} else {
-			return ErrNonFatal, fmt.Errorf("parseLine: line=%d bad boolean smooth=[%s]: %v: line=[%v]", p.lineCount, smooth, err, line)
+		s, err := smoothGroup(smooth)
+		if err != nil {
+			return ErrNonFatal, p.errorf(ErrBadValue, "bad smoothing group=[%s]: %v", smooth, err)
 		}
+		p.setSmooth(o, s, options)
 	case strings.HasPrefix(line, "o ") || strings.HasPrefix(line, "g "):
-		name := line[2:]
-		if p.currGroup.Name == "" {
-			// only set missing name for group
-			p.currGroup.Name = name
-		} else if p.currGroup.Name != name {
-			// create new group
-			p.currGroup = o.newGroup(name, p.currGroup.Usemtl, len(o.Indices), p.currGroup.Smooth)
-		}
+		p.setGroup(o, line[0], line[2:])
 	case strings.HasPrefix(line, "usemtl "):
-		usemtl := line[7:]
-		if p.currGroup.Usemtl == "" {
-			// only set the missing material name for group
-			p.currGroup.Usemtl = usemtl
-		} else if p.currGroup.Usemtl != usemtl {
-			if p.currGroup.IndexCount == 0 {
+		p.setMaterial(o, line[7:], options)
+	case strings.HasPrefix(line, "lod "):
+		lod, err := strconv.Atoi(strings.TrimSpace(line[4:]))
+		if err != nil || lod < 0 || lod > 100 {
+			return ErrNonFatal, p.errorf(ErrBadValue, "bad level of detail=[%s]", line[4:])
+		}
+		if p.currGroup.Lod != lod {
+			if p.currGroup.isEmpty() {
 				// mark previous empty group as bogus
 				p.currGroup.IndexCount = -1
 			}
-			// create new group for material
-			p.currGroup = o.newGroup(p.currGroup.Name, usemtl, len(o.Indices), p.currGroup.Smooth)
+			// create new group
+			p.currGroup = o.splitGroup(p.currGroup)
+			p.currGroup.Lod = lod
+		}
+	case strings.HasPrefix(line, "usemap "):
+		usemap := line[7:]
+		if usemap == "off" {
+			usemap = ""
 		}
+		if p.currGroup.Usemap == usemap {
+			break
+		}
+		usemap = strings.Clone(usemap) // do not retain the input line
+		if p.currGroup.isEmpty() {
+			// only set the texture map for empty group
+			p.currGroup.Usemap = usemap
+		} else {
+			// create new group for texture map
+			p.currGroup = o.splitGroup(p.currGroup)
+			p.currGroup.Usemap = usemap
+		}
+	case strings.HasPrefix(line, "shadow_obj "):
+		o.ShadowObj = strings.Clone(strings.TrimSpace(line[11:]))
+	case strings.HasPrefix(line, "trace_obj "):
+		o.TraceObj = strings.Clone(strings.TrimSpace(line[10:]))
 	case strings.HasPrefix(line, "mtllib "):
-		mtllib := line[7:]
-		if o.Mtllib != "" {
-			options.log(fmt.Sprintf("parseLine: line=%d mtllib redefinition old=%s new=%s", p.lineCount, o.Mtllib, mtllib))
+		for _, lib := range splitMtllib(line[7:], options.MtllibKeepSpaces) {
+			addMtllib(o, lib)
+		}
+	case strings.HasPrefix(line, "maplib "):
+		for _, lib := range strings.Fields(line[7:]) {
+			addMaplib(o, lib)
 		}
-		o.Mtllib = mtllib
 	case strings.HasPrefix(line, "f "):
 		p.faceLines++
 
-		face := line[2:]
-		f := strings.Fields(face)
-		size := len(f)
-		if size < 3 || size > 4 {
-			return ErrNonFatal, fmt.Errorf("parseLine: line=%d bad face=[%s] size=%d", p.lineCount, face, size)
-		}
-		// triangle face: v0 v1 v2
-		// quad face:
-		// v0 v1 v2 v3 =>
-		// v0 v1 v2
-		// v2 v3 v0
-		p.triangles++
-		if err := addVertex(p, o, f[0], options); err != nil {
-			return ErrNonFatal, fmt.Errorf("parseLine: line=%d bad face=[%s] index_v0=[%s]: %v", p.lineCount, face, f[0], err)
-		}
-		if err := addVertex(p, o, f[1], options); err != nil {
-			return ErrNonFatal, fmt.Errorf("parseLine: line=%d bad face=[%s] index_v1=[%s]: %v", p.lineCount, face, f[1], err)
-		}
-		if err := addVertex(p, o, f[2], options); err != nil {
-			return ErrNonFatal, fmt.Errorf("parseLine: line=%d bad face=[%s] index_v2=[%s]: %v", p.lineCount, face, f[2], err)
+		// a bad face must not leave a partial triangle behind
+		indices := len(o.Indices)
+		count := p.currGroup.IndexCount
+		triangles := p.triangles
+
+		if err := parseFace(p, o, line[2:], options); err != nil {
+			o.Indices = o.Indices[:indices]
+			if o.Corners != nil {
+				o.Corners = o.Corners[:indices]
+			}
+			p.currGroup.IndexCount = count
+			p.triangles = triangles
+			if errors.Is(err, errForwardRef) {
+				p.faceLines--
+				return ErrNonFatal, errForwardRef
+			}
+			p.skippedFaces++
+			return ErrNonFatal, err
 		}
-		if size > 3 {
-			// quad face
-			p.triangles++
-			if err := addVertex(p, o, f[2], options); err != nil {
-				return ErrNonFatal, fmt.Errorf("parseLine: line=%d bad face=[%s] index_v2=[%s]: %v", p.lineCount, face, f[2], err)
+		p.tagTriangles(o, indices, count, options)
+	case strings.HasPrefix(line, "p "):
+		if err := parsePointElement(p, o, line[2:], options); err != nil {
+			if errors.Is(err, errForwardRef) {
+				return ErrNonFatal, errForwardRef
 			}
-			if err := addVertex(p, o, f[3], options); err != nil {
-				return ErrNonFatal, fmt.Errorf("parseLine: line=%d bad face=[%s] index_v3=[%s]: %v", p.lineCount, face, f[3], err)
+			return ErrNonFatal, err
+		}
+	case strings.HasPrefix(line, "l "):
+		if err := parseLineElement(p, o, line[2:], options); err != nil {
+			if errors.Is(err, errForwardRef) {
+				return ErrNonFatal, errForwardRef
 			}
-			if err := addVertex(p, o, f[0], options); err != nil {
-				return ErrNonFatal, fmt.Errorf("parseLine: line=%d bad face=[%s] index_v0=[%s]: %v", p.lineCount, face, f[0], err)
+			return ErrNonFatal, err
+		}
+	case isFreeFormLine(line):
+		if err := parseFreeForm(p, o, line, options); err != nil {
+			if errors.Is(err, errForwardRef) {
+				return ErrNonFatal, errForwardRef
 			}
+			return ErrNonFatal, err
 		}
-	case strings.HasPrefix(line, "v "):
-		p.vertLines++
-	case strings.HasPrefix(line, "vt "):
-		p.textLines++
-	case strings.HasPrefix(line, "vn "):
-		p.normLines++
 	default:
-		return ErrNonFatal, fmt.Errorf("parseLine %v: [%v]: unexpected", p.lineCount, line)
+		if options.OnUnknown != nil {
+			keyword, rest, _ := strings.Cut(line, " ")
+			if err := options.OnUnknown(p.lineCount, keyword, strings.TrimSpace(rest)); err != nil {
+				return ErrNonFatal, p.errorf(ErrUnexpected, "directive=%s: %v", keyword, err)
+			}
+			return ErrNonFatal, nil
+		}
+		return ErrNonFatal, p.errorf(ErrUnexpected, "unexpected: [%s]", line)
 	}
 
 	return ErrNonFatal, nil
 }
 
+// splitMtllib splits the mtllib argument into library filenames.
+// Tokens are accumulated until one ending with ".mtl" is found,
+// so that "my materials.mtl" is taken as a single filename
+// while "a.mtl b.mtl" yields two libraries.
+func splitMtllib(s string, keepSpaces bool) []string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	if keepSpaces {
+		return []string{s}
+	}
+
+	fields := strings.Fields(s)
+
+	hasExt := false
+	for _, f := range fields {
+		if strings.HasSuffix(strings.ToLower(f), ".mtl") {
+			hasExt = true
+			break
+		}
+	}
+	if !hasExt {
+		return fields // no hint at all: assume space-separated names
+	}
+
+	var libs []string
+	var pending []string
+	for _, f := range fields {
+		pending = append(pending, f)
+		if strings.HasSuffix(strings.ToLower(f), ".mtl") {
+			libs = append(libs, strings.Join(pending, " "))
+			pending = nil
+		}
+	}
+	if len(pending) > 0 {
+		// trailing tokens without extension belong to last name
+		last := len(libs) - 1
+		libs[last] = strings.Join(append([]string{libs[last]}, pending...), " ")
+	}
+
+	return libs
+}
+
+func addMtllib(o *Obj, lib string) {
+	for _, l := range o.Mtllibs {
+		if l == lib {
+			return // already known
+		}
+	}
+	lib = strings.Clone(lib) // do not retain the input line
+	o.Mtllibs = append(o.Mtllibs, lib)
+	if o.Mtllib == "" {
+		o.Mtllib = lib
+	}
+}
+
+// materialIndex finds usemtl in the material table, adding it if missing.
+func materialIndex(o *Obj, usemtl string) int {
+	if i := slices.Index(o.MaterialNames, usemtl); i >= 0 {
+		return i
+	}
+	o.MaterialNames = append(o.MaterialNames, strings.Clone(usemtl))
+	return len(o.MaterialNames) - 1
+}
+
+func addMaplib(o *Obj, lib string) {
+	if slices.Contains(o.Maplibs, lib) {
+		return // already known
+	}
+	o.Maplibs = append(o.Maplibs, strings.Clone(lib))
+}
+
 func closeToZero(f float64) bool {
 	return math.Abs(f-0) < 0.000001
 }
+
+// scaleFactors gets the per-axis position scale from the Scale and
+// ScaleXYZ options, and whether it is not the identity.
+func (options *ObjParserOptions) scaleFactors() ([3]float64, bool) {
+	f := [3]float64{1, 1, 1}
+	if options.Scale != 0 {
+		f = [3]float64{options.Scale, options.Scale, options.Scale}
+	}
+	for i, s := range options.ScaleXYZ {
+		if s != 0 {
+			f[i] *= s
+		}
+	}
+	return f, f != [3]float64{1, 1, 1}
+}
+
+// scaleNormal adjusts normal n for positions scaled by factors: normals
+// are scaled by the inverse factors, then renormalized.
+func scaleNormal(n *[3]float64, factors [3]float64) {
+	var l float64
+	for i := range n {
+		n[i] /= factors[i]
+		l += n[i] * n[i]
+	}
+	if l = math.Sqrt(l); l > 0 {
+		for i := range n {
+			n[i] /= l
+		}
+	}
+}
@@ -1,9 +1,14 @@
 package gwob
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func BenchmarkCube1(b *testing.B) {
@@ -548,3 +553,152 @@ f 1/1/1 8/8/1 5/5/1
 f 1/1/3 5/5/3 6/6/3
 f 1/1/3 6/6/3 3/3/3
 `
+
+func TestMtllibMultiple(t *testing.T) {
+
+	options := ObjParserOptions{LogStats: LogStats, Logger: func(msg string) { fmt.Printf("TestMtllibMultiple NewObjFromBuf: log: %s\n", msg) }}
+
+	table := []struct {
+		input string
+		want  []string
+	}{
+		{"mtllib a.mtl\n", []string{"a.mtl"}},
+		{"mtllib a.mtl b.mtl\n", []string{"a.mtl", "b.mtl"}},
+		{"mtllib my materials.mtl\n", []string{"my materials.mtl"}},
+		{"mtllib a.mtl my b.MTL\nmtllib c.mtl a.mtl\n", []string{"a.mtl", "my b.MTL", "c.mtl"}},
+		{"mtllib a b\n", []string{"a", "b"}},
+	}
+
+	for _, data := range table {
+		o, err := NewObjFromBuf("mtllib", []byte(data.input), &options)
+		if err != nil {
+			t.Errorf("TestMtllibMultiple: NewObjFromBuf: %v", err)
+			continue
+		}
+		if len(o.Mtllibs) != len(data.want) {
+			t.Errorf("TestMtllibMultiple: input=[%s] want=%q got=%q", data.input, data.want, o.Mtllibs)
+			continue
+		}
+		for i, lib := range data.want {
+			if o.Mtllibs[i] != lib {
+				t.Errorf("TestMtllibMultiple: input=[%s] want=%q got=%q", data.input, data.want, o.Mtllibs)
+				break
+			}
+		}
+		if o.Mtllib != data.want[0] {
+			t.Errorf("TestMtllibMultiple: input=[%s] Mtllib: want=%s got=%s", data.input, data.want[0], o.Mtllib)
+		}
+	}
+
+	keep := ObjParserOptions{MtllibKeepSpaces: true}
+	o, err := NewObjFromBuf("mtllib", []byte("mtllib a b.mtl c.mtl\n"), &keep)
+	if err != nil {
+		t.Errorf("TestMtllibMultiple: NewObjFromBuf: %v", err)
+		return
+	}
+	if len(o.Mtllibs) != 1 || o.Mtllibs[0] != "a b.mtl c.mtl" {
+		t.Errorf("TestMtllibMultiple: keep spaces: got=%q", o.Mtllibs)
+	}
+}
+
+func TestForwardMixed(t *testing.T) {
+
+	options := ObjParserOptions{LogStats: LogStats, Logger: func(msg string) { fmt.Printf("TestForwardMixed NewObjFromBuf: log: %s\n", msg) }}
+
+	o, err := NewObjFromBuf("forwardMixedObj", []byte(forwardMixedObj), &options)
+	if err != nil {
+		t.Errorf("TestForwardMixed: NewObjFromBuf: %v", err)
+		return
+	}
+
+	if !sliceEqualInt(forwardMixedIndices, o.Indices) {
+		t.Errorf("TestForwardMixed: indices: want=%v got=%v", forwardMixedIndices, o.Indices)
+	}
+
+	if !sliceEqualFloat(relativeCoord, o.Coord) {
+		t.Errorf("TestForwardMixed: coord: want=%v got=%v", relativeCoord, o.Coord)
+	}
+
+	expectInt(t, "TestForwardMixed: groups", 2, len(o.Groups))
+	expectInt(t, "TestForwardMixed: group 1 begin", 3, o.Groups[1].IndexBegin)
+	expectInt(t, "TestForwardMixed: group 1 count", 9, o.Groups[1].IndexCount)
+}
+
+func TestNoUnify(t *testing.T) {
+
+	options := ObjParserOptions{NoUnify: true, LogStats: LogStats, Logger: func(msg string) { fmt.Printf("TestNoUnify NewObjFromBuf: log: %s\n", msg) }}
+
+	o, err := NewObjFromBuf("forwardMixedObj", []byte(forwardMixedObj), &options)
+	if err != nil {
+		t.Errorf("TestNoUnify: NewObjFromBuf: %v", err)
+		return
+	}
+
+	expectInt(t, "TestNoUnify: indices", len(forwardMixedIndices), len(o.Indices))
+	for i, ind := range o.Indices {
+		if ind != i {
+			t.Errorf("TestNoUnify: index %d: want sequential got %d", i, ind)
+		}
+	}
+	expectInt(t, "TestNoUnify: elements", len(forwardMixedIndices), o.NumberOfElements())
+
+	// every corner carries its own copy of the vertex data
+	for i, ind := range forwardMixedIndices {
+		want := relativeCoord[3*ind : 3*ind+3]
+		got := o.Coord[3*i : 3*i+3]
+		if !sliceEqualFloat(want, got) {
+			t.Errorf("TestNoUnify: corner %d: want=%v got=%v", i, want, got)
+		}
+	}
+}
+
+var forwardMixedObj = `
+g first
+v 1 1 1
+v 2 2 2
+v 3 3 3
+f 1 2 3
+g second
+# forward reference
+f 4 5 6
+# relative to vertices defined so far
+f -3 -2 -1
+v 4 4 4
+v 5 5 5
+v 6 6 6
+f -3 -2 -1
+`
+
+var forwardMixedIndices = []int{0, 1, 2, 3, 4, 5, 0, 1, 2, 3, 4, 5}
+
+func TestReadMaterialLibNilOptions(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "scene.mtl")
+	if err := os.WriteFile(file, []byte(sceneMtl), 0o644); err != nil {
+		t.Fatalf("TestReadMaterialLibNilOptions: WriteFile: %v", err)
+	}
+	fsys := fstest.MapFS{"scene.mtl": {Data: []byte(sceneMtl)}}
+
+	readers := []struct {
+		name string
+		read func() (MaterialLib, error)
+	}{
+		{"buf", func() (MaterialLib, error) { return ReadMaterialLibFromBuf([]byte(sceneMtl), nil) }},
+		{"reader", func() (MaterialLib, error) { return ReadMaterialLibFromReader(strings.NewReader(sceneMtl), nil) }},
+		{"string reader", func() (MaterialLib, error) {
+			return ReadMaterialLibFromStringReader(bufio.NewReader(strings.NewReader(sceneMtl)), nil)
+		}},
+		{"file", func() (MaterialLib, error) { return ReadMaterialLibFromFile(file, nil) }},
+		{"fs", func() (MaterialLib, error) { return ReadMaterialLibFromFS(fsys, "scene.mtl", nil) }},
+	}
+
+	for _, r := range readers {
+		lib, err := r.read()
+		if err != nil {
+			t.Errorf("TestReadMaterialLibNilOptions: %s: %v", r.name, err)
+			continue
+		}
+		if _, found := lib.Lib["red"]; !found {
+			t.Errorf("TestReadMaterialLibNilOptions: %s: material not found", r.name)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package gwob
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NewObjFromOFF reads a mesh from OFF (Object File Format), as used
+// by Geomview and geometry datasets. COFF vertex colors, either
+// integers in [0,255] or floats in [0,1], are stored in Colors, with
+// alpha discarded. Polygon faces are triangulated as fans, and face
+// colors are ignored. The result has a single unnamed group.
+func NewObjFromOFF(rd io.Reader) (*Obj, error) {
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+
+	// next gets the fields of the next line with content.
+	next := func() ([]string, error) {
+		for scanner.Scan() {
+			lineNum++
+			line, _, _ := strings.Cut(scanner.Text(), "#")
+			if fields := strings.Fields(line); len(fields) > 0 {
+				return fields, nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+	fail := func(err error) (*Obj, error) {
+		return nil, fmt.Errorf("NewObjFromOFF: line=%d: %w", lineNum, err)
+	}
+
+	fields, err := next()
+	if err != nil {
+		return fail(err)
+	}
+	keyword := fields[0]
+	var colors bool
+	switch keyword {
+	case "OFF":
+	case "COFF":
+		colors = true
+	default:
+		return fail(fmt.Errorf("unsupported header: %q", keyword))
+	}
+
+	fields = fields[1:]
+	if len(fields) == 0 {
+		if fields, err = next(); err != nil {
+			return fail(err)
+		}
+	}
+	if len(fields) < 2 {
+		return fail(fmt.Errorf("bad counts: %q", fields))
+	}
+	vertices, errV := strconv.Atoi(fields[0])
+	faces, errF := strconv.Atoi(fields[1])
+	if errV != nil || errF != nil || vertices < 0 || faces < 0 {
+		return fail(fmt.Errorf("bad counts: %q", fields))
+	}
+
+	o := &Obj{ColorFound: colors}
+	group := o.newGroup("", "", 0, 0)
+
+	for i := 0; i < vertices; i++ {
+		if fields, err = next(); err != nil {
+			return fail(err)
+		}
+		values, errParse := parseFloats(fields)
+		if errParse != nil {
+			return fail(errParse)
+		}
+		if len(values) < 3 {
+			return fail(fmt.Errorf("bad vertex: %q", fields))
+		}
+		o.Positions = append(o.Positions, values[:3]...)
+		if !colors {
+			continue
+		}
+		if len(values) < 6 {
+			return fail(fmt.Errorf("missing vertex color: %q", fields))
+		}
+		rgb := values[3:6]
+		if !strings.ContainsAny(strings.Join(fields[3:6], ""), ".eE") {
+			for c := range rgb {
+				rgb[c] /= 255 // integer color
+			}
+		}
+		o.Colors = append(o.Colors, rgb...)
+	}
+
+	for i := 0; i < faces; i++ {
+		if fields, err = next(); err != nil {
+			return fail(err)
+		}
+		n, errN := strconv.Atoi(fields[0])
+		if errN != nil || n < 3 || len(fields) < 1+n {
+			return fail(fmt.Errorf("bad face: %q", fields))
+		}
+		corners := make([]int, n)
+		for c := range corners {
+			v, errIndex := strconv.Atoi(fields[1+c])
+			if errIndex != nil {
+				return fail(errIndex)
+			}
+			if v < 0 || v >= vertices {
+				return fail(fmt.Errorf("index=%d out of range vertices=%d", v, vertices))
+			}
+			corners[c] = v
+		}
+		for c := 2; c < n; c++ {
+			pushIndex(group, o, corners[0])
+			pushIndex(group, o, corners[c-1])
+			pushIndex(group, o, corners[c])
+		}
+	}
+
+	if o.Positions == nil {
+		setupStride(o)
+		return o, nil
+	}
+	o.Interleave()
+	return o, nil
+}
+
+// NewObjFromOFFFile reads a mesh from an OFF file.
+func NewObjFromOFFFile(filename string) (*Obj, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewObjFromOFF(f)
+}
+
+func parseFloats(fields []string) ([]float32, error) {
+	values := make([]float32, len(fields))
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 32)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = float32(v)
+	}
+	return values, nil
+}
+
+// ToOFF writes the Obj triangles in OFF format, as COFF
+// with integer colors when the Obj has vertex colors.
+// Texture coordinates, normals and groups are not represented.
+func (o *Obj) ToOFF(w io.Writer) error {
+	if len(o.Indices)%3 != 0 {
+		return fmt.Errorf("ToOFF: index count=%d must be a multiple of 3", len(o.Indices))
+	}
+
+	bw := bufio.NewWriter(w)
+	strides := o.NumberOfElements()
+
+	keyword := "OFF"
+	if o.ColorFound {
+		keyword = "COFF"
+	}
+	fmt.Fprintf(bw, "%s\n#%s\n%d %d 0\n", keyword, exportHeader, strides, len(o.Indices)/3)
+
+	var buf []byte
+	for s := 0; s < strides; s++ {
+		x, y, z := o.VertexCoordinates(s)
+		buf = appendFloats(buf[:0], x, y, z)
+		if o.ColorFound {
+			r, g, b := o.VertexColor(s)
+			buf = fmt.Appendf(buf, " %d %d %d 255", colorByte(r), colorByte(g), colorByte(b))
+		}
+		buf = append(buf, '\n')
+		bw.Write(buf)
+	}
+
+	for i := 0; i < len(o.Indices); i += 3 {
+		fmt.Fprintf(bw, "3 %d %d %d\n", o.Indices[i], o.Indices[i+1], o.Indices[i+2])
+	}
+
+	return bw.Flush()
+}
+
+// ToOFFFile saves the Obj to an OFF file.
+func (o *Obj) ToOFFFile(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return o.ToOFF(f)
+}
+
+// appendFloats appends space separated values, in shortest form.
+func appendFloats(buf []byte, values ...float32) []byte {
+	for _, f := range values {
+		if len(buf) > 0 {
+			buf = append(buf, ' ')
+		}
+		buf = strconv.AppendFloat(buf, float64(f), 'g', -1, 32)
+	}
+	return buf
+}
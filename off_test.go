@@ -0,0 +1,81 @@
+package gwob
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+var squareOFF = `COFF
+# square with a quad face
+4 1 0
+0 0 0 255 0 0 255
+1 0 0 0 255 0 255
+1 1 0 0 0 255 255
+0 1 0 255 255 255 255
+4 0 1 2 3
+`
+
+func TestOFF(t *testing.T) {
+	o, err := NewObjFromOFF(strings.NewReader(squareOFF))
+	if err != nil {
+		t.Fatalf("TestOFF: NewObjFromOFF: %v", err)
+	}
+	expectInt(t, "TestOFF: elements", 4, o.NumberOfElements())
+	if !sliceEqualInt([]int{0, 1, 2, 0, 2, 3}, o.Indices) {
+		t.Errorf("TestOFF: indices: %v", o.Indices)
+	}
+	wantColors := []float32{1, 0, 0, 0, 1, 0, 0, 0, 1, 1, 1, 1}
+	if !o.ColorFound || !sliceEqualFloat(wantColors, o.Colors) {
+		t.Errorf("TestOFF: colors: want=%v got=%v", wantColors, o.Colors)
+	}
+
+	// round trip
+	var buf bytes.Buffer
+	if errWrite := o.ToOFF(&buf); errWrite != nil {
+		t.Fatalf("TestOFF: ToOFF: %v", errWrite)
+	}
+	if !strings.HasPrefix(buf.String(), "COFF\n") {
+		t.Errorf("TestOFF: ToOFF: missing COFF header")
+	}
+	o2, err := NewObjFromOFF(&buf)
+	if err != nil {
+		t.Fatalf("TestOFF: reparse: %v", err)
+	}
+	if !sliceEqualInt(o.Indices, o2.Indices) {
+		t.Errorf("TestOFF: reparse indices: want=%v got=%v", o.Indices, o2.Indices)
+	}
+	if !sliceEqualFloat(o.Coord, o2.Coord) {
+		t.Errorf("TestOFF: reparse coord: want=%v got=%v", o.Coord, o2.Coord)
+	}
+	if !sliceEqualFloat(o.Colors, o2.Colors) {
+		t.Errorf("TestOFF: reparse colors: want=%v got=%v", o.Colors, o2.Colors)
+	}
+}
+
+func TestOFFHeaderCounts(t *testing.T) {
+	o, err := NewObjFromOFF(strings.NewReader("OFF 3 1 0\n0 0 0\n1 0 0\n0 1 0\n3 0 1 2\n"))
+	if err != nil {
+		t.Fatalf("TestOFFHeaderCounts: NewObjFromOFF: %v", err)
+	}
+	if o.ColorFound {
+		t.Errorf("TestOFFHeaderCounts: unexpected colors")
+	}
+	if !sliceEqualInt([]int{0, 1, 2}, o.Indices) {
+		t.Errorf("TestOFFHeaderCounts: indices: %v", o.Indices)
+	}
+}
+
+func TestOFFBad(t *testing.T) {
+	bad := []string{
+		"PLY\n",
+		"OFF\n3 1 0\n0 0 0\n1 0 0\n",
+		"OFF\n3 1 0\n0 0 0\n1 0 0\n0 1 0\n3 0 1 3\n",
+		"NOFF\n0 0 0\n",
+	}
+	for _, s := range bad {
+		if _, err := NewObjFromOFF(strings.NewReader(s)); err == nil {
+			t.Errorf("TestOFFBad: unexpected success: %q", s)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package gwob
+
+import (
+	"math"
+	"sort"
+)
+
+// Forsyth vertex cache optimization parameters.
+// See: https://tomforsyth1000.github.io/papers/fast_vert_cache_opt.html
+const (
+	forsythCacheSize      = 32
+	forsythDecayPower     = 1.5
+	forsythLastTriScore   = 0.75
+	forsythValenceScale   = 2.0
+	forsythValencePower   = 0.5
+	forsythCacheSizeFloat = float64(forsythCacheSize)
+)
+
+// Optimize reorders the triangles of each group to improve the hit rate
+// of the GPU post-transform vertex cache, using Tom Forsyth's linear-speed
+// algorithm. Indices is rewritten in place: group ranges are preserved,
+// as are per-triangle Smoothing and MaterialIndex runs, which are
+// optimized separately. Vertex data is not changed.
+func (o *Obj) Optimize() {
+	if len(o.Indices)%3 != 0 {
+		return
+	}
+
+	order := make([]int, len(o.Indices)/3) // new triangle -> old triangle
+	for t := range order {
+		order[t] = t
+	}
+
+	opt := newForsyth(o.Indices)
+
+	for _, g := range o.Groups {
+		begin := g.IndexBegin / 3
+		end := (g.IndexBegin + g.IndexCount) / 3
+		for begin < end {
+			// split the group into runs of equal per-triangle attributes
+			run := begin + 1
+			for run < end && sameTriangleAttributes(o, begin, run) {
+				run++
+			}
+			opt.optimize(order[begin:run])
+			begin = run
+		}
+	}
+
+	permuteTriangles(o, order)
+}
+
+func sameTriangleAttributes(o *Obj, t1, t2 int) bool {
+	if t2 < len(o.Smoothing) && o.Smoothing[t1] != o.Smoothing[t2] {
+		return false
+	}
+	if t2 < len(o.MaterialIndex) && o.MaterialIndex[t1] != o.MaterialIndex[t2] {
+		return false
+	}
+	return true
+}
+
+// forsyth holds the optimizer state for a run of triangles.
+type forsyth struct {
+	indices   []int
+	cachePos  map[int]int   // vertex -> cache position, absent if not cached
+	valence   map[int]int   // vertex -> triangles not yet emitted
+	triangles map[int][]int // vertex -> triangles using it
+	score     map[int]float64
+}
+
+func newForsyth(indices []int) *forsyth {
+	return &forsyth{indices: indices}
+}
+
+func vertexScore(cachePos, valence int) float64 {
+	if valence == 0 {
+		return -1 // no triangles left
+	}
+	var score float64
+	switch {
+	case cachePos < 0:
+	case cachePos < 3:
+		score = forsythLastTriScore // used by the last triangle
+	default:
+		scale := 1 / (forsythCacheSizeFloat - 3)
+		score = math.Pow(1-float64(cachePos-3)*scale, forsythDecayPower)
+	}
+	return score + forsythValenceScale*math.Pow(float64(valence), -forsythValencePower)
+}
+
+func (f *forsyth) vertexScore(v int) float64 {
+	pos, found := f.cachePos[v]
+	if !found {
+		pos = -1
+	}
+	return vertexScore(pos, f.valence[v])
+}
+
+// optimize reorders the triangles listed in tris, in place.
+func (f *forsyth) optimize(tris []int) {
+	if len(tris) < 2 {
+		return
+	}
+
+	f.cachePos = map[int]int{}
+	f.valence = map[int]int{}
+	f.triangles = map[int][]int{}
+	f.score = map[int]float64{}
+
+	for i, t := range tris {
+		for _, v := range f.indices[3*t : 3*t+3] {
+			f.valence[v]++
+			f.triangles[v] = append(f.triangles[v], i)
+		}
+	}
+	for v := range f.valence {
+		f.score[v] = f.vertexScore(v)
+	}
+
+	triScore := make([]float64, len(tris))
+	for i, t := range tris {
+		for _, v := range f.indices[3*t : 3*t+3] {
+			triScore[i] += f.score[v]
+		}
+	}
+
+	added := make([]bool, len(tris))
+	result := make([]int, 0, len(tris))
+	var cache []int
+	best := -1
+	cursor := 0 // next candidate when the cache has no triangles left
+
+	for len(result) < len(tris) {
+		if best < 0 {
+			// pick the best remaining triangle, scanning forward
+			for added[cursor] {
+				cursor++
+			}
+			best = cursor
+			for i := cursor + 1; i < len(tris) && i < cursor+forsythCacheSize; i++ {
+				if !added[i] && triScore[i] > triScore[best] {
+					best = i
+				}
+			}
+		}
+
+		added[best] = true
+		result = append(result, tris[best])
+		corners := f.indices[3*tris[best] : 3*tris[best]+3]
+
+		// update cache: the triangle vertices move to the front
+		newCache := make([]int, 0, forsythCacheSize+3)
+		newCache = append(newCache, corners...)
+		for _, v := range cache {
+			if v != corners[0] && v != corners[1] && v != corners[2] {
+				newCache = append(newCache, v)
+			}
+		}
+		for _, v := range corners {
+			f.valence[v]--
+			list := f.triangles[v]
+			for i, t := range list {
+				if t == best {
+					list[i] = list[len(list)-1]
+					f.triangles[v] = list[:len(list)-1]
+					break
+				}
+			}
+		}
+		for pos, v := range newCache {
+			if pos < forsythCacheSize {
+				f.cachePos[v] = pos
+			} else {
+				delete(f.cachePos, v) // evicted
+			}
+		}
+		if len(newCache) > forsythCacheSize {
+			newCache = newCache[:forsythCacheSize]
+		}
+
+		// rescore the touched vertices and their remaining triangles
+		touched := append(append([]int(nil), newCache...), cache...)
+		for _, v := range touched {
+			f.score[v] = f.vertexScore(v)
+		}
+		best = -1
+		bestScore := math.Inf(-1)
+		for _, v := range touched {
+			for _, i := range f.triangles[v] {
+				t := tris[i]
+				s := f.score[f.indices[3*t]] + f.score[f.indices[3*t+1]] + f.score[f.indices[3*t+2]]
+				triScore[i] = s
+				if s > bestScore {
+					best, bestScore = i, s
+				}
+			}
+		}
+		cache = newCache
+	}
+
+	copy(tris, result)
+}
+
+// permuteTriangles reorders the triangles of o, where order maps each
+// new triangle position to the old one, keeping the per-triangle and
+// per-index arrays in sync. Faces are remapped while their triangles
+// stay together, otherwise they are dropped.
+func permuteTriangles(o *Obj, order []int) {
+	inverse := make([]int, len(order)) // old triangle -> new triangle
+	for t, old := range order {
+		inverse[old] = t
+	}
+
+	indices := make([]int, len(o.Indices))
+	for t, old := range order {
+		copy(indices[3*t:3*t+3], o.Indices[3*old:3*old+3])
+	}
+	copy(o.Indices, indices)
+
+	if len(o.Corners) == len(o.Indices) {
+		corners := make([]FaceIndex, len(o.Corners))
+		for t, old := range order {
+			copy(corners[3*t:3*t+3], o.Corners[3*old:3*old+3])
+		}
+		o.Corners = corners
+	}
+	o.Smoothing = permuteInts(o.Smoothing, order)
+	o.MaterialIndex = permuteInts(o.MaterialIndex, order)
+
+	for i, c := range o.Comments {
+		if t := c.Index / 3; t < len(inverse) {
+			o.Comments[i].Index = 3 * inverse[t]
+		}
+	}
+	sort.SliceStable(o.Comments, func(i, j int) bool {
+		return o.Comments[i].Index < o.Comments[j].Index
+	})
+
+	for i := range o.Faces {
+		f := &o.Faces[i]
+		first := f.IndexBegin / 3
+		for j := 0; j < f.IndexCount/3; j++ {
+			if inverse[first+j] != inverse[first]+j {
+				o.Faces = nil // face split apart
+				return
+			}
+		}
+		if f.IndexCount > 0 {
+			f.IndexBegin = 3 * inverse[first]
+		}
+	}
+	sort.SliceStable(o.Faces, func(i, j int) bool {
+		return o.Faces[i].IndexBegin < o.Faces[j].IndexBegin
+	})
+}
+
+// permuteInts reorders per-triangle values, if present.
+func permuteInts(values []int, order []int) []int {
+	if len(values) != len(order) {
+		return values
+	}
+	result := make([]int, len(values))
+	for t, old := range order {
+		result[t] = values[old]
+	}
+	return result
+}
+
+// ACMR gets the average cache miss ratio of Indices, the number of
+// vertex transforms per triangle with a FIFO post-transform cache of
+// cacheSize entries. Lower is better: 0.5 is ideal for large meshes
+// and 3 means no reuse. See Optimize.
+func (o *Obj) ACMR(cacheSize int) float64 {
+	triangles := len(o.Indices) / 3
+	if triangles == 0 {
+		return 0
+	}
+	var fifo []int
+	cached := map[int]bool{}
+	misses := 0
+	for _, v := range o.Indices[:3*triangles] {
+		if cached[v] {
+			continue
+		}
+		misses++
+		fifo = append(fifo, v)
+		cached[v] = true
+		if len(fifo) > cacheSize {
+			delete(cached, fifo[0])
+			fifo = fifo[1:]
+		}
+	}
+	return float64(misses) / float64(triangles)
+}
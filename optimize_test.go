@@ -0,0 +1,132 @@
+package gwob
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// gridObj builds a size x size grid of quads, with shuffled triangles.
+func gridObj(size int) *Obj {
+	var coord []float32
+	for y := 0; y <= size; y++ {
+		for x := 0; x <= size; x++ {
+			coord = append(coord, float32(x), float32(y), 0)
+		}
+	}
+	var tris [][3]int
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			a := y*(size+1) + x
+			b, c, d := a+1, a+size+2, a+size+1
+			tris = append(tris, [3]int{a, b, c}, [3]int{c, d, a})
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	r.Shuffle(len(tris), func(i, j int) { tris[i], tris[j] = tris[j], tris[i] })
+	var indices []int
+	for _, t := range tris {
+		indices = append(indices, t[:]...)
+	}
+	o, _ := NewObjFromVertex(coord, indices)
+	return o
+}
+
+func sortedTriangles(indices []int) [][3]int {
+	var tris [][3]int
+	for i := 0; i < len(indices); i += 3 {
+		tris = append(tris, [3]int{indices[i], indices[i+1], indices[i+2]})
+	}
+	sort.Slice(tris, func(i, j int) bool {
+		a, b := tris[i], tris[j]
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		if a[1] != b[1] {
+			return a[1] < b[1]
+		}
+		return a[2] < b[2]
+	})
+	return tris
+}
+
+func TestOptimize(t *testing.T) {
+	o := gridObj(40)
+	want := sortedTriangles(o.Indices)
+
+	before := o.ACMR(forsythCacheSize)
+	o.Optimize()
+	after := o.ACMR(forsythCacheSize)
+
+	if after >= before || after > 0.8 {
+		t.Errorf("TestOptimize: ACMR before=%.3f after=%.3f", before, after)
+	}
+
+	got := sortedTriangles(o.Indices)
+	if len(got) != len(want) {
+		t.Fatalf("TestOptimize: triangles: want=%d got=%d", len(want), len(got))
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			t.Fatalf("TestOptimize: triangle %d: want=%v got=%v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestOptimizeMaterials(t *testing.T) {
+	options := NewObjParserOptions(WithMaterialPerTriangle(true), WithKeepFaces(true))
+	o, err := NewObjFromBuf("materialsObj", []byte(materialsObj), options)
+	if err != nil {
+		t.Fatalf("TestOptimizeMaterials: NewObjFromBuf: %v", err)
+	}
+	want := trianglesWithMaterial(o)
+	groups := make([]Group, len(o.Groups))
+	for i, g := range o.Groups {
+		groups[i] = *g
+	}
+
+	o.Optimize()
+
+	for i, g := range o.Groups {
+		if g.IndexBegin != groups[i].IndexBegin || g.IndexCount != groups[i].IndexCount {
+			t.Errorf("TestOptimizeMaterials: group %d range changed", i)
+		}
+	}
+	if got := trianglesWithMaterial(o); !reflect.DeepEqual(want, got) {
+		t.Errorf("TestOptimizeMaterials: triangle materials: want=%v got=%v", want, got)
+	}
+	for _, f := range o.Faces {
+		for j, i := range f.Indices(o) {
+			found := false
+			for _, v := range f.Vertices {
+				found = found || v == i
+			}
+			if !found {
+				t.Errorf("TestOptimizeMaterials: face at %d: index %d=%d not a face vertex", f.IndexBegin, j, i)
+			}
+		}
+	}
+}
+
+// trianglesWithMaterial lists the triangles of each group with their
+// material, sorted, to compare meshes regardless of triangle order.
+func trianglesWithMaterial(o *Obj) [][][4]int {
+	var groups [][][4]int
+	for _, g := range o.Groups {
+		var list [][4]int
+		for i := g.IndexBegin; i < g.IndexBegin+g.IndexCount; i += 3 {
+			list = append(list, [4]int{o.Indices[i], o.Indices[i+1], o.Indices[i+2], o.MaterialIndex[i/3]})
+		}
+		sort.Slice(list, func(i, j int) bool {
+			for k := range list[i] {
+				if list[i][k] != list[j][k] {
+					return list[i][k] < list[j][k]
+				}
+			}
+			return false
+		})
+		groups = append(groups, list)
+	}
+	return groups
+}
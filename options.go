@@ -0,0 +1,177 @@
+package gwob
+
+import (
+	"log/slog"
+)
+
+// Option is a functional option for ObjParserOptions.
+//
+// Example:
+//
+//	options := gwob.NewObjParserOptions(gwob.WithLogger(log.Print), gwob.WithIgnoreNormals(true))
+//	o, err := gwob.NewObjFromFile("gopher.obj", options)
+type Option func(*ObjParserOptions)
+
+// NewObjParserOptions creates parser options from functional options.
+// Options not set keep their zero values.
+func NewObjParserOptions(opts ...Option) *ObjParserOptions {
+	options := &ObjParserOptions{}
+	return options.Apply(opts...)
+}
+
+// Apply applies functional options to existing parser options.
+func (opt *ObjParserOptions) Apply(opts ...Option) *ObjParserOptions {
+	for _, o := range opts {
+		o(opt)
+	}
+	return opt
+}
+
+// WithLogger sets the function used to report parser messages.
+func WithLogger(logger func(string)) Option {
+	return func(opt *ObjParserOptions) {
+		opt.Logger = logger
+	}
+}
+
+// WithLogStats enables logging of parser statistics.
+func WithLogStats(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.LogStats = enable
+	}
+}
+
+// WithIgnoreNormals discards normal coordinates found in the input.
+func WithIgnoreNormals(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.IgnoreNormals = enable
+	}
+}
+
+// WithMtllibKeepSpaces takes the whole mtllib argument as a single filename.
+func WithMtllibKeepSpaces(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.MtllibKeepSpaces = enable
+	}
+}
+
+// WithStrict aborts parsing on the first error, instead of skipping the bad line.
+func WithStrict(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.Strict = enable
+	}
+}
+
+// WithNoUnify emits one stride per face corner, without vertex unification.
+func WithNoUnify(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.NoUnify = enable
+	}
+}
+
+// WithNonInterleaved fills separate Positions, TexCoords and Normals arrays instead of Coord.
+func WithNonInterleaved(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.NonInterleaved = enable
+	}
+}
+
+// WithKeepRawData retains the original v/vt/vn arrays and per-corner indices.
+func WithKeepRawData(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.KeepRawData = enable
+	}
+}
+
+// WithKeepFaces retains the original polygon faces.
+func WithKeepFaces(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.KeepFaces = enable
+	}
+}
+
+// WithInterleaveColors adds vertex colors to the Coord stride.
+func WithInterleaveColors(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.InterleaveColors = enable
+	}
+}
+
+// WithKeepHomogeneous retains the v weight and the third vt coordinate.
+func WithKeepHomogeneous(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.KeepHomogeneous = enable
+	}
+}
+
+// WithFlipTextureV stores 1-v instead of v for texture coordinates.
+func WithFlipTextureV(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.FlipTextureV = enable
+	}
+}
+
+// WithScale multiplies positions by scale, zero meaning 1.
+func WithScale(scale float64) Option {
+	return func(opt *ObjParserOptions) {
+		opt.Scale = scale
+	}
+}
+
+// WithScaleXYZ additionally scales each axis, zero meaning 1.
+func WithScaleXYZ(scale [3]float64) Option {
+	return func(opt *ObjParserOptions) {
+		opt.ScaleXYZ = scale
+	}
+}
+
+// WithSmoothPerTriangle records per-triangle smoothing groups in Obj.Smoothing,
+// instead of splitting groups on smoothing changes.
+func WithSmoothPerTriangle(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.SmoothPerTriangle = enable
+	}
+}
+
+// WithMaterialPerTriangle records per-triangle materials in Obj.MaterialIndex,
+// instead of splitting groups on material changes.
+func WithMaterialPerTriangle(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.MaterialPerTriangle = enable
+	}
+}
+
+// WithKeepComments retains comment lines for round-trip export.
+func WithKeepComments(enable bool) Option {
+	return func(opt *ObjParserOptions) {
+		opt.KeepComments = enable
+	}
+}
+
+// WithSlog sends leveled, structured parser messages to a slog.Logger.
+func WithSlog(logger *slog.Logger) Option {
+	return func(opt *ObjParserOptions) {
+		opt.Slog = logger
+	}
+}
+
+// WithLimits bounds the resources consumed by the parser.
+func WithLimits(limits Limits) Option {
+	return func(opt *ObjParserOptions) {
+		opt.Limits = limits
+	}
+}
+
+// WithOnUnknown handles OBJ statements not recognized by the parser.
+func WithOnUnknown(handler func(lineNum int, keyword, rest string) error) Option {
+	return func(opt *ObjParserOptions) {
+		opt.OnUnknown = handler
+	}
+}
+
+// WithProgress sets the function periodically invoked during parsing.
+func WithProgress(progress func(bytesRead, totalBytes int64, phase string)) Option {
+	return func(opt *ObjParserOptions) {
+		opt.Progress = progress
+	}
+}
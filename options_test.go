@@ -0,0 +1,34 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestFunctionalOptions(t *testing.T) {
+
+	var logged int
+	options := NewObjParserOptions(WithLogger(func(string) { logged++ }), WithLogStats(true), WithIgnoreNormals(true))
+
+	if !options.LogStats || !options.IgnoreNormals || options.MtllibKeepSpaces {
+		t.Errorf("TestFunctionalOptions: unexpected options: %+v", options)
+	}
+
+	o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), options)
+	if err != nil {
+		t.Errorf("TestFunctionalOptions: NewObjFromBuf: %v", err)
+		return
+	}
+
+	if o.NormCoordFound {
+		t.Errorf("TestFunctionalOptions: normals should have been ignored")
+	}
+	if logged == 0 {
+		t.Errorf("TestFunctionalOptions: stats should have been logged")
+	}
+
+	legacy := &ObjParserOptions{LogStats: true}
+	legacy.Apply(WithMtllibKeepSpaces(true))
+	if !legacy.LogStats || !legacy.MtllibKeepSpaces {
+		t.Errorf("TestFunctionalOptions: Apply: unexpected options: %+v", legacy)
+	}
+}
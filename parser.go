@@ -57,3 +57,78 @@ func parseFloatVector3Space(text string) ([]float64, error) {
 func parseFloatVector3Comma(text string) ([]float64, error) {
 	return parseFloatVectorComma(text, 3)
 }
+
+// isSpace reports ASCII whitespace.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\v' || c == '\f'
+}
+
+// nextField scans the next whitespace-separated field starting at i,
+// returning it as a substring (no allocation) and the position after it.
+// An empty field means the end of text.
+func nextField(text string, i int) (string, int) {
+	for i < len(text) && isSpace(text[i]) {
+		i++
+	}
+	start := i
+	for i < len(text) && !isSpace(text[i]) {
+		i++
+	}
+	return text[start:i], i
+}
+
+// splitFields stores whitespace-separated fields into dst without allocation.
+// It returns the total number of fields, which may exceed len(dst).
+func splitFields(dst []string, text string) int {
+	var count int
+	for i := 0; ; {
+		var f string
+		f, i = nextField(text, i)
+		if f == "" {
+			return count
+		}
+		if count < len(dst) {
+			dst[count] = f
+		}
+		count++
+	}
+}
+
+// parseFloatsSpace parses whitespace-separated floats into dst without allocation.
+// It returns the total number of fields, which may exceed len(dst).
+func parseFloatsSpace(dst []float64, text string) (int, error) {
+	var count int
+	for i := 0; ; {
+		var f string
+		f, i = nextField(text, i)
+		if f == "" {
+			return count, nil
+		}
+		if count < len(dst) {
+			var err error
+			if dst[count], err = strconv.ParseFloat(f, 64); err != nil {
+				return count, fmt.Errorf("parseFloatsSpace: text=[%s] elem[%d]=[%s] failure: %v", text, count, f, err)
+			}
+		}
+		count++
+	}
+}
+
+// splitCorner splits a face corner "v", "v/vt", "v//vn" or "v/vt/vn"
+// into ind without allocation. Missing indices are left empty.
+func splitCorner(ind *[3]string, corner string) (int, bool) {
+	size := 0
+	start := 0
+	for i := 0; i <= len(corner); i++ {
+		if i < len(corner) && corner[i] != '/' {
+			continue
+		}
+		if size >= len(ind) {
+			return size + 1, false // too many slashes
+		}
+		ind[size] = corner[start:i]
+		size++
+		start = i + 1
+	}
+	return size, ind[0] != ""
+}
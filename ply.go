@@ -0,0 +1,162 @@
+package gwob
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+)
+
+// PLYOptions controls the PLY output of ToPLY.
+// The zero value writes ASCII PLY with every attribute found.
+type PLYOptions struct {
+	// Binary writes the binary_little_endian format instead of ascii.
+	Binary bool
+
+	// NoColors omits vertex colors, even when ColorFound is set.
+	NoColors bool
+}
+
+// ToPLY writes the Obj triangles as a PLY (Stanford polygon) file,
+// with one vertex per stride: positions, then normals, texture
+// coordinates (s,t) and colors (uchar red,green,blue) when found.
+// Groups and materials are not represented.
+func (o *Obj) ToPLY(w io.Writer, options *PLYOptions) error {
+	if o.Coord == nil && o.Positions != nil {
+		c := *o
+		c.Interleave()
+		return c.ToPLY(w, options)
+	}
+	if options == nil {
+		options = &PLYOptions{}
+	}
+	if len(o.Indices)%3 != 0 {
+		return fmt.Errorf("ToPLY: index count=%d must be a multiple of 3", len(o.Indices))
+	}
+
+	strides := o.NumberOfElements()
+	colors := o.ColorFound && !options.NoColors
+
+	bw := bufio.NewWriter(w)
+
+	format := "ascii"
+	if options.Binary {
+		format = "binary_little_endian"
+	}
+	fmt.Fprintf(bw, "ply\nformat %s 1.0\ncomment%s\n", format, exportHeader)
+	fmt.Fprintf(bw, "element vertex %d\n", strides)
+	bw.WriteString("property float x\nproperty float y\nproperty float z\n")
+	if o.NormCoordFound {
+		bw.WriteString("property float nx\nproperty float ny\nproperty float nz\n")
+	}
+	if o.TextCoordFound {
+		bw.WriteString("property float s\nproperty float t\n")
+	}
+	if colors {
+		bw.WriteString("property uchar red\nproperty uchar green\nproperty uchar blue\n")
+	}
+	fmt.Fprintf(bw, "element face %d\n", len(o.Indices)/3)
+	bw.WriteString("property list uchar int vertex_indices\nend_header\n")
+
+	pw := plyWriter{binary: options.Binary}
+
+	for s := 0; s < strides; s++ {
+		pw.buf = pw.buf[:0]
+		x, y, z := o.VertexCoordinates(s)
+		pw.floats(x, y, z)
+		if o.NormCoordFound {
+			n := s*o.StrideSize/4 + o.StrideOffsetNormal/4
+			pw.floats(o.Coord[n : n+3]...)
+		}
+		if o.TextCoordFound {
+			t := s*o.StrideSize/4 + o.StrideOffsetTexture/4
+			pw.floats(o.Coord[t : t+2]...)
+		}
+		if colors {
+			r, g, b := o.VertexColor(s)
+			pw.bytes(colorByte(r), colorByte(g), colorByte(b))
+		}
+		pw.end()
+		bw.Write(pw.buf)
+	}
+
+	for i := 0; i < len(o.Indices); i += 3 {
+		pw.buf = pw.buf[:0]
+		pw.bytes(3)
+		pw.ints(o.Indices[i : i+3]...)
+		pw.end()
+		bw.Write(pw.buf)
+	}
+
+	return bw.Flush()
+}
+
+// ToPLYFile saves the Obj to a PLY file.
+func (o *Obj) ToPLYFile(filename string, options *PLYOptions) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return o.ToPLY(f, options)
+}
+
+// plyWriter formats one PLY element, as ascii or little endian binary.
+type plyWriter struct {
+	binary bool
+	buf    []byte
+}
+
+func (pw *plyWriter) sep() {
+	if len(pw.buf) > 0 {
+		pw.buf = append(pw.buf, ' ')
+	}
+}
+
+func (pw *plyWriter) floats(values ...float32) {
+	for _, f := range values {
+		if pw.binary {
+			pw.buf = binary.LittleEndian.AppendUint32(pw.buf, math.Float32bits(f))
+			continue
+		}
+		pw.sep()
+		pw.buf = strconv.AppendFloat(pw.buf, float64(f), 'g', -1, 32)
+	}
+}
+
+func (pw *plyWriter) ints(values ...int) {
+	for _, i := range values {
+		if pw.binary {
+			pw.buf = binary.LittleEndian.AppendUint32(pw.buf, uint32(i))
+			continue
+		}
+		pw.sep()
+		pw.buf = strconv.AppendInt(pw.buf, int64(i), 10)
+	}
+}
+
+func (pw *plyWriter) bytes(values ...byte) {
+	for _, b := range values {
+		if pw.binary {
+			pw.buf = append(pw.buf, b)
+			continue
+		}
+		pw.sep()
+		pw.buf = strconv.AppendUint(pw.buf, uint64(b), 10)
+	}
+}
+
+// end terminates an ascii element line.
+func (pw *plyWriter) end() {
+	if !pw.binary {
+		pw.buf = append(pw.buf, '\n')
+	}
+}
+
+// colorByte converts a color component from [0,1] to [0,255].
+func colorByte(c float32) byte {
+	return byte(math.Round(float64(min(max(c, 0), 1)) * 255))
+}
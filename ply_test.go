@@ -0,0 +1,115 @@
+package gwob
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPLYASCII(t *testing.T) {
+	o, err := NewObjFromBuf("colorsObj", []byte(colorsObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestPLYASCII: NewObjFromBuf: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if errPly := o.ToPLY(&buf, nil); errPly != nil {
+		t.Fatalf("TestPLYASCII: ToPLY: %v", errPly)
+	}
+
+	header, body, found := strings.Cut(buf.String(), "end_header\n")
+	if !found {
+		t.Fatalf("TestPLYASCII: missing end_header")
+	}
+	for _, want := range []string{"format ascii 1.0\n", "element vertex 4\n", "element face 2\n", "property uchar red\n"} {
+		if !strings.Contains(header, want) {
+			t.Errorf("TestPLYASCII: header missing %q", want)
+		}
+	}
+	if strings.Contains(header, "property float nx") || strings.Contains(header, "property float s") {
+		t.Errorf("TestPLYASCII: unexpected normals or texture coordinates")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+	want := []string{
+		"0 0 0 255 255 255",
+		"1 0 0 255 0 0",
+		"1 1 0 0 255 0",
+		"0 1 0 0 0 255",
+		"3 0 1 2",
+		"3 2 3 0",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("TestPLYASCII: lines: want=%d got=%d: %q", len(want), len(lines), lines)
+	}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("TestPLYASCII: line %d: want=%q got=%q", i, w, lines[i])
+		}
+	}
+
+	buf.Reset()
+	if errPly := o.ToPLY(&buf, &PLYOptions{NoColors: true}); errPly != nil {
+		t.Fatalf("TestPLYASCII: ToPLY NoColors: %v", errPly)
+	}
+	if strings.Contains(buf.String(), "red") {
+		t.Errorf("TestPLYASCII: NoColors: unexpected colors")
+	}
+}
+
+func TestPLYBinary(t *testing.T) {
+	o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestPLYBinary: NewObjFromBuf: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if errPly := o.ToPLY(&buf, &PLYOptions{Binary: true}); errPly != nil {
+		t.Fatalf("TestPLYBinary: ToPLY: %v", errPly)
+	}
+
+	header, body, found := strings.Cut(buf.String(), "end_header\n")
+	if !found {
+		t.Fatalf("TestPLYBinary: missing end_header")
+	}
+	if !strings.Contains(header, "format binary_little_endian 1.0\n") {
+		t.Errorf("TestPLYBinary: bad format: %q", header)
+	}
+	for _, want := range []string{"property float nx\n", "property float s\n"} {
+		if !strings.Contains(header, want) {
+			t.Errorf("TestPLYBinary: header missing %q", want)
+		}
+	}
+
+	strides := o.NumberOfElements()
+	vertexSize := 4 * 8 // x y z nx ny nz s t
+	faceSize := 1 + 3*4
+	expectInt(t, "TestPLYBinary: body size", strides*vertexSize+len(o.Indices)/3*faceSize, len(body))
+
+	data := []byte(body)
+	for s := 0; s < strides; s++ {
+		x, y, z := o.VertexCoordinates(s)
+		got := make([]float32, 3)
+		for c := range got {
+			got[c] = math.Float32frombits(binary.LittleEndian.Uint32(data[s*vertexSize+4*c:]))
+		}
+		if !sliceEqualFloat([]float32{x, y, z}, got) {
+			t.Errorf("TestPLYBinary: vertex %d: want=%v got=%v", s, []float32{x, y, z}, got)
+		}
+	}
+	faces := data[strides*vertexSize:]
+	for f := 0; f < len(o.Indices)/3; f++ {
+		face := faces[f*faceSize:]
+		if face[0] != 3 {
+			t.Errorf("TestPLYBinary: face %d: bad count=%d", f, face[0])
+		}
+		for c := 0; c < 3; c++ {
+			i := int(binary.LittleEndian.Uint32(face[1+4*c:]))
+			if want := o.Indices[3*f+c]; i != want {
+				t.Errorf("TestPLYBinary: face %d corner %d: want=%d got=%d", f, c, want, i)
+			}
+		}
+	}
+}
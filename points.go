@@ -0,0 +1,30 @@
+package gwob
+
+import (
+	"strings"
+)
+
+// parsePointElement parses the vertices of a p statement into
+// unified indices on the current group.
+func parsePointElement(p *objParser, o *Obj, text string, options *ObjParserOptions) error {
+	corners := strings.Fields(text)
+	if len(corners) < 1 {
+		return p.errorf(ErrBadFace, "bad point=[%s] size=%d", text, len(corners))
+	}
+
+	// resolve all vertices before emitting any
+	keys := make([]vertexKey, len(corners))
+	for i, corner := range corners {
+		k, err := resolveCorner(p, corner, options)
+		if err != nil {
+			return p.errorf(ErrBadFace, "bad point=[%s] index_v%d=[%s]: %v", text, i, corner, err)
+		}
+		keys[i] = k
+	}
+
+	for _, k := range keys {
+		p.currGroup.Points = append(p.currGroup.Points, unifyVertex(p, o, k, options))
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+var pointsObj = `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+g cloud
+p 1 2
+p 3
+p -1 4
+v 0 1 0
+p 99
+`
+
+func TestPoints(t *testing.T) {
+
+	o, err := NewObjFromBuf("pointsObj", []byte(pointsObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestPoints: NewObjFromBuf: %v", err)
+	}
+
+	expectInt(t, "TestPoints: groups", 1, len(o.Groups))
+	expectInt(t, "TestPoints: errors", 1, o.Errors())
+	expectInt(t, "TestPoints: warnings", 0, o.Warnings())
+
+	want := []int{0, 1, 2, 2, 3}
+	if !sliceEqualInt(want, o.Groups[0].Points) {
+		t.Errorf("TestPoints: points: want=%v got=%v", want, o.Groups[0].Points)
+	}
+	expectInt(t, "TestPoints: elements", 4, o.NumberOfElements())
+
+	// round trip
+	var buf bytes.Buffer
+	if errWrite := o.ToWriter(&buf); errWrite != nil {
+		t.Fatalf("TestPoints: ToWriter: %v", errWrite)
+	}
+	o2, err := NewObjFromBuf("pointsObj2", buf.Bytes(), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestPoints: reparse: %v", err)
+	}
+	if !sliceEqualInt(want, o2.Groups[0].Points) {
+		t.Errorf("TestPoints: reparsed points: want=%v got=%v", want, o2.Groups[0].Points)
+	}
+}
@@ -0,0 +1,161 @@
+package gwob
+
+import (
+	"bytes"
+	"io"
+)
+
+// Obj64 is an Obj with double precision vertex data, for meshes such as
+// CAD or geospatial models which need more than float32 precision.
+// Coord has the same interleaved layout as Obj.Coord, whose stride fields
+// count bytes of 4-byte floats: divide them by 4 to index Coord.
+// The embedded Obj holds indices, groups and flags, but no Coord.
+type Obj64 struct {
+	*Obj
+	Coord []float64
+}
+
+// NewObj64FromBuf parses Obj64 from a buffer.
+// Gzip-compressed buffers are decompressed transparently.
+func NewObj64FromBuf(objName string, buf []byte, options *ObjParserOptions) (*Obj64, error) {
+	if isGzip(buf) {
+		return NewObj64FromReader(objName, bytes.NewReader(buf), options)
+	}
+	return readObj64(objName, bytes.NewBuffer(buf), int64(len(buf)), options)
+}
+
+// NewObj64FromReader parses Obj64 from a reader.
+// Gzip-compressed streams are decompressed transparently.
+func NewObj64FromReader(objName string, rd io.Reader, options *ObjParserOptions) (*Obj64, error) {
+	return newObj64FromReaderSize(objName, rd, -1, options)
+}
+
+// NewObj64FromFile parses Obj64 from a file.
+func NewObj64FromFile(filename string, options *ObjParserOptions) (*Obj64, error) {
+
+	input, size, errOpen := openFileSize(filename)
+	if errOpen != nil {
+		return nil, errOpen
+	}
+
+	defer input.Close()
+
+	return newObj64FromReaderSize(filename, input, size, options)
+}
+
+func newObj64FromReaderSize(objName string, rd io.Reader, size int64, options *ObjParserOptions) (*Obj64, error) {
+	reader, compressed, err := newBufReader(rd)
+	if err != nil {
+		return nil, err
+	}
+	if compressed {
+		size = -1 // decompressed size is unknown
+	}
+	return readObj64(objName, reader, size, options)
+}
+
+func readObj64(objName string, reader StringReader, size int64, options *ObjParserOptions) (*Obj64, error) {
+	p := &objParser{double: true, material: -1}
+	o, err := parseObj(p, objName, reader, size, options)
+	return &Obj64{Obj: o, Coord: p.coord64}, err
+}
+
+// Coord64 gets vertex data.
+func (o *Obj64) Coord64(i int) float64 {
+	return o.Coord[i]
+}
+
+// NumberOfElements gets the number of strides.
+func (o *Obj64) NumberOfElements() int {
+	return 4 * len(o.Coord) / o.StrideSize
+}
+
+// VertexCoordinates gets vertex coordinates for a stride index.
+func (o *Obj64) VertexCoordinates(stride int) (float64, float64, float64) {
+	offset := o.StrideOffsetPosition / 4
+	floatsPerStride := o.StrideSize / 4
+	f := offset + stride*floatsPerStride
+	return o.Coord[f], o.Coord[f+1], o.Coord[f+2]
+}
+
+// Float32 converts to an Obj with single precision vertex data.
+// Indices and groups are shared with o.
+func (o *Obj64) Float32() *Obj {
+	o32 := *o.Obj
+	o32.Coord = make([]float32, len(o.Coord))
+	for i, c := range o.Coord {
+		o32.Coord[i] = float32(c)
+	}
+	return &o32
+}
+
+// ToWriter writes OBJ to writer stream.
+func (o *Obj64) ToWriter(w io.Writer) error {
+	return o.Float32().ToWriter(w)
+}
+
+// ToWriterWithOptions writes OBJ to writer stream, formatted according to the write options.
+func (o *Obj64) ToWriterWithOptions(w io.Writer, options *WriteOptions) error {
+	return o.Float32().ToWriterWithOptions(w, options)
+}
+
+// ToFile saves OBJ to file.
+func (o *Obj64) ToFile(filename string) error {
+	return o.Float32().ToFile(filename)
+}
+
+// pushVert stores a v statement in the selected precision.
+func (p *objParser) pushVert(x, y, z float64) {
+	if p.double {
+		p.vertCoord64 = append(p.vertCoord64, x, y, z)
+		return
+	}
+	p.vertCoord = p.reserveFloat32s(p.vertCoord, 3)
+	p.vertCoord = append(p.vertCoord, float32(x), float32(y), float32(z))
+}
+
+// pushText stores a vt statement in the selected precision.
+func (p *objParser) pushText(u, v float64) {
+	if p.double {
+		p.textCoord64 = append(p.textCoord64, u, v)
+		return
+	}
+	if p.textCoord == nil {
+		p.textCoord = p.float32s(2 * p.hint.TexCoords)
+	}
+	p.textCoord = p.reserveFloat32s(p.textCoord, 2)
+	p.textCoord = append(p.textCoord, float32(u), float32(v))
+}
+
+// pushNorm stores a vn statement in the selected precision.
+func (p *objParser) pushNorm(x, y, z float64) {
+	if p.double {
+		p.normCoord64 = append(p.normCoord64, x, y, z)
+		return
+	}
+	if p.normCoord == nil {
+		p.normCoord = p.float32s(3 * p.hint.Normals)
+	}
+	p.normCoord = p.reserveFloat32s(p.normCoord, 3)
+	p.normCoord = append(p.normCoord, float32(x), float32(y), float32(z))
+}
+
+// scratchLen gets the lengths of the v, vt and vn buffers.
+func (p *objParser) scratchLen() (int, int, int) {
+	if p.double {
+		return len(p.vertCoord64), len(p.textCoord64), len(p.normCoord64)
+	}
+	return len(p.vertCoord), len(p.textCoord), len(p.normCoord)
+}
+
+// appendCorner appends the unified vertex data for a face corner.
+func appendCorner[T float32 | float64](coord, vert, text, norm []T, vOffset, tOffset, nOffset int, hasTexture, hasNormal bool) []T {
+	coord = append(coord, vert[vOffset], vert[vOffset+1], vert[vOffset+2]) // x y z
+	if hasTexture {
+		coord = append(coord, text[tOffset], text[tOffset+1]) // u v
+	}
+	if hasNormal {
+		coord = append(coord, norm[nOffset], norm[nOffset+1], norm[nOffset+2]) // x y z
+	}
+	return coord
+}
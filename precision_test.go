@@ -0,0 +1,42 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestObj64(t *testing.T) {
+
+	o, err := NewObj64FromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestObj64: NewObj64FromBuf: %v", err)
+	}
+	if !sliceEqualInt(cubeIndices, o.Indices) {
+		t.Errorf("TestObj64: indices: want=%v got=%v", cubeIndices, o.Indices)
+	}
+	if len(o.Obj.Coord) != 0 {
+		t.Errorf("TestObj64: embedded Obj should have no float32 coord")
+	}
+	if !sliceEqualFloat(cubeCoord, o.Float32().Coord) {
+		t.Errorf("TestObj64: coord: want=%v got=%v", cubeCoord, o.Coord)
+	}
+	expectInt(t, "TestObj64: elements", 24, o.NumberOfElements())
+
+	// beyond float32 precision
+	geo := `
+v 6378137.125 -0.000000001 1
+v 6378137.25 1 0
+v 6378137.5 0 0 2
+f 1 2 -1
+`
+	o, err = NewObj64FromBuf("geo", []byte(geo), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestObj64: geo: %v", err)
+	}
+	x, y, _ := o.VertexCoordinates(0)
+	if x != 6378137.125 || y != -0.000000001 {
+		t.Errorf("TestObj64: geo: precision lost: x=%v y=%v", x, y)
+	}
+	if x, _, _ := o.VertexCoordinates(2); x != 6378137.5/2 {
+		t.Errorf("TestObj64: geo: homogeneous w: x=%v", x)
+	}
+}
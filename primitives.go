@@ -0,0 +1,181 @@
+package gwob
+
+import "math"
+
+// The primitive constructors build meshes centered on the origin, Y up,
+// with counter-clockwise outward triangles, texture coordinates and
+// normals, in a single unnamed group. Segment counts below the minimum
+// are raised to it.
+
+// surface is a parametric surface mapping (u,v) in [0,1]² to a position
+// and its normal, with cross(dP/du, dP/dv) pointing along the normal.
+type surface func(u, v float64) (pos, normal [3]float64)
+
+// addSurface adds a grid of cols x rows quads sampled from the surface,
+// with texture coordinates (u,v). Triangles collapsed by the surface,
+// as at the poles of a sphere, are skipped.
+func addSurface(b *ObjBuilder, cols, rows int, f surface) {
+	vertices := make([]int, (cols+1)*(rows+1))
+	positions := make([][3]float32, len(vertices))
+	for j := 0; j <= rows; j++ {
+		for i := 0; i <= cols; i++ {
+			u, v := float64(i)/float64(cols), float64(j)/float64(rows)
+			p, n := f(u, v)
+			snap(&p)
+			snap(&n)
+			k := j*(cols+1) + i
+			positions[k] = float32s(p)
+			vertices[k] = addPrimitiveVertex(b, p, n, u, v)
+		}
+	}
+	for j := 0; j < rows; j++ {
+		for i := 0; i < cols; i++ {
+			a := j*(cols+1) + i
+			quad := [4]int{a, a + 1, a + cols + 2, a + cols + 1}
+			for _, tri := range [2][3]int{{quad[0], quad[1], quad[2]}, {quad[0], quad[2], quad[3]}} {
+				pa, pb, pc := positions[tri[0]], positions[tri[1]], positions[tri[2]]
+				if pa == pb || pb == pc || pc == pa {
+					continue
+				}
+				b.AddTriangle(vertices[tri[0]], vertices[tri[1]], vertices[tri[2]])
+			}
+		}
+	}
+}
+
+// addPrimitiveVertex adds a vertex with position p, normal n and texture
+// coordinates (u,v).
+func addPrimitiveVertex(b *ObjBuilder, p, n [3]float64, u, v float64) int {
+	pf, nf := float32s(p), float32s(n)
+	vertex, _ := b.AddVertex(
+		b.AddPosition(pf[0], pf[1], pf[2]),
+		b.AddUV(float32(u), float32(v)),
+		b.AddNormal(nf[0], nf[1], nf[2]),
+	)
+	return vertex
+}
+
+// snap zeroes the rounding residues of trigonometry, like sin(2π), so
+// that the ends of a closed surface meet exactly.
+func snap(v *[3]float64) {
+	for i, c := range v {
+		if math.Abs(c) < 1e-12 {
+			v[i] = 0
+		}
+	}
+}
+
+func float32s(v [3]float64) [3]float32 {
+	return [3]float32{float32(v[0]), float32(v[1]), float32(v[2])}
+}
+
+// NewCube builds a cube of the given side, with 4 vertices per face so
+// that each face gets its own normal and the whole texture.
+func NewCube(size float32) *Obj {
+	h := float64(size) / 2
+	faces := [6][3][3]float64{ // normal, u axis, v axis
+		{{1, 0, 0}, {0, 0, -1}, {0, 1, 0}},
+		{{-1, 0, 0}, {0, 0, 1}, {0, 1, 0}},
+		{{0, 1, 0}, {1, 0, 0}, {0, 0, -1}},
+		{{0, -1, 0}, {1, 0, 0}, {0, 0, 1}},
+		{{0, 0, 1}, {1, 0, 0}, {0, 1, 0}},
+		{{0, 0, -1}, {-1, 0, 0}, {0, 1, 0}},
+	}
+	b := NewObjBuilder(nil)
+	for _, face := range faces {
+		n, du, dv := face[0], face[1], face[2]
+		addSurface(b, 1, 1, func(u, v float64) (pos, normal [3]float64) {
+			for i := range pos {
+				pos[i] = h * (n[i] + (2*u-1)*du[i] + (2*v-1)*dv[i])
+			}
+			return pos, n
+		})
+	}
+	return b.Build()
+}
+
+// NewPlane builds a plane of width along X and depth along Z, facing up,
+// split into segmentsX x segmentsZ quads (at least 1x1). The texture v
+// coordinate grows towards -Z.
+func NewPlane(width, depth float32, segmentsX, segmentsZ int) *Obj {
+	w, d := float64(width), float64(depth)
+	b := NewObjBuilder(nil)
+	addSurface(b, max(segmentsX, 1), max(segmentsZ, 1), func(u, v float64) (pos, normal [3]float64) {
+		return [3]float64{(u - 0.5) * w, 0, (0.5 - v) * d}, [3]float64{0, 1, 0}
+	})
+	return b.Build()
+}
+
+// NewUVSphere builds a sphere of the given radius from segments
+// meridians (at least 3) and rings parallels (at least 2). The texture u
+// coordinate goes around Y, and v from the bottom pole to the top one.
+func NewUVSphere(radius float32, segments, rings int) *Obj {
+	r := float64(radius)
+	b := NewObjBuilder(nil)
+	addSurface(b, max(segments, 3), max(rings, 2), func(u, v float64) (pos, normal [3]float64) {
+		theta, phi := 2*math.Pi*u, math.Pi*v
+		ring := math.Sin(phi)
+		normal = [3]float64{ring * math.Cos(theta), -math.Cos(phi), -ring * math.Sin(theta)}
+		return [3]float64{r * normal[0], r * normal[1], r * normal[2]}, normal
+	})
+	return b.Build()
+}
+
+// NewCylinder builds a closed cylinder of the given radius and height
+// along Y, with segments sides (at least 3). The side texture wraps
+// around Y; each cap maps the texture onto its disk.
+func NewCylinder(radius, height float32, segments int) *Obj {
+	r, h := float64(radius), float64(height)/2
+	segments = max(segments, 3)
+	b := NewObjBuilder(nil)
+	addSurface(b, segments, 1, func(u, v float64) (pos, normal [3]float64) {
+		theta := 2 * math.Pi * u
+		normal = [3]float64{math.Cos(theta), 0, -math.Sin(theta)}
+		return [3]float64{r * normal[0], (2*v - 1) * h, r * normal[2]}, normal
+	})
+
+	for _, side := range [2]float64{1, -1} {
+		n := [3]float64{0, side, 0}
+		center := addPrimitiveVertex(b, [3]float64{0, side * h, 0}, n, 0.5, 0.5)
+		rim := make([]int, segments)
+		for i := range rim {
+			u := float64(i) / float64(segments)
+			theta := 2 * math.Pi * u // as on the side
+			c, s := math.Cos(theta), math.Sin(theta)
+			p := [3]float64{r * c, side * h, -r * s}
+			snap(&p)
+			rim[i] = addPrimitiveVertex(b, p, n, 0.5+0.5*c, 0.5+0.5*side*s)
+		}
+		for i := range rim {
+			next := rim[(i+1)%segments]
+			if side > 0 {
+				b.AddTriangle(center, rim[i], next)
+			} else {
+				b.AddTriangle(center, next, rim[i])
+			}
+		}
+	}
+	return b.Build()
+}
+
+// NewTorus builds a torus around Y, with the tube of minorRadius going
+// around a circle of majorRadius, from majorSegments tube sections and
+// minorSegments sides (at least 3 each). The texture u coordinate goes
+// around Y, and v around the tube.
+func NewTorus(majorRadius, minorRadius float32, majorSegments, minorSegments int) *Obj {
+	R, r := float64(majorRadius), float64(minorRadius)
+	b := NewObjBuilder(nil)
+	addSurface(b, max(majorSegments, 3), max(minorSegments, 3), func(u, v float64) (pos, normal [3]float64) {
+		theta, phi := 2*math.Pi*u, 2*math.Pi*v
+		radial := [3]float64{math.Cos(theta), 0, -math.Sin(theta)}
+		for i := range normal {
+			normal[i] = math.Cos(phi) * radial[i]
+		}
+		normal[1] = math.Sin(phi)
+		for i := range pos {
+			pos[i] = R*radial[i] + r*normal[i]
+		}
+		return pos, normal
+	})
+	return b.Build()
+}
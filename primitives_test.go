@@ -0,0 +1,74 @@
+package gwob
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPrimitives(t *testing.T) {
+	table := []struct {
+		name       string
+		o          *Obj
+		triangles  int
+		area       float64
+		volume     float64
+		tolerance  float64 // relative, for the faceting
+		watertight bool
+	}{
+		{"cube", NewCube(2), 12, 24, 8, 1e-6, true},
+		{"plane", NewPlane(2, 3, 4, 6), 48, 6, 0, 1e-6, false},
+		{"sphere", NewUVSphere(1, 64, 32), 2 * 64 * 31, 4 * math.Pi, 4 * math.Pi / 3, 0.01, true},
+		{"cylinder", NewCylinder(1, 2, 64), 4 * 64, 6 * math.Pi, 2 * math.Pi, 0.01, true},
+		{"torus", NewTorus(2, 0.5, 64, 32), 2 * 64 * 32, 4 * math.Pi * math.Pi, 2 * math.Pi * math.Pi * 0.25 * 2, 0.01, true},
+	}
+
+	for _, data := range table {
+		o := data.o
+		expectInt(t, "TestPrimitives: "+data.name+": triangles", data.triangles, len(o.Indices)/3)
+		if !o.TextCoordFound || !o.NormCoordFound {
+			t.Errorf("TestPrimitives: %s: missing texture coordinates or normals", data.name)
+		}
+
+		near := func(got, want float64) bool {
+			return math.Abs(got-want) <= data.tolerance*math.Max(1, math.Abs(want))
+		}
+		if a := o.SurfaceArea(); !near(a, data.area) {
+			t.Errorf("TestPrimitives: %s: area=%v want=%v", data.name, a, data.area)
+		}
+		if v := o.Volume(); !near(v, data.volume) {
+			t.Errorf("TestPrimitives: %s: volume=%v want=%v", data.name, v, data.volume)
+		}
+
+		if report := o.CheckManifold(); report.Watertight() != data.watertight || !report.Manifold() {
+			t.Errorf("TestPrimitives: %s: watertight=%v manifold=%v", data.name, report.Watertight(), report.Manifold())
+		}
+		if n := o.Clone().FixWinding(); n != 0 {
+			t.Errorf("TestPrimitives: %s: inconsistent winding: %d", data.name, n)
+		}
+
+		// vertex normals agree with the winding
+		for tri := 0; tri < len(o.Indices)/3; tri++ {
+			face := o.triangleNormal(tri)
+			for c := 0; c < 3; c++ {
+				n := o.Vertex(o.Indices[3*tri+c]).Normal
+				if face[0]*n[0]+face[1]*n[1]+face[2]*n[2] <= 0 {
+					t.Errorf("TestPrimitives: %s: triangle %d corner %d: normal=%v face=%v", data.name, tri, c, n, face)
+				}
+			}
+		}
+
+		for s := 0; s < o.NumberOfElements(); s++ {
+			v := o.Vertex(s)
+			if v.UV[0] < 0 || v.UV[0] > 1 || v.UV[1] < 0 || v.UV[1] > 1 {
+				t.Errorf("TestPrimitives: %s: stride %d: uv=%v", data.name, s, v.UV)
+			}
+		}
+	}
+}
+
+func TestPrimitivesMinimum(t *testing.T) {
+	expectInt(t, "TestPrimitivesMinimum: plane", 2, len(NewPlane(1, 1, 0, 0).Indices)/3)
+	expectInt(t, "TestPrimitivesMinimum: sphere", 2*3, len(NewUVSphere(1, 0, 0).Indices)/3)
+	expectInt(t, "TestPrimitivesMinimum: cylinder", 4*3, len(NewCylinder(1, 1, 1).Indices)/3)
+	expectInt(t, "TestPrimitivesMinimum: torus", 2*3*3, len(NewTorus(2, 1, 0, 0).Indices)/3)
+}
@@ -0,0 +1,36 @@
+package gwob
+
+// Parsing phases reported to ObjParserOptions.Progress.
+const (
+	ProgressRead = "read" // 1st pass: reading input and vertex data
+	ProgressScan = "scan" // 2nd pass: building faces and groups
+)
+
+// progressInterval is the minimum amount of bytes between progress reports.
+const progressInterval = 1 << 20
+
+func (p *objParser) startProgress() {
+	p.bytesDone = 0
+	p.nextProgress = progressInterval
+}
+
+func (p *objParser) advanceProgress(phase string, total int64, n int, options *ObjParserOptions) {
+	p.bytesDone += int64(n)
+	if options.Progress == nil || p.bytesDone < p.nextProgress {
+		return
+	}
+	options.Progress(p.bytesDone, total, phase)
+	p.nextProgress = p.bytesDone + progressInterval
+}
+
+// endProgress always reports the end of the phase.
+// The total becomes known at this point, even for streams of unknown size.
+func (p *objParser) endProgress(phase string, total int64, options *ObjParserOptions) {
+	if options.Progress == nil {
+		return
+	}
+	if total < 0 {
+		total = p.bytesDone
+	}
+	options.Progress(p.bytesDone, total, phase)
+}
@@ -0,0 +1,72 @@
+package gwob
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+
+	// build input larger than the progress interval
+	// forward reference forces the 2nd pass
+	buf := bytes.Buffer{}
+	buf.WriteString("f 1 2 3\n")
+	for buf.Len() < 3*progressInterval {
+		buf.WriteString("v 1.000000 2.000000 3.000000\n")
+	}
+	size := int64(buf.Len())
+
+	type report struct {
+		done, total int64
+		phase       string
+	}
+	var reports []report
+
+	options := NewObjParserOptions(WithProgress(func(bytesRead, totalBytes int64, phase string) {
+		reports = append(reports, report{bytesRead, totalBytes, phase})
+	}))
+
+	if _, err := NewObjFromBuf("progress", buf.Bytes(), options); err != nil {
+		t.Errorf("TestProgress: NewObjFromBuf: %v", err)
+		return
+	}
+
+	var reads, scans int
+	var last report
+	for _, r := range reports {
+		switch r.phase {
+		case ProgressRead:
+			reads++
+			if r.total != size {
+				t.Errorf("TestProgress: read total: want=%d got=%d", size, r.total)
+			}
+		case ProgressScan:
+			scans++
+		}
+		if r.phase == last.phase && r.done < last.done {
+			t.Errorf("TestProgress: progress going backwards: %v after %v", r, last)
+		}
+		last = r
+	}
+
+	if reads < 3 {
+		t.Errorf("TestProgress: too few read reports: %d", reads)
+	}
+	if scans < 1 {
+		t.Errorf("TestProgress: too few scan reports: %d", scans)
+	}
+	if last.phase != ProgressScan || last.done != last.total {
+		t.Errorf("TestProgress: bad final report: %v", last)
+	}
+
+	// unknown size reports total at the end
+	reports = nil
+	if _, err := NewObjFromReader("progress", strings.NewReader("v 0 0 0\n"), options); err != nil {
+		t.Errorf("TestProgress: NewObjFromReader: %v", err)
+		return
+	}
+	if len(reports) != 1 || reports[0].total != 8 {
+		t.Errorf("TestProgress: unknown size: %v", reports)
+	}
+}
@@ -0,0 +1,40 @@
+package gwob
+
+// QuadSplit selects how quads are split into triangles.
+type QuadSplit int
+
+const (
+	// QuadSplitFixed splits along the v0-v2 diagonal: (v0,v1,v2)+(v2,v3,v0).
+	QuadSplitFixed QuadSplit = iota
+	// QuadSplitShortest splits along the shortest diagonal.
+	QuadSplitShortest
+	// QuadSplitAlternate alternates between the two diagonals on successive quads.
+	QuadSplitAlternate
+)
+
+// WithQuadSplit sets the quad triangulation strategy.
+func WithQuadSplit(split QuadSplit) Option {
+	return func(opt *ObjParserOptions) {
+		opt.QuadSplit = split
+	}
+}
+
+// splitQuadOdd reports whether a quad should be split along the v1-v3 diagonal.
+func (p *objParser) splitQuadOdd(keys []vertexKey, options *ObjParserOptions) bool {
+	switch options.QuadSplit {
+	case QuadSplitShortest:
+		return p.distance2(keys[1].v, keys[3].v) < p.distance2(keys[0].v, keys[2].v)
+	case QuadSplitAlternate:
+		p.quads++
+		return p.quads%2 == 0
+	}
+	return false
+}
+
+// distance2 gets the squared distance between two v statements.
+func (p *objParser) distance2(a, b int) float64 {
+	ax, ay, az := p.position(a)
+	bx, by, bz := p.position(b)
+	dx, dy, dz := ax-bx, ay-by, az-bz
+	return dx*dx + dy*dy + dz*dz
+}
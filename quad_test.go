@@ -0,0 +1,50 @@
+package gwob
+
+import (
+	"testing"
+)
+
+var quadSplitObj = `
+v 0 0 0
+v 4 0 0
+v 1 1 0
+v 0 4 0
+f 1 2 3 4
+f 1 2 3 4
+`
+
+func TestQuadSplit(t *testing.T) {
+
+	table := []struct {
+		name    string
+		split   QuadSplit
+		indices []int
+	}{
+		{"fixed", QuadSplitFixed, []int{0, 1, 2, 2, 3, 0, 0, 1, 2, 2, 3, 0}},
+		{"shortest", QuadSplitShortest, []int{0, 1, 2, 2, 3, 0, 0, 1, 2, 2, 3, 0}},
+		{"alternate", QuadSplitAlternate, []int{0, 1, 2, 2, 3, 0, 0, 1, 3, 1, 2, 3}},
+	}
+
+	for _, data := range table {
+		options := NewObjParserOptions(WithQuadSplit(data.split))
+		o, err := NewObjFromBuf("quadSplitObj", []byte(quadSplitObj), options)
+		if err != nil {
+			t.Errorf("TestQuadSplit: %s: %v", data.name, err)
+			continue
+		}
+		if !sliceEqualInt(data.indices, o.Indices) {
+			t.Errorf("TestQuadSplit: %s: want=%v got=%v", data.name, data.indices, o.Indices)
+		}
+	}
+
+	// v1-v3 is now the shortest diagonal
+	long := "v 0 0 0\nv 1 0 0\nv 4 4 0\nv 0 1 0\nf 1 2 3 4\n"
+	o, err := NewObjFromBuf("long", []byte(long), NewObjParserOptions(WithQuadSplit(QuadSplitShortest)))
+	if err != nil {
+		t.Fatalf("TestQuadSplit: long: %v", err)
+	}
+	want := []int{0, 1, 2, 1, 3, 2} // unified in order v0 v1 v3 v2
+	if !sliceEqualInt(want, o.Indices) {
+		t.Errorf("TestQuadSplit: long: want=%v got=%v", want, o.Indices)
+	}
+}
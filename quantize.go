@@ -0,0 +1,178 @@
+package gwob
+
+import (
+	"math"
+	"unsafe"
+)
+
+// Vertex formats of QuantizedAttribute, named as in WebGPU.
+const (
+	FormatUnorm16x4 = "unorm16x4" // 4 uint16 mapping [0,65535] to [0,1]
+	FormatUnorm16x2 = "unorm16x2" // 2 uint16 mapping [0,65535] to [0,1]
+	FormatSnorm16x2 = "snorm16x2" // 2 int16 mapping [-32767,32767] to [-1,1]
+)
+
+// QuantizedAttribute describes one attribute within the stride of a
+// QuantizedVertexBuffer.
+type QuantizedAttribute struct {
+	Name   string // one of the Attribute* names
+	Offset int    // byte offset within the stride
+	Format string // one of the Format* names
+}
+
+// QuantizedVertexBuffer is a compact interleaved vertex buffer of 16-bit
+// integers, taking half or less of the memory of Coord:
+//
+//   - positions are unorm16x4, normalized to the bounding box, with a
+//     fourth component of 65535, read as w=1 so that PositionTransform
+//     applies its translation: the position is
+//     PositionOffset + PositionScale*q;
+//   - texture coordinates are unorm16x2, normalized to their range: the
+//     coordinate is TexCoordOffset + TexCoordScale*q;
+//   - normals are snorm16x2, octahedron encoded: see OctDecode.
+//
+// Colors are not included.
+type QuantizedVertexBuffer struct {
+	Data       []byte // native byte order
+	Stride     int    // bytes per vertex
+	Attributes []QuantizedAttribute
+
+	PositionOffset, PositionScale [3]float32
+	TexCoordOffset, TexCoordScale [2]float32
+}
+
+// Attribute finds an attribute by name.
+func (q *QuantizedVertexBuffer) Attribute(name string) (QuantizedAttribute, bool) {
+	for _, a := range q.Attributes {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return QuantizedAttribute{}, false
+}
+
+// PositionTransform gets the column-major 4x4 matrix turning quantized
+// positions, as read by the GPU in [0,1], into model positions. Multiply
+// the model matrix by it to dequantize in the vertex shader for free.
+func (q *QuantizedVertexBuffer) PositionTransform() [16]float32 {
+	s, t := q.PositionScale, q.PositionOffset
+	return [16]float32{
+		s[0], 0, 0, 0,
+		0, s[1], 0, 0,
+		0, 0, s[2], 0,
+		t[0], t[1], t[2], 1,
+	}
+}
+
+// QuantizedVertexBuffer builds the quantized form of the vertex data,
+// in stride order, to be drawn with the same Indices.
+func (o *Obj) QuantizedVertexBuffer() QuantizedVertexBuffer {
+	var q QuantizedVertexBuffer
+	strides := o.NumberOfElements()
+
+	q.Attributes = append(q.Attributes, QuantizedAttribute{Name: AttributePosition, Offset: 0, Format: FormatUnorm16x4})
+	q.Stride = 8
+	if o.TextCoordFound {
+		q.Attributes = append(q.Attributes, QuantizedAttribute{Name: AttributeTexCoord, Offset: q.Stride, Format: FormatUnorm16x2})
+		q.Stride += 4
+	}
+	if o.NormCoordFound {
+		q.Attributes = append(q.Attributes, QuantizedAttribute{Name: AttributeNormal, Offset: q.Stride, Format: FormatSnorm16x2})
+		q.Stride += 4
+	}
+
+	min, max := o.Bounds()
+	for i := range min {
+		q.PositionOffset[i] = min[i]
+		q.PositionScale[i] = max[i] - min[i]
+	}
+	if o.TextCoordFound && strides > 0 {
+		lo := [2]float32{float32(math.Inf(1)), float32(math.Inf(1))}
+		hi := [2]float32{float32(math.Inf(-1)), float32(math.Inf(-1))}
+		for s := 0; s < strides; s++ {
+			uv := o.vertexTexCoord(s)
+			for i := range lo {
+				lo[i] = float32(math.Min(float64(lo[i]), float64(uv[i])))
+				hi[i] = float32(math.Max(float64(hi[i]), float64(uv[i])))
+			}
+		}
+		for i := range lo {
+			q.TexCoordOffset[i] = lo[i]
+			q.TexCoordScale[i] = hi[i] - lo[i]
+		}
+	}
+
+	words := make([]uint16, 0, strides*q.Stride/2)
+	for s := 0; s < strides; s++ {
+		x, y, z := o.VertexCoordinates(s)
+		for i, c := range [3]float32{x, y, z} {
+			words = append(words, unorm16(c, q.PositionOffset[i], q.PositionScale[i]))
+		}
+		words = append(words, math.MaxUint16) // w=1
+		if o.TextCoordFound {
+			uv := o.vertexTexCoord(s)
+			for i := range q.TexCoordOffset {
+				words = append(words, unorm16(uv[i], q.TexCoordOffset[i], q.TexCoordScale[i]))
+			}
+		}
+		if o.NormCoordFound {
+			n := o.vertexNormal(s)
+			e := OctEncode([3]float32{n[0], n[1], n[2]})
+			words = append(words, uint16(e[0]), uint16(e[1]))
+		}
+	}
+	if len(words) > 0 {
+		q.Data = unsafe.Slice((*byte)(unsafe.Pointer(&words[0])), 2*len(words))
+	}
+	return q
+}
+
+// unorm16 maps v from [offset, offset+scale] to [0,65535].
+func unorm16(v, offset, scale float32) uint16 {
+	if scale == 0 {
+		return 0
+	}
+	f := float64((v - offset) / scale)
+	return uint16(math.Round(math.Max(0, math.Min(1, f)) * math.MaxUint16))
+}
+
+// snorm16 maps v from [-1,1] to [-32767,32767].
+func snorm16(v float64) int16 {
+	return int16(math.Round(math.Max(-1, math.Min(1, v)) * math.MaxInt16))
+}
+
+// OctEncode maps a unit vector to a point of the octahedron unfolded on
+// the [-1,1] square, as two snorm16 values. Zero vectors encode as (0,0),
+// decoding to +z.
+func OctEncode(n [3]float32) [2]int16 {
+	x, y, z := float64(n[0]), float64(n[1]), float64(n[2])
+	l := math.Abs(x) + math.Abs(y) + math.Abs(z)
+	if l == 0 {
+		return [2]int16{}
+	}
+	x, y, z = x/l, y/l, z/l
+	if z < 0 {
+		// fold the lower hemisphere over the diagonals
+		x, y = (1-math.Abs(y))*sign(x), (1-math.Abs(x))*sign(y)
+	}
+	return [2]int16{snorm16(x), snorm16(y)}
+}
+
+// OctDecode gets the unit vector for a point encoded by OctEncode.
+func OctDecode(e [2]int16) [3]float32 {
+	x := math.Max(-1, float64(e[0])/math.MaxInt16)
+	y := math.Max(-1, float64(e[1])/math.MaxInt16)
+	z := 1 - math.Abs(x) - math.Abs(y)
+	if z < 0 {
+		x, y = (1-math.Abs(y))*sign(x), (1-math.Abs(x))*sign(y)
+	}
+	return unit([3]float64{x, y, z})
+}
+
+// sign gets -1 for negative v, else 1.
+func sign(v float64) float64 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,97 @@
+package gwob
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestQuantizedVertexBuffer(t *testing.T) {
+	str := `
+v -1 0 2
+v 3 1 2
+v 0 1 4
+vt 0.5 -1
+vt 1.5 1
+vt 1 0
+vn 0 0 1
+vn 0.6 -0.8 0
+vn 0 0.6 -0.8
+f 1/1/1 2/2/2 3/3/3
+`
+	o, err := NewObjFromBuf("quant", []byte(str), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestQuantizedVertexBuffer: NewObjFromBuf: %v", err)
+	}
+
+	q := o.QuantizedVertexBuffer()
+	expectInt(t, "TestQuantizedVertexBuffer: stride", 16, q.Stride)
+	expectInt(t, "TestQuantizedVertexBuffer: size", 3*16, len(q.Data))
+	expectInt(t, "TestQuantizedVertexBuffer: attributes", 3, len(q.Attributes))
+	if a, ok := q.Attribute(AttributeNormal); !ok || a.Offset != 12 || a.Format != FormatSnorm16x2 {
+		t.Errorf("TestQuantizedVertexBuffer: normal attribute=%+v", a)
+	}
+	if q.PositionOffset != [3]float32{-1, 0, 2} || q.PositionScale != [3]float32{4, 1, 2} {
+		t.Errorf("TestQuantizedVertexBuffer: position offset=%v scale=%v", q.PositionOffset, q.PositionScale)
+	}
+	if q.TexCoordOffset != [2]float32{0.5, -1} || q.TexCoordScale != [2]float32{1, 2} {
+		t.Errorf("TestQuantizedVertexBuffer: uv offset=%v scale=%v", q.TexCoordOffset, q.TexCoordScale)
+	}
+
+	near := func(a, b, eps float32) bool {
+		return math.Abs(float64(a-b)) <= float64(eps)
+	}
+	word := func(s, offset int) uint16 {
+		return binary.NativeEndian.Uint16(q.Data[s*q.Stride+offset:])
+	}
+	m := q.PositionTransform()
+	for s := 0; s < o.NumberOfElements(); s++ {
+		v := o.Vertex(s)
+		// full matrix product, as the vertex shader does with w read as 1
+		var gpu [4]float32
+		for c := range gpu {
+			gpu[c] = float32(word(s, 2*c)) / math.MaxUint16
+		}
+		for i := 0; i < 3; i++ {
+			var p float32
+			for c := range gpu {
+				p += m[4*c+i] * gpu[c]
+			}
+			if !near(p, v.Position[i], q.PositionScale[i]/math.MaxUint16) {
+				t.Errorf("TestQuantizedVertexBuffer: stride %d: position %d=%v want=%v", s, i, p, v.Position[i])
+			}
+		}
+		expectInt(t, "TestQuantizedVertexBuffer: w", math.MaxUint16, int(word(s, 6)))
+		for i := 0; i < 2; i++ {
+			uv := q.TexCoordOffset[i] + q.TexCoordScale[i]*float32(word(s, 8+2*i))/math.MaxUint16
+			if !near(uv, v.UV[i], q.TexCoordScale[i]/math.MaxUint16) {
+				t.Errorf("TestQuantizedVertexBuffer: stride %d: uv %d=%v want=%v", s, i, uv, v.UV[i])
+			}
+		}
+		n := OctDecode([2]int16{int16(word(s, 12)), int16(word(s, 14))})
+		for i := range n {
+			if !near(n[i], v.Normal[i], 1e-3) {
+				t.Errorf("TestQuantizedVertexBuffer: stride %d: normal=%v want=%v", s, n, v.Normal)
+				break
+			}
+		}
+	}
+}
+
+func TestOctEncode(t *testing.T) {
+	for _, n := range [][3]float32{
+		{0, 0, 1}, {0, 0, -1}, {1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0},
+		{0.6, 0, -0.8}, {-0.48, 0.6, -0.64}, {0.36, -0.48, 0.8},
+	} {
+		got := OctDecode(OctEncode(n))
+		for i := range n {
+			if math.Abs(float64(got[i]-n[i])) > 1e-4 {
+				t.Errorf("TestOctEncode: %v: got=%v", n, got)
+				break
+			}
+		}
+	}
+	if e := OctEncode([3]float32{}); e != [2]int16{} {
+		t.Errorf("TestOctEncode: zero=%v", e)
+	}
+}
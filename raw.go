@@ -0,0 +1,24 @@
+package gwob
+
+// pushCorner records the original indices for a face corner.
+func pushCorner(o *Obj, key vertexKey, options *ObjParserOptions) {
+	if !options.KeepRawData {
+		return
+	}
+	n := key.n
+	if options.IgnoreNormals {
+		n = -1
+	}
+	o.Corners = append(o.Corners, FaceIndex{V: key.v, T: key.t, N: n})
+}
+
+// keepRawData hands the v/vt/vn arrays over to the Obj.
+// The parser must not reuse them afterwards.
+func (p *objParser) keepRawData(o *Obj) {
+	o.RawPositions = p.vertCoord
+	o.RawTexCoords = p.textCoord
+	o.RawNormals = p.normCoord
+	p.vertCoord = nil
+	p.textCoord = nil
+	p.normCoord = nil
+}
@@ -0,0 +1,46 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestKeepRawData(t *testing.T) {
+
+	options := NewObjParserOptions(WithKeepRawData(true))
+
+	parser := NewParser(options)
+	o, err := parser.ParseBuf("cubeObj", []byte(cubeObj))
+	if err != nil {
+		t.Fatalf("TestKeepRawData: ParseBuf: %v", err)
+	}
+	// raw data must not be reused by the next parse
+	if _, err := parser.ParseBuf("forwardMixedObj", []byte(forwardMixedObj)); err != nil {
+		t.Fatalf("TestKeepRawData: ParseBuf: %v", err)
+	}
+
+	expectInt(t, "TestKeepRawData: raw positions", 3*8, len(o.RawPositions))
+	expectInt(t, "TestKeepRawData: corners", len(o.Indices), len(o.Corners))
+
+	floatsPerStride := o.StrideSize / 4
+	for i, c := range o.Corners {
+		stride := o.Indices[i] * floatsPerStride
+		v := o.Coord[stride : stride+3]
+		if !sliceEqualFloat(o.RawPositions[3*c.V:3*c.V+3], v) {
+			t.Errorf("TestKeepRawData: corner %d: position: raw=%v unified=%v", i, o.RawPositions[3*c.V:3*c.V+3], v)
+		}
+		if c.T < 0 {
+			t.Errorf("TestKeepRawData: corner %d: missing texture index", i)
+			continue
+		}
+		tex := o.Coord[stride+3 : stride+5]
+		if !sliceEqualFloat(o.RawTexCoords[2*c.T:2*c.T+2], tex) {
+			t.Errorf("TestKeepRawData: corner %d: texture: raw=%v unified=%v", i, o.RawTexCoords[2*c.T:2*c.T+2], tex)
+		}
+	}
+
+	o, err = NewObjFromBuf("cubeObj", []byte(cubeObj+"f 1 2 99\n"), options)
+	if err != nil {
+		t.Fatalf("TestKeepRawData: bad face: %v", err)
+	}
+	expectInt(t, "TestKeepRawData: corners after bad face", len(o.Indices), len(o.Corners))
+}
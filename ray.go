@@ -0,0 +1,110 @@
+package gwob
+
+import "math"
+
+// RayHit describes where a ray meets a triangle.
+type RayHit struct {
+	Triangle    int        // hit triangle: its indices start at Indices[3*Triangle]
+	Distance    float32    // from the ray origin to Point
+	Point       [3]float32 // hit position
+	Barycentric [3]float32 // weights of the triangle corners at Point
+	UV          [2]float32 // interpolated texture coordinates, if TextCoordFound
+	Normal      [3]float32 // interpolated vertex normal if NormCoordFound, else the face normal
+}
+
+// RayIntersect finds the nearest triangle hit by the ray from origin
+// along dir, from either side. It tests every triangle: see the bvh
+// package for large meshes.
+func (o *Obj) RayIntersect(origin, dir [3]float32) (RayHit, bool) {
+	var best RayHit
+	found := false
+	for t := 0; t < len(o.Indices)/3; t++ {
+		hit, ok := o.IntersectTriangle(t, origin, dir)
+		if ok && (!found || hit.Distance < best.Distance) {
+			best, found = hit, true
+		}
+	}
+	return best, found
+}
+
+// IntersectTriangle tests the ray from origin along dir against triangle
+// t, from either side, with the Möller-Trumbore algorithm.
+func (o *Obj) IntersectTriangle(t int, origin, dir [3]float32) (RayHit, bool) {
+	d := [3]float64{float64(dir[0]), float64(dir[1]), float64(dir[2])}
+	l := math.Sqrt(d[0]*d[0] + d[1]*d[1] + d[2]*d[2])
+	if l == 0 {
+		return RayHit{}, false
+	}
+	for i := range d {
+		d[i] /= l
+	}
+
+	var p [3][3]float64
+	for c := range p {
+		x, y, z := o.VertexCoordinates(o.Indices[3*t+c])
+		p[c] = [3]float64{float64(x), float64(y), float64(z)}
+	}
+	e1 := sub64(p[1], p[0])
+	e2 := sub64(p[2], p[0])
+	h := cross64(d, e2)
+	det := dot64(e1, h)
+	if det == 0 {
+		return RayHit{}, false // parallel or degenerate
+	}
+	s := sub64([3]float64{float64(origin[0]), float64(origin[1]), float64(origin[2])}, p[0])
+	u := dot64(s, h) / det
+	if u < 0 || u > 1 {
+		return RayHit{}, false
+	}
+	q := cross64(s, e1)
+	v := dot64(d, q) / det
+	if v < 0 || u+v > 1 {
+		return RayHit{}, false
+	}
+	dist := dot64(e2, q) / det
+	if dist < 0 {
+		return RayHit{}, false
+	}
+
+	hit := RayHit{
+		Triangle:    t,
+		Distance:    float32(dist),
+		Barycentric: [3]float32{float32(1 - u - v), float32(u), float32(v)},
+	}
+	w := [3]float64{1 - u - v, u, v}
+	for i := range hit.Point {
+		hit.Point[i] = float32(w[0]*p[0][i] + w[1]*p[1][i] + w[2]*p[2][i])
+	}
+	if o.TextCoordFound {
+		for c := 0; c < 3; c++ {
+			uv := o.vertexTexCoord(o.Indices[3*t+c])
+			hit.UV[0] += float32(w[c]) * uv[0]
+			hit.UV[1] += float32(w[c]) * uv[1]
+		}
+	}
+	if o.NormCoordFound {
+		var n [3]float64
+		for c := 0; c < 3; c++ {
+			vn := o.vertexNormal(o.Indices[3*t+c])
+			for i := range n {
+				n[i] += w[c] * float64(vn[i])
+			}
+		}
+		hit.Normal = unit(n)
+	} else {
+		hit.Normal = unit(cross64(e1, e2))
+	}
+	return hit, true
+}
+
+func sub64(a, b [3]float64) [3]float64 {
+	return [3]float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func dot64(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func cross64(a, b [3]float64) [3]float64 {
+	return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
@@ -0,0 +1,90 @@
+package gwob
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRayIntersect(t *testing.T) {
+	o, err := NewObjFromBuf("cube", []byte(cubeObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestRayIntersect: NewObjFromBuf: %v", err)
+	}
+
+	hit, ok := o.RayIntersect([3]float32{0.25, 5, -0.5}, [3]float32{0, -2, 0})
+	if !ok {
+		t.Fatalf("TestRayIntersect: missed top")
+	}
+	if hit.Distance != 4 {
+		t.Errorf("TestRayIntersect: distance=%v", hit.Distance)
+	}
+	if hit.Point != [3]float32{0.25, 1, -0.5} {
+		t.Errorf("TestRayIntersect: point=%v", hit.Point)
+	}
+	if hit.Normal != [3]float32{0, 1, 0} {
+		t.Errorf("TestRayIntersect: normal=%v", hit.Normal)
+	}
+	var sum float32
+	var p [3]float32
+	for c, w := range hit.Barycentric {
+		sum += w
+		x, y, z := o.VertexCoordinates(o.Indices[3*hit.Triangle+c])
+		p[0] += w * x
+		p[1] += w * y
+		p[2] += w * z
+	}
+	if math.Abs(float64(sum-1)) > 1e-6 {
+		t.Errorf("TestRayIntersect: barycentric sum=%v", sum)
+	}
+	for i := range p {
+		if math.Abs(float64(p[i]-hit.Point[i])) > 1e-6 {
+			t.Errorf("TestRayIntersect: barycentric point=%v want=%v", p, hit.Point)
+			break
+		}
+	}
+
+	// from inside, the back side is hit too
+	hit, ok = o.RayIntersect([3]float32{0, 0, 0}, [3]float32{1, 0, 0})
+	if !ok || hit.Distance != 1 || hit.Normal != [3]float32{1, 0, 0} {
+		t.Errorf("TestRayIntersect: inside: ok=%v hit=%+v", ok, hit)
+	}
+
+	if _, ok := o.RayIntersect([3]float32{0, 5, 0}, [3]float32{0, 1, 0}); ok {
+		t.Errorf("TestRayIntersect: hit behind origin")
+	}
+	if _, ok := o.RayIntersect([3]float32{3, 5, 0}, [3]float32{0, -1, 0}); ok {
+		t.Errorf("TestRayIntersect: hit beside")
+	}
+	if _, ok := o.RayIntersect([3]float32{0, 5, 0}, [3]float32{}); ok {
+		t.Errorf("TestRayIntersect: hit without direction")
+	}
+}
+
+func TestRayIntersectUV(t *testing.T) {
+	str := `
+v 0 0 0
+v 2 0 0
+v 0 2 0
+vt 0 0
+vt 1 0
+vt 0 1
+f 1/1 2/2 3/3
+`
+	o, err := NewObjFromBuf("uv", []byte(str), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestRayIntersectUV: NewObjFromBuf: %v", err)
+	}
+	hit, ok := o.RayIntersect([3]float32{0.5, 1, -1}, [3]float32{0, 0, 1})
+	if !ok {
+		t.Fatalf("TestRayIntersectUV: missed")
+	}
+	if hit.UV != [2]float32{0.25, 0.5} {
+		t.Errorf("TestRayIntersectUV: uv=%v", hit.UV)
+	}
+	if hit.Normal != [3]float32{0, 0, 1} {
+		t.Errorf("TestRayIntersectUV: face normal=%v", hit.Normal)
+	}
+	if hit.Barycentric != [3]float32{0.25, 0.25, 0.5} {
+		t.Errorf("TestRayIntersectUV: barycentric=%v", hit.Barycentric)
+	}
+}
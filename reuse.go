@@ -0,0 +1,94 @@
+package gwob
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// Parser parses multiple OBJ inputs reusing its internal buffers,
+// reducing allocations for servers parsing many files.
+// A Parser must not be used concurrently.
+//
+// Example:
+//
+//	parser := gwob.NewParser(options)
+//	for _, name := range files {
+//	    o, err := parser.ParseFile(name)
+//	    // snip
+//	}
+type Parser struct {
+	options *ObjParserOptions
+	state   objParser
+	br      *bufio.Reader
+}
+
+// NewParser creates a reusable parser.
+func NewParser(options *ObjParserOptions) *Parser {
+	return &Parser{
+		options: options,
+	}
+}
+
+// Reset clears the parser state, keeping allocated buffers for the next parse.
+// Parse methods call Reset automatically.
+func (ps *Parser) Reset() {
+	p := &ps.state
+	*p = objParser{
+		vertCoord:  p.vertCoord[:0],
+		textCoord:  p.textCoord[:0],
+		normCoord:  p.normCoord[:0],
+		lineBuf:    p.lineBuf[:0],
+		indexTable: p.indexTable,
+		material:   -1,
+	}
+	clear(p.indexTable)
+}
+
+// Parse parses Obj from a reader.
+// Gzip-compressed streams are decompressed transparently.
+func (ps *Parser) Parse(objName string, rd io.Reader) (*Obj, error) {
+	return ps.parseSize(objName, rd, -1)
+}
+
+// ParseBuf parses Obj from a buffer.
+func (ps *Parser) ParseBuf(objName string, buf []byte) (*Obj, error) {
+	return ps.parseSize(objName, bytes.NewReader(buf), int64(len(buf)))
+}
+
+// ParseFile parses Obj from a file.
+func (ps *Parser) ParseFile(filename string) (*Obj, error) {
+	input, size, errOpen := openFileSize(filename)
+	if errOpen != nil {
+		return nil, errOpen
+	}
+
+	defer input.Close()
+
+	return ps.parseSize(filename, input, size)
+}
+
+func (ps *Parser) parseSize(objName string, rd io.Reader, size int64) (*Obj, error) {
+	ps.Reset()
+
+	if ps.br == nil {
+		ps.br = bufio.NewReader(rd)
+	} else {
+		ps.br.Reset(rd)
+	}
+
+	reader, compressed, err := gunzipBufReader(ps.br)
+	if err != nil {
+		return nil, err
+	}
+	if compressed {
+		size = -1
+	}
+
+	o, errParse := parseObj(&ps.state, objName, reader, size, ps.options)
+
+	ps.state.currGroup = nil // do not retain output
+	ps.state.diagnostics = nil
+
+	return o, errParse
+}
@@ -0,0 +1,65 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func BenchmarkParserReuse(b *testing.B) {
+	buf := []byte(cubeObj)
+	parser := NewParser(&ObjParserOptions{})
+	for i := 0; i < b.N; i++ {
+		parser.ParseBuf("cubeObj", buf)
+	}
+}
+
+func TestParserReuse(t *testing.T) {
+
+	parser := NewParser(&ObjParserOptions{})
+
+	for i := 0; i < 3; i++ {
+		o, err := parser.ParseBuf("cubeObj", []byte(cubeObj))
+		if err != nil {
+			t.Errorf("TestParserReuse: cube: %v", err)
+			return
+		}
+		if !sliceEqualInt(cubeIndices, o.Indices) {
+			t.Errorf("TestParserReuse: cube: indices: want=%v got=%v", cubeIndices, o.Indices)
+		}
+		if !sliceEqualFloat(cubeCoord, o.Coord) {
+			t.Errorf("TestParserReuse: cube: coord: want=%v got=%v", cubeCoord, o.Coord)
+		}
+
+		o, err = parser.ParseBuf("forwardMixedObj", []byte(forwardMixedObj))
+		if err != nil {
+			t.Errorf("TestParserReuse: forward: %v", err)
+			return
+		}
+		if !sliceEqualInt(forwardMixedIndices, o.Indices) {
+			t.Errorf("TestParserReuse: forward: indices: want=%v got=%v", forwardMixedIndices, o.Indices)
+		}
+		if !sliceEqualFloat(relativeCoord, o.Coord) {
+			t.Errorf("TestParserReuse: forward: coord: want=%v got=%v", relativeCoord, o.Coord)
+		}
+	}
+}
+
+func TestParserReuseMaterialPerTriangle(t *testing.T) {
+	str := "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3\nusemtl a\nf 1 2 3\n"
+	options := NewObjParserOptions(WithMaterialPerTriangle(true))
+
+	want, err := NewObjFromBuf("material", []byte(str), options)
+	if err != nil {
+		t.Fatalf("TestParserReuseMaterialPerTriangle: NewObjFromBuf: %v", err)
+	}
+
+	parser := NewParser(options)
+	for i := 0; i < 2; i++ {
+		o, err := parser.ParseBuf("material", []byte(str))
+		if err != nil {
+			t.Fatalf("TestParserReuseMaterialPerTriangle: ParseBuf: %v", err)
+		}
+		if !sliceEqualInt(want.MaterialIndex, o.MaterialIndex) {
+			t.Errorf("TestParserReuseMaterialPerTriangle: material index: want=%v got=%v", want.MaterialIndex, o.MaterialIndex)
+		}
+	}
+}
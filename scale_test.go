@@ -0,0 +1,35 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestScale(t *testing.T) {
+	str := `
+v 100 200 300
+v 0 0 0
+v 1 1 1 2
+vn 1 1 0
+f 1//1 2//1 3//1
+`
+	table := []struct {
+		name    string
+		options *ObjParserOptions
+		coord   []float32
+	}{
+		{"none", NewObjParserOptions(), []float32{100, 200, 300, 1, 1, 0, 0, 0, 0, 1, 1, 0, 0.5, 0.5, 0.5, 1, 1, 0}},
+		{"uniform", NewObjParserOptions(WithScale(0.5)), []float32{50, 100, 150, 1, 1, 0, 0, 0, 0, 1, 1, 0, 0.25, 0.25, 0.25, 1, 1, 0}},
+		{"axes", NewObjParserOptions(WithScaleXYZ([3]float64{2, 2, 0})), []float32{200, 400, 300, 0.70710677, 0.70710677, 0, 0, 0, 0, 0.70710677, 0.70710677, 0, 1, 1, 0.5, 0.70710677, 0.70710677, 0}},
+		{"both", NewObjParserOptions(WithScale(2), WithScaleXYZ([3]float64{0, 0, -1})), []float32{200, 400, -600, 0.70710677, 0.70710677, 0, 0, 0, 0, 0.70710677, 0.70710677, 0, 1, 1, -1, 0.70710677, 0.70710677, 0}},
+	}
+
+	for _, data := range table {
+		o, err := NewObjFromBuf("scale", []byte(str), data.options)
+		if err != nil {
+			t.Fatalf("TestScale: %s: NewObjFromBuf: %v", data.name, err)
+		}
+		if !sliceEqualFloat(data.coord, o.Coord) {
+			t.Errorf("TestScale: %s: want=%v got=%v", data.name, data.coord, o.Coord)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package gwob
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FaceIndex holds zero-based absolute indices for a face corner.
+// Missing texture or normal indices are -1.
+type FaceIndex struct {
+	V, T, N int
+}
+
+// ObjCallbacks receives events from ScanObj.
+// Nil callbacks are skipped. A callback returning an error aborts the scan.
+type ObjCallbacks struct {
+	Vertex    func(x, y, z, w float64) error // w defaults to 1
+	Color     func(r, g, b float64) error    // non-standard "v x y z r g b", after Vertex
+	TexCoord  func(u, v, w float64) error    // w defaults to 0
+	Normal    func(x, y, z float64) error
+	FaceIndex func(corners []FaceIndex) error // corners slice is reused between calls
+	Line      func(corners []FaceIndex) error // l statement, corners slice is reused
+	Point     func(corners []FaceIndex) error // p statement, corners slice is reused
+	Param     func(u, v, w float64) error     // vp statement, w defaults to 1
+	Group     func(name string) error         // from both g and o statements
+	UseMtl    func(name string) error
+	MtlLib    func(names []string) error
+	Smooth    func(group int) error
+
+	// OnError receives parse errors. Returning nil skips the bad line,
+	// otherwise the scan is aborted. If OnError is nil, any parse error aborts.
+	OnError func(err *ParseError) error
+
+	// Limits bounds the scan. MaxLineLength, MaxVertices (v statements)
+	// and MaxFaces apply: the scanner holds no geometry.
+	Limits Limits
+}
+
+// ScanObj parses OBJ from a reader, emitting events without building an Obj,
+// so geometry can be streamed into other data structures with constant memory.
+// Relative indices are resolved into absolute zero-based indices.
+// Forward references (indices to vertices not yet seen) are passed on as is.
+// Lines are read like the parser does: byte order mark, line continuation
+// and whitespace handling are the same. Statements without a callback,
+// such as free-form geometry, are skipped. With nil callbacks, the input is only checked.
+func ScanObj(rd io.Reader, callbacks *ObjCallbacks) error {
+
+	reader, _, errReader := newBufReader(rd)
+	if errReader != nil {
+		return errReader
+	}
+
+	if callbacks == nil {
+		callbacks = &ObjCallbacks{} // only validate the input
+	}
+
+	s := &scanner{cb: callbacks}
+
+	for {
+		s.lineCount++
+		rawLine, lines, err := readStatement(reader, callbacks.Limits.MaxLineLength)
+
+		var limit *LimitError
+		if errors.As(err, &limit) {
+			limit.Line = s.lineCount
+			return limit
+		}
+
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("ScanObj: error: %w", err)
+		}
+
+		if errLine := s.scanLine(cleanLine(rawLine)); errLine != nil {
+			return errLine
+		}
+
+		if errLimit := s.checkLimits(); errLimit != nil {
+			return errLimit
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+
+		s.lineCount += lines - 1 // continued lines
+	}
+}
+
+// scanner holds ScanObj state.
+type scanner struct {
+	cb        *ObjCallbacks
+	lineCount int
+	line      string
+	vertLines int
+	textLines int
+	normLines int
+	faceLines int
+	corners   []FaceIndex
+}
+
+func (s *scanner) errorf(kind error, format string, args ...interface{}) error {
+	return newParseError("", s.lineCount, s.line, kind, format, args...)
+}
+
+// scanLine parses a line and handles parse errors according to OnError.
+func (s *scanner) scanLine(line string) error {
+	s.line = line
+
+	err := s.parse(line)
+	if err == nil {
+		return nil
+	}
+
+	var pe *ParseError
+	if !errors.As(err, &pe) || s.cb.OnError == nil {
+		return err // callback error or no error handler
+	}
+
+	return s.cb.OnError(pe)
+}
+
+func (s *scanner) parse(line string) error {
+	if line == "" || line[0] == '#' {
+		return nil
+	}
+
+	keyword := directive(line)
+	rest := strings.TrimSpace(line[len(keyword):])
+	cb := s.cb
+
+	switch keyword {
+	case "v":
+		s.vertLines++
+		var v [6]float64
+		size, err := parseFloatsSpace(v[:], rest)
+		if err != nil {
+			return s.errorf(ErrBadCoord, "bad vertex=[%s]: %v", rest, err)
+		}
+		w := 1.0
+		switch size {
+		case 3, 6:
+		case 4:
+			w = v[3]
+		default:
+			return s.errorf(ErrBadCoord, "bad vertex=[%s] number of coords: %d", rest, size)
+		}
+		if cb.Vertex != nil {
+			if err := cb.Vertex(v[0], v[1], v[2], w); err != nil {
+				return err
+			}
+		}
+		if size == 6 && cb.Color != nil {
+			return cb.Color(v[3], v[4], v[5])
+		}
+	case "vt":
+		s.textLines++
+		t, err := parseFloatSliceSpace(rest)
+		if err != nil {
+			return s.errorf(ErrBadCoord, "bad vertex texture=[%s]: %v", rest, err)
+		}
+		switch len(t) {
+		case 1:
+			t = append(t, 0, 0)
+		case 2:
+			t = append(t, 0)
+		case 3:
+		default:
+			return s.errorf(ErrBadCoord, "bad vertex texture=[%s] size=%d", rest, len(t))
+		}
+		if cb.TexCoord != nil {
+			return cb.TexCoord(t[0], t[1], t[2])
+		}
+	case "vn":
+		s.normLines++
+		n, err := parseFloatVector3Space(rest)
+		if err != nil {
+			return s.errorf(ErrBadCoord, "bad vertex normal=[%s]: %v", rest, err)
+		}
+		if cb.Normal != nil {
+			return cb.Normal(n[0], n[1], n[2])
+		}
+	case "vp":
+		vp := [3]float64{0, 0, 1}
+		size, err := parseFloatsSpace(vp[:], rest)
+		if err != nil {
+			return s.errorf(ErrBadCoord, "bad parameter vertex=[%s]: %v", rest, err)
+		}
+		if size < 1 || size > 3 {
+			return s.errorf(ErrBadCoord, "bad parameter vertex=[%s] size=%d", rest, size)
+		}
+		if cb.Param != nil {
+			return cb.Param(vp[0], vp[1], vp[2])
+		}
+	case "f":
+		s.faceLines++
+		if err := s.parseCorners(rest, "face", 3); err != nil {
+			return err
+		}
+		if cb.FaceIndex != nil {
+			return cb.FaceIndex(s.corners)
+		}
+	case "l":
+		if err := s.parseCorners(rest, "line", 2); err != nil {
+			return err
+		}
+		if cb.Line != nil {
+			return cb.Line(s.corners)
+		}
+	case "p":
+		if err := s.parseCorners(rest, "point", 1); err != nil {
+			return err
+		}
+		if cb.Point != nil {
+			return cb.Point(s.corners)
+		}
+	case "g", "o":
+		if cb.Group != nil {
+			return cb.Group(rest)
+		}
+	case "usemtl":
+		if cb.UseMtl != nil {
+			return cb.UseMtl(rest)
+		}
+	case "mtllib":
+		if cb.MtlLib != nil {
+			return cb.MtlLib(splitMtllib(rest, false))
+		}
+	case "s":
+		group, err := smoothGroup(rest)
+		if err != nil {
+			return s.errorf(ErrBadValue, "bad smoothing group=[%s]: %v", rest, err)
+		}
+		if cb.Smooth != nil {
+			return cb.Smooth(group)
+		}
+	default:
+		if !objStatements[keyword] {
+			return s.errorf(ErrUnexpected, "unexpected: [%s]", line)
+		}
+	}
+
+	return nil
+}
+
+// parseCorners parses the corners of an f, l or p statement into s.corners,
+// reusing its storage.
+func (s *scanner) parseCorners(rest, element string, min int) error {
+	s.corners = s.corners[:0]
+	for i := 0; ; {
+		var c string
+		c, i = nextField(rest, i)
+		if c == "" {
+			break
+		}
+		fi, err := s.parseCorner(c)
+		if err != nil {
+			return s.errorf(ErrBadFace, "bad %s=[%s] index=[%s]: %v", element, rest, c, err)
+		}
+		s.corners = append(s.corners, fi)
+	}
+	if len(s.corners) < min {
+		return s.errorf(ErrBadFace, "bad %s=[%s] size=%d", element, rest, len(s.corners))
+	}
+	return nil
+}
+
+// checkLimits verifies the scanner counters against the limits.
+func (s *scanner) checkLimits() error {
+	lim := &s.cb.Limits
+	if max := lim.MaxVertices; max > 0 && s.vertLines > max {
+		return &LimitError{Line: s.lineCount, Limit: "MaxVertices", Max: int64(max), Value: int64(s.vertLines)}
+	}
+	if max := lim.MaxFaces; max > 0 && s.faceLines > max {
+		return &LimitError{Line: s.lineCount, Limit: "MaxFaces", Max: int64(max), Value: int64(s.faceLines)}
+	}
+	return nil
+}
+
+// parseCorner parses v, v/vt, v//vn or v/vt/vn. Errors carry their kind
+// but not the position, which the caller reports for the whole statement.
+func (s *scanner) parseCorner(c string) (FaceIndex, error) {
+	var ind [3]string
+	size, ok := splitCorner(&ind, c)
+	if !ok {
+		if size > len(ind) {
+			return FaceIndex{}, fmt.Errorf("%w: size=%d", ErrBadIndex, size)
+		}
+		return FaceIndex{}, fmt.Errorf("%w: missing vertex index", ErrBadIndex)
+	}
+
+	counts := [3]int{s.vertLines, s.textLines, s.normLines}
+	abs := [3]int{-1, -1, -1}
+
+	for i, part := range ind[:size] {
+		if part == "" {
+			continue // missing vt or vn
+		}
+		value, err := strconv.Atoi(part)
+		if err != nil || value == 0 {
+			return FaceIndex{}, fmt.Errorf("%w: bad integer=[%s]", ErrBadIndex, part)
+		}
+		abs[i] = solveRelativeIndex(value, counts[i])
+		if abs[i] < 0 {
+			return FaceIndex{}, fmt.Errorf("%w: invalid relative index=[%s]", ErrIndexOutOfRange, part)
+		}
+	}
+
+	return FaceIndex{V: abs[0], T: abs[1], N: abs[2]}, nil
+}
@@ -0,0 +1,151 @@
+package gwob
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestScanObj(t *testing.T) {
+
+	var vertices, texCoords, normals, groups, materials, libs int
+	var faces [][]FaceIndex
+
+	callbacks := &ObjCallbacks{
+		Vertex:   func(_, _, _, _ float64) error { vertices++; return nil },
+		TexCoord: func(_, _, _ float64) error { texCoords++; return nil },
+		Normal:   func(_, _, _ float64) error { normals++; return nil },
+		FaceIndex: func(corners []FaceIndex) error {
+			faces = append(faces, append([]FaceIndex(nil), corners...))
+			return nil
+		},
+		Group:  func(string) error { groups++; return nil },
+		UseMtl: func(string) error { materials++; return nil },
+		MtlLib: func(names []string) error { libs += len(names); return nil },
+	}
+
+	if err := ScanObj(strings.NewReader(cubeObj), callbacks); err != nil {
+		t.Errorf("TestScanObj: ScanObj: %v", err)
+		return
+	}
+
+	expectInt(t, "TestScanObj: vertices", 8, vertices)
+	expectInt(t, "TestScanObj: texCoords", 3, texCoords)
+	expectInt(t, "TestScanObj: normals", 6, normals)
+	expectInt(t, "TestScanObj: faces", 12, len(faces))
+	expectInt(t, "TestScanObj: groups", 1, groups)
+	expectInt(t, "TestScanObj: materials", 1, materials)
+	expectInt(t, "TestScanObj: libs", 1, libs)
+
+	// f -6/-2/-6 -7/-2/-6 -8/-2/-6
+	want := []FaceIndex{{2, 1, 0}, {1, 1, 0}, {0, 1, 0}}
+	for i, w := range want {
+		if faces[0][i] != w {
+			t.Errorf("TestScanObj: face 0 corner %d: want=%v got=%v", i, w, faces[0][i])
+		}
+	}
+
+	// parse errors
+	bad := "v 0 0 0\nbogus\nf 1//1 1 1\n"
+	if err := ScanObj(strings.NewReader(bad), &ObjCallbacks{}); !errors.Is(err, ErrUnexpected) {
+		t.Errorf("TestScanObj: want ErrUnexpected got %v", err)
+	}
+	var count int
+	skip := &ObjCallbacks{OnError: func(*ParseError) error { count++; return nil }}
+	if err := ScanObj(strings.NewReader(bad), skip); err != nil {
+		t.Errorf("TestScanObj: OnError: unexpected error: %v", err)
+	}
+	expectInt(t, "TestScanObj: OnError count", 1, count)
+
+	// callback error aborts
+	stop := errors.New("stop")
+	if err := ScanObj(strings.NewReader(cubeObj), &ObjCallbacks{Vertex: func(_, _, _, _ float64) error { return stop }}); err != stop {
+		t.Errorf("TestScanObj: want stop got %v", err)
+	}
+}
+
+func TestScanObjStatements(t *testing.T) {
+	str := "\uFEFFv 0 0 0 1 0.5 0\nv 1 0 \\\n 0\nv\t1 1 0\nvp 0.5\nl 1 2 3\np 1 -1\n" +
+		"cstype bezier\ndeg 1\ncurv 0 1 1 2\nend\nf 1 2 3\n"
+
+	var colors, lines, points, params, faces int
+	callbacks := &ObjCallbacks{
+		Color: func(r, g, b float64) error {
+			colors++
+			if r != 1 || g != 0.5 || b != 0 {
+				t.Errorf("TestScanObjStatements: color: %v %v %v", r, g, b)
+			}
+			return nil
+		},
+		Line:  func(corners []FaceIndex) error { lines += len(corners); return nil },
+		Point: func(corners []FaceIndex) error { points += len(corners); return nil },
+		Param: func(u, v, w float64) error {
+			params++
+			if u != 0.5 || v != 0 || w != 1 {
+				t.Errorf("TestScanObjStatements: param: %v %v %v", u, v, w)
+			}
+			return nil
+		},
+		FaceIndex: func(corners []FaceIndex) error {
+			faces++
+			if corners[2].V != 2 {
+				t.Errorf("TestScanObjStatements: face: %v", corners)
+			}
+			return nil
+		},
+	}
+
+	if err := ScanObj(strings.NewReader(str), callbacks); err != nil {
+		t.Fatalf("TestScanObjStatements: ScanObj: %v", err)
+	}
+	expectInt(t, "TestScanObjStatements: colors", 1, colors)
+	expectInt(t, "TestScanObjStatements: line corners", 3, lines)
+	expectInt(t, "TestScanObjStatements: point corners", 2, points)
+	expectInt(t, "TestScanObjStatements: params", 1, params)
+	expectInt(t, "TestScanObjStatements: faces", 1, faces)
+}
+
+func TestScanObjLimits(t *testing.T) {
+	long := &ObjCallbacks{Limits: Limits{MaxLineLength: 10}}
+	err := ScanObj(strings.NewReader("v 0 0 0\nv 0.000000 0.000000 0.000000\n"), long)
+	var limit *LimitError
+	if !errors.As(err, &limit) || limit.Limit != "MaxLineLength" || limit.Line != 2 {
+		t.Errorf("TestScanObjLimits: line length: %v", err)
+	}
+
+	verts := &ObjCallbacks{Limits: Limits{MaxVertices: 2}}
+	if err := ScanObj(strings.NewReader(cubeObj), verts); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("TestScanObjLimits: vertices: %v", err)
+	}
+}
+
+func TestScanObjCorners(t *testing.T) {
+	if err := ScanObj(strings.NewReader(cubeObj), nil); err != nil {
+		t.Errorf("TestScanObjCorners: nil callbacks: %v", err)
+	}
+
+	err := ScanObj(strings.NewReader("v 0 0 0\nf 1 2 -5\n"), nil)
+	var pe *ParseError
+	if !errors.As(err, &pe) || !errors.Is(err, ErrBadFace) || !errors.Is(err, ErrIndexOutOfRange) {
+		t.Fatalf("TestScanObjCorners: want out of range face got %v", err)
+	}
+	if strings.Count(err.Error(), "line=") != 1 {
+		t.Errorf("TestScanObjCorners: position repeated: %v", err)
+	}
+
+	s := &scanner{cb: &ObjCallbacks{}, vertLines: 8, textLines: 4, normLines: 6}
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := s.parseCorners("-8/-4/-6 2/3 3//1 4", "face", 3); err != nil {
+			t.Fatalf("TestScanObjCorners: parseCorners: %v", err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("TestScanObjCorners: allocs per face=%v", allocs)
+	}
+	want := []FaceIndex{{0, 0, 0}, {1, 2, -1}, {2, -1, 0}, {3, -1, -1}}
+	for i, w := range want {
+		if s.corners[i] != w {
+			t.Errorf("TestScanObjCorners: corner %d: want=%v got=%v", i, w, s.corners[i])
+		}
+	}
+}
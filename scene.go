@@ -0,0 +1,127 @@
+package gwob
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Scene holds an OBJ together with its resolved materials.
+type Scene struct {
+	Obj       *Obj
+	Lib       MaterialLib // materials from all libs referenced by the OBJ
+	Materials []*Material // per-group material, parallel to Obj.Groups (nil if not found)
+
+	open opener
+}
+
+// opener opens an asset referenced by name relative to the OBJ location.
+type opener func(name string) (io.ReadCloser, error)
+
+// LoadScene parses an OBJ file and loads all material libs it references,
+// resolving their names relative to the OBJ path.
+func LoadScene(filename string, options *ObjParserOptions) (*Scene, error) {
+	dir := filepath.Dir(filename)
+	open := func(name string) (io.ReadCloser, error) {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, filepath.FromSlash(name))
+		}
+		return os.Open(name)
+	}
+	return loadScene(filepath.Base(filename), open, options)
+}
+
+func loadScene(objName string, open opener, options *ObjParserOptions) (*Scene, error) {
+
+	if options == nil {
+		options = defaultObjParserOptions()
+	}
+
+	input, errOpen := open(objName)
+	if errOpen != nil {
+		return nil, errOpen
+	}
+
+	o, errObj := NewObjFromReader(objName, input, options)
+	input.Close()
+	if errObj != nil {
+		return nil, errObj
+	}
+
+	s := &Scene{Obj: o, Lib: NewMaterialLib(), open: open}
+
+	for _, libName := range o.Mtllibs {
+		lib, errLib := s.loadLib(libName, options)
+		if errLib != nil {
+			// a missing lib should not prevent using the geometry
+			options.warn(fmt.Sprintf("loadScene: obj=%s mtllib=%s: %v", objName, libName, errLib))
+			continue
+		}
+		for name, mat := range lib.Lib {
+			if _, found := s.Lib.Lib[name]; !found {
+				s.Lib.Lib[name] = mat // first definition wins
+			}
+		}
+	}
+
+	s.Materials = make([]*Material, len(o.Groups))
+	for i, g := range o.Groups {
+		mat, found := s.Lib.Lib[g.Usemtl]
+		if !found && g.Usemtl != "" {
+			options.warn(fmt.Sprintf("loadScene: obj=%s group=%s material=%s NOT FOUND", objName, g.Name, g.Usemtl))
+		}
+		s.Materials[i] = mat
+	}
+
+	return s, nil
+}
+
+func (s *Scene) loadLib(name string, options *ObjParserOptions) (MaterialLib, error) {
+	input, errOpen := s.open(name)
+	if errOpen != nil {
+		return NewMaterialLib(), errOpen
+	}
+	defer input.Close()
+	return ReadMaterialLibFromReader(input, options)
+}
+
+// Material gets the material resolved for a group.
+func (s *Scene) Material(g *Group) *Material {
+	for i, gr := range s.Obj.Groups {
+		if gr == g {
+			return s.Materials[i]
+		}
+	}
+	return nil
+}
+
+// TextureNames lists the texture maps referenced by the scene materials.
+func (s *Scene) TextureNames() []string {
+	var names []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if name == "" || seen[name] {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	for _, mat := range s.Materials {
+		if mat == nil {
+			continue
+		}
+		add(mat.MapKd)
+		add(mat.MapKa)
+		add(mat.MapKs)
+		add(mat.MapD)
+		add(mat.Bump)
+	}
+	return names
+}
+
+// OpenTexture opens a texture map referenced by the scene materials,
+// resolving the name the same way material libs were resolved.
+func (s *Scene) OpenTexture(name string) (io.ReadCloser, error) {
+	return s.open(name)
+}
@@ -0,0 +1,64 @@
+package gwob
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadScene(t *testing.T) {
+
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "scene.obj"), []byte(sceneObj), 0o644); err != nil {
+		t.Errorf("TestLoadScene: write obj: %v", err)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(dir, "scene.mtl"), []byte(sceneMtl), 0o644); err != nil {
+		t.Errorf("TestLoadScene: write mtl: %v", err)
+		return
+	}
+
+	options := ObjParserOptions{LogStats: LogStats, Logger: func(msg string) { fmt.Printf("TestLoadScene LoadScene: log: %s\n", msg) }}
+
+	s, err := LoadScene(filepath.Join(dir, "scene.obj"), &options)
+	if err != nil {
+		t.Errorf("TestLoadScene: LoadScene: %v", err)
+		return
+	}
+
+	expectInt(t, "TestLoadScene: groups", 2, len(s.Obj.Groups))
+	expectInt(t, "TestLoadScene: materials", 2, len(s.Materials))
+
+	if mat := s.Material(s.Obj.Groups[0]); mat == nil || mat.Name != "red" {
+		t.Errorf("TestLoadScene: group 0: want material red got %v", mat)
+	}
+	if mat := s.Materials[1]; mat != nil {
+		t.Errorf("TestLoadScene: group 1: unexpected material %v", mat)
+	}
+
+	names := s.TextureNames()
+	if len(names) != 1 || names[0] != "red.png" {
+		t.Errorf("TestLoadScene: textures: want=[red.png] got=%v", names)
+	}
+}
+
+var sceneObj = `
+mtllib scene.mtl missing.mtl
+v 0 0 0
+v 1 0 0
+v 1 1 0
+g a
+usemtl red
+f 1 2 3
+g b
+usemtl unknown
+f 3 2 1
+`
+
+var sceneMtl = `
+newmtl red
+Kd 1 0 0
+map_Kd red.png
+`
@@ -0,0 +1,104 @@
+package gwob
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Save writes the scene into dir as basename.obj and basename.mtl,
+// the inverse of LoadScene. Texture maps are copied into dir and the
+// material references are rewritten to their new names. Textures are
+// referenced unchanged when the scene has no source to copy them from,
+// such as a Scene built by hand.
+func (s *Scene) Save(dir, basename string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	lib := NewMaterialLib()
+	renamed := map[string]string{} // original texture name => saved name
+	taken := map[string]bool{}
+
+	texture := func(name string) (string, error) {
+		if name == "" || s.open == nil {
+			return name, nil
+		}
+		if saved, found := renamed[name]; found {
+			return saved, nil
+		}
+		saved := uniqueName(path.Base(filepath.ToSlash(name)), taken)
+		if err := s.copyTexture(name, filepath.Join(dir, saved)); err != nil {
+			return "", fmt.Errorf("Scene.Save: texture=%s: %w", name, err)
+		}
+		renamed[name] = saved
+		return saved, nil
+	}
+
+	names := make([]string, 0, len(s.Lib.Lib))
+	for name := range s.Lib.Lib {
+		names = append(names, name)
+	}
+	sort.Strings(names) // deterministic texture renaming
+
+	for _, name := range names {
+		m := *s.Lib.Lib[name]
+		for _, field := range []*string{&m.MapKd, &m.MapKa, &m.MapKs, &m.MapD, &m.Bump} {
+			saved, err := texture(*field)
+			if err != nil {
+				return err
+			}
+			*field = saved
+		}
+		lib.Lib[name] = &m
+	}
+
+	o := *s.Obj
+	o.Mtllib = ""
+	o.Mtllibs = nil
+	if len(lib.Lib) > 0 {
+		mtllib := basename + ".mtl"
+		if err := lib.ToFile(filepath.Join(dir, mtllib)); err != nil {
+			return err
+		}
+		o.Mtllib = mtllib
+		o.Mtllibs = []string{mtllib}
+	}
+
+	return o.ToFile(filepath.Join(dir, basename+".obj"))
+}
+
+// uniqueName avoids clashes between textures of same base name
+// coming from different directories.
+func uniqueName(name string, taken map[string]bool) string {
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	unique := name
+	for i := 1; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d%s", stem, i, ext)
+	}
+	taken[unique] = true
+	return unique
+}
+
+func (s *Scene) copyTexture(name, filename string) error {
+	input, errOpen := s.open(name)
+	if errOpen != nil {
+		return errOpen
+	}
+	defer input.Close()
+
+	output, errCreate := os.Create(filename)
+	if errCreate != nil {
+		return errCreate
+	}
+	if _, err := io.Copy(output, input); err != nil {
+		output.Close()
+		return err
+	}
+	return output.Close()
+}
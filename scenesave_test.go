@@ -0,0 +1,76 @@
+package gwob
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSceneSave(t *testing.T) {
+
+	mtl := sceneMtl + `
+newmtl blue
+Kd 0 0 1
+Ns 10
+d 0.5
+map_Kd other/red.png
+map_Bump textures/bump.png
+`
+	fsys := fstest.MapFS{
+		"models/scene.obj":         {Data: []byte(sceneObj)},
+		"models/scene.mtl":         {Data: []byte(mtl)},
+		"models/red.png":           {Data: []byte("red")},
+		"models/other/red.png":     {Data: []byte("other red")},
+		"models/textures/bump.png": {Data: []byte("bump")},
+	}
+
+	options := NewObjParserOptions()
+
+	s, err := LoadSceneFS(fsys, "models/scene.obj", options)
+	if err != nil {
+		t.Fatalf("TestSceneSave: LoadSceneFS: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "out")
+	if errSave := s.Save(dir, "saved"); errSave != nil {
+		t.Fatalf("TestSceneSave: Save: %v", errSave)
+	}
+
+	s2, err := LoadScene(filepath.Join(dir, "saved.obj"), options)
+	if err != nil {
+		t.Fatalf("TestSceneSave: LoadScene: %v", err)
+	}
+	if len(s2.Obj.Mtllibs) != 1 || s2.Obj.Mtllibs[0] != "saved.mtl" {
+		t.Errorf("TestSceneSave: mtllibs: got=%v", s2.Obj.Mtllibs)
+	}
+	if !sliceEqualInt(s.Obj.Indices, s2.Obj.Indices) {
+		t.Errorf("TestSceneSave: indices: want=%v got=%v", s.Obj.Indices, s2.Obj.Indices)
+	}
+	expectInt(t, "TestSceneSave: materials", 2, len(s2.Lib.Lib))
+
+	blue := s2.Lib.Lib["blue"]
+	if blue == nil || blue.Kd != [3]float32{0, 0, 1} || blue.Ns != 10 || blue.D != 0.5 {
+		t.Fatalf("TestSceneSave: blue: got=%+v", blue)
+	}
+	red := s2.Lib.Lib["red"]
+	if red == nil || red.MapKd == blue.MapKd {
+		t.Errorf("TestSceneSave: texture names clash: red=%+v blue=%+v", red, blue)
+	}
+
+	want := map[string]string{
+		blue.MapKd: "other red",
+		blue.Bump:  "bump",
+		red.MapKd:  "red",
+	}
+	for name, data := range want {
+		buf, errRead := os.ReadFile(filepath.Join(dir, name))
+		if errRead != nil {
+			t.Errorf("TestSceneSave: texture %s: %v", name, errRead)
+			continue
+		}
+		if string(buf) != data {
+			t.Errorf("TestSceneSave: texture %s: want=%q got=%q", name, data, buf)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Package sdf samples signed distance fields from gwob OBJ data.
+
+The distance of each grid point to the surface is found with a bvh tree.
+The sign, negative inside and positive outside, comes from the parity of
+the surface crossings along a few rays, so it is only meaningful for
+watertight meshes: see gwob.Obj.CheckManifold and topology.FillHoles.
+*/
+package sdf
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/udhos/gwob"
+	"github.com/udhos/gwob/bvh"
+)
+
+// Grid is a regular 3D grid of samples.
+type Grid struct {
+	Origin   [3]float32 // position of sample (0,0,0)
+	CellSize float32    // distance between neighbor samples
+	Size     [3]int     // samples along x, y and z
+	Values   []float32  // x varies fastest, then y, then z
+}
+
+// Index gets the position of sample (x,y,z) in Values.
+func (g *Grid) Index(x, y, z int) int {
+	return x + g.Size[0]*(y+g.Size[1]*z)
+}
+
+// At gets the value of sample (x,y,z).
+func (g *Grid) At(x, y, z int) float32 {
+	return g.Values[g.Index(x, y, z)]
+}
+
+// Position gets the location of sample (x,y,z).
+func (g *Grid) Position(x, y, z int) [3]float32 {
+	return [3]float32{
+		g.Origin[0] + float32(x)*g.CellSize,
+		g.Origin[1] + float32(y)*g.CellSize,
+		g.Origin[2] + float32(z)*g.CellSize,
+	}
+}
+
+// signRays are the directions cast to find whether a point is inside:
+// skewed, so that they rarely graze edges, and voted on, so that a
+// graze does not flip the sign.
+var signRays = [3][3]float32{
+	{0.8017, 0.3419, 0.4903},
+	{-0.3145, 0.8866, -0.3392},
+	{0.2231, -0.4107, -0.8841},
+}
+
+// maxSamples caps the size of the grid built by Sample.
+const maxSamples = 1 << 28
+
+// Sample computes the signed distances from the triangles of o on a grid
+// of cellSize spacing, covering the bounding box of o plus padding cells
+// on every side.
+func Sample(o *gwob.Obj, cellSize float32, padding int) (*Grid, error) {
+	if cellSize <= 0 {
+		return nil, fmt.Errorf("sdf: bad cell size=%v", cellSize)
+	}
+	if padding < 0 {
+		return nil, fmt.Errorf("sdf: bad padding=%d", padding)
+	}
+	if len(o.Indices) < 3 {
+		return nil, fmt.Errorf("sdf: no triangles")
+	}
+
+	tree := bvh.New(o)
+	bounds := tree.Nodes[0].Bounds
+
+	g := &Grid{CellSize: cellSize}
+	total := 1.0
+	for i := range g.Size {
+		g.Origin[i] = bounds.Min[i] - float32(padding)*cellSize
+		cells := math.Ceil(float64(bounds.Max[i]-bounds.Min[i]) / float64(cellSize))
+		n := cells + 1 + 2*float64(padding)
+		total *= n
+		if !(total <= maxSamples) { // also catches NaN
+			return nil, fmt.Errorf("sdf: grid too large for cell size=%v padding=%d (limit=%d samples)", cellSize, padding, maxSamples)
+		}
+		g.Size[i] = int(n)
+	}
+	g.Values = make([]float32, g.Size[0]*g.Size[1]*g.Size[2])
+
+	for z := 0; z < g.Size[2]; z++ {
+		for y := 0; y < g.Size[1]; y++ {
+			for x := 0; x < g.Size[0]; x++ {
+				p := g.Position(x, y, z)
+				_, _, d := tree.Nearest(p)
+				if inside(tree, o, p) {
+					d = -d
+				}
+				g.Values[g.Index(x, y, z)] = d
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// inside votes on the crossing parity of the sign rays from p.
+func inside(tree *bvh.Tree, o *gwob.Obj, p [3]float32) bool {
+	votes := 0
+	for _, dir := range signRays {
+		if crossings(tree, o, p, dir)%2 == 1 {
+			votes++
+		}
+	}
+	return 2*votes > len(signRays)
+}
+
+// crossings counts the triangles hit by the ray from p along dir.
+func crossings(tree *bvh.Tree, o *gwob.Obj, p, dir [3]float32) int {
+	count := 0
+	tree.Traverse(func(b bvh.AABB) bool {
+		return rayBox(b, p, dir)
+	}, func(t int) bool {
+		if _, hit := o.IntersectTriangle(t, p, dir); hit {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// rayBox reports whether the ray from p along dir meets box b.
+func rayBox(b bvh.AABB, p, dir [3]float32) bool {
+	near, far := 0.0, math.Inf(1)
+	for i := range dir {
+		if dir[i] == 0 {
+			if p[i] < b.Min[i] || p[i] > b.Max[i] {
+				return false
+			}
+			continue
+		}
+		t0 := float64(b.Min[i]-p[i]) / float64(dir[i])
+		t1 := float64(b.Max[i]-p[i]) / float64(dir[i])
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		near = max(near, t0)
+		far = min(far, t1)
+	}
+	return near <= far
+}
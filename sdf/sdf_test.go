@@ -0,0 +1,101 @@
+package sdf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/udhos/gwob"
+)
+
+// cubeObj is a closed cube of side 2 centered on the origin.
+var cubeObj = `
+v -1 -1 -1
+v 1 -1 -1
+v 1 1 -1
+v -1 1 -1
+v -1 -1 1
+v 1 -1 1
+v 1 1 1
+v -1 1 1
+f 1 4 3 2
+f 5 6 7 8
+f 1 2 6 5
+f 2 3 7 6
+f 3 4 8 7
+f 4 1 5 8
+`
+
+// cubeDistance is the exact signed distance to the cube.
+func cubeDistance(p [3]float32) float64 {
+	var outside, inside float64
+	inside = math.Inf(-1)
+	for _, c := range p {
+		d := math.Abs(float64(c)) - 1
+		inside = max(inside, d)
+		d = max(d, 0)
+		outside += d * d
+	}
+	if inside > 0 {
+		return math.Sqrt(outside)
+	}
+	return inside
+}
+
+func TestSample(t *testing.T) {
+	o, err := gwob.NewObjFromBuf("cube", []byte(cubeObj), gwob.NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestSample: NewObjFromBuf: %v", err)
+	}
+
+	g, err := Sample(o, 0.25, 2)
+	if err != nil {
+		t.Fatalf("TestSample: %v", err)
+	}
+	if g.Size != [3]int{13, 13, 13} {
+		t.Errorf("TestSample: size=%v", g.Size)
+	}
+	if g.Origin != [3]float32{-1.5, -1.5, -1.5} {
+		t.Errorf("TestSample: origin=%v", g.Origin)
+	}
+	if len(g.Values) != 13*13*13 {
+		t.Errorf("TestSample: values=%d", len(g.Values))
+	}
+
+	for z := 0; z < g.Size[2]; z++ {
+		for y := 0; y < g.Size[1]; y++ {
+			for x := 0; x < g.Size[0]; x++ {
+				p := g.Position(x, y, z)
+				want := cubeDistance(p)
+				if got := g.At(x, y, z); math.Abs(float64(got)-want) > 1e-5 {
+					t.Errorf("TestSample: %v: distance=%v want=%v", p, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestSampleErrors(t *testing.T) {
+	o, err := gwob.NewObjFromBuf("cube", []byte(cubeObj), gwob.NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestSampleErrors: NewObjFromBuf: %v", err)
+	}
+	if _, err := Sample(o, 0, 0); err == nil {
+		t.Errorf("TestSampleErrors: zero cell size")
+	}
+	if _, err := Sample(o, 1, -1); err == nil {
+		t.Errorf("TestSampleErrors: negative padding")
+	}
+	if _, err := Sample(o, 1e-7, 1); err == nil {
+		t.Errorf("TestSampleErrors: grid too large")
+	}
+	if _, err := Sample(o, 1, 1<<40); err == nil {
+		t.Errorf("TestSampleErrors: padding too large")
+	}
+	empty, err := gwob.NewObjFromVertex(nil, nil)
+	if err != nil {
+		t.Fatalf("TestSampleErrors: NewObjFromVertex: %v", err)
+	}
+	if _, err := Sample(empty, 1, 0); err == nil {
+		t.Errorf("TestSampleErrors: no triangles")
+	}
+}
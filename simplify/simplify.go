@@ -0,0 +1,50 @@
+/*
+Package simplify reduces the triangle count of gwob meshes by quadric
+error metric edge-collapse decimation.
+
+Vertices are only moved onto their neighbors, so the surviving ones keep
+their texture coordinates, normals and colors. Vertices on group,
+material or smoothing boundaries, and on texture seams (positions shared
+by vertices with different attributes), are never moved, so those
+boundaries are preserved exactly.
+*/
+package simplify
+
+import (
+	"fmt"
+
+	"github.com/udhos/gwob"
+)
+
+// Options sets the stop criteria. Simplification stops at the first
+// criterion reached, or when no further collapse is allowed.
+type Options struct {
+	// TargetTriangles is the triangle count to reach.
+	TargetTriangles int
+
+	// TargetRatio is the fraction of the original triangles to reach,
+	// used when TargetTriangles is zero.
+	TargetRatio float64
+
+	// MaxError is the largest allowed geometric deviation, relative to
+	// the bounding box diagonal: 0.01 is 1% of the model size.
+	// Zero means no limit.
+	MaxError float64
+}
+
+// Simplify returns a decimated copy of o, see gwob.Obj.Decimate. Group
+// ranges and per-triangle smoothing and materials are kept consistent,
+// unused vertices are dropped, and the original faces are discarded.
+func Simplify(o *gwob.Obj, options Options) (*gwob.Obj, error) {
+	if len(o.Indices)%3 != 0 {
+		return nil, fmt.Errorf("simplify: index count=%d must be a multiple of 3", len(o.Indices))
+	}
+	triangles := len(o.Indices) / 3
+
+	target := options.TargetTriangles
+	if target == 0 && options.TargetRatio > 0 {
+		target = int(options.TargetRatio * float64(triangles))
+	}
+
+	return o.Decimate(target, options.MaxError)
+}
@@ -0,0 +1,101 @@
+package simplify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/udhos/gwob"
+)
+
+// gridObj builds a 10x10 grid of quads, split in groups left (x<5) and
+// right (x>=5).
+func gridObj(t *testing.T) *gwob.Obj {
+	var b strings.Builder
+	for y := 0; y <= 10; y++ {
+		for x := 0; x <= 10; x++ {
+			fmt.Fprintf(&b, "v %d %d 0\nvt %v %v\n", x, y, float64(x)/10, float64(y)/10)
+		}
+	}
+	for _, g := range []string{"left", "right"} {
+		fmt.Fprintf(&b, "g %s\n", g)
+		for y := 0; y < 10; y++ {
+			for x := 0; x < 10; x++ {
+				if (x < 5) != (g == "left") {
+					continue
+				}
+				a := y*11 + x + 1
+				fmt.Fprintf(&b, "f %d/%d %d/%d %d/%d %d/%d\n", a, a, a+1, a+1, a+12, a+12, a+11, a+11)
+			}
+		}
+	}
+	o, err := gwob.NewObjFromBuf("grid", []byte(b.String()), gwob.NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("gridObj: NewObjFromBuf: %v", err)
+	}
+	return o
+}
+
+func TestSimplify(t *testing.T) {
+	o := gridObj(t)
+	indices := len(o.Indices)
+
+	s, err := Simplify(o, Options{TargetRatio: 0.1})
+	if err != nil {
+		t.Fatalf("TestSimplify: %v", err)
+	}
+	if len(o.Indices) != indices {
+		t.Errorf("TestSimplify: input changed: indices=%d want=%d", len(o.Indices), indices)
+	}
+	if len(s.Indices) >= indices/2 {
+		t.Errorf("TestSimplify: indices=%d from %d", len(s.Indices), indices)
+	}
+	if s.NumberOfElements() >= o.NumberOfElements() {
+		t.Errorf("TestSimplify: vertices=%d from %d", s.NumberOfElements(), o.NumberOfElements())
+	}
+
+	// groups cover the indices in order
+	next := 0
+	for _, g := range s.Groups {
+		if g.IndexCount == 0 {
+			continue
+		}
+		if g.IndexBegin != next {
+			t.Errorf("TestSimplify: group %s begin=%d want=%d", g.Name, g.IndexBegin, next)
+		}
+		next = g.IndexBegin + g.IndexCount
+	}
+	if next != len(s.Indices) {
+		t.Errorf("TestSimplify: groups end=%d indices=%d", next, len(s.Indices))
+	}
+
+	// the group boundary x=5 keeps all its vertices, with their UVs
+	for y := 0; y <= 10; y++ {
+		found := false
+		for _, v := range s.Indices {
+			x, vy, _ := s.VertexCoordinates(v)
+			if x == 5 && vy == float32(y) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("TestSimplify: boundary vertex 5,%d removed", y)
+		}
+	}
+	for v := 0; v < s.NumberOfElements(); v++ {
+		x, y, _ := s.VertexCoordinates(v)
+		stride := s.Coord[v*s.StrideSize/4:]
+		u, tv := stride[s.StrideOffsetTexture/4], stride[s.StrideOffsetTexture/4+1]
+		if u != x/10 || tv != y/10 {
+			t.Errorf("TestSimplify: vertex %v,%v has uv %v,%v", x, y, u, tv)
+		}
+	}
+}
+
+func TestSimplifyBadIndices(t *testing.T) {
+	o := &gwob.Obj{Indices: []int{0, 1}}
+	if _, err := Simplify(o, Options{}); err == nil {
+		t.Errorf("TestSimplifyBadIndices: expected error")
+	}
+}
@@ -0,0 +1,107 @@
+package gwob
+
+// SmoothOptions changes how Smooth moves vertices. The zero value gives
+// plain Laplacian smoothing keeping boundaries and texture seams fixed.
+type SmoothOptions struct {
+	// Mu, when negative, turns on Taubin smoothing: each iteration is
+	// followed by a step of factor Mu, inflating the mesh back to undo
+	// the shrinking of plain Laplacian smoothing. Usual values are a bit
+	// larger in magnitude than lambda, such as -0.53 for lambda 0.5.
+	Mu float64
+
+	// MoveBoundary lets the vertices on open boundaries, and on edges
+	// shared by more than two triangles, move.
+	MoveBoundary bool
+
+	// MoveSeams lets the vertices on texture seams, positions shared by
+	// vertices with different texture coordinates, move.
+	MoveSeams bool
+}
+
+// Smooth applies Laplacian smoothing. See SmoothWithOptions.
+func (o *Obj) Smooth(iterations int, lambda float64) {
+	o.SmoothWithOptions(iterations, lambda, nil)
+}
+
+// SmoothWithOptions moves each vertex, at each of the iterations, by
+// lambda times the offset to the average of its neighbors, removing
+// noise from scanned geometry. Lambda is usually between 0 and 1.
+// Vertices sharing a position move together, so seams stay closed.
+// Normals are not updated: see GenerateSmoothNormals.
+func (o *Obj) SmoothWithOptions(iterations int, lambda float64, options *SmoothOptions) {
+	if options == nil {
+		options = &SmoothOptions{}
+	}
+
+	adj := o.Adjacency()
+	ids := adj.Position
+
+	// positions of the connected vertices, by position id
+	strides := len(ids)
+	neighbors := make([][]int, strides)
+	for _, e := range adj.Edges {
+		neighbors[e.A] = append(neighbors[e.A], e.B)
+		neighbors[e.B] = append(neighbors[e.B], e.A)
+	}
+	pos := make([][3]float64, strides)
+	for id, list := range neighbors {
+		if len(list) > 0 {
+			x, y, z := o.VertexCoordinates(id)
+			pos[id] = [3]float64{float64(x), float64(y), float64(z)}
+		}
+	}
+
+	fixed := make([]bool, strides)
+	if !options.MoveBoundary {
+		for i, e := range adj.Edges {
+			if len(adj.EdgeFaces[i]) != 2 {
+				fixed[e.A] = true
+				fixed[e.B] = true
+			}
+		}
+	}
+	if !options.MoveSeams && o.TextCoordFound {
+		for s, id := range ids {
+			if s == id {
+				continue
+			}
+			a, b := o.vertexTexCoord(s), o.vertexTexCoord(id)
+			if a[0] != b[0] || a[1] != b[1] {
+				fixed[id] = true
+			}
+		}
+	}
+
+	next := make([][3]float64, strides)
+	step := func(factor float64) {
+		for id, p := range pos {
+			if !fixed[id] && len(neighbors[id]) > 0 {
+				var avg [3]float64
+				for _, n := range neighbors[id] {
+					for i := range avg {
+						avg[i] += pos[n][i]
+					}
+				}
+				k := float64(len(neighbors[id]))
+				for i := range p {
+					p[i] += factor * (avg[i]/k - p[i])
+				}
+			}
+			next[id] = p
+		}
+		pos, next = next, pos
+	}
+	for i := 0; i < iterations; i++ {
+		step(lambda)
+		if options.Mu < 0 {
+			step(options.Mu)
+		}
+	}
+
+	for s, id := range ids {
+		if len(neighbors[id]) > 0 {
+			p := pos[id]
+			o.SetVertexCoordinates(s, float32(p[0]), float32(p[1]), float32(p[2]))
+		}
+	}
+}
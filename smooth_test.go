@@ -0,0 +1,111 @@
+package gwob
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// bumpyGrid builds a 5x5 vertex grid in the z=0 plane, with its center
+// vertex raised to z=1. With seam, the middle column of vertices is
+// duplicated with different texture coordinates on each side.
+func bumpyGrid(t *testing.T, seam bool) *Obj {
+	var b strings.Builder
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 5; x++ {
+			z := 0
+			if x == 2 && y == 2 {
+				z = 1
+			}
+			fmt.Fprintf(&b, "v %d %d %d\n", x, y, z)
+		}
+	}
+	fmt.Fprintf(&b, "vt 0 0\nvt 1 1\n")
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			a := y*5 + x + 1
+			vt := 1
+			if seam && x >= 2 {
+				vt = 2
+			}
+			fmt.Fprintf(&b, "f %d/%d %d/%d %d/%d %d/%d\n", a, vt, a+1, vt, a+6, vt, a+5, vt)
+		}
+	}
+	o, err := NewObjFromBuf("grid", []byte(b.String()), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("bumpyGrid: NewObjFromBuf: %v", err)
+	}
+	return o
+}
+
+// heightAt lists the heights of the strides at position (x,y).
+func heightAt(o *Obj, x, y float32) []float32 {
+	var list []float32
+	for s := 0; s < o.NumberOfElements(); s++ {
+		vx, vy, vz := o.VertexCoordinates(s)
+		if math.Abs(float64(vx-x)) < 0.3 && math.Abs(float64(vy-y)) < 0.3 {
+			list = append(list, vz)
+		}
+	}
+	return list
+}
+
+func TestSmooth(t *testing.T) {
+	o := bumpyGrid(t, false)
+	corner0 := o.Vertex(0)
+
+	o.Smooth(3, 0.5)
+
+	z := heightAt(o, 2, 2)
+	if len(z) != 1 || z[0] >= 0.5 || z[0] <= 0 {
+		t.Errorf("TestSmooth: center heights=%v", z)
+	}
+	if z := heightAt(o, 1, 2); len(z) != 1 || z[0] <= 0 {
+		t.Errorf("TestSmooth: neighbor heights=%v", z)
+	}
+	if o.Vertex(0) != corner0 {
+		t.Errorf("TestSmooth: boundary moved: %+v", o.Vertex(0))
+	}
+
+	o = bumpyGrid(t, false)
+	o.SmoothWithOptions(3, 0.5, &SmoothOptions{MoveBoundary: true})
+	if o.Vertex(0) == corner0 {
+		t.Errorf("TestSmooth: boundary not moved")
+	}
+}
+
+func TestSmoothSeams(t *testing.T) {
+	o := bumpyGrid(t, true)
+	expectInt(t, "TestSmoothSeams: elements", 30, o.NumberOfElements())
+
+	o.Smooth(3, 0.5)
+	if z := heightAt(o, 2, 2); len(z) != 2 || z[0] != 1 || z[1] != 1 {
+		t.Errorf("TestSmoothSeams: seam heights=%v", z)
+	}
+	if z := heightAt(o, 1, 2); len(z) != 1 || z[0] <= 0 {
+		t.Errorf("TestSmoothSeams: neighbor heights=%v", z)
+	}
+
+	o = bumpyGrid(t, true)
+	o.SmoothWithOptions(3, 0.5, &SmoothOptions{MoveSeams: true})
+	if z := heightAt(o, 2, 2); len(z) != 2 || z[0] != z[1] || z[0] >= 0.5 {
+		t.Errorf("TestSmoothSeams: moved seam heights=%v", z) // seam stays closed
+	}
+}
+
+func TestSmoothTaubin(t *testing.T) {
+	plain, err := NewObjFromBuf("box", []byte(boxObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestSmoothTaubin: NewObjFromBuf: %v", err)
+	}
+	taubin := plain.Clone()
+
+	plain.Smooth(5, 0.5)
+	taubin.SmoothWithOptions(5, 0.5, &SmoothOptions{Mu: -0.53})
+
+	vp, vt := plain.Volume(), taubin.Volume()
+	if vp >= 1 || vt <= vp {
+		t.Errorf("TestSmoothTaubin: volume plain=%v taubin=%v", vp, vt)
+	}
+}
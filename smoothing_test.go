@@ -0,0 +1,58 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+var smoothingObj = `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+g square
+s 1
+f 1 2 3 4
+s off
+f 1 2 3
+s 2
+f 1 3 4
+`
+
+func TestSmoothPerTriangle(t *testing.T) {
+
+	o, err := NewObjFromBuf("smoothingObj", []byte(smoothingObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestSmoothPerTriangle: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestSmoothPerTriangle: split groups", 3, len(o.Groups))
+	if o.Smoothing != nil {
+		t.Errorf("TestSmoothPerTriangle: unexpected smoothing: %v", o.Smoothing)
+	}
+
+	options := NewObjParserOptions(WithSmoothPerTriangle(true))
+	o, err = NewObjFromBuf("smoothingObj", []byte(smoothingObj), options)
+	if err != nil {
+		t.Fatalf("TestSmoothPerTriangle: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestSmoothPerTriangle: groups", 1, len(o.Groups))
+	expectInt(t, "TestSmoothPerTriangle: group smooth", 1, o.Groups[0].Smooth)
+	want := []int{1, 1, 0, 2}
+	if !sliceEqualInt(want, o.Smoothing) {
+		t.Errorf("TestSmoothPerTriangle: smoothing: want=%v got=%v", want, o.Smoothing)
+	}
+
+	// round trip
+	var buf bytes.Buffer
+	if errWrite := o.ToWriter(&buf); errWrite != nil {
+		t.Fatalf("TestSmoothPerTriangle: ToWriter: %v", errWrite)
+	}
+	o2, err := NewObjFromBuf("smoothingObj2", buf.Bytes(), options)
+	if err != nil {
+		t.Fatalf("TestSmoothPerTriangle: reparse: %v", err)
+	}
+	expectInt(t, "TestSmoothPerTriangle: reparsed groups", 1, len(o2.Groups))
+	if !sliceEqualInt(want, o2.Smoothing) {
+		t.Errorf("TestSmoothPerTriangle: reparsed smoothing: want=%v got=%v", want, o2.Smoothing)
+	}
+}
@@ -0,0 +1,56 @@
+package gwob
+
+import "sort"
+
+// SortGroupsByMaterial reorders the groups, and their triangles in
+// Indices, so that groups using the same material are adjacent,
+// letting renderers batch draw calls. Materials keep the order of their
+// first use, and groups with the same material keep their relative
+// order. Triangles with per-triangle materials (MaterialPerTriangle
+// option) are also grouped by material within each group.
+func (o *Obj) SortGroupsByMaterial() {
+	groupRank := map[string]int{}
+	for _, g := range o.Groups {
+		if _, found := groupRank[g.Usemtl]; !found {
+			groupRank[g.Usemtl] = len(groupRank)
+		}
+	}
+	sort.SliceStable(o.Groups, func(i, j int) bool {
+		return groupRank[o.Groups[i].Usemtl] < groupRank[o.Groups[j].Usemtl]
+	})
+
+	triangles := len(o.Indices) / 3
+	perTriangle := len(o.MaterialIndex) == triangles
+	materialRank := map[int]int{}
+	if perTriangle {
+		for _, m := range o.MaterialIndex {
+			if _, found := materialRank[m]; !found {
+				materialRank[m] = len(materialRank)
+			}
+		}
+	}
+
+	order := make([]int, 0, triangles) // new triangle -> old triangle
+	placed := make([]bool, triangles)
+	for _, g := range o.Groups {
+		begin := len(order)
+		for t := g.IndexBegin / 3; t < (g.IndexBegin+g.IndexCount)/3 && t < triangles; t++ {
+			order = append(order, t)
+			placed[t] = true
+		}
+		if perTriangle {
+			run := order[begin:]
+			sort.SliceStable(run, func(i, j int) bool {
+				return materialRank[o.MaterialIndex[run[i]]] < materialRank[o.MaterialIndex[run[j]]]
+			})
+		}
+		g.IndexBegin = 3 * begin
+	}
+	for t, p := range placed {
+		if !p {
+			order = append(order, t) // not in any group
+		}
+	}
+
+	permuteTriangles(o, order)
+}
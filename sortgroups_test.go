@@ -0,0 +1,86 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestSortGroupsByMaterial(t *testing.T) {
+	str := `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+g a
+usemtl red
+f 1 2 3
+g b
+usemtl blue
+f 1 3 4
+g c
+usemtl red
+f 2 3 4
+f 1 2 4
+`
+	o, err := NewObjFromBuf("sort", []byte(str), NewObjParserOptions(WithKeepFaces(true)))
+	if err != nil {
+		t.Fatalf("TestSortGroupsByMaterial: NewObjFromBuf: %v", err)
+	}
+	o.SortGroupsByMaterial()
+
+	var names []string
+	for _, g := range o.Groups {
+		if g.IndexCount > 0 {
+			names = append(names, g.Name+":"+g.Usemtl)
+		}
+	}
+	if want := []string{"a:red", "c:red", "b:blue"}; len(names) != 3 || names[0] != want[0] || names[1] != want[1] || names[2] != want[2] {
+		t.Errorf("TestSortGroupsByMaterial: groups: want=%v got=%v", want, names)
+	}
+	if want := []int{0, 1, 2, 1, 2, 3, 0, 1, 3, 0, 2, 3}; !sliceEqualInt(want, o.Indices) {
+		t.Errorf("TestSortGroupsByMaterial: indices: want=%v got=%v", want, o.Indices)
+	}
+	next := 0
+	for _, g := range o.Groups {
+		if g.IndexBegin != next {
+			t.Errorf("TestSortGroupsByMaterial: group %s begin=%d want=%d", g.Name, g.IndexBegin, next)
+		}
+		next += g.IndexCount
+	}
+	expectInt(t, "TestSortGroupsByMaterial: faces", 4, len(o.Faces))
+	for _, f := range o.Faces {
+		if f.IndexBegin < f.Group.IndexBegin || f.IndexBegin >= f.Group.IndexBegin+f.Group.IndexCount {
+			t.Errorf("TestSortGroupsByMaterial: face at %d outside group %s", f.IndexBegin, f.Group.Name)
+		}
+	}
+}
+
+func TestSortGroupsByMaterialPerTriangle(t *testing.T) {
+	str := `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+usemtl red
+f 1 2 3
+usemtl blue
+f 1 3 4
+usemtl red
+f 2 3 4
+`
+	o, err := NewObjFromBuf("sort", []byte(str), NewObjParserOptions(WithMaterialPerTriangle(true)))
+	if err != nil {
+		t.Fatalf("TestSortGroupsByMaterialPerTriangle: NewObjFromBuf: %v", err)
+	}
+	o.SortGroupsByMaterial()
+
+	var materials []string
+	for _, m := range o.MaterialIndex {
+		materials = append(materials, o.MaterialNames[m])
+	}
+	if want := []string{"red", "red", "blue"}; len(materials) != 3 || materials[0] != want[0] || materials[1] != want[1] || materials[2] != want[2] {
+		t.Errorf("TestSortGroupsByMaterialPerTriangle: materials: want=%v got=%v", want, materials)
+	}
+	if want := []int{0, 1, 2, 1, 2, 3, 0, 2, 3}; !sliceEqualInt(want, o.Indices) {
+		t.Errorf("TestSortGroupsByMaterialPerTriangle: indices: want=%v got=%v", want, o.Indices)
+	}
+}
@@ -0,0 +1,264 @@
+package gwob
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// STLOptions controls STL import.
+type STLOptions struct {
+	// Weld merges vertices with identical positions, so that triangles
+	// share indices instead of each having 3 unique vertices. Facet
+	// normals are then dropped, since a shared vertex cannot carry
+	// the normals of all its facets.
+	Weld bool
+}
+
+// stlHeaderSize is the binary STL header, followed by the triangle count.
+const stlHeaderSize = 80
+
+// stlTriangleSize is a binary STL triangle: normal, 3 vertices
+// and a 2-byte attribute count.
+const stlTriangleSize = 12*4 + 2
+
+// NewObjFromSTL reads a mesh from ASCII or binary STL, detected
+// from the content. Each ASCII solid becomes a group named after it,
+// while a binary STL is a single unnamed group. Facet normals are kept
+// as vertex normals, unless all of them are zero or options.Weld is set.
+// Options may be nil.
+func NewObjFromSTL(rd io.Reader, options *STLOptions) (*Obj, error) {
+	if options == nil {
+		options = &STLOptions{}
+	}
+
+	data, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newSTLBuilder(options.Weld)
+
+	if isBinarySTL(data) {
+		err = b.readBinary(data)
+	} else {
+		err = b.readASCII(string(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("NewObjFromSTL: %w", err)
+	}
+
+	return b.finish(), nil
+}
+
+// NewObjFromSTLFile reads a mesh from an STL file.
+func NewObjFromSTLFile(filename string, options *STLOptions) (*Obj, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewObjFromSTL(f, options)
+}
+
+// isBinarySTL detects binary STL by its size, which is fixed by the
+// triangle count. Checking the "solid" prefix alone is not enough,
+// since many binary files start their header with it.
+func isBinarySTL(data []byte) bool {
+	if len(data) < stlHeaderSize+4 {
+		return false
+	}
+	count := binary.LittleEndian.Uint32(data[stlHeaderSize:])
+	if int64(len(data)) == stlHeaderSize+4+int64(count)*stlTriangleSize {
+		return true
+	}
+	return !bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("solid"))
+}
+
+// stlBuilder accumulates STL facets into an Obj.
+type stlBuilder struct {
+	o       *Obj
+	group   *Group
+	weld    bool
+	welded  map[[3]float32]int // position to vertex index
+	normals []float32          // facet normal of each unwelded vertex
+	normal  bool               // some facet normal is not zero
+}
+
+func newSTLBuilder(weld bool) *stlBuilder {
+	b := &stlBuilder{o: &Obj{}, weld: weld}
+	if weld {
+		b.welded = map[[3]float32]int{}
+	}
+	return b
+}
+
+// beginGroup starts a group for a solid, reusing an empty current one.
+func (b *stlBuilder) beginGroup(name string) {
+	if b.group != nil && b.group.IndexCount == 0 {
+		b.group.Name = name
+		return
+	}
+	b.group = b.o.newGroup(name, "", len(b.o.Indices), 0)
+}
+
+// vertex gets the index for a facet corner.
+func (b *stlBuilder) vertex(pos [3]float32, normal [3]float32) int {
+	o := b.o
+	if b.weld {
+		if i, found := b.welded[pos]; found {
+			return i
+		}
+	}
+	i := len(o.Positions) / 3
+	o.Positions = append(o.Positions, pos[:]...)
+	if b.weld {
+		b.welded[pos] = i
+	} else {
+		b.normals = append(b.normals, normal[:]...)
+	}
+	return i
+}
+
+// facet adds a polygon facet, triangulated as a fan.
+func (b *stlBuilder) facet(normal [3]float32, corners [][3]float32) error {
+	if len(corners) < 3 {
+		return fmt.Errorf("facet with %d vertices", len(corners))
+	}
+	if b.group == nil {
+		b.beginGroup("")
+	}
+	if normal != [3]float32{} {
+		b.normal = true
+	}
+	first := b.vertex(corners[0], normal)
+	prev := b.vertex(corners[1], normal)
+	for _, c := range corners[2:] {
+		curr := b.vertex(c, normal)
+		pushIndex(b.group, b.o, first)
+		pushIndex(b.group, b.o, prev)
+		pushIndex(b.group, b.o, curr)
+		prev = curr
+	}
+	return nil
+}
+
+func (b *stlBuilder) readBinary(data []byte) error {
+	count := int(binary.LittleEndian.Uint32(data[stlHeaderSize:]))
+	body := data[stlHeaderSize+4:]
+	if len(body) < count*stlTriangleSize {
+		return fmt.Errorf("binary size=%d too short for triangles=%d", len(data), count)
+	}
+
+	vec := func(t []byte) [3]float32 {
+		return [3]float32{
+			math.Float32frombits(binary.LittleEndian.Uint32(t)),
+			math.Float32frombits(binary.LittleEndian.Uint32(t[4:])),
+			math.Float32frombits(binary.LittleEndian.Uint32(t[8:])),
+		}
+	}
+
+	corners := make([][3]float32, 3)
+	for i := 0; i < count; i++ {
+		t := body[i*stlTriangleSize:]
+		corners[0] = vec(t[12:])
+		corners[1] = vec(t[24:])
+		corners[2] = vec(t[36:])
+		if err := b.facet(vec(t), corners); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *stlBuilder) readASCII(text string) error {
+	var normal [3]float32
+	var corners [][3]float32
+	inFacet := false
+
+	for n, line := range strings.Split(text, "\n") {
+		lineNum := n + 1
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "solid":
+			b.beginGroup(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "solid")))
+		case "facet":
+			if inFacet {
+				return fmt.Errorf("line=%d: facet inside facet", lineNum)
+			}
+			normal = [3]float32{}
+			if len(fields) == 5 && fields[1] == "normal" {
+				v, err := parseSTLVector(fields[2:])
+				if err != nil {
+					return fmt.Errorf("line=%d: %w", lineNum, err)
+				}
+				normal = v
+			}
+			corners = corners[:0]
+			inFacet = true
+		case "vertex":
+			if !inFacet {
+				return fmt.Errorf("line=%d: vertex outside facet", lineNum)
+			}
+			if len(fields) != 4 {
+				return fmt.Errorf("line=%d: bad vertex: %q", lineNum, line)
+			}
+			v, err := parseSTLVector(fields[1:])
+			if err != nil {
+				return fmt.Errorf("line=%d: %w", lineNum, err)
+			}
+			corners = append(corners, v)
+		case "endfacet":
+			if !inFacet {
+				return fmt.Errorf("line=%d: endfacet outside facet", lineNum)
+			}
+			if err := b.facet(normal, corners); err != nil {
+				return fmt.Errorf("line=%d: %w", lineNum, err)
+			}
+			inFacet = false
+		case "outer", "endloop", "endsolid":
+		default:
+			return fmt.Errorf("line=%d: unexpected: %q", lineNum, line)
+		}
+	}
+
+	if inFacet {
+		return fmt.Errorf("missing endfacet")
+	}
+	return nil
+}
+
+func parseSTLVector(fields []string) ([3]float32, error) {
+	var v [3]float32
+	for i, f := range fields {
+		value, err := strconv.ParseFloat(f, 32)
+		if err != nil {
+			return v, err
+		}
+		v[i] = float32(value)
+	}
+	return v, nil
+}
+
+// finish builds the interleaved Obj.
+func (b *stlBuilder) finish() *Obj {
+	o := b.o
+	if b.normal && !b.weld {
+		o.Normals = b.normals
+		o.NormCoordFound = true
+	}
+	if o.Positions == nil {
+		setupStride(o)
+		return o
+	}
+	o.Interleave()
+	return o
+}
@@ -0,0 +1,109 @@
+package gwob
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+var squareSTL = `solid square
+  facet normal 0 0 1
+    outer loop
+      vertex 0 0 0
+      vertex 1 0 0
+      vertex 1 1 0
+    endloop
+  endfacet
+  facet normal 0 0 1
+    outer loop
+      vertex 1 1 0
+      vertex 0 1 0
+      vertex 0 0 0
+    endloop
+  endfacet
+endsolid square
+`
+
+func TestSTLASCII(t *testing.T) {
+	o, err := NewObjFromSTL(strings.NewReader(squareSTL), nil)
+	if err != nil {
+		t.Fatalf("TestSTLASCII: NewObjFromSTL: %v", err)
+	}
+	expectInt(t, "TestSTLASCII: elements", 6, o.NumberOfElements())
+	if !sliceEqualInt([]int{0, 1, 2, 3, 4, 5}, o.Indices) {
+		t.Errorf("TestSTLASCII: indices: %v", o.Indices)
+	}
+	if !o.NormCoordFound {
+		t.Errorf("TestSTLASCII: normals not found")
+	}
+	expectInt(t, "TestSTLASCII: groups", 1, len(o.Groups))
+	if o.Groups[0].Name != "square" {
+		t.Errorf("TestSTLASCII: group name: %q", o.Groups[0].Name)
+	}
+
+	o, err = NewObjFromSTL(strings.NewReader(squareSTL), &STLOptions{Weld: true})
+	if err != nil {
+		t.Fatalf("TestSTLASCII: weld: NewObjFromSTL: %v", err)
+	}
+	expectInt(t, "TestSTLASCII: weld: elements", 4, o.NumberOfElements())
+	if !sliceEqualInt([]int{0, 1, 2, 2, 3, 0}, o.Indices) {
+		t.Errorf("TestSTLASCII: weld: indices: %v", o.Indices)
+	}
+	if o.NormCoordFound {
+		t.Errorf("TestSTLASCII: weld: unexpected normals")
+	}
+}
+
+func TestSTLBinary(t *testing.T) {
+	triangles := [][12]float32{
+		{0, 0, 0, 0, 0, 0, 1, 0, 0, 1, 1, 0},
+		{0, 0, 0, 1, 1, 0, 0, 1, 0, 0, 0, 0},
+	}
+	var buf bytes.Buffer
+	buf.WriteString("solid but binary")
+	buf.Write(make([]byte, stlHeaderSize-buf.Len()))
+	binary.Write(&buf, binary.LittleEndian, uint32(len(triangles)))
+	for _, tri := range triangles {
+		for _, f := range tri {
+			binary.Write(&buf, binary.LittleEndian, math.Float32bits(f))
+		}
+		binary.Write(&buf, binary.LittleEndian, uint16(0))
+	}
+
+	o, err := NewObjFromSTL(bytes.NewReader(buf.Bytes()), &STLOptions{Weld: true})
+	if err != nil {
+		t.Fatalf("TestSTLBinary: NewObjFromSTL: %v", err)
+	}
+	expectInt(t, "TestSTLBinary: elements", 4, o.NumberOfElements())
+	if !sliceEqualInt([]int{0, 1, 2, 2, 3, 0}, o.Indices) {
+		t.Errorf("TestSTLBinary: indices: %v", o.Indices)
+	}
+	x, y, z := o.VertexCoordinates(3)
+	if !sliceEqualFloat([]float32{0, 1, 0}, []float32{x, y, z}) {
+		t.Errorf("TestSTLBinary: vertex 3: %v %v %v", x, y, z)
+	}
+
+	o, err = NewObjFromSTL(bytes.NewReader(buf.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("TestSTLBinary: no weld: NewObjFromSTL: %v", err)
+	}
+	expectInt(t, "TestSTLBinary: no weld: elements", 6, o.NumberOfElements())
+	if o.NormCoordFound {
+		t.Errorf("TestSTLBinary: zero normals should be dropped")
+	}
+}
+
+func TestSTLBad(t *testing.T) {
+	bad := []string{
+		"solid x\nvertex 0 0 0\nendsolid x\n",
+		"solid x\nfacet normal 0 0 1\nouter loop\nvertex 0 0 0\nvertex 1 0 0\nendloop\nendfacet\nendsolid x\n",
+		"solid x\nfacet normal 0 0 1\nouter loop\nvertex 0 0 zero\n",
+	}
+	for _, s := range bad {
+		if _, err := NewObjFromSTL(strings.NewReader(s), nil); err == nil {
+			t.Errorf("TestSTLBad: unexpected success: %q", s)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package gwob
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ObjWriter writes OBJ statements as they are produced, so that generated
+// geometry can be exported without first building a whole Obj in memory.
+// Write errors are sticky: after the first one every method returns it.
+type ObjWriter struct {
+	ow      *objWriter
+	bw      *bufio.Writer
+	options *WriteOptions
+
+	vertices  int // v statements written
+	texCoords int // vt statements written
+	normals   int // vn statements written
+}
+
+// NewObjWriter creates an ObjWriter formatting output according to
+// the write options, which may be nil. Options only meaningful for
+// a whole Obj, like Compact and Polygons, are ignored.
+func NewObjWriter(w io.Writer, options *WriteOptions) *ObjWriter {
+	if options == nil {
+		options = &WriteOptions{}
+	}
+	bw := bufio.NewWriter(w)
+	return &ObjWriter{
+		ow:      newObjWriter(bw, nil, options),
+		bw:      bw,
+		options: options,
+	}
+}
+
+// WriteHeader writes the header comment and the material libs.
+func (w *ObjWriter) WriteHeader(mtllibs ...string) error {
+	w.ow.header(w.options)
+	if len(mtllibs) > 0 {
+		w.ow.statement("mtllib ", strings.Join(mtllibs, " "))
+	}
+	return w.ow.err
+}
+
+// WriteVertex writes a vertex position, returning its index.
+func (w *ObjWriter) WriteVertex(x, y, z float32) (int, error) {
+	w.ow.line("v")
+	w.ow.floats(x, y, z)
+	w.ow.end()
+	w.vertices++
+	return w.vertices - 1, w.ow.err
+}
+
+// WriteTexCoord writes a texture coordinate, returning its index.
+func (w *ObjWriter) WriteTexCoord(u, v float32) (int, error) {
+	w.ow.line("vt")
+	w.ow.texFloats(u, v)
+	w.ow.end()
+	w.texCoords++
+	return w.texCoords - 1, w.ow.err
+}
+
+// WriteNormal writes a vertex normal, returning its index.
+func (w *ObjWriter) WriteNormal(x, y, z float32) (int, error) {
+	w.ow.line("vn")
+	w.ow.floats(x, y, z)
+	w.ow.end()
+	w.normals++
+	return w.normals - 1, w.ow.err
+}
+
+// BeginGroup starts a new group, with an optional material.
+func (w *ObjWriter) BeginGroup(name, usemtl string) error {
+	w.ow.statement("g ", name)
+	if usemtl != "" {
+		w.ow.statement("usemtl ", usemtl)
+	}
+	return w.ow.err
+}
+
+// WriteTriangle writes a triangle from vertex indices.
+// Like ToWriter, each index also refers to the texture coordinate
+// and the normal of the same index, when any were written.
+func (w *ObjWriter) WriteTriangle(a, b, c int) error {
+	return w.WriteFace(
+		w.unified(a),
+		w.unified(b),
+		w.unified(c),
+	)
+}
+
+func (w *ObjWriter) unified(i int) FaceIndex {
+	f := FaceIndex{V: i, T: -1, N: -1}
+	if w.texCoords > 0 {
+		f.T = i
+	}
+	if w.normals > 0 {
+		f.N = i
+	}
+	return f
+}
+
+// WriteFace writes a polygon face from v/vt/vn indices.
+// Negative T or N omit the texture coordinate or the normal.
+func (w *ObjWriter) WriteFace(corners ...FaceIndex) error {
+	if w.ow.err != nil {
+		return w.ow.err
+	}
+	if len(corners) < 3 {
+		return fmt.Errorf("ObjWriter.WriteFace: bad face size=%d", len(corners))
+	}
+	for _, f := range corners {
+		if f.V < 0 || f.V >= w.vertices || f.T >= w.texCoords || f.N >= w.normals {
+			return fmt.Errorf("ObjWriter.WriteFace: index out of range: %+v", f)
+		}
+	}
+
+	w.ow.line("f")
+	for _, f := range corners {
+		w.ow.text(" ")
+		w.ow.index(f.V, nil, w.vertices)
+		if f.T >= 0 {
+			w.ow.text("/")
+			w.ow.index(f.T, nil, w.texCoords)
+		} else if f.N >= 0 {
+			w.ow.text("/")
+		}
+		if f.N >= 0 {
+			w.ow.text("/")
+			w.ow.index(f.N, nil, w.normals)
+		}
+	}
+	w.ow.end()
+	return w.ow.err
+}
+
+// Close flushes buffered output. It does not close the underlying writer.
+func (w *ObjWriter) Close() error {
+	if w.ow.err != nil {
+		return w.ow.err
+	}
+	w.ow.err = w.bw.Flush()
+	return w.ow.err
+}
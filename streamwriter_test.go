@@ -0,0 +1,54 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestObjWriter(t *testing.T) {
+	for _, relative := range []bool{false, true} {
+		var buf bytes.Buffer
+		w := NewObjWriter(&buf, &WriteOptions{RelativeIndices: relative})
+
+		if err := w.WriteHeader("square.mtl"); err != nil {
+			t.Fatalf("TestObjWriter: WriteHeader: %v", err)
+		}
+		if err := w.BeginGroup("square", "red"); err != nil {
+			t.Fatalf("TestObjWriter: BeginGroup: %v", err)
+		}
+		for _, v := range [][3]float32{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}} {
+			if _, err := w.WriteVertex(v[0], v[1], v[2]); err != nil {
+				t.Fatalf("TestObjWriter: WriteVertex: %v", err)
+			}
+			if _, err := w.WriteNormal(0, 0, 1); err != nil {
+				t.Fatalf("TestObjWriter: WriteNormal: %v", err)
+			}
+		}
+		if err := w.WriteTriangle(0, 1, 2); err != nil {
+			t.Fatalf("TestObjWriter: WriteTriangle: %v", err)
+		}
+		if err := w.WriteTriangle(2, 3, 0); err != nil {
+			t.Fatalf("TestObjWriter: WriteTriangle: %v", err)
+		}
+		if err := w.WriteTriangle(2, 3, 4); err == nil {
+			t.Errorf("TestObjWriter: WriteTriangle: out of range index accepted")
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("TestObjWriter: Close: %v", err)
+		}
+
+		o, err := NewObjFromBuf("square", buf.Bytes(), NewObjParserOptions(WithStrict(true)))
+		if err != nil {
+			t.Fatalf("TestObjWriter: relative=%v: NewObjFromBuf: %v", relative, err)
+		}
+		if !sliceEqualInt([]int{0, 1, 2, 2, 3, 0}, o.Indices) {
+			t.Errorf("TestObjWriter: relative=%v: indices: got=%v", relative, o.Indices)
+		}
+		if o.Mtllib != "square.mtl" || len(o.Groups) != 1 || o.Groups[0].Usemtl != "red" {
+			t.Errorf("TestObjWriter: relative=%v: mtllib=%s groups=%d", relative, o.Mtllib, len(o.Groups))
+		}
+		if !o.NormCoordFound {
+			t.Errorf("TestObjWriter: relative=%v: normals not found", relative)
+		}
+	}
+}
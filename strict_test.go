@@ -0,0 +1,44 @@
+package gwob
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStrict(t *testing.T) {
+
+	table := []struct {
+		input string
+		kind  error
+	}{
+		{"v 0 0 0\nv 1 0 0\nv 1 1 0\nf 1 2 3\n", nil},
+		{"v 0 0 0\nbogus\n", ErrUnexpected},
+		{"v 0 0 0\nv 1 0 0\nv 1 1 0\nf 1 2 4\n", ErrIndexOutOfRange},
+		{"v 0 0 0\nv 1 0 0\nv 1 1 0\nf 1 2\n", ErrBadFace},
+		{"v 0 x 0\n", ErrBadCoord},
+		{"s maybe\n", ErrBadValue},
+	}
+
+	for _, data := range table {
+		_, errLoose := NewObjFromBuf("strict", []byte(data.input), NewObjParserOptions())
+		if errLoose != nil {
+			t.Errorf("TestStrict: non-strict: input=[%s]: %v", data.input, errLoose)
+		}
+
+		_, err := NewObjFromBuf("strict", []byte(data.input), NewObjParserOptions(WithStrict(true)))
+		if data.kind == nil {
+			if err != nil {
+				t.Errorf("TestStrict: input=[%s]: unexpected error: %v", data.input, err)
+			}
+			continue
+		}
+		if !errors.Is(err, data.kind) {
+			t.Errorf("TestStrict: input=[%s]: want %v got %v", data.input, data.kind, err)
+		}
+	}
+
+	_, errLib := ReadMaterialLibFromBuf([]byte("newmtl a\nKd 1 x 1\nKa 1 1 1\n"), NewObjParserOptions(WithStrict(true)))
+	if !errors.Is(errLib, ErrBadValue) {
+		t.Errorf("TestStrict: lib: want %v got %v", ErrBadValue, errLib)
+	}
+}
@@ -0,0 +1,159 @@
+package gwob
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// threeGeometry is the three.js JSON object format for a BufferGeometry,
+// as loaded by THREE.BufferGeometryLoader.
+type threeGeometry struct {
+	Metadata threeMetadata `json:"metadata"`
+	Type     string        `json:"type"`
+	Data     threeData     `json:"data"`
+	UserData *threeUser    `json:"userData,omitempty"`
+}
+
+type threeMetadata struct {
+	Version   float64 `json:"version"`
+	Type      string  `json:"type"`
+	Generator string  `json:"generator"`
+}
+
+type threeData struct {
+	Attributes map[string]threeAttribute `json:"attributes"`
+	Index      *threeIndex               `json:"index,omitempty"`
+	Groups     []threeGroup              `json:"groups,omitempty"`
+}
+
+type threeAttribute struct {
+	ItemSize   int       `json:"itemSize"`
+	Type       string    `json:"type"`
+	Array      []float32 `json:"array"`
+	Normalized bool      `json:"normalized"`
+}
+
+type threeIndex struct {
+	Type  string `json:"type"`
+	Array []int  `json:"array"`
+}
+
+type threeGroup struct {
+	Start         int `json:"start"`
+	Count         int `json:"count"`
+	MaterialIndex int `json:"materialIndex"`
+}
+
+// threeUser lists the material names, in materialIndex order.
+type threeUser struct {
+	Materials []string `json:"materials"`
+}
+
+// ToThreeJS writes the Obj as three.js BufferGeometry JSON, with
+// position, normal, uv and color attributes, the index and one group
+// per run of triangles sharing a material. Group materialIndex values
+// refer to the material names listed, in order of first use, in
+// userData.materials (empty for triangles without usemtl), so the
+// viewer can build its material array from the MTL lib.
+func (o *Obj) ToThreeJS(w io.Writer) error {
+	if len(o.Indices)%3 != 0 {
+		return fmt.Errorf("ToThreeJS: index count=%d must be a multiple of 3", len(o.Indices))
+	}
+
+	strides := o.NumberOfElements()
+	attributes := map[string]threeAttribute{}
+
+	position := make([]float32, 0, 3*strides)
+	for s := 0; s < strides; s++ {
+		x, y, z := o.VertexCoordinates(s)
+		position = append(position, x, y, z)
+	}
+	attributes["position"] = threeFloats(position, 3)
+
+	if o.NormCoordFound {
+		normal := make([]float32, 0, 3*strides)
+		for s := 0; s < strides; s++ {
+			normal = append(normal, o.vertexNormal(s)...)
+		}
+		attributes["normal"] = threeFloats(normal, 3)
+	}
+
+	if o.TextCoordFound {
+		uv := make([]float32, 0, 2*strides)
+		for s := 0; s < strides; s++ {
+			uv = append(uv, o.vertexTexCoord(s)...)
+		}
+		attributes["uv"] = threeFloats(uv, 2)
+	}
+
+	if o.ColorFound {
+		color := make([]float32, 0, 3*strides)
+		for s := 0; s < strides; s++ {
+			r, g, b := o.VertexColor(s)
+			color = append(color, r, g, b)
+		}
+		attributes["color"] = threeFloats(color, 3)
+	}
+
+	indexType := "Uint16Array"
+	if strides > 65535 {
+		indexType = "Uint32Array"
+	}
+
+	geom := threeGeometry{
+		Metadata: threeMetadata{Version: 4.5, Type: "BufferGeometry", Generator: "gwob"},
+		Type:     "BufferGeometry",
+		Data: threeData{
+			Attributes: attributes,
+			Index:      &threeIndex{Type: indexType, Array: o.Indices},
+		},
+	}
+	if geom.Data.Index.Array == nil {
+		geom.Data.Index.Array = []int{}
+	}
+
+	// groups by material
+	materials := map[string]int{}
+	var names []string
+	for _, g := range o.Groups {
+		for t := g.IndexBegin / 3; t < (g.IndexBegin+g.IndexCount)/3; t++ {
+			usemtl := g.Usemtl
+			if t < len(o.MaterialIndex) && o.MaterialIndex[t] >= 0 {
+				usemtl = o.MaterialNames[o.MaterialIndex[t]]
+			}
+			m, found := materials[usemtl]
+			if !found {
+				m = len(names)
+				names = append(names, usemtl)
+				materials[usemtl] = m
+			}
+			groups := geom.Data.Groups
+			if last := len(groups) - 1; last >= 0 && groups[last].MaterialIndex == m && groups[last].Start+groups[last].Count == 3*t {
+				groups[last].Count += 3
+				continue
+			}
+			geom.Data.Groups = append(groups, threeGroup{Start: 3 * t, Count: 3, MaterialIndex: m})
+		}
+	}
+	if len(names) > 0 {
+		geom.UserData = &threeUser{Materials: names}
+	}
+
+	return json.NewEncoder(w).Encode(geom)
+}
+
+// ToThreeJSFile saves the Obj as three.js BufferGeometry JSON.
+func (o *Obj) ToThreeJSFile(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return o.ToThreeJS(f)
+}
+
+func threeFloats(array []float32, itemSize int) threeAttribute {
+	return threeAttribute{ItemSize: itemSize, Type: "Float32Array", Array: array}
+}
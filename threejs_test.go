@@ -0,0 +1,77 @@
+package gwob
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestThreeJS(t *testing.T) {
+	table := []struct {
+		perTriangle bool
+		groups      []threeGroup
+		materials   []string
+	}{
+		{
+			// the 1st triangle takes the group usemtl
+			perTriangle: false,
+			groups: []threeGroup{
+				{Start: 0, Count: 9, MaterialIndex: 0},
+				{Start: 9, Count: 3, MaterialIndex: 1},
+				{Start: 12, Count: 6, MaterialIndex: 0},
+			},
+			materials: []string{"red", "blue"},
+		},
+		{
+			perTriangle: true,
+			groups: []threeGroup{
+				{Start: 0, Count: 3, MaterialIndex: 0},
+				{Start: 3, Count: 6, MaterialIndex: 1},
+				{Start: 9, Count: 3, MaterialIndex: 2},
+				{Start: 12, Count: 6, MaterialIndex: 1},
+			},
+			materials: []string{"", "red", "blue"},
+		},
+	}
+
+	for _, data := range table {
+		perTriangle := data.perTriangle
+		options := NewObjParserOptions(WithMaterialPerTriangle(perTriangle))
+		o, err := NewObjFromBuf("materialsObj", []byte(materialsObj), options)
+		if err != nil {
+			t.Fatalf("TestThreeJS: NewObjFromBuf: %v", err)
+		}
+
+		var buf bytes.Buffer
+		if errWrite := o.ToThreeJS(&buf); errWrite != nil {
+			t.Fatalf("TestThreeJS: ToThreeJS: %v", errWrite)
+		}
+
+		var geom threeGeometry
+		if errJSON := json.Unmarshal(buf.Bytes(), &geom); errJSON != nil {
+			t.Fatalf("TestThreeJS: unmarshal: %v", errJSON)
+		}
+		if geom.Type != "BufferGeometry" {
+			t.Errorf("TestThreeJS: type: %q", geom.Type)
+		}
+		position := geom.Data.Attributes["position"]
+		expectInt(t, "TestThreeJS: position item size", 3, position.ItemSize)
+		expectInt(t, "TestThreeJS: positions", 3*o.NumberOfElements(), len(position.Array))
+		if _, found := geom.Data.Attributes["normal"]; found {
+			t.Errorf("TestThreeJS: unexpected normal attribute")
+		}
+		if !sliceEqualInt(o.Indices, geom.Data.Index.Array) {
+			t.Errorf("TestThreeJS: index: want=%v got=%v", o.Indices, geom.Data.Index.Array)
+		}
+
+		wantGroups := data.groups
+		if !reflect.DeepEqual(wantGroups, geom.Data.Groups) {
+			t.Errorf("TestThreeJS: perTriangle=%v groups: want=%v got=%v", perTriangle, wantGroups, geom.Data.Groups)
+		}
+		wantMaterials := data.materials
+		if geom.UserData == nil || !reflect.DeepEqual(wantMaterials, geom.UserData.Materials) {
+			t.Errorf("TestThreeJS: perTriangle=%v materials: want=%v got=%v", perTriangle, wantMaterials, geom.UserData)
+		}
+	}
+}
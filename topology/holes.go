@@ -0,0 +1,176 @@
+package topology
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/udhos/gwob"
+)
+
+// BorderVertices lists the vertices along a border loop from Borders.
+func (m *Mesh) BorderVertices(loop []int) []int {
+	list := make([]int, len(loop))
+	for i, h := range loop {
+		list[i] = m.HalfEdges[h].Origin
+	}
+	return list
+}
+
+// Perimeter gets the length of a border loop from Borders.
+func (m *Mesh) Perimeter(loop []int) float64 {
+	var sum float64
+	for _, h := range loop {
+		sum += length(m.position(m.Dest(h)), m.position(m.HalfEdges[h].Origin))
+	}
+	return sum
+}
+
+func (m *Mesh) position(v int) vec {
+	p := m.Vertices[v].Position
+	return vec{float64(p[0]), float64(p[1]), float64(p[2])}
+}
+
+type vec [3]float64
+
+func sub(a, b vec) vec { return vec{a[0] - b[0], a[1] - b[1], a[2] - b[2]} }
+
+func dot(a, b vec) float64 { return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] }
+
+func length(a, b vec) float64 {
+	d := sub(a, b)
+	return math.Sqrt(dot(d, d))
+}
+
+// angle gets the angle at b between a and c.
+func angle(a, b, c vec) float64 {
+	u, v := sub(a, b), sub(c, b)
+	l := math.Sqrt(dot(u, u) * dot(v, v))
+	if l == 0 {
+		return 0
+	}
+	return math.Acos(max(-1, min(1, dot(u, v)/l)))
+}
+
+// connected reports whether an edge joins vertices a and b.
+func (m *Mesh) connected(a, b int) bool {
+	for _, h := range m.Outgoing(a) {
+		if m.Dest(h) == b {
+			return true
+		}
+	}
+	return false
+}
+
+// FillHole closes a border loop from Borders with triangles, returning
+// how many were added. It repeatedly cuts off the sharpest corner of the
+// hole, avoiding edges already in the mesh. The new faces take the
+// Triangle of a face next to the hole, so Obj puts them in its group,
+// and the strides of the faces around the hole.
+func (m *Mesh) FillHole(loop []int) (int, error) {
+	if len(loop) < 3 {
+		return 0, fmt.Errorf("topology: border loop too short: %d", len(loop))
+	}
+	for _, h := range loop {
+		if !m.IsBorder(h) {
+			return 0, fmt.Errorf("topology: half-edge=%d not on border", h)
+		}
+	}
+
+	// plan the cuts first, so that a failure leaves the mesh unchanged
+	poly := m.BorderVertices(loop)
+	added := map[[2]int]bool{}
+	var cuts []int // corner position in the shrinking polygon
+	for len(poly) > 3 {
+		best := -1
+		bestAngle := math.Inf(1)
+		for i, v := range poly {
+			prev := poly[(i+len(poly)-1)%len(poly)]
+			next := poly[(i+1)%len(poly)]
+			if added[[2]int{min(prev, next), max(prev, next)}] || m.connected(prev, next) {
+				continue
+			}
+			if a := angle(m.position(prev), m.position(v), m.position(next)); a < bestAngle {
+				best, bestAngle = i, a
+			}
+		}
+		if best < 0 {
+			return 0, fmt.Errorf("topology: cannot fill border loop at vertex=%d", poly[0])
+		}
+		prev := poly[(best+len(poly)-1)%len(poly)]
+		next := poly[(best+1)%len(poly)]
+		added[[2]int{min(prev, next), max(prev, next)}] = true
+		cuts = append(cuts, best)
+		poly = append(poly[:best], poly[best+1:]...)
+	}
+
+	triangle := m.Faces[m.HalfEdges[m.HalfEdges[loop[0]].Twin].Face].Triangle
+
+	// border half-edges take the strides of the neighbor corners
+	hs := append([]int(nil), loop...)
+	for _, h := range hs {
+		m.HalfEdges[h].Stride = m.HalfEdges[m.HalfEdges[m.HalfEdges[h].Twin].Next].Stride
+	}
+
+	for _, i := range cuts {
+		n := len(hs)
+		before := hs[(i+n-2)%n]
+		a := hs[(i+n-1)%n] // prev -> corner
+		b := hs[i]         // corner -> next
+		after := hs[(i+1)%n]
+		prev := m.HalfEdges[a].Origin
+		next := m.HalfEdges[after].Origin
+
+		f := len(m.Faces)
+		c := len(m.HalfEdges) // next -> prev, in the face
+		border := c + 1       // prev -> next, on the shrunk border
+		m.Faces = append(m.Faces, Face{HalfEdge: a, Triangle: triangle})
+		m.HalfEdges = append(m.HalfEdges,
+			HalfEdge{Origin: next, Twin: border, Next: a, Face: f, Stride: m.HalfEdges[after].Stride},
+			HalfEdge{Origin: prev, Twin: c, Next: after, Face: -1, Stride: m.HalfEdges[a].Stride},
+		)
+		m.HalfEdges[a].Next = b
+		m.HalfEdges[a].Face = f
+		m.HalfEdges[b].Next = c
+		m.HalfEdges[b].Face = f
+		m.HalfEdges[before].Next = border
+		m.Vertices[prev].HalfEdge = border
+		m.Vertices[m.HalfEdges[b].Origin].HalfEdge = b
+
+		hs[(i+n-1)%n] = border
+		hs = append(hs[:i], hs[i+1:]...)
+	}
+
+	f := len(m.Faces)
+	m.Faces = append(m.Faces, Face{HalfEdge: hs[0], Triangle: triangle})
+	for _, h := range hs {
+		m.HalfEdges[h].Face = f
+	}
+
+	return len(cuts) + 1, nil
+}
+
+// FillHoles closes the holes of o whose perimeter is at most
+// maxPerimeter, or all of them for zero, returning the filled copy of o
+// and the number of triangles added. See Mesh.FillHole and Mesh.Obj.
+func FillHoles(o *gwob.Obj, maxPerimeter float64) (*gwob.Obj, int, error) {
+	m, err := New(o)
+	if err != nil {
+		return nil, 0, err
+	}
+	var count int
+	for _, loop := range m.Borders() {
+		if maxPerimeter > 0 && m.Perimeter(loop) > maxPerimeter {
+			continue
+		}
+		added, err := m.FillHole(loop)
+		if err != nil {
+			return nil, 0, err
+		}
+		count += added
+	}
+	filled, err := m.Obj(o)
+	if err != nil {
+		return nil, 0, err
+	}
+	return filled, count, nil
+}
@@ -0,0 +1,104 @@
+package topology
+
+import (
+	"testing"
+)
+
+// openBoxObj is a unit cube missing its top quad, with a small hole on
+// a side: a triangle missing from the bottom.
+var openBoxObj = `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+v 0 0 1
+v 1 0 1
+v 1 1 1
+v 0 1 1
+g box
+f 1 4 3
+f 1 2 6 5
+f 2 3 7 6
+f 3 4 8 7
+f 4 1 5 8
+`
+
+func TestBorders(t *testing.T) {
+	m, err := New(parse(t, "TestBorders", openBoxObj))
+	if err != nil {
+		t.Fatalf("TestBorders: New: %v", err)
+	}
+	checkMesh(t, "TestBorders", m)
+	borders := m.Borders()
+	if len(borders) != 2 {
+		t.Fatalf("TestBorders: borders=%d", len(borders))
+	}
+	var perimeters []float64
+	for _, loop := range borders {
+		perimeters = append(perimeters, m.Perimeter(loop))
+	}
+	small, large := min(perimeters[0], perimeters[1]), max(perimeters[0], perimeters[1])
+	if d := small - (2 + 1.4142135); d > 1e-6 || d < -1e-6 {
+		t.Errorf("TestBorders: small perimeter=%v", small)
+	}
+	if large != 4 {
+		t.Errorf("TestBorders: large perimeter=%v", large)
+	}
+}
+
+func TestFillHoles(t *testing.T) {
+	o := parse(t, "TestFillHoles", openBoxObj)
+
+	table := []struct {
+		name         string
+		maxPerimeter float64
+		added        int
+		watertight   bool
+	}{
+		{"small", 3.5, 1, false},
+		{"all", 0, 3, true},
+	}
+	for _, data := range table {
+		filled, added, err := FillHoles(o, data.maxPerimeter)
+		if err != nil {
+			t.Fatalf("TestFillHoles: %s: %v", data.name, err)
+		}
+		if added != data.added {
+			t.Errorf("TestFillHoles: %s: added=%d want=%d", data.name, added, data.added)
+		}
+		if len(filled.Indices) != len(o.Indices)+3*added {
+			t.Errorf("TestFillHoles: %s: indices=%d", data.name, len(filled.Indices))
+		}
+		if g := filled.Groups[0]; g.IndexCount != len(filled.Indices) {
+			t.Errorf("TestFillHoles: %s: group count=%d", data.name, g.IndexCount)
+		}
+		report := filled.CheckManifold()
+		if report.Watertight() != data.watertight {
+			t.Errorf("TestFillHoles: %s: watertight=%v", data.name, report.Watertight())
+		}
+		m, err := New(filled)
+		if err != nil {
+			t.Fatalf("TestFillHoles: %s: New: %v", data.name, err) // winding is consistent
+		}
+		checkMesh(t, "TestFillHoles: "+data.name, m)
+	}
+}
+
+func TestFillHoleMesh(t *testing.T) {
+	m, err := New(parse(t, "TestFillHoleMesh", openBoxObj))
+	if err != nil {
+		t.Fatalf("TestFillHoleMesh: New: %v", err)
+	}
+	for _, loop := range m.Borders() {
+		if _, err := m.FillHole(loop); err != nil {
+			t.Fatalf("TestFillHoleMesh: FillHole: %v", err)
+		}
+		checkMesh(t, "TestFillHoleMesh", m)
+	}
+	if len(m.Borders()) != 0 || len(m.Faces) != 12 {
+		t.Errorf("TestFillHoleMesh: borders=%d faces=%d", len(m.Borders()), len(m.Faces))
+	}
+	if _, err := m.FillHole([]int{0, 1, 2}); err == nil {
+		t.Errorf("TestFillHoleMesh: filled a face")
+	}
+}
@@ -0,0 +1,347 @@
+/*
+Package topology builds half-edge meshes from gwob OBJ data.
+
+A half-edge mesh stores, for every directed edge of every face, its twin
+on the neighbor face and the next half-edge around its own face. This
+makes local queries and edits, like walking around a vertex, following a
+hole border or collapsing an edge, simple and cheap, where the flat index
+buffer of gwob.Obj would require a search over all the triangles.
+
+Vertices are identified by position, as in gwob.Obj.Adjacency, so texture
+and normal seams do not cut the surface. Each half-edge remembers the Obj
+stride of its face corner, so that the attributes survive the round trip
+through Mesh.Obj.
+*/
+package topology
+
+import (
+	"fmt"
+
+	"github.com/udhos/gwob"
+)
+
+// HalfEdge is a directed edge of a face, or of a border.
+type HalfEdge struct {
+	Origin int // vertex the half-edge leaves from
+	Twin   int // opposite half-edge
+	Next   int // next half-edge around the face, or along the border
+	Face   int // face on the left, -1 for border half-edges
+	Stride int // Obj stride of the face corner at Origin, -1 for none
+}
+
+// Vertex is a mesh vertex.
+type Vertex struct {
+	Position [3]float32
+	HalfEdge int // an outgoing half-edge, the border one if any; -1 if isolated
+	Stride   int // first Obj stride at the position, -1 for none
+}
+
+// Face is a mesh face. A face whose HalfEdge is negative has been deleted.
+type Face struct {
+	HalfEdge int // a half-edge of the face
+	Triangle int // Obj triangle the face comes from, or -1
+}
+
+// Mesh is a half-edge mesh.
+//
+// Faces not on the surface border are paired with a twin face half-edge.
+// Border half-edges have Face -1 and are linked by Next along the
+// border, in the opposite direction of the faces, so that every half-edge
+// has a twin and a next.
+type Mesh struct {
+	Vertices  []Vertex
+	HalfEdges []HalfEdge
+	Faces     []Face
+}
+
+// New builds the half-edge mesh for the triangles of o. Triangles
+// repeating a position have no proper edges and are left out. It fails
+// on non-manifold edges, shared by more than two triangles or crossed in
+// the same direction by two triangles of inconsistent winding.
+func New(o *gwob.Obj) (*Mesh, error) {
+	if len(o.Indices)%3 != 0 {
+		return nil, fmt.Errorf("topology: index count=%d must be a multiple of 3", len(o.Indices))
+	}
+
+	adj := o.Adjacency()
+	m := &Mesh{}
+
+	vertexOf := make([]int, len(adj.Position)) // position stride -> vertex
+	for s, p := range adj.Position {
+		if p != s {
+			vertexOf[s] = vertexOf[p]
+			continue
+		}
+		x, y, z := o.VertexCoordinates(s)
+		vertexOf[s] = len(m.Vertices)
+		m.Vertices = append(m.Vertices, Vertex{
+			Position: [3]float32{x, y, z},
+			HalfEdge: -1,
+			Stride:   s,
+		})
+	}
+
+	directed := map[[2]int]int{} // (from, to) vertices -> half-edge
+	for t := 0; t < len(o.Indices)/3; t++ {
+		corner := o.Indices[3*t : 3*t+3]
+		var v [3]int
+		for c, s := range corner {
+			v[c] = vertexOf[s]
+		}
+		if v[0] == v[1] || v[1] == v[2] || v[2] == v[0] {
+			continue
+		}
+		f := len(m.Faces)
+		first := len(m.HalfEdges)
+		m.Faces = append(m.Faces, Face{HalfEdge: first, Triangle: t})
+		for c := 0; c < 3; c++ {
+			key := [2]int{v[c], v[(c+1)%3]}
+			if _, found := directed[key]; found {
+				return nil, fmt.Errorf("topology: non-manifold edge %d-%d at triangle=%d", corner[c], corner[(c+1)%3], t)
+			}
+			h := first + c
+			directed[key] = h
+			m.HalfEdges = append(m.HalfEdges, HalfEdge{
+				Origin: v[c],
+				Twin:   -1,
+				Next:   first + (c+1)%3,
+				Face:   f,
+				Stride: corner[c],
+			})
+			if twin, found := directed[[2]int{key[1], key[0]}]; found {
+				m.HalfEdges[h].Twin = twin
+				m.HalfEdges[twin].Twin = h
+			}
+			m.Vertices[v[c]].HalfEdge = h
+		}
+	}
+
+	// close the borders with face-less half-edges
+	faceHalfEdges := len(m.HalfEdges)
+	borderFrom := map[int][]int{} // vertex -> unlinked border half-edges leaving it
+	for h := 0; h < faceHalfEdges; h++ {
+		if m.HalfEdges[h].Twin >= 0 {
+			continue
+		}
+		b := len(m.HalfEdges)
+		from := m.HalfEdges[m.HalfEdges[h].Next].Origin
+		m.HalfEdges[h].Twin = b
+		m.HalfEdges = append(m.HalfEdges, HalfEdge{
+			Origin: from,
+			Twin:   h,
+			Next:   -1,
+			Face:   -1,
+			Stride: -1,
+		})
+		borderFrom[from] = append(borderFrom[from], b)
+		m.Vertices[from].HalfEdge = b
+	}
+	for b := faceHalfEdges; b < len(m.HalfEdges); b++ {
+		to := m.HalfEdges[m.HalfEdges[b].Twin].Origin
+		list := borderFrom[to]
+		m.HalfEdges[b].Next = list[0]
+		borderFrom[to] = list[1:]
+	}
+
+	return m, nil
+}
+
+// Prev gets the half-edge before h around its face or border.
+func (m *Mesh) Prev(h int) int {
+	p := h
+	for {
+		next := m.HalfEdges[p].Next
+		if next == h {
+			return p
+		}
+		p = next
+	}
+}
+
+// Dest gets the vertex half-edge h points to.
+func (m *Mesh) Dest(h int) int {
+	return m.HalfEdges[m.HalfEdges[h].Twin].Origin
+}
+
+// IsBorder reports whether half-edge h lies outside the surface.
+func (m *Mesh) IsBorder(h int) bool {
+	return m.HalfEdges[h].Face < 0
+}
+
+// FaceHalfEdges lists the half-edges around face f.
+func (m *Mesh) FaceHalfEdges(f int) []int {
+	return m.loop(m.Faces[f].HalfEdge)
+}
+
+// FaceVertices lists the vertices around face f.
+func (m *Mesh) FaceVertices(f int) []int {
+	list := m.FaceHalfEdges(f)
+	for i, h := range list {
+		list[i] = m.HalfEdges[h].Origin
+	}
+	return list
+}
+
+func (m *Mesh) loop(start int) []int {
+	var list []int
+	for h := start; ; {
+		list = append(list, h)
+		h = m.HalfEdges[h].Next
+		if h == start {
+			return list
+		}
+	}
+}
+
+// Outgoing lists the half-edges leaving vertex v, walking around it
+// from v.HalfEdge. On a vertex where several surface sheets meet, only
+// the sheet of v.HalfEdge is visited.
+func (m *Mesh) Outgoing(v int) []int {
+	start := m.Vertices[v].HalfEdge
+	if start < 0 {
+		return nil
+	}
+	var list []int
+	for h := start; ; {
+		list = append(list, h)
+		h = m.HalfEdges[m.HalfEdges[h].Twin].Next
+		if h == start {
+			return list
+		}
+	}
+}
+
+// Borders lists the closed loops of border half-edges, one per hole or
+// open boundary of the surface.
+func (m *Mesh) Borders() [][]int {
+	var loops [][]int
+	visited := make([]bool, len(m.HalfEdges))
+	for h, he := range m.HalfEdges {
+		if he.Face >= 0 || visited[h] {
+			continue
+		}
+		loop := m.loop(h)
+		for _, b := range loop {
+			visited[b] = true
+		}
+		loops = append(loops, loop)
+	}
+	return loops
+}
+
+// Obj converts the mesh back to a copy of src, the Obj it was built
+// from. Vertex positions are written back to the strides, and the
+// triangles are replaced by the faces, which must be triangles. Each face
+// takes the group, material and smoothing group of its Obj triangle;
+// faces without one, or corners without a stride, use the last group and
+// the vertex stride. The original polygons are discarded, and strides
+// left unused are kept: call Clean to drop them.
+func (m *Mesh) Obj(src *gwob.Obj) (*gwob.Obj, error) {
+	o := src.Clone()
+	triangles := len(src.Indices) / 3
+
+	// faces ordered by source triangle, those without one last
+	count := make([]int, triangles+2)
+	for f, face := range m.Faces {
+		if face.HalfEdge < 0 {
+			continue
+		}
+		if len(m.FaceHalfEdges(f)) != 3 {
+			return nil, fmt.Errorf("topology: face=%d is not a triangle", f)
+		}
+		count[m.faceKey(f, triangles)+1]++
+	}
+	for k := 1; k < len(count); k++ {
+		count[k] += count[k-1]
+	}
+	before := append([]int(nil), count...) // faces before each key
+	total := count[len(count)-1]
+
+	o.Indices = make([]int, 3*total)
+	perTriangle := func(values []int) []int {
+		if len(values) != triangles {
+			return values
+		}
+		return make([]int, total)
+	}
+	o.Smoothing = perTriangle(src.Smoothing)
+	o.MaterialIndex = perTriangle(src.MaterialIndex)
+	o.Corners = nil
+	o.Faces = nil
+
+	template := triangles - 1 // for faces without a triangle
+	for f, face := range m.Faces {
+		if face.HalfEdge < 0 {
+			continue
+		}
+		key := m.faceKey(f, triangles)
+		n := count[key]
+		count[key]++
+		for c, h := range m.FaceHalfEdges(f) {
+			he := m.HalfEdges[h]
+			stride := he.Stride
+			if stride < 0 {
+				stride = m.Vertices[he.Origin].Stride
+			}
+			if stride < 0 {
+				return nil, fmt.Errorf("topology: vertex=%d has no stride", he.Origin)
+			}
+			o.Indices[3*n+c] = stride
+		}
+		t := face.Triangle
+		if key == triangles {
+			t = template
+		}
+		if t >= 0 && len(src.Smoothing) == triangles {
+			o.Smoothing[n] = src.Smoothing[t]
+		}
+		if t >= 0 && len(src.MaterialIndex) == triangles {
+			o.MaterialIndex[n] = src.MaterialIndex[t]
+		}
+	}
+
+	newIndex := func(i int) int {
+		return 3 * before[min(i/3, triangles)]
+	}
+	last := -1 // group taking the faces without a triangle
+	for i, g := range o.Groups {
+		if g.IndexCount > 0 || last < 0 {
+			last = i
+		}
+	}
+	for i, g := range o.Groups {
+		end := newIndex(g.IndexBegin + g.IndexCount)
+		if i == last {
+			end = 3 * total
+		}
+		g.IndexBegin = newIndex(g.IndexBegin)
+		g.IndexCount = end - g.IndexBegin
+	}
+	for i, c := range o.Comments {
+		o.Comments[i].Index = newIndex(c.Index)
+	}
+
+	for _, v := range m.Vertices {
+		if v.Stride >= 0 {
+			o.SetVertexCoordinates(v.Stride, v.Position[0], v.Position[1], v.Position[2])
+		}
+	}
+	for _, he := range m.HalfEdges {
+		if he.Face >= 0 && he.Stride >= 0 {
+			p := m.Vertices[he.Origin].Position
+			o.SetVertexCoordinates(he.Stride, p[0], p[1], p[2])
+		}
+	}
+
+	return o, nil
+}
+
+// faceKey orders face f by its Obj triangle, putting faces without one
+// after all triangles.
+func (m *Mesh) faceKey(f, triangles int) int {
+	t := m.Faces[f].Triangle
+	if t < 0 || t >= triangles {
+		return triangles
+	}
+	return t
+}
@@ -0,0 +1,185 @@
+package topology
+
+import (
+	"testing"
+
+	"github.com/udhos/gwob"
+)
+
+// cubeObj is a unit cube of quads in two groups, with texture seams.
+var cubeObj = `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+v 0 0 1
+v 1 0 1
+v 1 1 1
+v 0 1 1
+vt 0 0
+vt 1 0
+vt 1 1
+vt 0 1
+g bottom
+f 1/1 4/4 3/3 2/2
+f 5/1 6/2 7/3 8/4
+g sides
+s 1
+f 1/1 2/2 6/3 5/4
+f 2/1 3/2 7/3 6/4
+f 3/1 4/2 8/3 7/4
+f 4/1 1/2 5/3 8/4
+`
+
+func parse(t *testing.T, name, buf string) *gwob.Obj {
+	o, err := gwob.NewObjFromBuf(name, []byte(buf), gwob.NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("%s: NewObjFromBuf: %v", name, err)
+	}
+	return o
+}
+
+// checkMesh verifies the half-edge invariants.
+func checkMesh(t *testing.T, name string, m *Mesh) {
+	for h, he := range m.HalfEdges {
+		if m.HalfEdges[he.Twin].Twin != h {
+			t.Errorf("%s: half-edge %d: twin of twin=%d", name, h, m.HalfEdges[he.Twin].Twin)
+		}
+		if m.HalfEdges[he.Twin].Origin != m.HalfEdges[he.Next].Origin {
+			t.Errorf("%s: half-edge %d: twin and next disagree on destination", name, h)
+		}
+		if m.HalfEdges[m.Prev(h)].Next != h {
+			t.Errorf("%s: half-edge %d: bad prev", name, h)
+		}
+		if he.Face >= 0 && len(m.FaceHalfEdges(he.Face)) != 3 {
+			t.Errorf("%s: half-edge %d: face %d not a triangle", name, h, he.Face)
+		}
+	}
+	outgoing := 0
+	for v := range m.Vertices {
+		for _, h := range m.Outgoing(v) {
+			if m.HalfEdges[h].Origin != v {
+				t.Errorf("%s: vertex %d: outgoing half-edge %d from %d", name, v, h, m.HalfEdges[h].Origin)
+			}
+			outgoing++
+		}
+	}
+	if outgoing != len(m.HalfEdges) {
+		t.Errorf("%s: outgoing=%d half-edges=%d", name, outgoing, len(m.HalfEdges))
+	}
+}
+
+func TestNewClosed(t *testing.T) {
+	o := parse(t, "TestNewClosed", cubeObj)
+	m, err := New(o)
+	if err != nil {
+		t.Fatalf("TestNewClosed: New: %v", err)
+	}
+	checkMesh(t, "TestNewClosed", m)
+	if len(m.Vertices) != 8 || len(m.Faces) != 12 || len(m.HalfEdges) != 36 {
+		t.Errorf("TestNewClosed: vertices=%d faces=%d half-edges=%d", len(m.Vertices), len(m.Faces), len(m.HalfEdges))
+	}
+	if borders := m.Borders(); len(borders) != 0 {
+		t.Errorf("TestNewClosed: borders=%v", borders)
+	}
+}
+
+func TestNewOpen(t *testing.T) {
+	o := parse(t, "TestNewOpen", "v 0 0 0\nv 1 0 0\nv 1 1 0\nv 0 1 0\nf 1 2 3 4\n")
+	m, err := New(o)
+	if err != nil {
+		t.Fatalf("TestNewOpen: New: %v", err)
+	}
+	checkMesh(t, "TestNewOpen", m)
+	if len(m.HalfEdges) != 10 {
+		t.Errorf("TestNewOpen: half-edges=%d", len(m.HalfEdges))
+	}
+	borders := m.Borders()
+	if len(borders) != 1 || len(borders[0]) != 4 {
+		t.Fatalf("TestNewOpen: borders=%v", borders)
+	}
+	for _, h := range borders[0] {
+		if !m.IsBorder(h) {
+			t.Errorf("TestNewOpen: half-edge %d not on border", h)
+		}
+	}
+	for v := range m.Vertices {
+		if !m.IsBorder(m.Vertices[v].HalfEdge) {
+			t.Errorf("TestNewOpen: vertex %d: half-edge not on border", v)
+		}
+	}
+}
+
+func TestNewNonManifold(t *testing.T) {
+	table := []struct {
+		name string
+		buf  string
+	}{
+		{"fin", "v 0 0 0\nv 1 0 0\nv 0 1 0\nv 0 -1 0\nv 0 0 1\nf 1 2 3\nf 2 1 4\nf 1 2 5\n"},
+		{"winding", "v 0 0 0\nv 1 0 0\nv 0 1 0\nv 0 -1 0\nf 1 2 3\nf 1 2 4\n"},
+	}
+	for _, data := range table {
+		if _, err := New(parse(t, data.name, data.buf)); err == nil {
+			t.Errorf("TestNewNonManifold: %s: missing error", data.name)
+		}
+	}
+}
+
+func TestObjRoundTrip(t *testing.T) {
+	o := parse(t, "TestObjRoundTrip", cubeObj)
+	m, err := New(o)
+	if err != nil {
+		t.Fatalf("TestObjRoundTrip: New: %v", err)
+	}
+	back, err := m.Obj(o)
+	if err != nil {
+		t.Fatalf("TestObjRoundTrip: Obj: %v", err)
+	}
+	if report := gwob.Diff(o, back, 0); !report.Equal() {
+		t.Errorf("TestObjRoundTrip: %s", report.String())
+	}
+}
+
+func TestObjEdit(t *testing.T) {
+	o := parse(t, "TestObjEdit", cubeObj)
+	m, err := New(o)
+	if err != nil {
+		t.Fatalf("TestObjEdit: New: %v", err)
+	}
+
+	// delete the first face, move a vertex
+	m.Faces[0].HalfEdge = -1
+	m.Vertices[6].Position = [3]float32{2, 2, 2}
+
+	back, err := m.Obj(o)
+	if err != nil {
+		t.Fatalf("TestObjEdit: Obj: %v", err)
+	}
+	if len(back.Indices) != 33 {
+		t.Errorf("TestObjEdit: indices=%d", len(back.Indices))
+	}
+	bottom, sides := back.Groups[0], back.Groups[1]
+	if bottom.IndexBegin != 0 || bottom.IndexCount != 9 || sides.IndexBegin != 9 || sides.IndexCount != 24 {
+		t.Errorf("TestObjEdit: groups bottom=%d,%d sides=%d,%d", bottom.IndexBegin, bottom.IndexCount, sides.IndexBegin, sides.IndexCount)
+	}
+	if back.Indices[0] != o.Indices[3] {
+		t.Errorf("TestObjEdit: first index=%d want=%d", back.Indices[0], o.Indices[3])
+	}
+	moved := 0
+	for _, s := range back.Indices {
+		x, y, z := back.VertexCoordinates(s)
+		if x > 1 {
+			moved++
+			if x != 2 || y != 2 || z != 2 {
+				t.Errorf("TestObjEdit: stride %d at %v,%v,%v", s, x, y, z)
+			}
+		}
+	}
+	if moved == 0 {
+		t.Errorf("TestObjEdit: vertex not moved")
+	}
+	x, y, z := o.VertexCoordinates(m.Vertices[6].Stride)
+	if x != 1 || y != 1 || z != 1 {
+		t.Errorf("TestObjEdit: source changed: %v,%v,%v", x, y, z)
+	}
+}
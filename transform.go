@@ -0,0 +1,45 @@
+package gwob
+
+// Translate moves all vertices by (x,y,z). Homogeneous positions kept
+// by the KeepHomogeneous option are moved by (x,y,z) times their weight.
+func (o *Obj) Translate(x, y, z float32) {
+	for s := 0; s < o.NumberOfElements(); s++ {
+		w := float32(1)
+		if s < len(o.W) {
+			w = o.W[s]
+		}
+		p := o.vertexPosition(s)
+		p[0] += x * w
+		p[1] += y * w
+		p[2] += z * w
+	}
+}
+
+// Center moves the mesh so that the center of its bounding box is at
+// the origin.
+func (o *Obj) Center() {
+	lo, hi := o.Bounds()
+	o.Translate(-(lo[0]+hi[0])/2, -(lo[1]+hi[1])/2, -(lo[2]+hi[2])/2)
+}
+
+// FitToUnitCube centers the mesh and scales it uniformly, keeping its
+// proportions, so that it fits the [-1,1] cube with its largest
+// dimension spanning it. A mesh with no extent is only centered.
+func (o *Obj) FitToUnitCube() {
+	o.Center()
+	lo, hi := o.Bounds()
+	var size float32
+	for i := range lo {
+		size = max(size, hi[i]-lo[i])
+	}
+	if size == 0 {
+		return
+	}
+	f := 2 / size
+	for s := 0; s < o.NumberOfElements(); s++ {
+		p := o.vertexPosition(s)
+		p[0] *= f
+		p[1] *= f
+		p[2] *= f
+	}
+}
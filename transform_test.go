@@ -0,0 +1,62 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestCenter(t *testing.T) {
+	table := []struct {
+		name    string
+		options *ObjParserOptions
+	}{
+		{"interleaved", NewObjParserOptions()},
+		{"separate", NewObjParserOptions(WithNonInterleaved(true))},
+	}
+	str := `
+v 1 2 3
+v 5 2 3
+v 1 4 4
+f 1 2 3
+`
+	for _, data := range table {
+		o, err := NewObjFromBuf("center", []byte(str), data.options)
+		if err != nil {
+			t.Fatalf("TestCenter: %s: NewObjFromBuf: %v", data.name, err)
+		}
+
+		o.Center()
+		min, max := o.Bounds()
+		if want := []float32{-2, -1, -0.5}; !sliceEqualFloat(want, min[:]) {
+			t.Errorf("TestCenter: %s: min: want=%v got=%v", data.name, want, min)
+		}
+		if want := []float32{2, 1, 0.5}; !sliceEqualFloat(want, max[:]) {
+			t.Errorf("TestCenter: %s: max: want=%v got=%v", data.name, want, max)
+		}
+
+		o.Translate(10, 0, 0)
+		o.FitToUnitCube()
+		min, max = o.Bounds()
+		if want := []float32{-1, -0.5, -0.25}; !sliceEqualFloat(want, min[:]) {
+			t.Errorf("TestCenter: %s: fit min: want=%v got=%v", data.name, want, min)
+		}
+		if want := []float32{1, 0.5, 0.25}; !sliceEqualFloat(want, max[:]) {
+			t.Errorf("TestCenter: %s: fit max: want=%v got=%v", data.name, want, max)
+		}
+	}
+}
+
+func TestTranslateHomogeneous(t *testing.T) {
+	str := `
+v 1 1 1 2
+f 1 1 1
+`
+	o, err := NewObjFromBuf("homogeneous", []byte(str), NewObjParserOptions(WithKeepHomogeneous(true)))
+	if err != nil {
+		t.Fatalf("TestTranslateHomogeneous: NewObjFromBuf: %v", err)
+	}
+	o.Translate(1, 0, 0)
+	x, y, _ := o.VertexCoordinates(0)
+	if x != 3 || y != 1 {
+		t.Errorf("TestTranslateHomogeneous: got x=%v y=%v want x=3 y=1", x, y)
+	}
+}
@@ -0,0 +1,47 @@
+package gwob
+
+// Triangle is a triangle of the mesh, with its resolved positions.
+type Triangle struct {
+	Index     int           // triangle number: its indices start at Indices[3*Index]
+	Vertices  [3]int        // stride indices
+	Positions [3][3]float32 // vertex positions
+}
+
+// Triangles lists the triangles of the group, or of the whole mesh if
+// group is nil. See ForEachTriangle.
+func (o *Obj) Triangles(group *Group) []Triangle {
+	var list []Triangle
+	o.ForEachTriangle(group, func(t Triangle) bool {
+		list = append(list, t)
+		return true
+	})
+	return list
+}
+
+// ForEachTriangle calls fn for each triangle of the group, or of the
+// whole mesh if group is nil, in order, until fn returns false.
+func (o *Obj) ForEachTriangle(group *Group, fn func(t Triangle) bool) {
+	begin, end := o.triangleRange(group)
+	for i := begin; i < end; i++ {
+		t := Triangle{Index: i}
+		for c := range t.Vertices {
+			s := o.Indices[3*i+c]
+			t.Vertices[c] = s
+			t.Positions[c][0], t.Positions[c][1], t.Positions[c][2] = o.VertexCoordinates(s)
+		}
+		if !fn(t) {
+			return
+		}
+	}
+}
+
+// triangleRange gets the triangles of the group, or of the whole mesh if
+// group is nil, as the half-open range [begin, end).
+func (o *Obj) triangleRange(group *Group) (begin, end int) {
+	begin, end = 0, len(o.Indices)/3
+	if group != nil {
+		begin = group.IndexBegin / 3
+		end = min((group.IndexBegin+group.IndexCount)/3, end)
+	}
+	return begin, end
+}
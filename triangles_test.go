@@ -0,0 +1,45 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestTriangles(t *testing.T) {
+	o, err := NewObjFromBuf("materials", []byte(materialsObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestTriangles: NewObjFromBuf: %v", err)
+	}
+
+	all := o.Triangles(nil)
+	expectInt(t, "TestTriangles: all", len(o.Indices)/3, len(all))
+	for i, tri := range all {
+		expectInt(t, "TestTriangles: index", i, tri.Index)
+		for c, s := range tri.Vertices {
+			if s != o.Indices[3*i+c] {
+				t.Errorf("TestTriangles: triangle %d corner %d: stride=%d want=%d", i, c, s, o.Indices[3*i+c])
+			}
+			x, y, z := o.VertexCoordinates(s)
+			if tri.Positions[c] != [3]float32{x, y, z} {
+				t.Errorf("TestTriangles: triangle %d corner %d: position=%v", i, c, tri.Positions[c])
+			}
+		}
+	}
+
+	count := 0
+	for _, g := range o.Groups {
+		list := o.Triangles(g)
+		expectInt(t, "TestTriangles: group "+g.Name, g.IndexCount/3, len(list))
+		if len(list) > 0 {
+			expectInt(t, "TestTriangles: group first "+g.Name, g.IndexBegin/3, list[0].Index)
+		}
+		count += len(list)
+	}
+	expectInt(t, "TestTriangles: groups", len(all), count)
+
+	visited := 0
+	o.ForEachTriangle(nil, func(t Triangle) bool {
+		visited++
+		return visited < 2
+	})
+	expectInt(t, "TestTriangles: stop", 2, visited)
+}
@@ -0,0 +1,130 @@
+package gwob
+
+import (
+	"math"
+)
+
+// triangulate splits a polygon with more than 4 corners into triangles,
+// appending corner positions (3 per triangle) to tris.
+// Convex polygons are fan triangulated, concave ones are ear clipped
+// after projection onto the plane given by their Newell normal.
+func (p *objParser) triangulate(tris []int, keys []vertexKey) []int {
+	pts := make([][2]float64, len(keys))
+	p.projectPolygon(pts, keys)
+	return triangulatePolygon(tris, pts)
+}
+
+// position gets the coordinates of a v statement.
+func (p *objParser) position(v int) (float64, float64, float64) {
+	if p.double {
+		return p.vertCoord64[3*v], p.vertCoord64[3*v+1], p.vertCoord64[3*v+2]
+	}
+	return float64(p.vertCoord[3*v]), float64(p.vertCoord[3*v+1]), float64(p.vertCoord[3*v+2])
+}
+
+// projectPolygon drops the dominant axis of the polygon normal,
+// keeping the winding seen from the front side.
+func (p *objParser) projectPolygon(pts [][2]float64, keys []vertexKey) {
+	pos := make([][3]float64, len(keys))
+	for i, k := range keys {
+		pos[i][0], pos[i][1], pos[i][2] = p.position(k.v)
+	}
+
+	var nx, ny, nz float64
+	for i := range pos {
+		a, b := pos[i], pos[(i+1)%len(pos)]
+		nx += (a[1] - b[1]) * (a[2] + b[2])
+		ny += (a[2] - b[2]) * (a[0] + b[0])
+		nz += (a[0] - b[0]) * (a[1] + b[1])
+	}
+
+	// (u,v) axes such that u x v points along the dominant normal axis
+	u, v := 0, 1
+	flip := nz < 0
+	switch ax, ay, az := math.Abs(nx), math.Abs(ny), math.Abs(nz); {
+	case ax >= ay && ax >= az:
+		u, v, flip = 1, 2, nx < 0
+	case ay >= az:
+		u, v, flip = 2, 0, ny < 0
+	}
+
+	for i, c := range pos {
+		pts[i] = [2]float64{c[u], c[v]}
+		if flip {
+			pts[i][0] = -pts[i][0]
+		}
+	}
+}
+
+// triangulatePolygon triangulates a counter-clockwise 2D polygon.
+func triangulatePolygon(tris []int, pts [][2]float64) []int {
+	n := len(pts)
+
+	if isConvex(pts) {
+		for i := 1; i < n-1; i++ {
+			tris = append(tris, 0, i, i+1)
+		}
+		return tris
+	}
+
+	// ear clipping
+	ring := make([]int, n)
+	for i := range ring {
+		ring[i] = i
+	}
+	for len(ring) > 3 {
+		ear := -1
+		for i := range ring {
+			if isEar(pts, ring, i) {
+				ear = i
+				break
+			}
+		}
+		if ear < 0 {
+			break // degenerate polygon: fan the remainder
+		}
+		prev := ring[(ear+len(ring)-1)%len(ring)]
+		next := ring[(ear+1)%len(ring)]
+		tris = append(tris, prev, ring[ear], next)
+		ring = append(ring[:ear], ring[ear+1:]...)
+	}
+	for i := 1; i < len(ring)-1; i++ {
+		tris = append(tris, ring[0], ring[i], ring[i+1])
+	}
+	return tris
+}
+
+func cross2(o, a, b [2]float64) float64 {
+	return (a[0]-o[0])*(b[1]-o[1]) - (a[1]-o[1])*(b[0]-o[0])
+}
+
+// isConvex reports whether a counter-clockwise polygon has no reflex corner.
+func isConvex(pts [][2]float64) bool {
+	n := len(pts)
+	for i := range pts {
+		if cross2(pts[(i+n-1)%n], pts[i], pts[(i+1)%n]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isEar reports whether corner i of the ring can be clipped:
+// it must be convex and its triangle must contain no other corner.
+func isEar(pts [][2]float64, ring []int, i int) bool {
+	n := len(ring)
+	a, b, c := pts[ring[(i+n-1)%n]], pts[ring[i]], pts[ring[(i+1)%n]]
+	if cross2(a, b, c) <= 0 {
+		return false
+	}
+	for j := range ring {
+		if j == i || j == (i+n-1)%n || j == (i+1)%n {
+			continue
+		}
+		q := pts[ring[j]]
+		if cross2(a, b, q) >= 0 && cross2(b, c, q) >= 0 && cross2(c, a, q) >= 0 {
+			return false
+		}
+	}
+	return true
+}
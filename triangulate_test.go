@@ -0,0 +1,85 @@
+package gwob
+
+import (
+	"math"
+	"testing"
+)
+
+// triangleNormal gets the (unnormalized) normal of a triangle in o.
+func triangleNormal(o *Obj, t int) [3]float64 {
+	var v [3][3]float64
+	for i := range v {
+		x, y, z := o.VertexCoordinates(o.Indices[3*t+i])
+		v[i] = [3]float64{float64(x), float64(y), float64(z)}
+	}
+	a := [3]float64{v[1][0] - v[0][0], v[1][1] - v[0][1], v[1][2] - v[0][2]}
+	b := [3]float64{v[2][0] - v[0][0], v[2][1] - v[0][1], v[2][2] - v[0][2]}
+	return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+func TestPolygonFaces(t *testing.T) {
+
+	table := []struct {
+		name   string
+		obj    string
+		normal [3]float64 // expected face normal direction
+		area   float64
+	}{
+		{"convex pentagon", `
+v 0 0 0
+v 2 0 0
+v 3 1 0
+v 1 3 0
+v -1 1 0
+f 1 2 3 4 5
+`, [3]float64{0, 0, 1}, 7},
+		{"concave arrow", `
+v 0 0 0
+v 4 0 0
+v 4 4 0
+v 2 1 0
+v 0 4 0
+f 1 2 3 4 5
+`, [3]float64{0, 0, 1}, 10},
+		{"concave arrow clockwise", `
+v 0 0 0
+v 4 0 0
+v 4 4 0
+v 2 1 0
+v 0 4 0
+f 5 4 3 2 1
+`, [3]float64{0, 0, -1}, 10},
+		{"concave L in yz plane", `
+v 0 0 0
+v 0 0 2
+v 0 1 2
+v 0 1 1
+v 0 2 1
+v 0 2 0
+f 6 5 4 3 2 1
+`, [3]float64{1, 0, 0}, 3},
+	}
+
+	for _, data := range table {
+		o, err := NewObjFromBuf(data.name, []byte(data.obj), NewObjParserOptions())
+		if err != nil {
+			t.Errorf("TestPolygonFaces: %s: %v", data.name, err)
+			continue
+		}
+		corners := len(o.Indices) / 3
+		expectInt(t, "TestPolygonFaces: "+data.name+": triangles", o.NumberOfElements()-2, corners)
+
+		var area float64
+		for tri := 0; tri < corners; tri++ {
+			n := triangleNormal(o, tri)
+			dot := n[0]*data.normal[0] + n[1]*data.normal[1] + n[2]*data.normal[2]
+			if dot <= 0 {
+				t.Errorf("TestPolygonFaces: %s: triangle %d: bad winding normal=%v", data.name, tri, n)
+			}
+			area += dot / 2
+		}
+		if math.Abs(area-data.area) > 1e-6 {
+			t.Errorf("TestPolygonFaces: %s: area: want=%v got=%v", data.name, data.area, area)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package gwob
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOnUnknown(t *testing.T) {
+	str := cubeObj + "\nxattr color red\nxbad 1\n"
+
+	var keywords, rests []string
+	errBad := errors.New("bad extension")
+	handler := func(lineNum int, keyword, rest string) error {
+		keywords = append(keywords, keyword)
+		rests = append(rests, rest)
+		if keyword == "xbad" {
+			return errBad
+		}
+		return nil
+	}
+
+	o, err := NewObjFromBuf("cubeObj", []byte(str), NewObjParserOptions(WithOnUnknown(handler)))
+	if err != nil {
+		t.Fatalf("TestOnUnknown: NewObjFromBuf: %v", err)
+	}
+	if len(keywords) != 2 || keywords[0] != "xattr" || rests[0] != "color red" || keywords[1] != "xbad" {
+		t.Errorf("TestOnUnknown: keywords=%q rests=%q", keywords, rests)
+	}
+	expectInt(t, "TestOnUnknown: errors", 1, o.Errors())
+	if !sliceEqualInt(cubeIndices, o.Indices) {
+		t.Errorf("TestOnUnknown: indices: want=%v got=%v", cubeIndices, o.Indices)
+	}
+
+	_, err = NewObjFromBuf("cubeObj", []byte(str), NewObjParserOptions(WithOnUnknown(handler), WithStrict(true)))
+	if !errors.Is(err, errBad) {
+		t.Errorf("TestOnUnknown: strict: want %v got %v", errBad, err)
+	}
+}
@@ -0,0 +1,51 @@
+package gwob
+
+import (
+	"bytes"
+	"testing"
+)
+
+var usemapObj = `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+usemap brick
+f 1 2 3
+usemap wood
+f 1 2 3
+usemap off
+f 1 2 3
+`
+
+func TestUsemap(t *testing.T) {
+
+	o, err := NewObjFromBuf("usemapObj", []byte(usemapObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestUsemap: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestUsemap: errors", 0, o.Errors())
+
+	want := []string{"brick", "wood", ""}
+	expectInt(t, "TestUsemap: groups", len(want), len(o.Groups))
+	for i, g := range o.Groups {
+		if i < len(want) && g.Usemap != want[i] {
+			t.Errorf("TestUsemap: group %d: want=%q got=%q", i, want[i], g.Usemap)
+		}
+	}
+
+	// round trip
+	var buf bytes.Buffer
+	if errWrite := o.ToWriter(&buf); errWrite != nil {
+		t.Fatalf("TestUsemap: ToWriter: %v", errWrite)
+	}
+	o2, err := NewObjFromBuf("usemapObj2", buf.Bytes(), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestUsemap: reparse: %v", err)
+	}
+	expectInt(t, "TestUsemap: reparsed groups", len(want), len(o2.Groups))
+	for i, g := range o2.Groups {
+		if i < len(want) && g.Usemap != want[i] {
+			t.Errorf("TestUsemap: reparsed group %d: want=%q got=%q", i, want[i], g.Usemap)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package gwob
+
+// Vertex is a typed view of the attributes of a stride.
+type Vertex struct {
+	Position  [3]float32
+	UV        [2]float32
+	Normal    [3]float32
+	HasUV     bool
+	HasNormal bool
+}
+
+// Vertex gets the attributes for a stride index.
+func (o *Obj) Vertex(stride int) Vertex {
+	var v Vertex
+	v.Position[0], v.Position[1], v.Position[2] = o.VertexCoordinates(stride)
+	if o.TextCoordFound {
+		copy(v.UV[:], o.vertexTexCoord(stride))
+		v.HasUV = true
+	}
+	if o.NormCoordFound {
+		copy(v.Normal[:], o.vertexNormal(stride))
+		v.HasNormal = true
+	}
+	return v
+}
+
+// Vertices lists the attributes of all strides. See ForEachVertex.
+func (o *Obj) Vertices() []Vertex {
+	list := make([]Vertex, 0, o.NumberOfElements())
+	o.ForEachVertex(func(stride int, v Vertex) bool {
+		list = append(list, v)
+		return true
+	})
+	return list
+}
+
+// ForEachVertex calls fn for each stride, in order, until fn returns false.
+func (o *Obj) ForEachVertex(fn func(stride int, v Vertex) bool) {
+	for s := 0; s < o.NumberOfElements(); s++ {
+		if !fn(s, o.Vertex(s)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestVertices(t *testing.T) {
+	o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestVertices: NewObjFromBuf: %v", err)
+	}
+
+	list := o.Vertices()
+	expectInt(t, "TestVertices: count", o.NumberOfElements(), len(list))
+	for s, v := range list {
+		f := s * cubeStrideSize / 4
+		var got []float32
+		got = append(got, v.Position[:]...)
+		got = append(got, v.UV[:]...)
+		got = append(got, v.Normal[:]...)
+		if want := cubeCoord[f : f+8]; !v.HasUV || !v.HasNormal || !sliceEqualFloat(want, got) {
+			t.Errorf("TestVertices: stride %d: want=%v got=%v uv=%v normal=%v", s, want, got, v.HasUV, v.HasNormal)
+		}
+	}
+
+	box, _ := NewObjFromBuf("box", []byte(boxObj), NewObjParserOptions())
+	if v := box.Vertex(1); v.HasUV || v.HasNormal || v.Position != [3]float32{0, 1, 0} { // second in the first face
+		t.Errorf("TestVertices: box vertex: %+v", v)
+	}
+
+	visited := 0
+	o.ForEachVertex(func(stride int, v Vertex) bool {
+		expectInt(t, "TestVertices: stride", visited, stride)
+		visited++
+		return visited < 3
+	})
+	expectInt(t, "TestVertices: stop", 3, visited)
+}
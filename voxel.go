@@ -0,0 +1,259 @@
+package gwob
+
+import (
+	"fmt"
+	"math"
+)
+
+// VoxelGrid is a regular 3D grid of occupied cells.
+type VoxelGrid struct {
+	Origin   [3]float32 // lower corner of cell (0,0,0)
+	CellSize float32
+	Size     [3]int // cells along x, y and z
+	Cells    []bool // x varies fastest, then y, then z
+}
+
+// Index gets the position of cell (x,y,z) in Cells.
+func (g *VoxelGrid) Index(x, y, z int) int {
+	return x + g.Size[0]*(y+g.Size[1]*z)
+}
+
+// Occupied reports whether cell (x,y,z) is occupied. Cells outside the
+// grid are empty.
+func (g *VoxelGrid) Occupied(x, y, z int) bool {
+	if x < 0 || y < 0 || z < 0 || x >= g.Size[0] || y >= g.Size[1] || z >= g.Size[2] {
+		return false
+	}
+	return g.Cells[g.Index(x, y, z)]
+}
+
+// Count gets the number of occupied cells.
+func (g *VoxelGrid) Count() int {
+	count := 0
+	for _, c := range g.Cells {
+		if c {
+			count++
+		}
+	}
+	return count
+}
+
+// maxVoxelCells caps the size of the grid built by Voxelize.
+const maxVoxelCells = 1 << 28
+
+// Voxelize marks the cells of a grid of cellSize spacing, covering the
+// bounding box, that are touched by the triangles. The result is a
+// surface shell: see VoxelGrid.FillInterior for solids.
+func (o *Obj) Voxelize(cellSize float64) (*VoxelGrid, error) {
+	if cellSize <= 0 {
+		return nil, fmt.Errorf("Voxelize: bad cell size=%v", cellSize)
+	}
+	min, max := o.Bounds()
+	g := &VoxelGrid{Origin: min, CellSize: float32(cellSize)}
+	total := 1.0
+	for i := range g.Size {
+		n := math.Max(1, math.Ceil(float64(max[i]-min[i])/cellSize))
+		total *= n
+		if !(total <= maxVoxelCells) { // also catches NaN
+			return nil, fmt.Errorf("Voxelize: grid too large for cell size=%v (limit=%d cells)", cellSize, maxVoxelCells)
+		}
+		g.Size[i] = int(n)
+	}
+	g.Cells = make([]bool, g.Size[0]*g.Size[1]*g.Size[2])
+
+	// cell gets the cell coordinate of position p along axis i.
+	cell := func(p float64, i int) int {
+		c := int(math.Floor((p - float64(g.Origin[i])) / cellSize))
+		return clamp(c, 0, g.Size[i]-1)
+	}
+
+	for t := 0; t < len(o.Indices)/3; t++ {
+		var tri [3][3]float64
+		for c := range tri {
+			x, y, z := o.VertexCoordinates(o.Indices[3*t+c])
+			tri[c] = [3]float64{float64(x), float64(y), float64(z)}
+		}
+		var lo, hi [3]int
+		for i := range lo {
+			lo[i] = cell(math.Min(tri[0][i], math.Min(tri[1][i], tri[2][i])), i)
+			hi[i] = cell(math.Max(tri[0][i], math.Max(tri[1][i], tri[2][i])), i)
+		}
+		half := cellSize / 2
+		for z := lo[2]; z <= hi[2]; z++ {
+			for y := lo[1]; y <= hi[1]; y++ {
+				for x := lo[0]; x <= hi[0]; x++ {
+					i := g.Index(x, y, z)
+					if g.Cells[i] {
+						continue
+					}
+					center := [3]float64{
+						float64(g.Origin[0]) + (float64(x)+0.5)*cellSize,
+						float64(g.Origin[1]) + (float64(y)+0.5)*cellSize,
+						float64(g.Origin[2]) + (float64(z)+0.5)*cellSize,
+					}
+					g.Cells[i] = triangleBoxOverlap(tri, center, half)
+				}
+			}
+		}
+	}
+
+	return g, nil
+}
+
+func clamp(v, lo, hi int) int {
+	return min(max(v, lo), hi)
+}
+
+// triangleBoxOverlap tests a triangle against the cube of the given
+// center and half size, by the separating axis theorem, after
+// Akenine-Möller, "Fast 3D Triangle-Box Overlap Testing" (2001).
+func triangleBoxOverlap(tri [3][3]float64, center [3]float64, half float64) bool {
+	var v [3][3]float64
+	for c := range v {
+		v[c] = sub64(tri[c], center)
+	}
+	edges := [3][3]float64{sub64(v[1], v[0]), sub64(v[2], v[1]), sub64(v[0], v[2])}
+
+	// separated along axis, if the triangle projection misses the box one
+	separated := func(axis [3]float64) bool {
+		p0, p1, p2 := dot64(v[0], axis), dot64(v[1], axis), dot64(v[2], axis)
+		r := half * (math.Abs(axis[0]) + math.Abs(axis[1]) + math.Abs(axis[2]))
+		return math.Min(p0, math.Min(p1, p2)) > r || math.Max(p0, math.Max(p1, p2)) < -r
+	}
+
+	// box normals
+	for i := 0; i < 3; i++ {
+		var axis [3]float64
+		axis[i] = 1
+		if separated(axis) {
+			return false
+		}
+	}
+	// triangle normal
+	if separated(cross64(edges[0], edges[1])) {
+		return false
+	}
+	// box edges crossed with triangle edges
+	for i := 0; i < 3; i++ {
+		var boxEdge [3]float64
+		boxEdge[i] = 1
+		for _, e := range edges {
+			if separated(cross64(boxEdge, e)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// FillInterior marks the empty cells enclosed by occupied ones, those not
+// reachable from the outside of the grid through face neighbors, turning
+// a closed shell into a solid. It returns the number of cells filled.
+func (g *VoxelGrid) FillInterior() int {
+	outside := make([]bool, len(g.Cells))
+	var stack [][3]int
+	visit := func(x, y, z int) {
+		if x < 0 || y < 0 || z < 0 || x >= g.Size[0] || y >= g.Size[1] || z >= g.Size[2] {
+			return
+		}
+		i := g.Index(x, y, z)
+		if g.Cells[i] || outside[i] {
+			return
+		}
+		outside[i] = true
+		stack = append(stack, [3]int{x, y, z})
+	}
+
+	// seed from the grid faces
+	for z := 0; z < g.Size[2]; z++ {
+		for y := 0; y < g.Size[1]; y++ {
+			for x := 0; x < g.Size[0]; x++ {
+				if x == 0 || y == 0 || z == 0 || x == g.Size[0]-1 || y == g.Size[1]-1 || z == g.Size[2]-1 {
+					visit(x, y, z)
+				}
+			}
+		}
+	}
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, d := range voxelNeighbors {
+			visit(c[0]+d[0], c[1]+d[1], c[2]+d[2])
+		}
+	}
+
+	filled := 0
+	for i, c := range g.Cells {
+		if !c && !outside[i] {
+			g.Cells[i] = true
+			filled++
+		}
+	}
+	return filled
+}
+
+// voxelNeighbors are the offsets of the face neighbors of a cell.
+var voxelNeighbors = [6][3]int{
+	{1, 0, 0}, {-1, 0, 0},
+	{0, 1, 0}, {0, -1, 0},
+	{0, 0, 1}, {0, 0, -1},
+}
+
+// Mesh builds a blocky mesh of the occupied cells, with a quad for each
+// cell face not shared with another occupied cell, wound outwards.
+// Corners are shared between quads. The mesh has no normals: see
+// GenerateFlatNormals.
+func (g *VoxelGrid) Mesh() *Obj {
+	b := NewObjBuilder(nil)
+	corners := map[[3]int]int{} // grid corner -> unified vertex
+	corner := func(c [3]int) int {
+		v, found := corners[c]
+		if !found {
+			v, _ = b.AddVertex(b.AddPosition(
+				g.Origin[0]+float32(c[0])*g.CellSize,
+				g.Origin[1]+float32(c[1])*g.CellSize,
+				g.Origin[2]+float32(c[2])*g.CellSize,
+			), -1, -1)
+			corners[c] = v
+		}
+		return v
+	}
+
+	for z := 0; z < g.Size[2]; z++ {
+		for y := 0; y < g.Size[1]; y++ {
+			for x := 0; x < g.Size[0]; x++ {
+				if !g.Occupied(x, y, z) {
+					continue
+				}
+				cell := [3]int{x, y, z}
+				for _, d := range voxelNeighbors {
+					if g.Occupied(x+d[0], y+d[1], z+d[2]) {
+						continue
+					}
+					// quad on the face plane, spanned by the other axes
+					a := 0
+					for d[a] == 0 {
+						a++
+					}
+					u, v := (a+1)%3, (a+2)%3
+					base := cell
+					if d[a] > 0 {
+						base[a]++
+					}
+					q := [4][3]int{base, base, base, base}
+					q[1][u]++
+					q[2][u]++
+					q[2][v]++
+					q[3][v]++
+					if d[a] < 0 {
+						q[1], q[3] = q[3], q[1]
+					}
+					v0, v1, v2, v3 := corner(q[0]), corner(q[1]), corner(q[2]), corner(q[3])
+					b.AddTriangle(v0, v1, v2)
+					b.AddTriangle(v0, v2, v3)
+				}
+			}
+		}
+	}
+	return b.Build()
+}
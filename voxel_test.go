@@ -0,0 +1,68 @@
+package gwob
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVoxelize(t *testing.T) {
+	o, err := NewObjFromBuf("box", []byte(boxObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestVoxelize: NewObjFromBuf: %v", err)
+	}
+
+	g, err := o.Voxelize(0.25)
+	if err != nil {
+		t.Fatalf("TestVoxelize: %v", err)
+	}
+	if g.Size != [3]int{4, 4, 4} {
+		t.Errorf("TestVoxelize: size=%v", g.Size)
+	}
+	expectInt(t, "TestVoxelize: shell", 64-8, g.Count())
+	if g.Occupied(1, 1, 1) || !g.Occupied(0, 1, 1) || g.Occupied(-1, 0, 0) || g.Occupied(4, 0, 0) {
+		t.Errorf("TestVoxelize: bad occupancy")
+	}
+
+	// the cavity counts as outside of the shell mesh
+	shell := g.Mesh()
+	expectInt(t, "TestVoxelize: shell triangles", 2*(6*16+6*4), len(shell.Indices)/3)
+
+	expectInt(t, "TestVoxelize: filled", 8, g.FillInterior())
+	expectInt(t, "TestVoxelize: solid", 64, g.Count())
+	expectInt(t, "TestVoxelize: filled again", 0, g.FillInterior())
+
+	m := g.Mesh()
+	expectInt(t, "TestVoxelize: triangles", 2*6*16, len(m.Indices)/3)
+	expectInt(t, "TestVoxelize: vertices", 5*5*5-3*3*3, m.NumberOfElements())
+	if v := m.Volume(); math.Abs(v-1) > 1e-6 {
+		t.Errorf("TestVoxelize: volume=%v", v) // outward winding
+	}
+	if report := m.CheckManifold(); !report.Watertight() {
+		t.Errorf("TestVoxelize: mesh not watertight: %+v", report)
+	}
+}
+
+func TestVoxelizeTriangle(t *testing.T) {
+	// a diagonal triangle crosses only the cells along it
+	o, err := NewObjFromBuf("tri", []byte("v 0 0 0\nv 4 4 0\nv 4 4 4\nf 1 2 3\n"), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestVoxelizeTriangle: NewObjFromBuf: %v", err)
+	}
+	g, err := o.Voxelize(1)
+	if err != nil {
+		t.Fatalf("TestVoxelizeTriangle: %v", err)
+	}
+	if g.Size != [3]int{4, 4, 4} {
+		t.Errorf("TestVoxelizeTriangle: size=%v", g.Size)
+	}
+	if g.Occupied(3, 0, 0) || g.Occupied(0, 3, 0) || !g.Occupied(2, 2, 1) || g.Occupied(0, 0, 3) {
+		t.Errorf("TestVoxelizeTriangle: bad occupancy")
+	}
+
+	if _, err := o.Voxelize(0); err == nil {
+		t.Errorf("TestVoxelizeTriangle: zero cell size")
+	}
+	if _, err := o.Voxelize(1e-9); err == nil {
+		t.Errorf("TestVoxelizeTriangle: grid too large")
+	}
+}
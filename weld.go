@@ -0,0 +1,225 @@
+package gwob
+
+import (
+	"fmt"
+	"math"
+)
+
+// weldVertex holds the attributes compared when welding.
+type weldVertex struct {
+	pos   [3]float32
+	tex   [2]float32
+	norm  [3]float32
+	color [3]float32
+}
+
+// welder merges vertices whose attributes are within tolerance,
+// using a spatial hash on positions.
+type welder struct {
+	epsPos, epsTex, epsNorm float32
+	cell                    float64
+	cells                   map[[3]int64][]int // cell -> unique vertices
+	unique                  []weldVertex
+}
+
+func newWelder(epsPos, epsTex, epsNorm float32) *welder {
+	return &welder{
+		epsPos:  epsPos,
+		epsTex:  epsTex,
+		epsNorm: epsNorm,
+		cell:    float64(epsPos),
+		cells:   map[[3]int64][]int{},
+	}
+}
+
+// cellOf gets the hash cell for a position: a grid of epsPos cells,
+// or the exact values for zero tolerance.
+func (w *welder) cellOf(pos [3]float32) [3]int64 {
+	var c [3]int64
+	for i, p := range pos {
+		if w.cell <= 0 {
+			c[i] = int64(math.Float32bits(p + 0)) // +0 turns -0 into 0
+			continue
+		}
+		c[i] = int64(math.Floor(float64(p) / w.cell))
+	}
+	return c
+}
+
+func within(a, b []float32, eps float32) bool {
+	for i := range a {
+		if d := a[i] - b[i]; d > eps || d < -eps {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *welder) match(a, b *weldVertex) bool {
+	return within(a.pos[:], b.pos[:], w.epsPos) &&
+		within(a.tex[:], b.tex[:], w.epsTex) &&
+		within(a.norm[:], b.norm[:], w.epsNorm) &&
+		a.color == b.color
+}
+
+// add gets the unique vertex matching v, adding it if none.
+func (w *welder) add(v weldVertex) int {
+	home := w.cellOf(v.pos)
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for dz := int64(-1); dz <= 1; dz++ {
+				c := [3]int64{home[0] + dx, home[1] + dy, home[2] + dz}
+				for _, u := range w.cells[c] {
+					if w.match(&v, &w.unique[u]) {
+						return u
+					}
+				}
+			}
+		}
+	}
+	u := len(w.unique)
+	w.unique = append(w.unique, v)
+	w.cells[home] = append(w.cells[home], u)
+	return u
+}
+
+// IndexTriangles builds an indexed Obj from a triangle soup: coord holds
+// 3 vertices per triangle, in the stride layout described by layout,
+// which must include a position. Vertices whose attributes are all
+// within epsilon are merged; colors must match exactly. The result has
+// the usual interleaved layout, with colors in Colors, and a single
+// unnamed group. It is the inverse of Flatten.
+func IndexTriangles(coord []float32, layout VertexLayout, epsilon float32) (*Obj, error) {
+	if layout.Stride < 4 || layout.Stride%4 != 0 {
+		return nil, fmt.Errorf("IndexTriangles: bad stride=%d", layout.Stride)
+	}
+	floatsPerStride := layout.Stride / 4
+	if len(coord)%floatsPerStride != 0 {
+		return nil, fmt.Errorf("IndexTriangles: coord size=%d not a multiple of stride floats=%d", len(coord), floatsPerStride)
+	}
+	vertices := len(coord) / floatsPerStride
+	if vertices%3 != 0 {
+		return nil, fmt.Errorf("IndexTriangles: vertex count=%d not a multiple of 3", vertices)
+	}
+
+	offsets := map[string]int{}
+	for _, a := range layout.Attributes {
+		want := map[string]int{
+			AttributePosition: 3,
+			AttributeTexCoord: 2,
+			AttributeNormal:   3,
+			AttributeColor:    3,
+		}[a.Name]
+		if want == 0 || a.Components != want || a.Offset%4 != 0 || a.Offset+4*want > layout.Stride {
+			return nil, fmt.Errorf("IndexTriangles: bad attribute: %+v", a)
+		}
+		offsets[a.Name] = a.Offset / 4
+	}
+	if _, found := offsets[AttributePosition]; !found {
+		return nil, fmt.Errorf("IndexTriangles: missing position attribute")
+	}
+	_, hasTex := offsets[AttributeTexCoord]
+	_, hasNorm := offsets[AttributeNormal]
+	_, hasColor := offsets[AttributeColor]
+
+	w := newWelder(epsilon, epsilon, epsilon)
+
+	o := &Obj{TextCoordFound: hasTex, NormCoordFound: hasNorm, ColorFound: hasColor}
+	group := o.newGroup("", "", 0, 0)
+
+	for i := 0; i < vertices; i++ {
+		stride := coord[i*floatsPerStride : (i+1)*floatsPerStride]
+		var v weldVertex
+		copy(v.pos[:], stride[offsets[AttributePosition]:])
+		if hasTex {
+			copy(v.tex[:], stride[offsets[AttributeTexCoord]:])
+		}
+		if hasNorm {
+			copy(v.norm[:], stride[offsets[AttributeNormal]:])
+		}
+		if hasColor {
+			copy(v.color[:], stride[offsets[AttributeColor]:])
+		}
+		pushIndex(group, o, w.add(v))
+	}
+
+	o.Positions = make([]float32, 0, 3*len(w.unique))
+	for _, v := range w.unique {
+		o.Positions = append(o.Positions, v.pos[:]...)
+		if hasTex {
+			o.TexCoords = append(o.TexCoords, v.tex[:]...)
+		}
+		if hasNorm {
+			o.Normals = append(o.Normals, v.norm[:]...)
+		}
+		if hasColor {
+			o.Colors = append(o.Colors, v.color[:]...)
+		}
+	}
+	o.Interleave()
+
+	return o, nil
+}
+
+// Weld merges vertices whose positions, texture coordinates and normals
+// are within the respective tolerances, rewriting Indices and compacting
+// the vertex data. Colors must match exactly. Each merged vertex keeps
+// the attributes of its first occurrence. Welding reduces the GPU memory
+// taken by exporters that duplicate vertices. It returns the number of
+// vertices removed.
+func (o *Obj) Weld(epsilonPos, epsilonUV, epsilonNormal float32) int {
+	strides := o.NumberOfElements()
+	w := newWelder(epsilonPos, epsilonUV, epsilonNormal)
+	remap := make([]int, strides) // old stride -> new stride
+	var src []int                 // new stride -> old stride
+
+	for s := 0; s < strides; s++ {
+		var v weldVertex
+		v.pos[0], v.pos[1], v.pos[2] = o.VertexCoordinates(s)
+		if o.TextCoordFound {
+			copy(v.tex[:], o.vertexTexCoord(s))
+		}
+		if o.NormCoordFound {
+			copy(v.norm[:], o.vertexNormal(s))
+		}
+		if o.ColorFound {
+			v.color[0], v.color[1], v.color[2] = o.VertexColor(s)
+		}
+		u := w.add(v)
+		if u == len(src) {
+			src = append(src, s)
+		}
+		remap[s] = u
+	}
+
+	if len(src) == strides {
+		return 0
+	}
+
+	o.remapIndices(remap)
+	selectStrides(o, src)
+
+	return strides - len(src)
+}
+
+// remapIndices rewrites every reference to a stride, through remap.
+func (o *Obj) remapIndices(remap []int) {
+	for i, v := range o.Indices {
+		o.Indices[i] = remap[v]
+	}
+	for _, g := range o.Groups {
+		for i, v := range g.Points {
+			g.Points[i] = remap[v]
+		}
+		for _, line := range g.Lines {
+			for i, v := range line {
+				line[i] = remap[v]
+			}
+		}
+	}
+	for _, f := range o.Faces {
+		for i, v := range f.Vertices {
+			f.Vertices[i] = remap[v]
+		}
+	}
+}
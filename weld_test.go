@@ -0,0 +1,96 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestIndexTriangles(t *testing.T) {
+	o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestIndexTriangles: NewObjFromBuf: %v", err)
+	}
+	elements := o.NumberOfElements()
+	indices := append([]int(nil), o.Indices...)
+	coord := append([]float32(nil), o.Coord...)
+
+	o.Flatten()
+
+	// perturb one corner, below epsilon
+	o.Coord[0] += 0.0001
+
+	o2, err := IndexTriangles(o.Coord, o.VertexLayout(), 0.001)
+	if err != nil {
+		t.Fatalf("TestIndexTriangles: IndexTriangles: %v", err)
+	}
+	expectInt(t, "TestIndexTriangles: elements", elements, o2.NumberOfElements())
+	expectInt(t, "TestIndexTriangles: stride", o.StrideSize, o2.StrideSize)
+	expectInt(t, "TestIndexTriangles: indices", len(indices), len(o2.Indices))
+	size := o2.StrideSize / 4
+	for i, v := range o2.Indices {
+		if !within(o2.Coord[v*size:(v+1)*size], coord[indices[i]*size:(indices[i]+1)*size], 0.001) {
+			t.Errorf("TestIndexTriangles: corner %d: want=%v got=%v", i,
+				coord[indices[i]*size:(indices[i]+1)*size], o2.Coord[v*size:(v+1)*size])
+		}
+	}
+
+	// exact match keeps the perturbed vertex apart
+	o3, err := IndexTriangles(o.Coord, o.VertexLayout(), 0)
+	if err != nil {
+		t.Fatalf("TestIndexTriangles: exact: IndexTriangles: %v", err)
+	}
+	expectInt(t, "TestIndexTriangles: exact elements", elements+1, o3.NumberOfElements())
+}
+
+func TestIndexTrianglesBad(t *testing.T) {
+	layout := VertexLayout{Stride: 12, Attributes: []VertexAttribute{{Name: AttributePosition, Components: 3}}}
+	if _, err := IndexTriangles(make([]float32, 6), layout, 0); err == nil {
+		t.Errorf("TestIndexTrianglesBad: expected error for 2 vertices")
+	}
+	if _, err := IndexTriangles(make([]float32, 9), VertexLayout{Stride: 12}, 0); err == nil {
+		t.Errorf("TestIndexTrianglesBad: expected error for missing position")
+	}
+	o, err := IndexTriangles(make([]float32, 9), layout, 0)
+	if err != nil {
+		t.Fatalf("TestIndexTrianglesBad: degenerate: %v", err)
+	}
+	expectInt(t, "TestIndexTrianglesBad: degenerate elements", 1, o.NumberOfElements())
+}
+
+func TestWeld(t *testing.T) {
+	str := `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 0 0.00001
+v 1 1 0
+v 0 1 0
+vt 0 0
+vt 0.5 0
+f 1/1 2/1 3/1
+f 4/1 5/1 6/1
+f 4/2 5/2 6/2
+p 4/1
+`
+	o, err := NewObjFromBuf("weld", []byte(str), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestWeld: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestWeld: elements", 9, o.NumberOfElements())
+
+	removed := o.Weld(0.001, 0.001, 0.001)
+	expectInt(t, "TestWeld: removed", 2, removed)
+	expectInt(t, "TestWeld: welded elements", 7, o.NumberOfElements())
+	if want := []int{0, 1, 2, 0, 2, 3, 4, 5, 6}; !sliceEqualInt(want, o.Indices) {
+		t.Errorf("TestWeld: indices: want=%v got=%v", want, o.Indices)
+	}
+	if p := o.Groups[0].Points[0]; p != 0 {
+		t.Errorf("TestWeld: point: want=0 got=%d", p)
+	}
+
+	// loose texture tolerance merges the rest
+	removed = o.Weld(0.001, 1, 0.001)
+	expectInt(t, "TestWeld: loose removed", 3, removed)
+	if want := []int{0, 1, 2, 0, 2, 3, 0, 2, 3}; !sliceEqualInt(want, o.Indices) {
+		t.Errorf("TestWeld: loose indices: want=%v got=%v", want, o.Indices)
+	}
+}
@@ -0,0 +1,42 @@
+package gwob
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// byteOrderMark is the UTF-8 encoding of U+FEFF, often written
+// at the start of files saved by Windows editors.
+const byteOrderMark = "\uFEFF"
+
+// cleanLine strips a leading byte order mark and surrounding space,
+// and turns any inner whitespace (tabs, no-break spaces, etc) into
+// plain spaces, so that keyword prefixes like "v " are matched.
+func cleanLine(rawLine string) string {
+	line := strings.TrimSpace(strings.TrimPrefix(rawLine, byteOrderMark))
+	for i := 0; i < len(line); i++ {
+		if c := line[i]; c == '\t' || c == '\v' || c == '\f' || c == '\r' || c >= utf8.RuneSelf {
+			return spaceToBlank(line) // slow path
+		}
+	}
+	return line
+}
+
+// spaceToBlank replaces unicode space runes with plain spaces.
+// Other bytes, including invalid UTF-8 such as Latin-1 names,
+// are copied unchanged.
+func spaceToBlank(line string) string {
+	var b strings.Builder
+	b.Grow(len(line))
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRuneInString(line[i:])
+		if r != utf8.RuneError && unicode.IsSpace(r) {
+			b.WriteByte(' ')
+		} else {
+			b.WriteString(line[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
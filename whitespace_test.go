@@ -0,0 +1,52 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestByteOrderMark(t *testing.T) {
+	str := "\uFEFFv 0 0 0\nv\t1 0 0\nv 1 1 0\nf\t1 2\t3\n"
+
+	o, err := NewObjFromBuf("bom", []byte(str), NewObjParserOptions(WithStrict(true)))
+	if err != nil {
+		t.Fatalf("TestByteOrderMark: NewObjFromBuf: %v", err)
+	}
+	if !sliceEqualInt([]int{0, 1, 2}, o.Indices) {
+		t.Errorf("TestByteOrderMark: indices: got=%v", o.Indices)
+	}
+	if !sliceEqualFloat([]float32{0, 0, 0, 1, 0, 0, 1, 1, 0}, o.Coord) {
+		t.Errorf("TestByteOrderMark: coord: got=%v", o.Coord)
+	}
+}
+
+func TestByteOrderMarkLib(t *testing.T) {
+	str := "\uFEFFnewmtl\tred\nKd\t1 0\t0\n"
+
+	lib, err := ReadMaterialLibFromBuf([]byte(str), NewObjParserOptions(WithStrict(true)))
+	if err != nil {
+		t.Fatalf("TestByteOrderMarkLib: ReadMaterialLibFromBuf: %v", err)
+	}
+	mtl, found := lib.Lib["red"]
+	if !found {
+		t.Fatalf("TestByteOrderMarkLib: material not found: %v", lib.Lib)
+	}
+	if mtl.Kd != [3]float32{1, 0, 0} {
+		t.Errorf("TestByteOrderMarkLib: Kd: got=%v", mtl.Kd)
+	}
+}
+
+func TestLatin1Names(t *testing.T) {
+	// Latin-1 bytes are not valid UTF-8 and must be kept unchanged
+	str := "mtllib caf\xe9.mtl\nv 0 0 0\nv 1 0 0\nv 1 1 0\ng gr\xfcn\nusemtl caf\xe9 cr\xe8me\nf 1 2 3\n"
+
+	o, err := NewObjFromBuf("latin1", []byte(str), NewObjParserOptions(WithStrict(true)))
+	if err != nil {
+		t.Fatalf("TestLatin1Names: NewObjFromBuf: %v", err)
+	}
+	if o.Mtllib != "caf\xe9.mtl" {
+		t.Errorf("TestLatin1Names: mtllib: got=%q", o.Mtllib)
+	}
+	if len(o.Groups) != 1 || o.Groups[0].Name != "gr\xfcn" || o.Groups[0].Usemtl != "caf\xe9 cr\xe8me" {
+		t.Errorf("TestLatin1Names: groups: got=%+v", o.Groups)
+	}
+}
@@ -0,0 +1,121 @@
+package gwob
+
+// FlipWinding reverses the winding of all triangles, by swapping their
+// second and third indices, turning front faces into back faces. Faces
+// kept by the KeepFaces option are reversed alike. Normals are not
+// changed: see InvertNormals.
+func (o *Obj) FlipWinding() {
+	for t := 0; t < len(o.Indices)/3; t++ {
+		o.flipTriangle(t)
+	}
+	for _, f := range o.Faces {
+		f.reverse()
+	}
+}
+
+// flipTriangle swaps the second and third corners of triangle t.
+func (o *Obj) flipTriangle(t int) {
+	i := 3 * t
+	o.Indices[i+1], o.Indices[i+2] = o.Indices[i+2], o.Indices[i+1]
+	if len(o.Corners) == len(o.Indices) {
+		o.Corners[i+1], o.Corners[i+2] = o.Corners[i+2], o.Corners[i+1]
+	}
+}
+
+// reverse reverses the corners of the face, keeping the first one.
+func (f *Face) reverse() {
+	for i, j := 1, len(f.Vertices)-1; i < j; i, j = i+1, j-1 {
+		f.Vertices[i], f.Vertices[j] = f.Vertices[j], f.Vertices[i]
+		if len(f.Corners) == len(f.Vertices) {
+			f.Corners[i], f.Corners[j] = f.Corners[j], f.Corners[i]
+		}
+	}
+}
+
+// FixWinding makes the winding of the triangles consistent, so that
+// neighbor triangles cross their shared edge in opposite directions.
+// The orientation is propagated from triangle to triangle across the
+// manifold edges of each connected piece of surface, and the triangles
+// disagreeing with the majority of their piece are flipped, along with
+// their faces. It returns the number of triangles flipped. Normals are
+// not changed.
+func (o *Obj) FixWinding() int {
+	adj := o.Adjacency()
+	ids := adj.Position
+	triangles := len(o.Indices) / 3
+
+	// forward reports whether triangle t crosses edge e from A to B.
+	forward := func(t int, e Edge) bool {
+		for c := 0; c < 3; c++ {
+			if ids[o.Indices[3*t+c]] == e.A && ids[o.Indices[3*t+(c+1)%3]] == e.B {
+				return true
+			}
+		}
+		return false
+	}
+
+	flip := make([]bool, triangles)
+	visited := make([]bool, triangles)
+	for seed := 0; seed < triangles; seed++ {
+		if visited[seed] || degenerateIDs(ids, o.Indices[3*seed:3*seed+3]) {
+			continue
+		}
+		visited[seed] = true
+		piece := []int{seed}
+		flipped := 0
+		for next := 0; next < len(piece); next++ {
+			t := piece[next]
+			for c := 0; c < 3; c++ {
+				e := newEdge(ids[o.Indices[3*t+c]], ids[o.Indices[3*t+(c+1)%3]])
+				faces := adj.EdgeFaces[adj.EdgeIndex[e]]
+				if len(faces) != 2 {
+					continue // border or non-manifold
+				}
+				n := faces[0]
+				if n == t {
+					n = faces[1]
+				}
+				if visited[n] {
+					continue
+				}
+				visited[n] = true
+				// same direction means opposite orientation
+				flip[n] = flip[t] != (forward(t, e) == forward(n, e))
+				if flip[n] {
+					flipped++
+				}
+				piece = append(piece, n)
+			}
+		}
+		if 2*flipped > len(piece) {
+			for _, t := range piece {
+				flip[t] = !flip[t]
+			}
+		}
+	}
+
+	count := 0
+	for t, f := range flip {
+		if f {
+			o.flipTriangle(t)
+			count++
+		}
+	}
+	for _, f := range o.Faces {
+		if f.IndexCount > 0 && flip[f.IndexBegin/3] {
+			f.reverse()
+		}
+	}
+	return count
+}
+
+// InvertNormals negates all vertex normals.
+func (o *Obj) InvertNormals() {
+	if !o.NormCoordFound {
+		return
+	}
+	for s := 0; s < o.NumberOfElements(); s++ {
+		n := o.vertexNormal(s)
+		n[0], n[1], n[2] = -n[0], -n[1], -n[2]
+	}
+}
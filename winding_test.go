@@ -0,0 +1,115 @@
+package gwob
+
+import (
+	"testing"
+)
+
+func TestFlipWinding(t *testing.T) {
+	table := []struct {
+		name    string
+		options *ObjParserOptions
+	}{
+		{"interleaved", NewObjParserOptions(WithKeepFaces(true), WithKeepRawData(true))},
+		{"separate", NewObjParserOptions(WithKeepFaces(true), WithNonInterleaved(true))},
+	}
+	str := `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vn 0 0 1
+f 1//1 2//1 3//1 4//1
+`
+	for _, data := range table {
+		o, err := NewObjFromBuf("flip", []byte(str), data.options)
+		if err != nil {
+			t.Fatalf("TestFlipWinding: %s: NewObjFromBuf: %v", data.name, err)
+		}
+		indices := append([]int(nil), o.Indices...)
+
+		o.FlipWinding()
+		o.InvertNormals()
+
+		for i := 0; i < len(indices); i += 3 {
+			want := []int{indices[i], indices[i+2], indices[i+1]}
+			if got := o.Indices[i : i+3]; !sliceEqualInt(want, got) {
+				t.Errorf("TestFlipWinding: %s: triangle %d: want=%v got=%v", data.name, i/3, want, got)
+			}
+		}
+		if len(o.Corners) > 0 && o.Corners[1].V != indices[2] {
+			t.Errorf("TestFlipWinding: %s: corners not flipped: %v", data.name, o.Corners)
+		}
+		if want := []int{0, 3, 2, 1}; !sliceEqualInt(want, o.Faces[0].Vertices) {
+			t.Errorf("TestFlipWinding: %s: face: want=%v got=%v", data.name, want, o.Faces[0].Vertices)
+		}
+
+		for s := 0; s < o.NumberOfElements(); s++ {
+			if n := o.vertexNormal(s); n[2] != -1 {
+				t.Errorf("TestFlipWinding: %s: vertex %d normal=%v", data.name, s, n)
+			}
+		}
+		if n := o.triangleNormal(0); n[2] != -1 {
+			t.Errorf("TestFlipWinding: %s: triangle normal=%v", data.name, n)
+		}
+	}
+}
+
+func TestFixWinding(t *testing.T) {
+	// box with the second and fourth quads reversed
+	str := `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+v 0 0 1
+v 1 0 1
+v 1 1 1
+v 0 1 1
+f 1 4 3 2
+f 5 8 7 6
+f 1 2 6 5
+f 2 6 7 3
+f 3 4 8 7
+f 4 1 5 8
+`
+	o, err := NewObjFromBuf("fix", []byte(str), NewObjParserOptions(WithKeepFaces(true)))
+	if err != nil {
+		t.Fatalf("TestFixWinding: NewObjFromBuf: %v", err)
+	}
+	expectInt(t, "TestFixWinding: flipped", 4, o.FixWinding())
+	expectInt(t, "TestFixWinding: flipped again", 0, o.FixWinding())
+
+	// every shared edge is crossed in both directions
+	crossed := map[[2]int]int{}
+	for i := 0; i < len(o.Indices); i += 3 {
+		for c := 0; c < 3; c++ {
+			a, b := o.Indices[i+c], o.Indices[i+(c+1)%3]
+			crossed[[2]int{a, b}]++
+		}
+	}
+	for e, count := range crossed {
+		if count != 1 || crossed[[2]int{e[1], e[0]}] != 1 {
+			t.Errorf("TestFixWinding: edge %v crossed %d times, reverse %d", e, count, crossed[[2]int{e[1], e[0]}])
+		}
+	}
+
+	for _, f := range []struct {
+		face int
+		want []int
+	}{
+		{1, []int{4, 5, 6, 7}}, // f 5 6 7 8
+		{3, []int{1, 2, 6, 5}}, // f 2 3 7 6
+	} {
+		var got []int
+		for _, c := range o.Faces[f.face].Corners {
+			got = append(got, c.V)
+		}
+		if !sliceEqualInt(got, f.want) {
+			t.Errorf("TestFixWinding: face %d: %v want %v", f.face, got, f.want)
+		}
+	}
+
+	// flipping everything keeps the winding consistent
+	o.FlipWinding()
+	expectInt(t, "TestFixWinding: flipped all", 0, o.FixWinding())
+}
@@ -0,0 +1,359 @@
+package gwob
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteOptions controls the OBJ output of ToWriterWithOptions.
+// The zero value reproduces the ToWriter output.
+type WriteOptions struct {
+	// FloatFormat is the strconv format for coordinates: 'f', 'e' or 'g'.
+	// Zero means 'f'.
+	FloatFormat byte
+
+	// Precision is the number of digits for coordinates, as in
+	// strconv.FormatFloat. Zero means 6, and negative means the
+	// fewest digits that represent the value exactly.
+	Precision int
+
+	// RelativeIndices writes negative indices, relative to the
+	// end of the vertex data, instead of absolute ones.
+	RelativeIndices bool
+
+	// Polygons writes the original faces retained by the KeepFaces
+	// option, such as quads and n-gons, instead of their triangles.
+	Polygons bool
+
+	// Compact writes each distinct position, texture coordinate and
+	// normal once, with separate v/vt/vn indices in faces, instead of
+	// one v/vt/vn triple per unified vertex.
+	Compact bool
+
+	// NoSmoothing omits the "s" statements.
+	NoSmoothing bool
+
+	// FlipTextureV writes 1-v instead of v for texture coordinates,
+	// as the matching ObjParserOptions.FlipTextureV.
+	FlipTextureV bool
+
+	// Header replaces the comment written at the top of the output.
+	// NoHeader omits it.
+	Header   string
+	NoHeader bool
+
+	// LineEnding terminates each line. Empty means "\n".
+	LineEnding string
+}
+
+// objWriter formats OBJ statements according to the write options.
+type objWriter struct {
+	w        io.Writer
+	o        *Obj
+	format   byte
+	prec     int
+	relative bool
+	flipV    bool
+	eol      string
+	strides  int // vertex count
+	buf      []byte
+	err      error
+
+	// compact export: unified index to v, vt and vn indices,
+	// nil for the identity, and the element counts.
+	posMap, texMap, normMap  []int
+	positions, texs, normals int
+}
+
+func newObjWriter(w io.Writer, o *Obj, options *WriteOptions) *objWriter {
+	ow := &objWriter{
+		w:        w,
+		o:        o,
+		format:   options.FloatFormat,
+		prec:     options.Precision,
+		relative: options.RelativeIndices,
+		flipV:    options.FlipTextureV,
+		eol:      options.LineEnding,
+	}
+	if o != nil {
+		ow.strides = o.NumberOfElements()
+	}
+	if ow.format == 0 {
+		ow.format = 'f'
+	}
+	if ow.prec == 0 {
+		ow.prec = 6
+	}
+	if ow.eol == "" {
+		ow.eol = "\n"
+	}
+	ow.positions = ow.strides
+	ow.texs = ow.strides
+	ow.normals = ow.strides
+	return ow
+}
+
+// line starts a new statement.
+func (ow *objWriter) line(keyword string) {
+	ow.buf = append(ow.buf[:0], keyword...)
+}
+
+func (ow *objWriter) floats(values ...float32) {
+	for _, f := range values {
+		ow.buf = append(ow.buf, ' ')
+		ow.buf = strconv.AppendFloat(ow.buf, float64(f), ow.format, ow.prec, 32)
+	}
+}
+
+// texFloats appends texture coordinates u and v, flipping v if asked.
+func (ow *objWriter) texFloats(u, v float32) {
+	if ow.flipV {
+		v = 1 - v
+	}
+	ow.floats(u, v)
+}
+
+func (ow *objWriter) text(s string) {
+	ow.buf = append(ow.buf, s...)
+}
+
+// index appends the 1-based, or relative, form of unified index i,
+// mapped to an element index by remap, among count elements.
+func (ow *objWriter) index(i int, remap []int, count int) {
+	if remap != nil {
+		i = remap[i]
+	}
+	if ow.relative {
+		ow.buf = strconv.AppendInt(ow.buf, int64(i-count), 10)
+		return
+	}
+	ow.buf = strconv.AppendInt(ow.buf, int64(i+1), 10)
+}
+
+// corner appends the v/vt/vn indices for unified index i.
+func (ow *objWriter) corner(i int, normal bool) {
+	ow.buf = append(ow.buf, ' ')
+	ow.index(i, ow.posMap, ow.positions)
+	if ow.o.TextCoordFound {
+		ow.buf = append(ow.buf, '/')
+		ow.index(i, ow.texMap, ow.texs)
+	} else if normal {
+		ow.buf = append(ow.buf, '/')
+	}
+	if normal {
+		ow.buf = append(ow.buf, '/')
+		ow.index(i, ow.normMap, ow.normals)
+	}
+}
+
+// position writes the v statement for stride s.
+func (ow *objWriter) position(s int) {
+	o := ow.o
+	v := s*o.StrideSize/4 + o.StrideOffsetPosition/4
+	ow.line("v")
+	ow.floats(o.Coord[v], o.Coord[v+1], o.Coord[v+2])
+	if s < len(o.W) {
+		ow.floats(o.W[s])
+	} else if o.ColorFound {
+		ow.floats(o.VertexColor(s))
+	}
+	ow.end()
+}
+
+// texCoord writes the vt statement for stride s.
+func (ow *objWriter) texCoord(s int) {
+	o := ow.o
+	t := s*o.StrideSize/4 + o.StrideOffsetTexture/4
+	ow.line("vt")
+	ow.texFloats(o.Coord[t], o.Coord[t+1])
+	if s < len(o.TexW) {
+		ow.floats(o.TexW[s])
+	}
+	ow.end()
+}
+
+// normal writes the vn statement for stride s.
+func (ow *objWriter) normal(s int) {
+	o := ow.o
+	n := s*o.StrideSize/4 + o.StrideOffsetNormal/4
+	ow.line("vn")
+	ow.floats(o.Coord[n], o.Coord[n+1], o.Coord[n+2])
+	ow.end()
+}
+
+// end terminates and writes the current statement.
+func (ow *objWriter) end() {
+	if ow.err != nil {
+		return
+	}
+	ow.buf = append(ow.buf, ow.eol...)
+	_, ow.err = ow.w.Write(ow.buf)
+}
+
+// statement writes a whole statement.
+func (ow *objWriter) statement(keyword, args string) {
+	ow.line(keyword)
+	ow.text(args)
+	ow.end()
+}
+
+// header writes the comment at the top of the output.
+func (ow *objWriter) header(options *WriteOptions) {
+	switch {
+	case options.NoHeader:
+	case options.Header == "":
+		ow.statement("#", exportHeader)
+	default:
+		for _, h := range strings.Split(options.Header, "\n") {
+			ow.statement("# ", h)
+		}
+	}
+}
+
+// comments writes the comments preceding the face at index,
+// returning the remaining ones.
+func (ow *objWriter) comments(comments []Comment, index int) []Comment {
+	for len(comments) > 0 && comments[0].Index <= index {
+		ow.statement("#", comments[0].Text)
+		comments = comments[1:]
+	}
+	return comments
+}
+
+// ToWriterWithOptions writes OBJ to writer stream,
+// formatted according to the write options. Nil options
+// produce the same output as ToWriter.
+func (o *Obj) ToWriterWithOptions(w io.Writer, options *WriteOptions) error {
+	if o.Coord == nil && o.Positions != nil {
+		c := *o
+		c.Interleave()
+		return c.ToWriterWithOptions(w, options)
+	}
+	if options == nil {
+		options = &WriteOptions{}
+	}
+
+	ow := newObjWriter(w, o, options)
+
+	ow.header(options)
+	comments := ow.comments(o.Comments, 0)
+	ow.statement("", "")
+
+	switch {
+	case len(o.Mtllibs) > 0:
+		ow.statement("mtllib ", strings.Join(o.Mtllibs, " "))
+	case o.Mtllib != "":
+		ow.statement("mtllib ", o.Mtllib)
+	}
+	if len(o.Maplibs) > 0 {
+		ow.statement("maplib ", strings.Join(o.Maplibs, " "))
+	}
+	if o.ShadowObj != "" {
+		ow.statement("shadow_obj ", o.ShadowObj)
+	}
+	if o.TraceObj != "" {
+		ow.statement("trace_obj ", o.TraceObj)
+	}
+
+	// write vertex data
+	if options.Compact {
+		ow.compact()
+	} else {
+		for s := 0; s < ow.strides; s++ {
+			ow.position(s)
+			if o.TextCoordFound {
+				ow.texCoord(s)
+			}
+			if o.NormCoordFound {
+				ow.normal(s)
+			}
+		}
+	}
+
+	var faces []Face
+	if options.Polygons {
+		faces = o.Faces
+	}
+
+	// write group faces
+	var usemap string
+	var lod int
+	for _, g := range o.Groups {
+		if g.Name != "" {
+			ow.statement("g ", g.Name)
+		}
+		if g.Usemtl != "" {
+			ow.statement("usemtl ", g.Usemtl)
+		}
+		if g.Lod != lod {
+			lod = g.Lod
+			ow.statement("lod ", strconv.Itoa(lod))
+		}
+		if g.Usemap != usemap {
+			usemap = g.Usemap
+			if usemap == "" {
+				ow.statement("usemap ", "off")
+			} else {
+				ow.statement("usemap ", usemap)
+			}
+		}
+		usemtl := g.Usemtl
+		smooth := g.Smooth
+		if !options.NoSmoothing {
+			ow.statement("s ", strconv.Itoa(smooth))
+		}
+		if g.IndexCount%3 != 0 {
+			return fmt.Errorf("group=%s count=%d must be a multiple of 3", g.Name, g.IndexCount)
+		}
+		pastEnd := g.IndexBegin + g.IndexCount
+		for s := g.IndexBegin; s < pastEnd; {
+			comments = ow.comments(comments, s)
+			if t := s / 3; t < len(o.MaterialIndex) && o.MaterialIndex[t] >= 0 && o.MaterialNames[o.MaterialIndex[t]] != usemtl {
+				usemtl = o.MaterialNames[o.MaterialIndex[t]]
+				ow.statement("usemtl ", usemtl)
+			}
+			if t := s / 3; !options.NoSmoothing && t < len(o.Smoothing) && o.Smoothing[t] != smooth {
+				smooth = o.Smoothing[t]
+				ow.statement("s ", strconv.Itoa(smooth))
+			}
+			for len(faces) > 0 && faces[0].IndexBegin < s {
+				faces = faces[1:] // not a face start
+			}
+			ow.line("f")
+			if len(faces) > 0 && faces[0].IndexBegin == s && faces[0].IndexCount > 0 {
+				for _, i := range faces[0].Vertices {
+					ow.corner(i, o.NormCoordFound)
+				}
+				s += faces[0].IndexCount
+				faces = faces[1:]
+			} else {
+				for f := s; f < s+3; f++ {
+					ow.corner(o.Indices[f], o.NormCoordFound)
+				}
+				s += 3
+			}
+			ow.end()
+		}
+		if len(g.Points) > 0 {
+			ow.line("p")
+			for _, i := range g.Points {
+				ow.text(" ")
+				ow.index(i, ow.posMap, ow.positions)
+			}
+			ow.end()
+		}
+		for _, line := range g.Lines {
+			ow.line("l")
+			for _, i := range line {
+				ow.corner(i, false) // l statements carry no normals
+			}
+			ow.end()
+		}
+	}
+
+	ow.comments(comments, len(o.Indices))
+
+	return ow.err
+}
@@ -0,0 +1,73 @@
+package gwob
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteOptions(t *testing.T) {
+	o, err := NewObjFromBuf("cubeObj", []byte(cubeObj), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestWriteOptions: NewObjFromBuf: %v", err)
+	}
+
+	var plain, nilOptions bytes.Buffer
+	if errWrite := o.ToWriter(&plain); errWrite != nil {
+		t.Fatalf("TestWriteOptions: ToWriter: %v", errWrite)
+	}
+	if errWrite := o.ToWriterWithOptions(&nilOptions, nil); errWrite != nil {
+		t.Fatalf("TestWriteOptions: ToWriterWithOptions: %v", errWrite)
+	}
+	if plain.String() != nilOptions.String() {
+		t.Errorf("TestWriteOptions: nil options differ from ToWriter")
+	}
+
+	options := &WriteOptions{
+		Precision:       -1,
+		RelativeIndices: true,
+		NoSmoothing:     true,
+		Header:          "Copyright example\nLicense: CC0",
+		LineEnding:      "\r\n",
+	}
+	var buf bytes.Buffer
+	if errWrite := o.ToWriterWithOptions(&buf, options); errWrite != nil {
+		t.Fatalf("TestWriteOptions: ToWriterWithOptions: %v", errWrite)
+	}
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "# Copyright example\r\n# License: CC0\r\n") {
+		t.Errorf("TestWriteOptions: missing header: %q", out[:40])
+	}
+	if strings.Contains(out, "\ns ") {
+		t.Errorf("TestWriteOptions: unexpected s statement")
+	}
+	if strings.Count(out, "\n") != strings.Count(out, "\r\n") {
+		t.Errorf("TestWriteOptions: bare line feed found")
+	}
+	if !strings.Contains(out, "\r\nv 1 1 -1\r\n") {
+		t.Errorf("TestWriteOptions: shortest float format not found")
+	}
+	if !strings.Contains(out, "\r\nf -") {
+		t.Errorf("TestWriteOptions: relative index not found")
+	}
+
+	o2, err := NewObjFromBuf("cubeObj2", buf.Bytes(), NewObjParserOptions())
+	if err != nil {
+		t.Fatalf("TestWriteOptions: reparse: %v", err)
+	}
+	if !sliceEqualInt(o.Indices, o2.Indices) {
+		t.Errorf("TestWriteOptions: indices: want=%v got=%v", o.Indices, o2.Indices)
+	}
+	if !sliceEqualFloat(o.Coord, o2.Coord) {
+		t.Errorf("TestWriteOptions: coord: want=%v got=%v", o.Coord, o2.Coord)
+	}
+
+	buf.Reset()
+	if errWrite := o.ToWriterWithOptions(&buf, &WriteOptions{FloatFormat: 'e', Precision: 2, NoHeader: true}); errWrite != nil {
+		t.Fatalf("TestWriteOptions: ToWriterWithOptions: %v", errWrite)
+	}
+	if !strings.HasPrefix(buf.String(), "\n") || !strings.Contains(buf.String(), "v 1.00e+00 1.00e+00 -1.00e+00\n") {
+		t.Errorf("TestWriteOptions: exponent format: %q", buf.String()[:60])
+	}
+}
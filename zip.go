@@ -0,0 +1,98 @@
+package gwob
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// NewObjFromZip parses the first OBJ found in a zip archive.
+func NewObjFromZip(zipFile string, options *ObjParserOptions) (*Obj, error) {
+
+	archive, errOpen := zip.OpenReader(zipFile)
+	if errOpen != nil {
+		return nil, errOpen
+	}
+
+	defer archive.Close()
+
+	return NewObjFromZipReader(&archive.Reader, options)
+}
+
+// NewObjFromZipReader parses the first OBJ found in an opened zip archive.
+func NewObjFromZipReader(archive *zip.Reader, options *ObjParserOptions) (*Obj, error) {
+
+	objName, errFind := findZipObj(archive)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	return NewObjFromFS(archive, objName, options)
+}
+
+// LoadSceneFromZip loads the first OBJ found in a zip archive together with its materials.
+// Material libs and textures are resolved against other archive entries.
+// The returned scene keeps no reference to the archive file,
+// hence textures should be read with LoadSceneFromZipReader.
+func LoadSceneFromZip(zipFile string, options *ObjParserOptions) (*Scene, error) {
+
+	archive, errOpen := zip.OpenReader(zipFile)
+	if errOpen != nil {
+		return nil, errOpen
+	}
+
+	defer archive.Close()
+
+	s, errLoad := LoadSceneFromZipReader(&archive.Reader, options)
+	if errLoad != nil {
+		return nil, errLoad
+	}
+
+	s.open = func(name string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("LoadSceneFromZip: zip=%s closed: can't open %s", zipFile, name)
+	}
+
+	return s, nil
+}
+
+// LoadSceneFromZipReader loads the first OBJ found in an opened zip archive together with its materials.
+func LoadSceneFromZipReader(archive *zip.Reader, options *ObjParserOptions) (*Scene, error) {
+
+	objName, errFind := findZipObj(archive)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	return loadScene(path.Base(objName), zipOpener(archive, path.Dir(objName)), options)
+}
+
+// findZipObj finds the first .obj entry in the archive.
+func findZipObj(archive *zip.Reader) (string, error) {
+	for _, f := range archive.File {
+		if strings.HasSuffix(strings.ToLower(f.Name), ".obj") && !f.FileInfo().IsDir() {
+			return f.Name, nil
+		}
+	}
+	return "", fmt.Errorf("findZipObj: no .obj file found in zip archive")
+}
+
+// zipOpener resolves names relative to dir, falling back to a search
+// by base name since exporters often flatten or reshuffle directories.
+func zipOpener(archive *zip.Reader, dir string) opener {
+	open := fsOpener(archive, dir)
+	return func(name string) (io.ReadCloser, error) {
+		f, err := open(name)
+		if err == nil {
+			return f, nil
+		}
+		base := strings.ToLower(path.Base(strings.ReplaceAll(name, "\\", "/")))
+		for _, entry := range archive.File {
+			if strings.ToLower(path.Base(entry.Name)) == base {
+				return entry.Open()
+			}
+		}
+		return nil, err
+	}
+}
@@ -0,0 +1,72 @@
+package gwob
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSceneFromZip(t *testing.T) {
+
+	buf := bytes.Buffer{}
+	zw := zip.NewWriter(&buf)
+	for _, entry := range []struct{ name, data string }{
+		{"readme.txt", "hello"},
+		{"pack/scene.obj", sceneObj},
+		{"pack/scene.mtl", sceneMtl},
+		{"textures/red.png", "png"},
+	} {
+		w, err := zw.Create(entry.name)
+		if err != nil {
+			t.Errorf("TestLoadSceneFromZip: create: %v", err)
+			return
+		}
+		io.WriteString(w, entry.data)
+	}
+	zw.Close()
+
+	zipFile := filepath.Join(t.TempDir(), "pack.zip")
+	if err := os.WriteFile(zipFile, buf.Bytes(), 0o644); err != nil {
+		t.Errorf("TestLoadSceneFromZip: write zip: %v", err)
+		return
+	}
+
+	options := ObjParserOptions{LogStats: LogStats, Logger: func(msg string) { fmt.Printf("TestLoadSceneFromZip: log: %s\n", msg) }}
+
+	o, err := NewObjFromZip(zipFile, &options)
+	if err != nil {
+		t.Errorf("TestLoadSceneFromZip: NewObjFromZip: %v", err)
+		return
+	}
+	expectInt(t, "TestLoadSceneFromZip: groups", 2, len(o.Groups))
+
+	s, errScene := LoadSceneFromZip(zipFile, &options)
+	if errScene != nil {
+		t.Errorf("TestLoadSceneFromZip: LoadSceneFromZip: %v", errScene)
+		return
+	}
+	if s.Materials[0] == nil || s.Materials[0].Name != "red" {
+		t.Errorf("TestLoadSceneFromZip: group 0: want material red got %v", s.Materials[0])
+	}
+
+	archive, errReader := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if errReader != nil {
+		t.Errorf("TestLoadSceneFromZip: zip.NewReader: %v", errReader)
+		return
+	}
+	s, errScene = LoadSceneFromZipReader(archive, &options)
+	if errScene != nil {
+		t.Errorf("TestLoadSceneFromZip: LoadSceneFromZipReader: %v", errScene)
+		return
+	}
+	tex, errTex := s.OpenTexture("red.png") // found by base name
+	if errTex != nil {
+		t.Errorf("TestLoadSceneFromZip: OpenTexture: %v", errTex)
+		return
+	}
+	tex.Close()
+}